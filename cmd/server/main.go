@@ -1,85 +1,445 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/usecases"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/services"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/config"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/health"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/mercadopago"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/outbox"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/printing"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/ratelimit"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/receipts"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/webhooks"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/worker"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/routers"
+	"go.opentelemetry.io/otel/attribute"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	envErr := godotenv.Load()
+
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server [-config file.yaml] [config print]")
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", os.Getenv("CONFIG_FILE"), "optional YAML configuration file (CONFIG_FILE)")
+	flags.Parse(os.Args[1:])
+
+	switch strings.Join(flags.Args(), " ") {
+	case "":
+	case "config print":
+		os.Exit(printConfig(*configPath))
+	default:
+		flags.Usage()
+		os.Exit(2)
 	}
 
-	db, err := initDatabase()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
 	}
-	defer db.Close()
 
-	router := gin.Default()
+	logger, err := logging.New(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		slog.Error("Invalid logging configuration", "error", err)
+		os.Exit(1)
+	}
+	// Route the standard log package (used by some libraries) through slog too
+	slog.SetDefault(logger)
 
-	routerConfig := routers.RouterConfig{
-		Engine: router,
-		DB:     db,
+	if envErr != nil {
+		logger.Info("No .env file found")
 	}
-	routers.SetupRoutes(routerConfig)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), cfg.Telemetry.TracesExporter, cfg.Telemetry.ServiceName, os.Stdout)
+	if err != nil {
+		logger.Error("Invalid tracing configuration", "error", err)
+		os.Exit(1)
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	var db *sql.DB
+	if persistance.UsesSQL(cfg.Database.Driver) {
+		db, err = initDatabase(cfg.Database)
+		if err != nil {
+			logger.Error("Failed to connect to database", "driver", cfg.Database.Driver, "error", err)
+			os.Exit(1)
+		}
+	} else {
+		logger.Warn("Data will not be persisted", "driver", cfg.Database.Driver)
 	}
-}
 
-func initDatabase() (*sql.DB, error) {
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
+	gatewaySet, err := persistance.NewGateways(cfg.Database.Driver, db, logger)
+	if err != nil {
+		logger.Error("Failed to set up gateways", "error", err)
+		os.Exit(1)
+	}
 
-	if dbUser == "" {
-		dbUser = "root"
+	// gin.New instead of gin.Default: access logs and panic recovery come
+	// from our own middleware so they use the structured logger
+	gin.SetMode(cfg.Server.GinMode)
+	router := gin.New()
+	// Without trusted proxies X-Forwarded-For is ignored, so clients cannot
+	// pick the IP their rate limit bucket is keyed on
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxyList()); err != nil {
+		logger.Error("Invalid trusted proxies", "error", err)
+		os.Exit(1)
 	}
+	readiness := health.NewChecker(readinessChecks(db, cfg.Payment.ProviderHealthURL)...)
+	rateLimitStore := ratelimit.NewMemoryStore()
 
-	if dbPassword == "" {
-		dbPassword = "password"
+	// ctx is cancelled on the first SIGINT/SIGTERM; background workers
+	// started on it stop while the HTTP server drains
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	workers := worker.NewGroup(logger)
+	workers.Go(ctx, "rate-limit-sweeper", func(ctx context.Context) error {
+		return rateLimitStore.Run(ctx, ratelimit.DefaultSweepInterval)
+	})
+	workers.Go(ctx, "idempotency-key-purger", func(ctx context.Context) error {
+		return purgeIdempotencyKeys(ctx, gatewaySet.Idempotency, cfg.Idempotency.KeyTTL, logger)
+	})
+	metrics := telemetry.NewMetrics(gatewaySet.Order)
+	// Without an access token the provider cannot be asked; stored reports
+	// stay readable
+	var paymentProvider output.PaymentProvider
+	if cfg.Payment.AccessToken != "" {
+		paymentProvider = mercadopago.NewClient(cfg.Payment.ProviderURL, cfg.Payment.AccessToken, &http.Client{Timeout: 10 * time.Second})
 	}
 
-	if dbHost == "" {
-		dbHost = "localhost"
+	// Each use case is built once and shared by the HTTP routes, the outbox
+	// relay and the background workers
+	useCases := routers.UseCases{
+		Customer: usecases.NewCustomerUseCase(gatewaySet.Customer),
+		Product:  usecases.NewProductUseCase(gatewaySet.Product),
+		Order: usecases.NewOrderUseCase(gatewaySet.Order, gatewaySet.OrderItem, gatewaySet.Product, gatewaySet.Payment, gatewaySet.Outbox,
+			gatewaySet.Promotion, gatewaySet.OrderDiscount, gatewaySet.OrderTax, gatewaySet.Customer, gatewaySet.Loyalty, gatewaySet.Transactions,
+			cfg.Payment.TTL, cfg.Store.Location(), cfg.Loyalty.Program(), services.NewPricingService(cfg.Pricing.Rules()), logger, metrics),
+		Payment: usecases.NewPaymentUseCase(gatewaySet.Payment, gatewaySet.Order, gatewaySet.OrderItem,
+			gatewaySet.Outbox, gatewaySet.Transactions, cfg.Payment.TTL, logger, metrics),
+		Webhook:   usecases.NewWebhookUseCase(gatewaySet.WebhookSubscription, gatewaySet.WebhookDelivery),
+		Promotion: usecases.NewPromotionUseCase(gatewaySet.Promotion),
+		Loyalty:   usecases.NewLoyaltyUseCase(gatewaySet.Order, gatewaySet.Customer, gatewaySet.Loyalty, gatewaySet.Payment, gatewaySet.Transactions, cfg.Loyalty.Program(), logger),
+		Receipt: usecases.NewReceiptUseCase(gatewaySet.Order, gatewaySet.OrderItem, gatewaySet.OrderTax, gatewaySet.Product, gatewaySet.Payment, gatewaySet.Receipt,
+			cfg.Store.Issuer(), receipts.NewXMLRenderer(cfg.Store.Location()), receipts.NewTextRenderer(cfg.Store.ReceiptWidth, cfg.Store.Location()), logger),
+		KitchenTicket: usecases.NewKitchenTicketUseCase(gatewaySet.Order, gatewaySet.OrderItem, gatewaySet.Product, gatewaySet.KitchenTicket,
+			printing.NewESCPOSRenderer(cfg.KitchenPrinter.TicketWidth, cfg.Store.Location()), kitchenPrinter(cfg.KitchenPrinter), cfg.KitchenPrinter.Retry(), logger),
+		StationTicket: usecases.NewStationTicketUseCase(gatewaySet.Order, gatewaySet.OrderItem, gatewaySet.Product, gatewaySet.StationTicket,
+			gatewaySet.Outbox, gatewaySet.Transactions, cfg.Kitchen.StationList(), logger, metrics),
 	}
+	useCases.Reconciliation = usecases.NewReconciliationUseCase(useCases.Payment, gatewaySet.Payment, gatewaySet.Reconciliation,
+		paymentProvider, gatewaySet.Transactions, logger)
 
-	if dbPort == "" {
-		dbPort = "3306"
+	workers.Go(ctx, "payment-expirer", func(ctx context.Context) error {
+		return expirePayments(ctx, useCases.Payment, cfg.Payment.ExpirationInterval, logger)
+	})
+	if paymentProvider != nil {
+		workers.Go(ctx, "payment-reconciler", func(ctx context.Context) error {
+			return reconcilePayments(ctx, useCases.Reconciliation, cfg.Reconciliation, logger)
+		})
+	}
+	if cfg.KitchenPrinter.Driver != printing.PrinterNone {
+		workers.Go(ctx, "kitchen-printer", func(ctx context.Context) error {
+			return printKitchenTickets(ctx, useCases.KitchenTicket, cfg.KitchenPrinter.PollInterval, logger)
+		})
 	}
+	// Every outbox event also feeds the partner webhook subscriptions, the
+	// loyalty ledger, the receipts of paid orders, the kitchen printer and the
+	// kitchen stations
+	publisher := outbox.NewMultiPublisher(eventPublisher(cfg.Outbox, logger), useCases.Webhook, useCases.Loyalty,
+		useCases.Receipt, useCases.KitchenTicket, useCases.StationTicket)
+	relay := outbox.NewRelay(gatewaySet.Outbox, publisher, logger, cfg.Outbox.RelayConfig())
+	workers.Go(ctx, "outbox-relay", relay.Run)
+	dispatcher := webhooks.NewDispatcher(gatewaySet.WebhookSubscription, gatewaySet.WebhookDelivery,
+		&http.Client{Timeout: cfg.Webhooks.Timeout}, logger, cfg.Webhooks.DispatcherConfig())
+	workers.Go(ctx, "webhook-dispatcher", dispatcher.Run)
+
+	routerConfig := routers.RouterConfig{
+		Engine:   router,
+		DB:       db,
+		Gateways: gatewaySet,
+		Logger:   logger,
+		Metrics:  metrics,
+
+		Readiness:      readiness,
+		RequestTimeout: cfg.Server.RequestTimeout,
+		RateLimitStore: rateLimitStore,
+		RateLimits:     cfg.RateLimit.Policies(),
+
+		UseCases: useCases,
+	}
+	routers.SetupRoutes(routerConfig)
 
-	if dbName == "" {
-		dbName = "fastfood"
+	server := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
 	}
 
-	dsn := dbUser + ":" + dbPassword + "@tcp(" + dbHost + ":" + dbPort + ")/" + dbName + "?charset=utf8mb4&parseTime=True&loc=Local"
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server starting", "port", cfg.Server.Port, "driver", cfg.Database.Driver, "environment", cfg.Server.Environment)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		logger.Error("Failed to start server", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process instead of waiting for the drain
+	stop()
+
+	logger.Info("Shutting down", "drain_delay", cfg.Server.ShutdownDrainDelay, "timeout", cfg.Server.ShutdownTimeout)
+	// Fail readiness first so Kubernetes stops routing new requests here
+	// before the listener closes
+	readiness.Drain()
+	time.Sleep(cfg.Server.ShutdownDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server did not drain in time", "error", err)
+	}
+	if err := workers.Wait(shutdownCtx); err != nil {
+		logger.Error("Background workers did not stop in time", "error", err)
+	}
+	if db != nil {
+		if err := db.Close(); err != nil {
+			logger.Error("Failed to close database", "error", err)
+		}
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Failed to flush traces", "error", err)
+	}
+
+	logger.Info("Server stopped")
+}
+
+// printConfig writes the effective configuration with secrets redacted and
+// reports validation errors on stderr. It returns the exit code.
+func printConfig(path string) int {
+	cfg, loadErr := config.Load(path)
+
+	out, err := cfg.YAML()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	os.Stdout.Write(out)
+
+	if loadErr != nil {
+		fmt.Fprintln(os.Stderr, "\ninvalid configuration:\n"+loadErr.Error())
+		return 1
+	}
+	return 0
+}
 
-	db, err := sql.Open("mysql", dsn)
+func initDatabase(cfg config.Database) (*sql.DB, error) {
+	sqlDriver, err := persistance.SQLDriverName(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
 
+	// otelsql adds a span per query as a child of the request span
+	db, err := otelsql.Open(sqlDriver, cfg.DSN(),
+		otelsql.WithAttributes(attribute.String("db.system", cfg.Driver)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	// The defaults keep a small pool per replica, since the HPA may run up to
+	// ten of them against one database
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	if err := db.Ping(); err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
+
+// purgeIdempotencyKeys deletes expired Idempotency-Key records every hour
+// until ctx is cancelled. A failed purge is retried on the next tick.
+func purgeIdempotencyKeys(ctx context.Context, gateway output.IdempotencyGateway, ttl time.Duration, logger *slog.Logger) error {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			deleted, err := gateway.DeleteCreatedBefore(ctx, time.Now().Add(-ttl))
+			if err != nil {
+				logger.WarnContext(ctx, "Failed to purge idempotency keys", "error", err)
+				continue
+			}
+			logger.DebugContext(ctx, "Purged idempotency keys", "deleted", deleted)
+		}
+	}
+}
+
+// expirePayments expires stale payments every interval, in batches until
+// none is left, cancelling the orders that were awaiting them
+func expirePayments(ctx context.Context, payments input.PaymentUseCase, interval time.Duration, logger *slog.Logger) error {
+	const batchSize = 100
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for {
+				expired, err := payments.ExpirePayments(ctx, time.Now(), batchSize)
+				if err != nil {
+					logger.WarnContext(ctx, "Failed to expire payments", "error", err)
+					break
+				}
+				if expired > 0 {
+					logger.DebugContext(ctx, "Expired payments", "expired", expired)
+				}
+				if expired < batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// printKitchenTickets prints the queued kitchen tickets every interval,
+// apart from the outbox relay so a slow printer never holds up events
+func printKitchenTickets(ctx context.Context, kitchenTickets input.KitchenTicketUseCase, interval time.Duration, logger *slog.Logger) error {
+	const batchSize = 100
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for {
+				printed, err := kitchenTickets.PrintDueTickets(ctx, time.Now(), batchSize)
+				if err != nil {
+					logger.WarnContext(ctx, "Failed to print kitchen tickets", "error", err)
+					break
+				}
+				if printed < batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// reconcilePayments asks the provider about pending payments older than
+// cfg.MinAge every cfg.Interval, then writes yesterday's report once the day
+// is over and it does not exist yet
+func reconcilePayments(ctx context.Context, reconciliation input.ReconciliationUseCase, cfg config.Reconciliation, logger *slog.Logger) error {
+	const batchSize = 100
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			now := time.Now()
+			updated, err := reconciliation.ReconcilePendingPayments(ctx, now.Add(-cfg.MinAge), batchSize)
+			if err != nil {
+				logger.WarnContext(ctx, "Failed to reconcile payments", "error", err)
+			} else if updated > 0 {
+				logger.InfoContext(ctx, "Reconciled payments", "updated", updated)
+			}
+
+			yesterday := now.UTC().AddDate(0, 0, -1)
+			_, err = reconciliation.GetReport(ctx, yesterday)
+			if errs.CodeOf(err) != errs.CodeReconciliationReportNotFound {
+				if err != nil {
+					logger.WarnContext(ctx, "Failed to read reconciliation report", "error", err)
+				}
+				continue
+			}
+			if _, err := reconciliation.GenerateReport(ctx, yesterday); err != nil {
+				logger.WarnContext(ctx, "Failed to generate reconciliation report", "error", err)
+			}
+		}
+	}
+}
+
+// eventPublisher returns the publisher selected by OUTBOX_PUBLISHER
+func eventPublisher(cfg config.Outbox, logger *slog.Logger) output.EventPublisher {
+	if cfg.Publisher == outbox.PublisherHTTP {
+		return outbox.NewHTTPPublisher(cfg.WebhookURL, &http.Client{Timeout: 10 * time.Second})
+	}
+	return outbox.NewLogPublisher(logger)
+}
+
+// kitchenPrinter returns the printer KITCHEN_PRINTER selects, or nil when the
+// store has none
+func kitchenPrinter(cfg config.KitchenPrinter) output.Printer {
+	switch cfg.Driver {
+	case printing.PrinterTCP:
+		return printing.NewTCPPrinter(cfg.Address, cfg.Timeout)
+	case printing.PrinterFile:
+		return printing.NewFilePrinter(cfg.Path)
+	}
+	return nil
+}
+
+// readinessChecks lists what /readyz verifies: the database and its schema
+// version when a SQL driver is used, and the payment provider when
+// PAYMENT_PROVIDER_HEALTH_URL is set
+func readinessChecks(db *sql.DB, providerHealthURL string) []health.Check {
+	var checks []health.Check
+	if db != nil {
+		checks = append(checks, health.DatabaseCheck(db), health.SchemaCheck(db))
+	}
+	if providerHealthURL != "" {
+		checks = append(checks, health.HTTPCheck("payment_provider", providerHealthURL, http.DefaultClient))
+	}
+	return checks
+}
@@ -22,7 +22,8 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "customers"
@@ -43,22 +44,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_CustomerResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -68,7 +72,8 @@ const docTemplate = `{
             "get": {
                 "description": "Get customer by ID",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "customers"
@@ -87,22 +92,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_CustomerResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -112,7 +114,8 @@ const docTemplate = `{
             "get": {
                 "description": "Get customer by CPF",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "customers"
@@ -125,28 +128,37 @@ const docTemplate = `{
                         "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client (totem) identifier for rate limiting",
+                        "name": "X-Client-Id",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_CustomerResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -159,7 +171,8 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "customers"
@@ -187,29 +200,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_CustomerResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -217,7 +226,8 @@ const docTemplate = `{
             "delete": {
                 "description": "Delete customer by ID",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "customers"
@@ -236,22 +246,221 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/{id}/loyalty": {
+            "get": {
+                "description": "Balance, next expiration and ledger of a customer's loyalty points. Completed orders earn points, redemptions at checkout spend them, cancellations give redeemed points back, refunds take earned points away and unused points expire",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Customer loyalty points",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_LoyaltyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchen/stations": {
+            "get": {
+                "description": "List the kitchen stations set in KITCHEN_STATIONS and the product categories each one prepares. Empty when the kitchen works from a single queue.",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "List kitchen stations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_KitchenStationResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchen/stations/{station}/tickets": {
+            "get": {
+                "description": "Get the pending and preparing tickets of a kitchen station, oldest first. Tickets of orders that left the kitchen (ready, completed or cancelled) are excluded.",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Get a station's queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Station name",
+                        "name": "station",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_StationTicketResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/kitchen/tickets/{id}/status": {
+            "put": {
+                "description": "Move a station ticket along pending \u003e preparing \u003e done. The order moves to in_progress when the first of its tickets is being prepared and to ready once all of them are done.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "kitchen"
+                ],
+                "summary": "Update station ticket status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Station ticket ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateStationTicketStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_StationTicketResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -261,25 +470,45 @@ const docTemplate = `{
             "get": {
                 "description": "Get all orders in the system",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
                 ],
                 "summary": "Get all orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-array_dto_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -290,7 +519,8 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
@@ -305,28 +535,55 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.CreateOrderRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client (totem) identifier for rate limiting",
+                        "name": "X-Client-Id",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_OrderResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -336,7 +593,8 @@ const docTemplate = `{
             "get": {
                 "description": "Get all orders for a specific CPF",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
@@ -349,21 +607,38 @@ const docTemplate = `{
                         "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-array_dto_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -373,7 +648,8 @@ const docTemplate = `{
             "get": {
                 "description": "Get all orders for a specific customer ID",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
@@ -386,21 +662,38 @@ const docTemplate = `{
                         "name": "customerId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-array_dto_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -408,27 +701,47 @@ const docTemplate = `{
         },
         "/orders/kitchen": {
             "get": {
-                "description": "Get orders for kitchen with priority ordering (Ready \u003e In Progress \u003e Received) and oldest first. Orders awaiting payment and completed orders are excluded.",
+                "description": "Get orders for kitchen with priority ordering (Ready \u003e In Progress \u003e Received) and oldest first. Orders awaiting payment and completed orders are excluded. With KITCHEN_STATIONS set, each station works from its own queue at /kitchen/stations/{station}/tickets.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
                 ],
                 "summary": "Get orders for kitchen",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-array_dto_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -438,7 +751,8 @@ const docTemplate = `{
             "get": {
                 "description": "Get order by ID with items",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
@@ -457,22 +771,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_OrderResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -480,7 +791,8 @@ const docTemplate = `{
             "delete": {
                 "description": "Delete order by ID",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
@@ -499,40 +811,35 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-any"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/orders/{id}/status": {
-            "put": {
-                "description": "Update the status of an existing order",
-                "consumes": [
-                    "application/json"
-                ],
+        "/orders/{id}/kitchen-ticket/reprint": {
+            "post": {
+                "description": "Queue the kitchen ticket of a paid order again, e.g. after the paper jammed or the printer gave up on it. Each copy is numbered; copies after the first are marked as reprints. The copy comes back pending and is printed in the background, retried while the printer fails.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "orders"
                 ],
-                "summary": "Update order status",
+                "summary": "Reprint an order's kitchen ticket",
                 "parameters": [
                     {
                         "type": "integer",
@@ -540,113 +847,123 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "status",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateOrderStatusRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_KitchenTicketResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/payments": {
-            "post": {
-                "description": "Create a new payment for an order",
-                "consumes": [
-                    "application/json"
-                ],
+        "/orders/{id}/receipt": {
+            "get": {
+                "description": "Get the fiscal-style receipt of a paid order, with its items, price breakdown, approximate taxes, approved payments and the customer's CPF when given. Receipts are issued once the order is paid and never change afterwards. Use format=nfce for the NFC-e–like XML document or format=text for the thermal printer layout; otherwise the receipt comes in the usual envelope.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "text/plain"
                 ],
                 "tags": [
-                    "payments"
+                    "orders"
                 ],
-                "summary": "Create new payment",
+                "summary": "Get the receipt of an order",
                 "parameters": [
                     {
-                        "description": "payment",
-                        "name": "payment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreatePaymentRequest"
-                        }
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "nfce",
+                            "text"
+                        ],
+                        "type": "string",
+                        "description": "Rendition",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_ReceiptResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/payments/status/{order_id}": {
+        "/orders/{id}/station-tickets": {
             "get": {
-                "description": "Get the current payment status for an order",
+                "description": "Get the tickets an order was split into, one per kitchen station that prepares part of it",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
-                    "payments"
+                    "orders"
                 ],
-                "summary": "Get payment status by order ID",
+                "summary": "Get an order's station tickets",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Order ID",
-                        "name": "order_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -655,217 +972,287 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-array_dto_StationTicketResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/payments/transaction/{transaction_id}": {
-            "get": {
-                "description": "Get payment details by transaction ID",
-                "produces": [
+        "/orders/{id}/status": {
+            "put": {
+                "description": "Update the status of an existing order. Allowed transitions: awaiting_payment \u003e received \u003e in_progress \u003e ready \u003e completed, and cancelled from any status before ready.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
                 "tags": [
-                    "payments"
+                    "orders"
                 ],
-                "summary": "Get payment by transaction ID",
+                "summary": "Update order status",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Transaction ID",
-                        "name": "transaction_id",
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateOrderStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/payments/webhook": {
+        "/payments": {
             "post": {
-                "description": "Webhook endpoint to receive payment status updates from payment provider",
+                "description": "Start a payment attempt for an order awaiting payment. A bill can be split by creating one payment per share, each with its own amount and payer; shares that exceed what is not yet covered are rejected with 409 payment_exceeds_balance. Repeating a pending share (same amount and payer) returns it instead; after it was rejected or canceled a new attempt is created, e.g. with another payment method. Orders no longer awaiting payment are rejected with 409 order_not_awaiting_payment.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
                     "payments"
                 ],
-                "summary": "Payment webhook endpoint",
+                "summary": "Create new payment",
                 "parameters": [
                     {
-                        "description": "webhook payload",
-                        "name": "webhook",
+                        "description": "payment",
+                        "name": "payment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.WebhookPaymentRequest"
+                            "$ref": "#/definitions/dto.CreatePaymentRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes retries replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_PaymentResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/products": {
+        "/payments/attempts/{order_id}": {
             "get": {
-                "description": "Get all products",
+                "description": "List every payment attempt of an order, oldest first, including each share of a split bill.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
+                ],
+                "summary": "List payment attempts of an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all products",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-array_dto_PaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create new product",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/payments/reconciliation/{date}": {
+            "get": {
+                "description": "Get the mismatches found between our payments and the payment provider's records on a UTC day. Reports are generated daily for the previous day. Send Accept: text/csv to download the mismatches as CSV.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "text/csv"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
                 ],
-                "summary": "Create new product",
+                "summary": "Get the payment reconciliation report of a day",
                 "parameters": [
                     {
-                        "description": "product",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateProductRequest"
-                        }
+                        "type": "string",
+                        "description": "Day (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_ReconciliationReportResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/products/category/{category}": {
+        "/payments/status/{order_id}": {
             "get": {
-                "description": "Get products by category (snack, drink, dessert, side)",
+                "description": "Get the status of the order's latest payment attempt, with the order total and how much of it is paid and still due",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
                 ],
-                "summary": "Get products by category",
+                "summary": "Get payment status by order ID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Product Category",
-                        "name": "category",
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
                         "in": "path",
                         "required": true
                     }
@@ -874,42 +1261,46 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_PaymentStatusResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/products/{id}": {
+        "/payments/transaction/{transaction_id}": {
             "get": {
-                "description": "Get product by ID",
+                "description": "Get payment details by transaction ID",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get payment by transaction ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transaction_id",
                         "in": "path",
                         "required": true
                     }
@@ -918,53 +1309,46 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_PaymentResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update product information",
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Webhook endpoint to receive payment status updates from payment provider. The order is received once its approved payments cover the total. Updates for a payment that already expired are rejected with 409 payment_expired; refunds of a payment that was not approved with 409 payment_not_refundable; any other change to a payment that is no longer pending (e.g. approved to rejected) with 409 invalid_payment_transition.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
                 ],
-                "summary": "Update product",
+                "summary": "Payment webhook endpoint",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "product",
-                        "name": "product",
+                        "description": "webhook payload",
+                        "name": "webhook",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateProductRequest"
+                            "$ref": "#/definitions/dto.WebhookPaymentRequest"
                         }
                     }
                 ],
@@ -972,46 +1356,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-any"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete product by ID",
+            }
+        },
+        "/payments/{id}/refund": {
+            "post": {
+                "description": "Record the refund of an approved payment, e.g. one share of a split bill, and publish payment.refunded. The order keeps its status; the money itself is returned through the payment provider. Payments that are not approved are rejected with 409 payment_not_refundable.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml"
                 ],
                 "tags": [
-                    "products"
+                    "payments"
                 ],
-                "summary": "Delete product",
+                "summary": "Refund a payment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Payment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1021,249 +1404,2646 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/presenters.Response-dto_PaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.CreateCustomerRequest": {
-            "type": "object",
-            "required": [
+        },
+        "/products": {
+            "get": {
+                "description": "Get all products",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create new product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create new product",
+                "parameters": [
+                    {
+                        "description": "product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/category/{category}": {
+            "get": {
+                "description": "Get products by category (snack, drink, dessert, side)",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get products by category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product Category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Get product by ID",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_ProductResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update product information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete product by ID",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "description": "List every promotion, oldest first",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "List promotions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a promotion. Without a code it applies to every order placed while in effect; with one it is a coupon. Types: percentage (value is the percent), fixed (value is the amount), buy_x_get_y (buy_quantity and free_quantity). Weekdays and daily_start/daily_end are read in the store's time zone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Create promotion",
+                "parameters": [
+                    {
+                        "description": "promotion",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{id}": {
+            "get": {
+                "description": "Get promotion by ID",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Get promotion by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_PromotionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a promotion's rule and time window. Orders already placed keep their discounts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Update promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "promotion",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a promotion. Orders already placed keep their discount lines",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Delete promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "List every webhook subscription",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a partner endpoint for order and payment events. Deliveries are signed with the secret (generated when omitted), which is only returned here. Event types: order.created, order.status_changed, order.received, order.in_progress, order.ready, order.completed, order.cancelled, payment.approved, payment.rejected, payment.canceled, payment.expired, payment.refunded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Subscribe to webhooks",
+                "parameters": [
+                    {
+                        "description": "subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateWebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Get webhook subscription by ID",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook subscription by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_WebhookSubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook subscription and its delivery log",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "List a subscription's deliveries, newest first, with attempts and the outcome of the last one",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook delivery log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (1-100), omit to list everything",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-array_dto_WebhookDeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Queue a delivery again with a fresh retry budget, e.g. after it failed or the partner lost it",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/presenters.Response-dto_WebhookDeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.CreateCustomerRequest": {
+            "type": "object",
+            "required": [
                 "cpf",
                 "email",
                 "first_name",
                 "last_name"
             ],
             "properties": {
-                "cpf": {
-                    "type": "string",
-                    "example": "123.456.789-00"
+                "cpf": {
+                    "type": "string",
+                    "example": "123.456.789-00"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao.silva@email.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "João"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Silva"
+                }
+            }
+        },
+        "dto.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "coupon_code": {
+                    "description": "CouponCode applies a coupon on top of the automatic promotions",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "TERCA10"
+                },
+                "cpf": {
+                    "type": "string",
+                    "example": "123.456.789-00"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderItemRequest"
+                    }
+                },
+                "payment_method": {
+                    "description": "Optional: qr_code, credit_card, debit_card",
+                    "type": "string",
+                    "example": "qr_code"
+                },
+                "payments": {
+                    "description": "Payments splits the bill; the amounts must add up to the order total.\nWhen given, PaymentMethod is ignored.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentPartRequest"
+                    }
+                },
+                "redeem_points": {
+                    "description": "RedeemPoints spends the customer's loyalty points as a discount; only\nas many as fit in the total are used",
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "dto.CreatePaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "order_id",
+                "payment_method"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payer": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ana"
+                },
+                "payment_method": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "description",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "snack"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Delicious cheeseburger with cheddar and pickles"
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://example.com/images/cheeseburger.png"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Cheeseburger"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 12.99
+                }
+            }
+        },
+        "dto.CreateWebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "order.ready",
+                        "order.completed"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16,
+                    "example": "0123456789abcdef0123456789abcdef"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/webhooks/fast-food"
+                }
+            }
+        },
+        "dto.CustomerResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string",
+                    "example": "123.456.789-00"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao.silva@email.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "João"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Silva"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-02T15:30:00Z"
+                }
+            }
+        },
+        "dto.KitchenStationResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "snack"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "grill"
+                }
+            }
+        },
+        "dto.KitchenTicketItemResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "X-Burger"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sem cebola"
+                    ]
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "bem passado"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.KitchenTicketResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "copy": {
+                    "description": "Copy counts the order's tickets; copies after the first are reprints",
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KitchenTicketItemResponse"
+                    }
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "dial tcp 192.168.0.50:9100: i/o timeout"
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "printed_at": {
+                    "type": "string",
+                    "example": "2026-10-19T12:05:00Z"
+                },
+                "reprint": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "description": "Status starts pending: the copy is printed in the background and\nretried while the printer fails",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "printed",
+                        "failed"
+                    ],
+                    "example": "pending"
+                }
+            }
+        },
+        "dto.LoyaltyEntryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-10-19T12:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-10-19T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "payment_id": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "earn",
+                        "redeem",
+                        "restore",
+                        "reverse",
+                        "expire"
+                    ],
+                    "example": "earn"
+                }
+            }
+        },
+        "dto.LoyaltyExpirationResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-10-19T12:00:00Z"
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "dto.LoyaltyResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance is the usable points; BalanceValue what they take off an order",
+                    "type": "integer",
+                    "example": 320
+                },
+                "balance_value": {
+                    "type": "number",
+                    "example": 16
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "entries": {
+                    "description": "Entries is the ledger, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LoyaltyEntryResponse"
+                    }
+                },
+                "next_expiration": {
+                    "$ref": "#/definitions/dto.LoyaltyExpirationResponse"
+                }
+            }
+        },
+        "dto.OrderDiscountResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 4
+                },
+                "code": {
+                    "type": "string",
+                    "example": "TERCA10"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Sobremesa de terça"
+                },
+                "promotion_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.OrderItemRequest": {
+            "type": "object",
+            "required": [
+                "modifiers",
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "modifiers": {
+                    "description": "Modifiers and Notes are printed on the kitchen ticket",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sem cebola"
+                    ]
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 140,
+                    "example": "bem passado"
+                },
+                "product_id": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 99,
+                    "example": 2
+                }
+            }
+        },
+        "dto.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sem cebola"
+                    ]
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "bem passado"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 19.99
+                },
+                "product_id": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "subtotal": {
+                    "type": "number",
+                    "example": 39.98
+                }
+            }
+        },
+        "dto.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string",
+                    "example": "123.456.789-00"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "customer_id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "discount": {
+                    "type": "number",
+                    "example": 4
+                },
+                "discounts": {
+                    "description": "Discounts lists what each promotion took off; Discount is their sum",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderDiscountResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderItemResponse"
+                    }
+                },
+                "rounding": {
+                    "type": "number",
+                    "example": -0.02
+                },
+                "service_fee": {
+                    "description": "ServiceFee is charged on the subtotal after discounts; Rounding is what\nrounding the total added, negative when it took off",
+                    "type": "number",
+                    "example": 3.99
+                },
+                "status": {
+                    "type": "string",
+                    "example": "received"
+                },
+                "subtotal": {
+                    "type": "number",
+                    "example": 43.98
+                },
+                "taxes": {
+                    "description": "Taxes are the approximate taxes included in the total, per product\ncategory",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderTaxResponse"
+                    }
+                },
+                "total": {
+                    "type": "number",
+                    "example": 43.95
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:30:00Z"
+                }
+            }
+        },
+        "dto.OrderTaxResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 5.38
+                },
+                "base": {
+                    "type": "number",
+                    "example": 39.98
+                },
+                "category": {
+                    "type": "string",
+                    "example": "snack"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 13.45
+                }
+            }
+        },
+        "dto.PaymentPartRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payment_method"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 19.99
+                },
+                "payer": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Ana"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "pix"
+                }
+            }
+        },
+        "dto.PaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payer": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PaymentStatusResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "amount_due": {
+                    "type": "number",
+                    "example": 19.98
+                },
+                "amount_paid": {
+                    "type": "number",
+                    "example": 20
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_total": {
+                    "type": "number",
+                    "example": 39.98
+                },
+                "payer": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ProductResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "snack"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Delicious cheeseburger with cheddar and pickles"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://example.com/images/cheeseburger.png"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Cheeseburger"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 12.99
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                }
+            }
+        },
+        "dto.PromotionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "description": "Active defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "buy_quantity": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "category": {
+                    "description": "Category limits the promotion to one product category",
+                    "type": "string",
+                    "example": "dessert"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "TERCA10"
+                },
+                "daily_end": {
+                    "type": "string",
+                    "example": "15:00"
+                },
+                "daily_start": {
+                    "type": "string",
+                    "example": "11:00"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-12-01T00:00:00Z"
+                },
+                "free_quantity": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "max_uses_per_customer": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Sobremesa de terça"
+                },
+                "starts_at": {
+                    "description": "StartsAt and EndsAt are RFC 3339 instants; either may be omitted",
+                    "type": "string",
+                    "example": "2026-11-01T00:00:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed",
+                        "buy_x_get_y"
+                    ],
+                    "example": "percentage"
+                },
+                "value": {
+                    "description": "Value is the percentage (percentage) or the amount (fixed) taken off",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "weekdays": {
+                    "description": "Weekdays, DailyStart and DailyEnd are read in the store's time zone",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "tuesday"
+                    ]
+                }
+            }
+        },
+        "dto.PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "buy_quantity": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "category": {
+                    "type": "string",
+                    "example": "dessert"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "TERCA10"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "daily_end": {
+                    "type": "string",
+                    "example": "15:00"
+                },
+                "daily_start": {
+                    "type": "string",
+                    "example": "11:00"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-12-01T00:00:00Z"
+                },
+                "free_quantity": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_uses_per_customer": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sobremesa de terça"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-11-01T00:00:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "percentage"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 10
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "tuesday"
+                    ]
+                }
+            }
+        },
+        "dto.ReceiptIssuerResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Rua das Flores, 100 - São Paulo/SP"
+                },
+                "cnpj": {
+                    "type": "string",
+                    "example": "12.345.678/0001-90"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fast Food"
+                }
+            }
+        },
+        "dto.ReceiptItemResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "snack"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "X-Burger"
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "product_id": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "total": {
+                    "type": "number",
+                    "example": 40
+                },
+                "unit_price": {
+                    "type": "number",
+                    "example": 20
+                }
+            }
+        },
+        "dto.ReceiptPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 39.6
+                },
+                "method": {
+                    "type": "string",
+                    "example": "pix"
+                },
+                "payer": {
+                    "type": "string",
+                    "example": "Ana"
+                },
+                "transaction_id": {
+                    "type": "string",
+                    "example": "123456789"
+                }
+            }
+        },
+        "dto.ReceiptResponse": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string",
+                    "example": "123.456.789-00"
+                },
+                "discount": {
+                    "type": "number",
+                    "example": 4
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "issued_at": {
+                    "type": "string",
+                    "example": "2026-10-19T12:00:00Z"
+                },
+                "issuer": {
+                    "$ref": "#/definitions/dto.ReceiptIssuerResponse"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReceiptItemResponse"
+                    }
+                },
+                "number": {
+                    "description": "Number is the receipt's number, the order ID",
+                    "type": "integer",
+                    "example": 42
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReceiptPaymentResponse"
+                    }
+                },
+                "rounding": {
+                    "type": "number",
+                    "example": 0
+                },
+                "service_fee": {
+                    "type": "number",
+                    "example": 3.6
+                },
+                "subtotal": {
+                    "type": "number",
+                    "example": 40
+                },
+                "tax_total": {
+                    "type": "number",
+                    "example": 4.84
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReceiptTaxResponse"
+                    }
+                },
+                "total": {
+                    "type": "number",
+                    "example": 39.6
+                }
+            }
+        },
+        "dto.ReceiptTaxResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 4.84
+                },
+                "category": {
+                    "type": "string",
+                    "example": "snack"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 13.45
+                }
+            }
+        },
+        "dto.ReconciliationMismatchResponse": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "example": "status"
+                },
+                "local_amount": {
+                    "type": "number"
+                },
+                "local_status": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "provider_amount": {
+                    "type": "number"
+                },
+                "provider_status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReconciliationReportResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-18"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReconciliationMismatchResponse"
+                    }
+                },
+                "payments_checked": {
+                    "type": "integer"
+                },
+                "provider_payments": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.StationTicketResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-10-19T12:05:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KitchenTicketItemResponse"
+                    }
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "station": {
+                    "type": "string",
+                    "example": "grill"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "preparing",
+                        "done"
+                    ],
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2026-10-19T12:07:00Z"
+                }
+            }
+        },
+        "dto.UpdateCustomerRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "joao.silva@email.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "João"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Silva"
+                }
+            }
+        },
+        "dto.UpdateOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "awaiting_payment",
+                        "received",
+                        "in_progress",
+                        "ready",
+                        "completed",
+                        "cancelled"
+                    ],
+                    "example": "received"
+                }
+            }
+        },
+        "dto.UpdateProductRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "description",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "snack"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Delicious cheeseburger with cheddar and pickles"
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://example.com/images/cheeseburger.png"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Cheeseburger"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 12.99
+                }
+            }
+        },
+        "dto.UpdateStationTicketStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "preparing",
+                        "done"
+                    ],
+                    "example": "preparing"
+                }
+            }
+        },
+        "dto.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:01Z"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "order.ready"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "endpoint answered 503 Service Unavailable"
+                },
+                "last_status_code": {
+                    "type": "integer",
+                    "example": 503
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:05:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "failed"
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.WebhookPaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "order_id",
+                "status",
+                "transaction_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "order.ready",
+                        "order.completed"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_6f1c2e..."
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-01T12:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/webhooks/fast-food"
+                }
+            }
+        },
+        "middleware.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "order_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "order not found"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v1/orders/42"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/problems/order_not_found"
+                }
+            }
+        },
+        "presenters.Meta": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/presenters.Pagination"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                }
+            }
+        },
+        "presenters.Pagination": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "presenters.Response-any": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-array_dto_KitchenStationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KitchenStationResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-array_dto_OrderResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OrderResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-array_dto_PaymentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentResponse"
+                    }
                 },
-                "email": {
-                    "type": "string",
-                    "example": "joao.silva@email.com"
+                "message": {
+                    "type": "string"
                 },
-                "first_name": {
-                    "type": "string",
-                    "example": "João"
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
                 },
-                "last_name": {
-                    "type": "string",
-                    "example": "Silva"
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.CreateOrderRequest": {
+        "presenters.Response-array_dto_ProductResponse": {
             "type": "object",
-            "required": [
-                "items"
-            ],
             "properties": {
-                "cpf": {
-                    "type": "string",
-                    "example": "123.456.789-00"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProductResponse"
+                    }
                 },
-                "customer_id": {
-                    "type": "integer",
-                    "example": 123
+                "message": {
+                    "type": "string"
                 },
-                "items": {
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-array_dto_PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.OrderItemRequest"
+                        "$ref": "#/definitions/dto.PromotionResponse"
                     }
                 },
-                "payment_method": {
-                    "description": "Optional: qr_code, credit_card, debit_card",
-                    "type": "string",
-                    "example": "qr_code"
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.CreatePaymentRequest": {
+        "presenters.Response-array_dto_StationTicketResponse": {
             "type": "object",
-            "required": [
-                "amount",
-                "order_id",
-                "payment_method"
-            ],
             "properties": {
-                "amount": {
-                    "type": "number"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.StationTicketResponse"
+                    }
                 },
-                "order_id": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 },
-                "payment_method": {
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-array_dto_WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WebhookDeliveryResponse"
+                    }
+                },
+                "message": {
                     "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.CreateProductRequest": {
+        "presenters.Response-array_dto_WebhookSubscriptionResponse": {
             "type": "object",
-            "required": [
-                "category",
-                "description",
-                "name",
-                "price"
-            ],
             "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "snack"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WebhookSubscriptionResponse"
+                    }
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Delicious cheeseburger with cheddar and pickles"
+                "message": {
+                    "type": "string"
                 },
-                "image_url": {
-                    "type": "string",
-                    "example": "https://example.com/images/cheeseburger.png"
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Cheeseburger"
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_CustomerResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.CustomerResponse"
                 },
-                "price": {
-                    "type": "number",
-                    "example": 12.99
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.OrderItemRequest": {
+        "presenters.Response-dto_KitchenTicketResponse": {
             "type": "object",
-            "required": [
-                "product_id",
-                "quantity"
-            ],
             "properties": {
-                "product_id": {
-                    "type": "integer",
-                    "example": 200
+                "data": {
+                    "$ref": "#/definitions/dto.KitchenTicketResponse"
                 },
-                "quantity": {
-                    "type": "integer",
-                    "example": 2
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.UpdateCustomerRequest": {
+        "presenters.Response-dto_LoyaltyResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "last_name"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "joao.silva@email.com"
+                "data": {
+                    "$ref": "#/definitions/dto.LoyaltyResponse"
                 },
-                "first_name": {
-                    "type": "string",
-                    "example": "João"
+                "message": {
+                    "type": "string"
                 },
-                "last_name": {
-                    "type": "string",
-                    "example": "Silva"
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.UpdateOrderStatusRequest": {
+        "presenters.Response-dto_OrderResponse": {
             "type": "object",
-            "required": [
-                "status"
-            ],
             "properties": {
-                "status": {
-                    "type": "string",
-                    "enum": [
-                        "awaiting_payment",
-                        "received",
-                        "in_progress",
-                        "ready",
-                        "completed",
-                        "cancelled"
-                    ],
-                    "example": "received"
+                "data": {
+                    "$ref": "#/definitions/dto.OrderResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.UpdateProductRequest": {
+        "presenters.Response-dto_PaymentResponse": {
             "type": "object",
-            "required": [
-                "category",
-                "description",
-                "name",
-                "price"
-            ],
             "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "snack"
+                "data": {
+                    "$ref": "#/definitions/dto.PaymentResponse"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Delicious cheeseburger with cheddar and pickles"
+                "message": {
+                    "type": "string"
                 },
-                "image_url": {
-                    "type": "string",
-                    "example": "https://example.com/images/cheeseburger.png"
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Cheeseburger"
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_PaymentStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.PaymentStatusResponse"
                 },
-                "price": {
-                    "type": "number",
-                    "example": 12.99
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.WebhookPaymentRequest": {
+        "presenters.Response-dto_ProductResponse": {
             "type": "object",
-            "required": [
-                "amount",
-                "order_id",
-                "status",
-                "transaction_id"
-            ],
             "properties": {
-                "amount": {
-                    "type": "number"
+                "data": {
+                    "$ref": "#/definitions/dto.ProductResponse"
                 },
-                "order_id": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 },
-                "payment_method": {
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.PromotionResponse"
+                },
+                "message": {
                     "type": "string"
                 },
-                "status": {
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_ReceiptResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.ReceiptResponse"
+                },
+                "message": {
                     "type": "string"
                 },
-                "timestamp": {
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_ReconciliationReportResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.ReconciliationReportResponse"
+                },
+                "message": {
                     "type": "string"
                 },
-                "transaction_id": {
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_StationTicketResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.StationTicketResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.WebhookDeliveryResponse"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "presenters.Response-dto_WebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.WebhookSubscriptionResponse"
+                },
+                "message": {
                     "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/presenters.Meta"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         }
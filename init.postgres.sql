@@ -0,0 +1,261 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id          BIGSERIAL PRIMARY KEY,
+    first_name  VARCHAR(100),
+    last_name   VARCHAR(100),
+    cpf         VARCHAR(14) UNIQUE,
+    email       VARCHAR(255) UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id          BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NULL REFERENCES customers(id) ON DELETE SET NULL,
+    cpf         VARCHAR(14) NULL,
+    status      VARCHAR(20) NOT NULL CHECK (status IN ('awaiting_payment', 'received', 'in_progress', 'ready', 'completed', 'cancelled')),
+    discount    NUMERIC(10,2) NOT NULL DEFAULT 0,
+    service_fee NUMERIC(10,2) NOT NULL DEFAULT 0,
+    rounding    NUMERIC(10,2) NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- v8: databases created before promotions
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+-- v10: databases created before the price breakdown
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS service_fee NUMERIC(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS rounding NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS products (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT,
+    price       REAL NOT NULL,
+    category    VARCHAR(20) CHECK (category IN ('snack', 'side', 'drink', 'dessert')),
+    image_url   VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id           BIGSERIAL PRIMARY KEY,
+    order_id     BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id   BIGINT NOT NULL REFERENCES products(id),
+    quantity     INTEGER NOT NULL DEFAULT 1,
+    price        REAL NOT NULL,
+    modifiers    TEXT NULL,
+    notes        VARCHAR(140) NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- v12: databases created before kitchen tickets
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS modifiers TEXT NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS notes VARCHAR(140) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS payments (
+    id             BIGSERIAL PRIMARY KEY,
+    order_id       BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    amount         NUMERIC(10,2) NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'pending',
+    payment_method VARCHAR(50) NOT NULL,
+    payer          VARCHAR(100) NOT NULL DEFAULT '',
+    transaction_id VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at     TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (status);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
+CREATE INDEX IF NOT EXISTS idx_payments_transaction_id ON payments (transaction_id);
+-- v5: databases created before payment expiry
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+-- v7: databases created before split payments
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS payer VARCHAR(100) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_payments_expiry ON payments (status, expires_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope           VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64) NOT NULL,
+    status_code     INTEGER NOT NULL DEFAULT 0,
+    content_type    VARCHAR(100) NOT NULL DEFAULT '',
+    body            BYTEA,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id              BIGSERIAL PRIMARY KEY,
+    event_type      VARCHAR(100) NOT NULL,
+    aggregate_type  VARCHAR(50) NOT NULL,
+    aggregate_id    BIGINT NOT NULL,
+    payload         TEXT NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      VARCHAR(1000) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at    TIMESTAMPTZ NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (next_attempt_at) WHERE published_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id          BIGSERIAL PRIMARY KEY,
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(255) NOT NULL,
+    event_types VARCHAR(1000) NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    subscription_id  BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id         BIGINT NOT NULL,
+    event_type       VARCHAR(100) NOT NULL,
+    payload          TEXT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error       VARCHAR(1000) NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    report_date       DATE PRIMARY KEY,
+    payments_checked  INTEGER NOT NULL DEFAULT 0,
+    provider_payments INTEGER NOT NULL DEFAULT 0,
+    generated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_mismatches (
+    id              BIGSERIAL PRIMARY KEY,
+    report_date     DATE NOT NULL REFERENCES reconciliation_reports(report_date) ON DELETE CASCADE,
+    kind            VARCHAR(30) NOT NULL,
+    payment_id      BIGINT NOT NULL DEFAULT 0,
+    order_id        BIGINT NOT NULL DEFAULT 0,
+    transaction_id  VARCHAR(255) NOT NULL DEFAULT '',
+    local_status    VARCHAR(30) NOT NULL DEFAULT '',
+    provider_status VARCHAR(30) NOT NULL DEFAULT '',
+    local_amount    NUMERIC(10,2) NOT NULL DEFAULT 0,
+    provider_amount NUMERIC(10,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_mismatches_date ON reconciliation_mismatches (report_date);
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id                    BIGSERIAL PRIMARY KEY,
+    name                  VARCHAR(100) NOT NULL,
+    code                  VARCHAR(50) NOT NULL DEFAULT '',
+    type                  VARCHAR(20) NOT NULL,
+    value                 NUMERIC(10,2) NOT NULL DEFAULT 0,
+    buy_quantity          INTEGER NOT NULL DEFAULT 0,
+    free_quantity         INTEGER NOT NULL DEFAULT 0,
+    category              VARCHAR(20) NOT NULL DEFAULT '',
+    starts_at             TIMESTAMPTZ,
+    ends_at               TIMESTAMPTZ,
+    weekdays              VARCHAR(20) NOT NULL DEFAULT '',
+    daily_start           VARCHAR(5) NOT NULL DEFAULT '',
+    daily_end             VARCHAR(5) NOT NULL DEFAULT '',
+    max_uses_per_customer INTEGER NOT NULL DEFAULT 0,
+    active                BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_code ON promotions (code);
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id           BIGSERIAL PRIMARY KEY,
+    order_id     BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id BIGINT NOT NULL,
+    code         VARCHAR(50) NOT NULL DEFAULT '',
+    description  VARCHAR(100) NOT NULL,
+    amount       NUMERIC(10,2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_discounts_order ON order_discounts (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_discounts_promotion ON order_discounts (promotion_id);
+
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+    id          BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL,
+    order_id    BIGINT NOT NULL DEFAULT 0,
+    payment_id  BIGINT NOT NULL DEFAULT 0,
+    type        VARCHAR(20) NOT NULL,
+    points      BIGINT NOT NULL,
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_customer ON loyalty_entries (customer_id);
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_order ON loyalty_entries (order_id);
+
+CREATE TABLE IF NOT EXISTS order_taxes (
+    id       BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL,
+    rate     NUMERIC(5,2) NOT NULL,
+    base     NUMERIC(10,2) NOT NULL,
+    amount   NUMERIC(10,2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_taxes_order ON order_taxes (order_id);
+
+CREATE TABLE IF NOT EXISTS receipts (
+    id         BIGSERIAL PRIMARY KEY,
+    order_id   BIGINT NOT NULL,
+    document   TEXT NOT NULL,
+    xml        TEXT NOT NULL,
+    plain_text TEXT NOT NULL,
+    issued_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS kitchen_tickets (
+    id              BIGSERIAL PRIMARY KEY,
+    order_id        BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    copy_number     INTEGER NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'printed' CHECK (status IN ('pending', 'printed', 'failed')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      VARCHAR(1000) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    printed_at      TIMESTAMPTZ,
+    UNIQUE (order_id, copy_number)
+);
+-- v14: databases created before kitchen tickets were printed in the background
+ALTER TABLE kitchen_tickets ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'printed';
+ALTER TABLE kitchen_tickets ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE kitchen_tickets ADD COLUMN IF NOT EXISTS last_error VARCHAR(1000) NOT NULL DEFAULT '';
+ALTER TABLE kitchen_tickets ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE kitchen_tickets ALTER COLUMN printed_at DROP NOT NULL, ALTER COLUMN printed_at DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS idx_kitchen_tickets_due ON kitchen_tickets (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS station_tickets (
+    id         BIGSERIAL PRIMARY KEY,
+    order_id   BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    station    VARCHAR(40) NOT NULL,
+    status     VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'preparing', 'done')),
+    items      TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, station)
+);
+CREATE INDEX IF NOT EXISTS idx_station_tickets_queue ON station_tickets (station, status);
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INTEGER PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version) VALUES (1), (2), (3), (4), (5), (6), (7), (8), (9), (10), (11), (12), (13) ON CONFLICT DO NOTHING;
@@ -1,5 +1,12 @@
 USE soat_fast_food;
 
+-- MySQL has no ADD COLUMN IF NOT EXISTS: databases created by an older
+-- version of this script get the columns added since then through ALTERs
+-- that only run when information_schema does not list the column yet
+--
+-- MySQL parses inline REFERENCES but does not create the foreign key, so
+-- nothing cascades: deleting an order removes its rows in every table itself
+
 CREATE TABLE IF NOT EXISTS customers (
     id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     first_name  VARCHAR(100),
@@ -15,10 +22,35 @@ CREATE TABLE IF NOT EXISTS orders (
     customer_id BIGINT UNSIGNED NULL REFERENCES customers(id),
     cpf         VARCHAR(14) NULL,
     status      ENUM('awaiting_payment', 'received', 'in_progress', 'ready', 'completed', 'cancelled'),
+    discount    DECIMAL(10,2) NOT NULL DEFAULT 0,
+    service_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    rounding    DECIMAL(10,2) NOT NULL DEFAULT 0,
     created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- v8: databases created before promotions
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'orders' AND column_name = 'discount') = 0,
+    'ALTER TABLE orders ADD COLUMN discount DECIMAL(10,2) NOT NULL DEFAULT 0', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- v10: databases created before the price breakdown
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'orders' AND column_name = 'service_fee') = 0,
+    'ALTER TABLE orders ADD COLUMN service_fee DECIMAL(10,2) NOT NULL DEFAULT 0', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'orders' AND column_name = 'rounding') = 0,
+    'ALTER TABLE orders ADD COLUMN rounding DECIMAL(10,2) NOT NULL DEFAULT 0', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
 CREATE TABLE IF NOT EXISTS products (
     id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     name        VARCHAR(100) NOT NULL,
@@ -36,17 +68,268 @@ CREATE TABLE IF NOT EXISTS order_items (
     product_id   BIGINT UNSIGNED NOT NULL REFERENCES products(id),
     quantity     INTEGER NOT NULL DEFAULT 1,
     price        FLOAT NOT NULL,
+    modifiers    TEXT NULL,
+    notes        VARCHAR(140) NOT NULL DEFAULT '',
     created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- v12: databases created before kitchen tickets
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'order_items' AND column_name = 'modifiers') = 0,
+    'ALTER TABLE order_items ADD COLUMN modifiers TEXT NULL', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'order_items' AND column_name = 'notes') = 0,
+    'ALTER TABLE order_items ADD COLUMN notes VARCHAR(140) NOT NULL DEFAULT ''''', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
 CREATE TABLE IF NOT EXISTS payments (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     order_id BIGINT UNSIGNED NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
     amount DECIMAL(10,2) NOT NULL,
     status VARCHAR(20) NOT NULL DEFAULT 'pending',
     payment_method VARCHAR(50) NOT NULL,
+    payer VARCHAR(100) NOT NULL DEFAULT '',
     transaction_id VARCHAR(255) DEFAULT '',
+    expires_at TIMESTAMP NULL DEFAULT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-);
\ No newline at end of file
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_payments_expiry (status, expires_at)
+);
+
+-- v5: databases created before payment expiry
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'payments' AND column_name = 'expires_at') = 0,
+    'ALTER TABLE payments ADD COLUMN expires_at TIMESTAMP NULL DEFAULT NULL', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'payments' AND index_name = 'idx_payments_expiry') = 0,
+    'ALTER TABLE payments ADD INDEX idx_payments_expiry (status, expires_at)', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+-- v7: databases created before split payments
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'payments' AND column_name = 'payer') = 0,
+    'ALTER TABLE payments ADD COLUMN payer VARCHAR(100) NOT NULL DEFAULT ''''', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope           VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64) NOT NULL,
+    status_code     INT NOT NULL DEFAULT 0,
+    content_type    VARCHAR(100) NOT NULL DEFAULT '',
+    body            MEDIUMBLOB,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, idempotency_key),
+    INDEX idx_idempotency_keys_created_at (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id              BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_type      VARCHAR(100) NOT NULL,
+    aggregate_type  VARCHAR(50) NOT NULL,
+    aggregate_id    BIGINT UNSIGNED NOT NULL,
+    payload         TEXT NOT NULL,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      VARCHAR(1000) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at    TIMESTAMP NULL DEFAULT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_outbox_events_pending (published_at, next_attempt_at)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(255) NOT NULL,
+    event_types VARCHAR(1000) NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    subscription_id  BIGINT UNSIGNED NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id         BIGINT UNSIGNED NOT NULL,
+    event_type       VARCHAR(100) NOT NULL,
+    payload          TEXT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts         INT NOT NULL DEFAULT 0,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error       VARCHAR(1000) NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at     TIMESTAMP NULL DEFAULT NULL,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_webhook_deliveries_event (subscription_id, event_id),
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at)
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    report_date       DATE PRIMARY KEY,
+    payments_checked  INT NOT NULL DEFAULT 0,
+    provider_payments INT NOT NULL DEFAULT 0,
+    generated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_mismatches (
+    id              BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    report_date     DATE NOT NULL REFERENCES reconciliation_reports(report_date) ON DELETE CASCADE,
+    kind            VARCHAR(30) NOT NULL,
+    payment_id      BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    order_id        BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    transaction_id  VARCHAR(255) NOT NULL DEFAULT '',
+    local_status    VARCHAR(30) NOT NULL DEFAULT '',
+    provider_status VARCHAR(30) NOT NULL DEFAULT '',
+    local_amount    DECIMAL(10,2) NOT NULL DEFAULT 0,
+    provider_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    INDEX idx_reconciliation_mismatches_date (report_date)
+);
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id                    BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name                  VARCHAR(100) NOT NULL,
+    code                  VARCHAR(50) NOT NULL DEFAULT '',
+    type                  VARCHAR(20) NOT NULL,
+    value                 DECIMAL(10,2) NOT NULL DEFAULT 0,
+    buy_quantity          INTEGER NOT NULL DEFAULT 0,
+    free_quantity         INTEGER NOT NULL DEFAULT 0,
+    category              VARCHAR(20) NOT NULL DEFAULT '',
+    starts_at             TIMESTAMP NULL DEFAULT NULL,
+    ends_at               TIMESTAMP NULL DEFAULT NULL,
+    weekdays              VARCHAR(20) NOT NULL DEFAULT '',
+    daily_start           VARCHAR(5) NOT NULL DEFAULT '',
+    daily_end             VARCHAR(5) NOT NULL DEFAULT '',
+    max_uses_per_customer INTEGER NOT NULL DEFAULT 0,
+    active                BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_promotions_code (code)
+);
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id     BIGINT UNSIGNED NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id BIGINT UNSIGNED NOT NULL,
+    code         VARCHAR(50) NOT NULL DEFAULT '',
+    description  VARCHAR(100) NOT NULL,
+    amount       DECIMAL(10,2) NOT NULL,
+    INDEX idx_order_discounts_order (order_id),
+    INDEX idx_order_discounts_promotion (promotion_id)
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+    id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    customer_id BIGINT UNSIGNED NOT NULL,
+    order_id    BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    payment_id  BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    type        VARCHAR(20) NOT NULL,
+    points      BIGINT NOT NULL,
+    expires_at  TIMESTAMP NULL DEFAULT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_loyalty_entries_customer (customer_id),
+    INDEX idx_loyalty_entries_order (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_taxes (
+    id       BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT UNSIGNED NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL,
+    rate     DECIMAL(5,2) NOT NULL,
+    base     DECIMAL(10,2) NOT NULL,
+    amount   DECIMAL(10,2) NOT NULL,
+    INDEX idx_order_taxes_order (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS receipts (
+    id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id   BIGINT UNSIGNED NOT NULL,
+    document   TEXT NOT NULL,
+    xml        TEXT NOT NULL,
+    plain_text TEXT NOT NULL,
+    issued_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_receipts_order (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS kitchen_tickets (
+    id              BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id        BIGINT UNSIGNED NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    copy_number     INT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'printed',
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      VARCHAR(1000) NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    printed_at      TIMESTAMP NULL DEFAULT NULL,
+    UNIQUE KEY uq_kitchen_tickets_order_copy (order_id, copy_number),
+    INDEX idx_kitchen_tickets_due (status, next_attempt_at)
+);
+
+-- v14: databases created before kitchen tickets were printed in the background
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND column_name = 'status') = 0,
+    'ALTER TABLE kitchen_tickets ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT ''printed''', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND column_name = 'attempts') = 0,
+    'ALTER TABLE kitchen_tickets ADD COLUMN attempts INT NOT NULL DEFAULT 0', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND column_name = 'last_error') = 0,
+    'ALTER TABLE kitchen_tickets ADD COLUMN last_error VARCHAR(1000) NOT NULL DEFAULT ''''', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND column_name = 'next_attempt_at') = 0,
+    'ALTER TABLE kitchen_tickets ADD COLUMN next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND column_name = 'printed_at'
+       AND (is_nullable = 'NO' OR column_default IS NOT NULL)) > 0,
+    'ALTER TABLE kitchen_tickets MODIFY printed_at TIMESTAMP NULL DEFAULT NULL', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+SET @ddl = IF((SELECT COUNT(*) FROM information_schema.statistics
+     WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND index_name = 'idx_kitchen_tickets_due') = 0,
+    'ALTER TABLE kitchen_tickets ADD INDEX idx_kitchen_tickets_due (status, next_attempt_at)', 'SELECT 1');
+PREPARE ddl FROM @ddl;
+EXECUTE ddl;
+DEALLOCATE PREPARE ddl;
+
+CREATE TABLE IF NOT EXISTS station_tickets (
+    id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    order_id   BIGINT UNSIGNED NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    station    VARCHAR(40) NOT NULL,
+    status     VARCHAR(20) NOT NULL DEFAULT 'pending',
+    items      TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_station_tickets_order_station (order_id, station),
+    INDEX idx_station_tickets_queue (station, status)
+);
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INT PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT IGNORE INTO schema_migrations (version) VALUES (1), (2), (3), (4), (5), (6), (7), (8), (9), (10), (11), (12), (13), (14);
@@ -8,13 +8,13 @@ type CreateCustomerRequest struct {
 }
 
 type CustomerResponse struct {
-	ID        uint64 `json:"id" example:"1"`
-	FirstName string `json:"first_name" example:"João"`
-	LastName  string `json:"last_name" example:"Silva"`
-	CPF       string `json:"cpf" example:"123.456.789-00"`
-	Email     string `json:"email" example:"joao.silva@email.com"`
-	CreatedAt string `json:"created_at" example:"2024-06-01T12:00:00Z"`
-	UpdatedAt string `json:"updated_at" example:"2024-06-02T15:30:00Z"`
+	ID        uint64 `json:"id" xml:"id" example:"1"`
+	FirstName string `json:"first_name" xml:"first_name" example:"João"`
+	LastName  string `json:"last_name" xml:"last_name" example:"Silva"`
+	CPF       string `json:"cpf" xml:"cpf" example:"123.456.789-00"`
+	Email     string `json:"email" xml:"email" example:"joao.silva@email.com"`
+	CreatedAt string `json:"created_at" xml:"created_at" example:"2024-06-01T12:00:00Z"`
+	UpdatedAt string `json:"updated_at" xml:"updated_at" example:"2024-06-02T15:30:00Z"`
 }
 
 type UpdateCustomerRequest struct {
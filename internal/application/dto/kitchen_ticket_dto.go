@@ -0,0 +1,23 @@
+package dto
+
+type KitchenTicketResponse struct {
+	ID      uint64 `json:"id" xml:"id" example:"1"`
+	OrderID uint64 `json:"order_id" xml:"order_id" example:"42"`
+	// Copy counts the order's tickets; copies after the first are reprints
+	Copy    int  `json:"copy" xml:"copy" example:"2"`
+	Reprint bool `json:"reprint" xml:"reprint" example:"true"`
+	// Status starts pending: the copy is printed in the background and
+	// retried while the printer fails
+	Status    string                      `json:"status" xml:"status" example:"pending" enums:"pending,printed,failed"`
+	Attempts  int                         `json:"attempts" xml:"attempts" example:"0"`
+	LastError string                      `json:"last_error,omitempty" xml:"last_error,omitempty" example:"dial tcp 192.168.0.50:9100: i/o timeout"`
+	Items     []KitchenTicketItemResponse `json:"items" xml:"items>item"`
+	PrintedAt string                      `json:"printed_at,omitempty" xml:"printed_at,omitempty" example:"2026-10-19T12:05:00Z"`
+}
+
+type KitchenTicketItemResponse struct {
+	Quantity    uint32   `json:"quantity" xml:"quantity" example:"2"`
+	Description string   `json:"description" xml:"description" example:"X-Burger"`
+	Modifiers   []string `json:"modifiers,omitempty" xml:"modifiers>modifier,omitempty" example:"sem cebola"`
+	Notes       string   `json:"notes,omitempty" xml:"notes,omitempty" example:"bem passado"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+type LoyaltyResponse struct {
+	CustomerID uint64 `json:"customer_id" xml:"customer_id" example:"1"`
+	// Balance is the usable points; BalanceValue what they take off an order
+	Balance        int64                      `json:"balance" xml:"balance" example:"320"`
+	BalanceValue   float32                    `json:"balance_value" xml:"balance_value" example:"16"`
+	NextExpiration *LoyaltyExpirationResponse `json:"next_expiration,omitempty" xml:"next_expiration,omitempty"`
+	// Entries is the ledger, oldest first
+	Entries []LoyaltyEntryResponse `json:"entries" xml:"entries>entry"`
+}
+
+// LoyaltyExpirationResponse tells how many points expire next, and when
+type LoyaltyExpirationResponse struct {
+	Points    int64  `json:"points" xml:"points" example:"120"`
+	ExpiresAt string `json:"expires_at" xml:"expires_at" example:"2027-10-19T12:00:00Z"`
+}
+
+type LoyaltyEntryResponse struct {
+	ID        uint64 `json:"id" xml:"id" example:"1"`
+	Type      string `json:"type" xml:"type" example:"earn" enums:"earn,redeem,restore,reverse,expire"`
+	Points    int64  `json:"points" xml:"points" example:"120"`
+	OrderID   uint64 `json:"order_id,omitempty" xml:"order_id,omitempty" example:"1"`
+	PaymentID uint64 `json:"payment_id,omitempty" xml:"payment_id,omitempty" example:"0"`
+	ExpiresAt string `json:"expires_at,omitempty" xml:"expires_at,omitempty" example:"2027-10-19T12:00:00Z"`
+	CreatedAt string `json:"created_at" xml:"created_at" example:"2026-10-19T12:00:00Z"`
+}
@@ -5,30 +5,77 @@ type CreateOrderRequest struct {
 	CPF           string             `json:"cpf" example:"123.456.789-00"`
 	Items         []OrderItemRequest `json:"items" binding:"required,dive"`
 	PaymentMethod string             `json:"payment_method" example:"qr_code"` // Optional: qr_code, credit_card, debit_card
+	// Payments splits the bill; the amounts must add up to the order total.
+	// When given, PaymentMethod is ignored.
+	Payments []PaymentPartRequest `json:"payments" binding:"omitempty,dive"`
+	// CouponCode applies a coupon on top of the automatic promotions
+	CouponCode string `json:"coupon_code" binding:"max=50" example:"TERCA10"`
+	// RedeemPoints spends the customer's loyalty points as a discount; only
+	// as many as fit in the total are used
+	RedeemPoints uint64 `json:"redeem_points" example:"100"`
+}
+
+// PaymentPartRequest is one share of a split bill
+type PaymentPartRequest struct {
+	PaymentMethod string  `json:"payment_method" binding:"required" example:"pix"`
+	Amount        float32 `json:"amount" binding:"required,gt=0" example:"19.99"`
+	Payer         string  `json:"payer" binding:"max=100" example:"Ana"`
 }
 
 type OrderItemRequest struct {
 	ProductID uint64 `json:"product_id" binding:"required" example:"200"`
-	Quantity  uint32 `json:"quantity" binding:"required,gt=0" example:"2"`
+	Quantity  uint32 `json:"quantity" binding:"required,gt=0,max=99" example:"2"`
+	// Modifiers and Notes are printed on the kitchen ticket
+	Modifiers []string `json:"modifiers" binding:"max=10,dive,required,max=50" example:"sem cebola"`
+	Notes     string   `json:"notes" binding:"max=140" example:"bem passado"`
 }
 
 type OrderResponse struct {
-	ID         uint64              `json:"id" example:"1"`
-	CustomerId uint64              `json:"customer_id" example:"123"`
-	CPF        string              `json:"cpf" example:"123.456.789-00"`
-	Status     string              `json:"status" example:"received"`
-	Items      []OrderItemResponse `json:"items"`
-	Total      float32             `json:"total" example:"39.98"`
-	CreatedAt  string              `json:"created_at" example:"2024-06-01T12:00:00Z"`
-	UpdatedAt  string              `json:"updated_at" example:"2024-06-01T12:30:00Z"`
+	ID         uint64              `json:"id" xml:"id" example:"1"`
+	CustomerId uint64              `json:"customer_id" xml:"customer_id" example:"123"`
+	CPF        string              `json:"cpf" xml:"cpf" example:"123.456.789-00"`
+	Status     string              `json:"status" xml:"status" example:"received"`
+	Items      []OrderItemResponse `json:"items" xml:"items>item"`
+	Subtotal   float32             `json:"subtotal" xml:"subtotal" example:"43.98"`
+	// Discounts lists what each promotion took off; Discount is their sum
+	Discounts []OrderDiscountResponse `json:"discounts" xml:"discounts>discount"`
+	Discount  float32                 `json:"discount" xml:"discount" example:"4"`
+	// ServiceFee is charged on the subtotal after discounts; Rounding is what
+	// rounding the total added, negative when it took off
+	ServiceFee float32 `json:"service_fee" xml:"service_fee" example:"3.99"`
+	Rounding   float32 `json:"rounding" xml:"rounding" example:"-0.02"`
+	Total      float32 `json:"total" xml:"total" example:"43.95"`
+	// Taxes are the approximate taxes included in the total, per product
+	// category
+	Taxes     []OrderTaxResponse `json:"taxes" xml:"taxes>tax"`
+	CreatedAt string             `json:"created_at" xml:"created_at" example:"2024-06-01T12:00:00Z"`
+	UpdatedAt string             `json:"updated_at" xml:"updated_at" example:"2024-06-01T12:30:00Z"`
 }
 
 type OrderItemResponse struct {
-	ID        uint64  `json:"id" example:"1"`
-	ProductID uint64  `json:"product_id" example:"200"`
-	Quantity  uint32  `json:"quantity" example:"2"`
-	Price     float32 `json:"price" example:"19.99"`
-	Subtotal  float32 `json:"subtotal" example:"39.98"`
+	ID        uint64   `json:"id" xml:"id" example:"1"`
+	ProductID uint64   `json:"product_id" xml:"product_id" example:"200"`
+	Quantity  uint32   `json:"quantity" xml:"quantity" example:"2"`
+	Price     float32  `json:"price" xml:"price" example:"19.99"`
+	Subtotal  float32  `json:"subtotal" xml:"subtotal" example:"39.98"`
+	Modifiers []string `json:"modifiers,omitempty" xml:"modifiers>modifier,omitempty" example:"sem cebola"`
+	Notes     string   `json:"notes,omitempty" xml:"notes,omitempty" example:"bem passado"`
+}
+
+// OrderDiscountResponse is a discount line of an order
+type OrderDiscountResponse struct {
+	PromotionID uint64  `json:"promotion_id" xml:"promotion_id" example:"1"`
+	Code        string  `json:"code,omitempty" xml:"code,omitempty" example:"TERCA10"`
+	Description string  `json:"description" xml:"description" example:"Sobremesa de terça"`
+	Amount      float32 `json:"amount" xml:"amount" example:"4"`
+}
+
+// OrderTaxResponse is a tax line of an order
+type OrderTaxResponse struct {
+	Category string  `json:"category" xml:"category" example:"snack"`
+	Rate     float32 `json:"rate" xml:"rate" example:"13.45"`
+	Base     float32 `json:"base" xml:"base" example:"39.98"`
+	Amount   float32 `json:"amount" xml:"amount" example:"5.38"`
 }
 
 type UpdateOrderStatusRequest struct {
@@ -1,35 +1,50 @@
 package dto
 
-// CreatePaymentRequest represents the request to create a payment
+// CreatePaymentRequest represents the request to create a payment. Amount
+// may be a share of the order total when the bill is split; Payer optionally
+// names who pays it.
 type CreatePaymentRequest struct {
 	OrderID       uint64  `json:"order_id" binding:"required"`
 	Amount        float32 `json:"amount" binding:"required,gt=0"`
 	PaymentMethod string  `json:"payment_method" binding:"required"`
+	Payer         string  `json:"payer" binding:"max=100" example:"Ana"`
 }
 
-// PaymentResponse represents the payment response
+// PaymentResponse represents the payment response. ExpiresAt is when a
+// pending payment expires and its order is cancelled, in UTC; it is omitted
+// when the payment never expires.
 type PaymentResponse struct {
-	ID            uint64  `json:"id"`
-	OrderID       uint64  `json:"order_id"`
-	Amount        float32 `json:"amount"`
-	Status        string  `json:"status"`
-	PaymentMethod string  `json:"payment_method"`
-	TransactionID string  `json:"transaction_id"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
+	ID            uint64  `json:"id" xml:"id"`
+	OrderID       uint64  `json:"order_id" xml:"order_id"`
+	Amount        float32 `json:"amount" xml:"amount"`
+	Status        string  `json:"status" xml:"status"`
+	PaymentMethod string  `json:"payment_method" xml:"payment_method"`
+	Payer         string  `json:"payer,omitempty" xml:"payer,omitempty"`
+	TransactionID string  `json:"transaction_id" xml:"transaction_id"`
+	ExpiresAt     string  `json:"expires_at,omitempty" xml:"expires_at,omitempty"`
+	CreatedAt     string  `json:"created_at" xml:"created_at"`
+	UpdatedAt     string  `json:"updated_at" xml:"updated_at"`
 }
 
-// PaymentStatusResponse represents the payment status query response
+// PaymentStatusResponse represents the payment status query response: the
+// order's latest payment attempt, plus how much of the order total the
+// approved parts already pay (AmountPaid) and what is left (AmountDue)
 type PaymentStatusResponse struct {
-	ID            uint64  `json:"id"`
-	OrderID       uint64  `json:"order_id"`
-	Status        string  `json:"status"`
-	Amount        float32 `json:"amount"`
-	TransactionID string  `json:"transaction_id"`
-	UpdatedAt     string  `json:"updated_at"`
+	ID            uint64  `json:"id" xml:"id"`
+	OrderID       uint64  `json:"order_id" xml:"order_id"`
+	Status        string  `json:"status" xml:"status"`
+	Amount        float32 `json:"amount" xml:"amount"`
+	Payer         string  `json:"payer,omitempty" xml:"payer,omitempty"`
+	TransactionID string  `json:"transaction_id" xml:"transaction_id"`
+	ExpiresAt     string  `json:"expires_at,omitempty" xml:"expires_at,omitempty"`
+	OrderTotal    float32 `json:"order_total" xml:"order_total" example:"39.98"`
+	AmountPaid    float32 `json:"amount_paid" xml:"amount_paid" example:"20"`
+	AmountDue     float32 `json:"amount_due" xml:"amount_due" example:"19.98"`
+	UpdatedAt     string  `json:"updated_at" xml:"updated_at"`
 }
 
-// WebhookPaymentRequest represents the webhook payload for payment status updates
+// WebhookPaymentRequest represents the webhook payload for payment status
+// updates. Status is approved, rejected, canceled or refunded.
 type WebhookPaymentRequest struct {
 	TransactionID string  `json:"transaction_id" binding:"required"`
 	OrderID       uint64  `json:"order_id" binding:"required"`
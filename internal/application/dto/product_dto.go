@@ -9,14 +9,14 @@ type CreateProductRequest struct {
 }
 
 type ProductResponse struct {
-	ID          uint64  `json:"id" example:"1"`
-	Name        string  `json:"name" example:"Cheeseburger"`
-	Description string  `json:"description" example:"Delicious cheeseburger with cheddar and pickles"`
-	Price       float32 `json:"price" example:"12.99"`
-	Category    string  `json:"category" example:"snack"`
-	ImageUrl    string  `json:"image_url" example:"https://example.com/images/cheeseburger.png"`
-	CreatedAt   string  `json:"created_at" example:"2024-06-01T12:00:00Z"`
-	UpdatedAt   string  `json:"updated_at" example:"2024-06-01T12:00:00Z"`
+	ID          uint64  `json:"id" xml:"id" example:"1"`
+	Name        string  `json:"name" xml:"name" example:"Cheeseburger"`
+	Description string  `json:"description" xml:"description" example:"Delicious cheeseburger with cheddar and pickles"`
+	Price       float32 `json:"price" xml:"price" example:"12.99"`
+	Category    string  `json:"category" xml:"category" example:"snack"`
+	ImageUrl    string  `json:"image_url" xml:"image_url" example:"https://example.com/images/cheeseburger.png"`
+	CreatedAt   string  `json:"created_at" xml:"created_at" example:"2024-06-01T12:00:00Z"`
+	UpdatedAt   string  `json:"updated_at" xml:"updated_at" example:"2024-06-01T12:00:00Z"`
 }
 
 type UpdateProductRequest struct {
@@ -0,0 +1,46 @@
+package dto
+
+// PromotionRequest creates or replaces a promotion. Without a code it
+// applies to every order placed while it is in effect; with one, only to
+// orders that send the coupon code.
+type PromotionRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Sobremesa de terça"`
+	Code string `json:"code" binding:"max=50" example:"TERCA10"`
+	Type string `json:"type" binding:"required" example:"percentage" enums:"percentage,fixed,buy_x_get_y"`
+	// Value is the percentage (percentage) or the amount (fixed) taken off
+	Value        float32 `json:"value" binding:"gte=0" example:"10"`
+	BuyQuantity  uint32  `json:"buy_quantity" example:"0"`
+	FreeQuantity uint32  `json:"free_quantity" example:"0"`
+	// Category limits the promotion to one product category
+	Category string `json:"category" example:"dessert"`
+	// StartsAt and EndsAt are RFC 3339 instants; either may be omitted
+	StartsAt string `json:"starts_at" example:"2026-11-01T00:00:00Z"`
+	EndsAt   string `json:"ends_at" example:"2026-12-01T00:00:00Z"`
+	// Weekdays, DailyStart and DailyEnd are read in the store's time zone
+	Weekdays           []string `json:"weekdays" example:"tuesday"`
+	DailyStart         string   `json:"daily_start" example:"11:00"`
+	DailyEnd           string   `json:"daily_end" example:"15:00"`
+	MaxUsesPerCustomer uint32   `json:"max_uses_per_customer" example:"1"`
+	// Active defaults to true
+	Active *bool `json:"active" example:"true"`
+}
+
+type PromotionResponse struct {
+	ID                 uint64   `json:"id" xml:"id" example:"1"`
+	Name               string   `json:"name" xml:"name" example:"Sobremesa de terça"`
+	Code               string   `json:"code" xml:"code" example:"TERCA10"`
+	Type               string   `json:"type" xml:"type" example:"percentage"`
+	Value              float32  `json:"value" xml:"value" example:"10"`
+	BuyQuantity        uint32   `json:"buy_quantity" xml:"buy_quantity" example:"0"`
+	FreeQuantity       uint32   `json:"free_quantity" xml:"free_quantity" example:"0"`
+	Category           string   `json:"category" xml:"category" example:"dessert"`
+	StartsAt           string   `json:"starts_at,omitempty" xml:"starts_at,omitempty" example:"2026-11-01T00:00:00Z"`
+	EndsAt             string   `json:"ends_at,omitempty" xml:"ends_at,omitempty" example:"2026-12-01T00:00:00Z"`
+	Weekdays           []string `json:"weekdays" xml:"weekdays>weekday" example:"tuesday"`
+	DailyStart         string   `json:"daily_start" xml:"daily_start" example:"11:00"`
+	DailyEnd           string   `json:"daily_end" xml:"daily_end" example:"15:00"`
+	MaxUsesPerCustomer uint32   `json:"max_uses_per_customer" xml:"max_uses_per_customer" example:"1"`
+	Active             bool     `json:"active" xml:"active" example:"true"`
+	CreatedAt          string   `json:"created_at" xml:"created_at" example:"2024-06-01T12:00:00Z"`
+	UpdatedAt          string   `json:"updated_at" xml:"updated_at" example:"2024-06-01T12:00:00Z"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+type ReceiptResponse struct {
+	ID uint64 `json:"id" xml:"id" example:"1"`
+	// Number is the receipt's number, the order ID
+	Number     uint64                   `json:"number" xml:"number" example:"42"`
+	Issuer     ReceiptIssuerResponse    `json:"issuer" xml:"issuer"`
+	CPF        string                   `json:"cpf,omitempty" xml:"cpf,omitempty" example:"123.456.789-00"`
+	Items      []ReceiptItemResponse    `json:"items" xml:"items>item"`
+	Subtotal   float32                  `json:"subtotal" xml:"subtotal" example:"40"`
+	Discount   float32                  `json:"discount" xml:"discount" example:"4"`
+	ServiceFee float32                  `json:"service_fee" xml:"service_fee" example:"3.6"`
+	Rounding   float32                  `json:"rounding" xml:"rounding" example:"0"`
+	Total      float32                  `json:"total" xml:"total" example:"39.6"`
+	Taxes      []ReceiptTaxResponse     `json:"taxes" xml:"taxes>tax"`
+	TaxTotal   float32                  `json:"tax_total" xml:"tax_total" example:"4.84"`
+	Payments   []ReceiptPaymentResponse `json:"payments" xml:"payments>payment"`
+	IssuedAt   string                   `json:"issued_at" xml:"issued_at" example:"2026-10-19T12:00:00Z"`
+	// XML and Text are the renditions stored when the receipt was issued,
+	// served as they are
+	XML  []byte `json:"-" xml:"-"`
+	Text []byte `json:"-" xml:"-"`
+}
+
+type ReceiptIssuerResponse struct {
+	Name    string `json:"name" xml:"name" example:"Fast Food"`
+	CNPJ    string `json:"cnpj,omitempty" xml:"cnpj,omitempty" example:"12.345.678/0001-90"`
+	Address string `json:"address,omitempty" xml:"address,omitempty" example:"Rua das Flores, 100 - São Paulo/SP"`
+}
+
+type ReceiptItemResponse struct {
+	Number      int     `json:"number" xml:"number" example:"1"`
+	ProductID   uint64  `json:"product_id" xml:"product_id" example:"200"`
+	Description string  `json:"description" xml:"description" example:"X-Burger"`
+	Category    string  `json:"category" xml:"category" example:"snack"`
+	Quantity    uint32  `json:"quantity" xml:"quantity" example:"2"`
+	UnitPrice   float32 `json:"unit_price" xml:"unit_price" example:"20"`
+	Total       float32 `json:"total" xml:"total" example:"40"`
+}
+
+type ReceiptTaxResponse struct {
+	Category string  `json:"category" xml:"category" example:"snack"`
+	Rate     float32 `json:"rate" xml:"rate" example:"13.45"`
+	Amount   float32 `json:"amount" xml:"amount" example:"4.84"`
+}
+
+type ReceiptPaymentResponse struct {
+	Method        string  `json:"method" xml:"method" example:"pix"`
+	Payer         string  `json:"payer,omitempty" xml:"payer,omitempty" example:"Ana"`
+	Amount        float32 `json:"amount" xml:"amount" example:"39.6"`
+	TransactionID string  `json:"transaction_id,omitempty" xml:"transaction_id,omitempty" example:"123456789"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// ReconciliationReportResponse represents the reconciliation of one UTC day
+type ReconciliationReportResponse struct {
+	Date             string                           `json:"date" xml:"date" example:"2026-10-18"`
+	PaymentsChecked  int                              `json:"payments_checked" xml:"payments_checked"`
+	ProviderPayments int                              `json:"provider_payments" xml:"provider_payments"`
+	Mismatches       []ReconciliationMismatchResponse `json:"mismatches" xml:"mismatches>mismatch"`
+	GeneratedAt      string                           `json:"generated_at" xml:"generated_at"`
+}
+
+// ReconciliationMismatchResponse represents one disagreement between a
+// payment and the provider. Kind is status, amount, missing_at_provider or
+// missing_locally.
+type ReconciliationMismatchResponse struct {
+	Kind           string  `json:"kind" xml:"kind" example:"status"`
+	PaymentID      uint64  `json:"payment_id,omitempty" xml:"payment_id,omitempty"`
+	OrderID        uint64  `json:"order_id" xml:"order_id"`
+	TransactionID  string  `json:"transaction_id,omitempty" xml:"transaction_id,omitempty"`
+	LocalStatus    string  `json:"local_status,omitempty" xml:"local_status,omitempty"`
+	ProviderStatus string  `json:"provider_status,omitempty" xml:"provider_status,omitempty"`
+	LocalAmount    float32 `json:"local_amount" xml:"local_amount"`
+	ProviderAmount float32 `json:"provider_amount" xml:"provider_amount"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type KitchenStationResponse struct {
+	Name       string   `json:"name" xml:"name" example:"grill"`
+	Categories []string `json:"categories" xml:"categories>category" example:"snack"`
+}
+
+type StationTicketResponse struct {
+	ID        uint64                      `json:"id" xml:"id" example:"1"`
+	OrderID   uint64                      `json:"order_id" xml:"order_id" example:"42"`
+	Station   string                      `json:"station" xml:"station" example:"grill"`
+	Status    string                      `json:"status" xml:"status" example:"pending" enums:"pending,preparing,done"`
+	Items     []KitchenTicketItemResponse `json:"items" xml:"items>item"`
+	CreatedAt string                      `json:"created_at" xml:"created_at" example:"2026-10-19T12:05:00Z"`
+	UpdatedAt string                      `json:"updated_at" xml:"updated_at" example:"2026-10-19T12:07:00Z"`
+}
+
+type UpdateStationTicketStatusRequest struct {
+	Status string `json:"status" binding:"required" example:"preparing" enums:"pending,preparing,done"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+// CreateWebhookSubscriptionRequest registers a partner endpoint. A secret is
+// generated when none is sent.
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required" example:"https://partner.example.com/webhooks/fast-food"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=255" example:"0123456789abcdef0123456789abcdef"`
+	EventTypes []string `json:"event_types" binding:"required,min=1" example:"order.ready,order.completed"`
+}
+
+// WebhookSubscriptionResponse represents a subscription. Secret is only
+// returned when the subscription is created.
+type WebhookSubscriptionResponse struct {
+	ID         uint64   `json:"id" xml:"id" example:"1"`
+	URL        string   `json:"url" xml:"url" example:"https://partner.example.com/webhooks/fast-food"`
+	EventTypes []string `json:"event_types" xml:"event_types>event_type" example:"order.ready,order.completed"`
+	Secret     string   `json:"secret,omitempty" xml:"secret,omitempty" example:"whsec_6f1c2e..."`
+	CreatedAt  string   `json:"created_at" xml:"created_at" example:"2024-06-01T12:00:00Z"`
+	UpdatedAt  string   `json:"updated_at" xml:"updated_at" example:"2024-06-01T12:00:00Z"`
+}
+
+// WebhookDeliveryResponse is one entry of a subscription's delivery log
+type WebhookDeliveryResponse struct {
+	ID             uint64 `json:"id" xml:"id" example:"10"`
+	SubscriptionID uint64 `json:"subscription_id" xml:"subscription_id" example:"1"`
+	EventID        uint64 `json:"event_id" xml:"event_id" example:"42"`
+	EventType      string `json:"event_type" xml:"event_type" example:"order.ready"`
+	Status         string `json:"status" xml:"status" example:"failed" enums:"pending,succeeded,failed"`
+	Attempts       int    `json:"attempts" xml:"attempts" example:"3"`
+	LastStatusCode int    `json:"last_status_code" xml:"last_status_code" example:"503"`
+	LastError      string `json:"last_error" xml:"last_error" example:"endpoint answered 503 Service Unavailable"`
+	NextAttemptAt  string `json:"next_attempt_at" xml:"next_attempt_at" example:"2024-06-01T12:05:00Z"`
+	DeliveredAt    string `json:"delivered_at,omitempty" xml:"delivered_at,omitempty" example:"2024-06-01T12:00:01Z"`
+	CreatedAt      string `json:"created_at" xml:"created_at" example:"2024-06-01T12:00:00Z"`
+}
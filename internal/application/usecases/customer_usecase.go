@@ -1,10 +1,10 @@
 package usecases
 
 import (
-	"errors"
-
+	"context"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
 )
@@ -19,19 +19,22 @@ func NewCustomerUseCase(customerGateway output.CustomerGateway) input.CustomerUs
 	}
 }
 
-func (uc *customerUseCase) CreateCustomer(request *dto.CreateCustomerRequest) (*dto.CustomerResponse, error) {
+func (uc *customerUseCase) CreateCustomer(ctx context.Context, request *dto.CreateCustomerRequest) (*dto.CustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUseCase.CreateCustomer")
+	defer span.End()
+
 	customer := entities.NewCustomer(request.FirstName, request.LastName, request.CPF, request.Email)
 
 	if !customer.IsValid() {
-		return nil, errors.New("invalid customer data")
+		return nil, errs.Validation(errs.CodeInvalidCustomer, "invalid customer data")
 	}
 
-	existingCustomer, _ := uc.customerGateway.GetByCPF(request.CPF)
+	existingCustomer, _ := uc.customerGateway.GetByCPF(ctx, customer.CPF)
 	if existingCustomer != nil {
-		return nil, errors.New("customer with this CPF already exists")
+		return nil, errs.Conflict(errs.CodeCustomerAlreadyExists, "customer with this CPF already exists")
 	}
 
-	err := uc.customerGateway.Create(customer)
+	err := uc.customerGateway.Create(ctx, customer)
 	if err != nil {
 		return nil, err
 	}
@@ -47,14 +50,17 @@ func (uc *customerUseCase) CreateCustomer(request *dto.CreateCustomerRequest) (*
 	}, nil
 }
 
-func (uc *customerUseCase) GetCustomerByCPF(cpf string) (*dto.CustomerResponse, error) {
-	customer, err := uc.customerGateway.GetByCPF(cpf)
+func (uc *customerUseCase) GetCustomerByCPF(ctx context.Context, cpf string) (*dto.CustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUseCase.GetCustomerByCPF")
+	defer span.End()
+
+	customer, err := uc.customerGateway.GetByCPF(ctx, entities.NormalizeCPF(cpf))
 	if err != nil {
 		return nil, err
 	}
 
 	if customer == nil {
-		return nil, errors.New("customer not found")
+		return nil, errs.NotFound(errs.CodeCustomerNotFound, "customer not found")
 	}
 
 	return &dto.CustomerResponse{
@@ -68,14 +74,17 @@ func (uc *customerUseCase) GetCustomerByCPF(cpf string) (*dto.CustomerResponse,
 	}, nil
 }
 
-func (uc *customerUseCase) GetCustomerByID(id uint64) (*dto.CustomerResponse, error) {
-	customer, err := uc.customerGateway.GetByID(id)
+func (uc *customerUseCase) GetCustomerByID(ctx context.Context, id uint64) (*dto.CustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUseCase.GetCustomerByID")
+	defer span.End()
+
+	customer, err := uc.customerGateway.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if customer == nil {
-		return nil, errors.New("customer not found")
+		return nil, errs.NotFound(errs.CodeCustomerNotFound, "customer not found")
 	}
 
 	return &dto.CustomerResponse{
@@ -89,23 +98,26 @@ func (uc *customerUseCase) GetCustomerByID(id uint64) (*dto.CustomerResponse, er
 	}, nil
 }
 
-func (uc *customerUseCase) UpdateCustomer(id uint64, request *dto.UpdateCustomerRequest) (*dto.CustomerResponse, error) {
-	customer, err := uc.customerGateway.GetByID(id)
+func (uc *customerUseCase) UpdateCustomer(ctx context.Context, id uint64, request *dto.UpdateCustomerRequest) (*dto.CustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerUseCase.UpdateCustomer")
+	defer span.End()
+
+	customer, err := uc.customerGateway.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if customer == nil {
-		return nil, errors.New("customer not found")
+		return nil, errs.NotFound(errs.CodeCustomerNotFound, "customer not found")
 	}
 
 	customer.UpdateCustomer(request.FirstName, request.LastName, request.Email)
 
 	if !customer.IsValid() {
-		return nil, errors.New("invalid customer data")
+		return nil, errs.Validation(errs.CodeInvalidCustomer, "invalid customer data")
 	}
 
-	err = uc.customerGateway.Update(customer)
+	err = uc.customerGateway.Update(ctx, customer)
 	if err != nil {
 		return nil, err
 	}
@@ -121,15 +133,18 @@ func (uc *customerUseCase) UpdateCustomer(id uint64, request *dto.UpdateCustomer
 	}, nil
 }
 
-func (uc *customerUseCase) DeleteCustomer(id uint64) error {
-	customer, err := uc.customerGateway.GetByID(id)
+func (uc *customerUseCase) DeleteCustomer(ctx context.Context, id uint64) error {
+	ctx, span := tracer.Start(ctx, "CustomerUseCase.DeleteCustomer")
+	defer span.End()
+
+	customer, err := uc.customerGateway.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if customer == nil {
-		return errors.New("customer not found")
+		return errs.NotFound(errs.CodeCustomerNotFound, "customer not found")
 	}
 
-	return uc.customerGateway.Delete(id)
+	return uc.customerGateway.Delete(ctx, id)
 }
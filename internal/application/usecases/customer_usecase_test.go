@@ -1,6 +1,7 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -9,6 +10,9 @@ import (
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
 )
 
+// ctx is shared by the tests in this package; none of them exercise cancellation
+var ctx = context.Background()
+
 type MockCustomerRepository struct {
 	customers map[string]*entities.Customer
 	nextID    uint64
@@ -21,7 +25,7 @@ func NewMockCustomerRepository() *MockCustomerRepository {
 	}
 }
 
-func (m *MockCustomerRepository) Create(customer *entities.Customer) error {
+func (m *MockCustomerRepository) Create(ctx context.Context, customer *entities.Customer) error {
 	if _, exists := m.customers[customer.CPF]; exists {
 		return errors.New("customer already exists")
 	}
@@ -31,7 +35,7 @@ func (m *MockCustomerRepository) Create(customer *entities.Customer) error {
 	return nil
 }
 
-func (m *MockCustomerRepository) GetByCPF(cpf string) (*entities.Customer, error) {
+func (m *MockCustomerRepository) GetByCPF(ctx context.Context, cpf string) (*entities.Customer, error) {
 	customer, exists := m.customers[cpf]
 	if !exists {
 		return nil, nil
@@ -39,7 +43,7 @@ func (m *MockCustomerRepository) GetByCPF(cpf string) (*entities.Customer, error
 	return customer, nil
 }
 
-func (m *MockCustomerRepository) GetByID(id uint64) (*entities.Customer, error) {
+func (m *MockCustomerRepository) GetByID(ctx context.Context, id uint64) (*entities.Customer, error) {
 	for _, customer := range m.customers {
 		if customer.ID == id {
 			return customer, nil
@@ -48,7 +52,11 @@ func (m *MockCustomerRepository) GetByID(id uint64) (*entities.Customer, error)
 	return nil, nil
 }
 
-func (m *MockCustomerRepository) Update(customer *entities.Customer) error {
+func (m *MockCustomerRepository) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Customer, error) {
+	return m.GetByID(ctx, id)
+}
+
+func (m *MockCustomerRepository) Update(ctx context.Context, customer *entities.Customer) error {
 	if _, exists := m.customers[customer.CPF]; !exists {
 		return errors.New("customer not found")
 	}
@@ -56,7 +64,7 @@ func (m *MockCustomerRepository) Update(customer *entities.Customer) error {
 	return nil
 }
 
-func (m *MockCustomerRepository) Delete(id uint64) error {
+func (m *MockCustomerRepository) Delete(ctx context.Context, id uint64) error {
 	for cpf, customer := range m.customers {
 		if customer.ID == id {
 			delete(m.customers, cpf)
@@ -79,7 +87,7 @@ func TestCustomerUseCase_CreateCustomer(t *testing.T) {
 	}
 
 	// Act
-	response, err := useCase.CreateCustomer(request)
+	response, err := useCase.CreateCustomer(ctx, request)
 
 	// Assert
 	if err != nil {
@@ -114,7 +122,7 @@ func TestCustomerUseCase_CreateCustomer_DuplicateCPF(t *testing.T) {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	mockRepo.Create(customer1)
+	mockRepo.Create(ctx, customer1)
 
 	// Segundo cliente com mesmo CPF
 	request := &dto.CreateCustomerRequest{
@@ -125,7 +133,7 @@ func TestCustomerUseCase_CreateCustomer_DuplicateCPF(t *testing.T) {
 	}
 
 	// Act
-	response, err := useCase.CreateCustomer(request)
+	response, err := useCase.CreateCustomer(ctx, request)
 
 	// Assert
 	if err == nil {
@@ -156,10 +164,10 @@ func TestCustomerUseCase_GetCustomerByCPF(t *testing.T) {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	mockRepo.Create(customer)
+	mockRepo.Create(ctx, customer)
 
 	// Act
-	response, err := useCase.GetCustomerByCPF("123.456.789-00")
+	response, err := useCase.GetCustomerByCPF(ctx, "123.456.789-00")
 
 	// Assert
 	if err != nil {
@@ -181,7 +189,7 @@ func TestCustomerUseCase_GetCustomerByCPF_NotFound(t *testing.T) {
 	useCase := NewCustomerUseCase(mockRepo)
 
 	// Act
-	response, err := useCase.GetCustomerByCPF("999.999.999-99")
+	response, err := useCase.GetCustomerByCPF(ctx, "999.999.999-99")
 
 	// Assert
 	if err == nil {
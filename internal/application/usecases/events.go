@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// orderEventPayload is the payload of order.* events. It leaves the CPF out:
+// events reach the outbox publisher and partner webhooks, and consumers that
+// need it read the order.
+type orderEventPayload struct {
+	OrderID        uint64  `json:"order_id"`
+	CustomerID     uint64  `json:"customer_id,omitempty"`
+	Status         string  `json:"status"`
+	PreviousStatus string  `json:"previous_status,omitempty"`
+	Total          float32 `json:"total"`
+}
+
+// paymentEventPayload is the payload of payment.* events
+type paymentEventPayload struct {
+	PaymentID     uint64  `json:"payment_id"`
+	OrderID       uint64  `json:"order_id"`
+	Amount        float32 `json:"amount"`
+	Status        string  `json:"status"`
+	PaymentMethod string  `json:"payment_method"`
+	Payer         string  `json:"payer,omitempty"`
+	TransactionID string  `json:"transaction_id,omitempty"`
+}
+
+// paymentEventTypes maps the statuses a payment can settle in to the event
+// they publish
+var paymentEventTypes = map[entities.PaymentStatus]string{
+	entities.PaymentStatusApproved: entities.EventPaymentApproved,
+	entities.PaymentStatusRejected: entities.EventPaymentRejected,
+	entities.PaymentStatusCanceled: entities.EventPaymentCanceled,
+	entities.PaymentStatusExpired:  entities.EventPaymentExpired,
+	entities.PaymentStatusRefunded: entities.EventPaymentRefunded,
+}
+
+// appendOrderEvent writes an order event to the outbox. previous is empty
+// for order.created. Call it with the ctx of the transaction that changed
+// the order.
+func appendOrderEvent(ctx context.Context, outbox output.OutboxGateway, eventType string, order *entities.Order, previous entities.OrderStatus) error {
+	event, err := entities.NewOutboxEvent(eventType, entities.AggregateOrder, order.ID, orderEventPayload{
+		OrderID:        order.ID,
+		CustomerID:     order.CustomerId,
+		Status:         string(order.Status),
+		PreviousStatus: string(previous),
+		Total:          order.CalculateTotal(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := outbox.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// appendPaymentEvent writes the event matching the payment's status to the
+// outbox; statuses without an event are ignored
+func appendPaymentEvent(ctx context.Context, outbox output.OutboxGateway, payment *entities.Payment) error {
+	eventType, ok := paymentEventTypes[payment.Status]
+	if !ok {
+		return nil
+	}
+
+	event, err := entities.NewOutboxEvent(eventType, entities.AggregatePayment, payment.ID, paymentEventPayload{
+		PaymentID:     payment.ID,
+		OrderID:       payment.OrderID,
+		Amount:        payment.Amount,
+		Status:        string(payment.Status),
+		PaymentMethod: payment.PaymentMethod,
+		Payer:         payment.Payer,
+		TransactionID: payment.TransactionID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := outbox.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append %s event: %w", eventType, err)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// kitchenTicketLease is how long a claimed copy is hidden from the other
+// replicas polling for due copies, far longer than a print can take
+const kitchenTicketLease = time.Minute
+
+type kitchenTicketUseCase struct {
+	orderGateway         output.OrderGateway
+	orderItemGateway     output.OrderItemGateway
+	productGateway       output.ProductGateway
+	kitchenTicketGateway output.KitchenTicketGateway
+	renderer             output.KitchenTicketRenderer
+	printer              output.Printer
+	retry                entities.KitchenPrintRetry
+	logger               *slog.Logger
+}
+
+// NewKitchenTicketUseCase creates the use case; a nil printer means the
+// store has none, so nothing is printed and reprints are refused. Failed
+// prints are retried as retry says.
+func NewKitchenTicketUseCase(
+	orderGateway output.OrderGateway,
+	orderItemGateway output.OrderItemGateway,
+	productGateway output.ProductGateway,
+	kitchenTicketGateway output.KitchenTicketGateway,
+	renderer output.KitchenTicketRenderer,
+	printer output.Printer,
+	retry entities.KitchenPrintRetry,
+	logger *slog.Logger,
+) input.KitchenTicketUseCase {
+	return &kitchenTicketUseCase{
+		orderGateway:         orderGateway,
+		orderItemGateway:     orderItemGateway,
+		productGateway:       productGateway,
+		kitchenTicketGateway: kitchenTicketGateway,
+		renderer:             renderer,
+		printer:              printer,
+		retry:                retry,
+		logger:               logger,
+	}
+}
+
+func (uc *kitchenTicketUseCase) ReprintTicket(ctx context.Context, orderID uint64) (*dto.KitchenTicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "KitchenTicketUseCase.ReprintTicket")
+	defer span.End()
+
+	if uc.printer == nil {
+		return nil, errs.Conflict(errs.CodeKitchenPrinterDisabled, "no kitchen printer is configured")
+	}
+
+	order, err := uc.orderGateway.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errs.NotFound(errs.CodeOrderNotFound, "order not found")
+	}
+	if !order.IsPaid() {
+		return nil, errs.Conflict(errs.CodeOrderNotPaid, fmt.Sprintf("order is %s; kitchen tickets are printed once it is paid", order.Status))
+	}
+
+	copies, err := uc.kitchenTicketGateway.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if len(copies) > 0 {
+		next = copies[len(copies)-1].Copy + 1
+	}
+
+	// Loaded only for the response; the printer worker rebuilds them
+	if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+		return nil, err
+	}
+	products, err := loadOrderProducts(ctx, uc.productGateway, order)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := entities.NewKitchenTicket(order, products, next)
+	created, err := uc.kitchenTicketGateway.Create(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errs.Conflict(errs.CodeKitchenTicketInProgress, "another copy of the ticket was requested at the same time; try again")
+	}
+
+	uc.logger.InfoContext(ctx, "kitchen ticket reprint queued", "order_id", order.ID, "copy", next)
+	return buildKitchenTicketResponse(ticket), nil
+}
+
+func (uc *kitchenTicketUseCase) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "KitchenTicketUseCase.Publish")
+	defer span.End()
+
+	if uc.printer == nil || event.EventType != entities.EventOrderStatusChanged {
+		return nil
+	}
+	var payload orderEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+	}
+	if entities.OrderStatus(payload.Status) != entities.OrderReceived {
+		return nil
+	}
+
+	// The relay may deliver the event again; a reprint also means the
+	// first copy is queued
+	copies, err := uc.kitchenTicketGateway.ListByOrderID(ctx, payload.OrderID)
+	if err != nil || len(copies) > 0 {
+		return err
+	}
+	// Orders the kitchen already finished or dropped need no ticket
+	order, err := uc.orderGateway.GetByID(ctx, payload.OrderID)
+	if err != nil || order == nil {
+		return err
+	}
+	if order.Status != entities.OrderReceived && order.Status != entities.OrderInProgress {
+		return nil
+	}
+
+	// Only queued here: printing is left to PrintDueTickets so a slow or
+	// broken printer never holds up or fails the relay
+	if _, err := uc.kitchenTicketGateway.Create(ctx, entities.NewKitchenTicket(order, nil, 1)); err != nil {
+		return err
+	}
+
+	uc.logger.InfoContext(ctx, "kitchen ticket queued", "order_id", order.ID)
+	return nil
+}
+
+func (uc *kitchenTicketUseCase) PrintDueTickets(ctx context.Context, now time.Time, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "KitchenTicketUseCase.PrintDueTickets")
+	defer span.End()
+
+	if uc.printer == nil {
+		return 0, nil
+	}
+
+	due, err := uc.kitchenTicketGateway.ListDue(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, ticket := range due {
+		// Every replica polls, so each copy is claimed before it is printed
+		claimed, err := uc.kitchenTicketGateway.Claim(ctx, ticket, now, time.Now().Add(kitchenTicketLease))
+		if err != nil {
+			return i, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := uc.print(ctx, ticket); err != nil {
+			return i, err
+		}
+	}
+
+	return len(due), nil
+}
+
+// print renders a queued copy from its order, sends it to the printer and
+// saves the outcome. Printer failures are recorded on the ticket, which is
+// retried later or given up on; only storage errors are returned, leaving
+// the ticket due.
+func (uc *kitchenTicketUseCase) print(ctx context.Context, ticket *entities.KitchenTicket) error {
+	order, err := uc.orderGateway.GetByID(ctx, ticket.OrderID)
+	if err != nil || order == nil {
+		// A deleted order takes its tickets with it
+		return err
+	}
+
+	switch {
+	case !order.IsPaid():
+		ticket.MarkFailed(fmt.Errorf("order is %s", order.Status), nil)
+	default:
+		if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+			return err
+		}
+		products, err := loadOrderProducts(ctx, uc.productGateway, order)
+		if err != nil {
+			return err
+		}
+		ticket.Fill(order, products)
+		at := time.Now()
+		ticket.PrintedAt = &at
+
+		document, err := uc.renderer.Render(ticket)
+		if err != nil {
+			// Rendering the same order again fails the same way
+			ticket.MarkFailed(fmt.Errorf("failed to render kitchen ticket: %w", err), nil)
+			break
+		}
+		if err := uc.printer.Print(ctx, document); err != nil {
+			ticket.MarkFailed(err, uc.retry.Next(ticket.Attempts+1, time.Now()))
+			break
+		}
+		ticket.MarkPrinted(at)
+	}
+
+	// Saved even when ctx is cancelled mid-print, so a ticket that came out
+	// is not printed again
+	if err := uc.kitchenTicketGateway.Update(context.WithoutCancel(ctx), ticket); err != nil {
+		return err
+	}
+
+	switch ticket.Status {
+	case entities.KitchenTicketPrinted:
+		uc.logger.InfoContext(ctx, "kitchen ticket printed", "order_id", order.ID, "copy", ticket.Copy, "attempts", ticket.Attempts)
+	case entities.KitchenTicketFailed:
+		uc.logger.ErrorContext(ctx, "kitchen ticket given up", "order_id", order.ID, "copy", ticket.Copy, "attempts", ticket.Attempts, "error", ticket.LastError)
+	default:
+		uc.logger.WarnContext(ctx, "kitchen ticket not printed", "order_id", order.ID, "copy", ticket.Copy, "attempts", ticket.Attempts, "error", ticket.LastError)
+	}
+	return nil
+}
+
+func buildKitchenTicketResponse(ticket *entities.KitchenTicket) *dto.KitchenTicketResponse {
+	response := &dto.KitchenTicketResponse{
+		ID:        ticket.ID,
+		OrderID:   ticket.OrderID,
+		Copy:      ticket.Copy,
+		Reprint:   ticket.IsReprint(),
+		Status:    string(ticket.Status),
+		Attempts:  ticket.Attempts,
+		LastError: ticket.LastError,
+		Items:     []dto.KitchenTicketItemResponse{},
+	}
+	if ticket.PrintedAt != nil {
+		response.PrintedAt = ticket.PrintedAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	for _, item := range ticket.Items {
+		response.Items = append(response.Items, dto.KitchenTicketItemResponse{
+			Quantity:    item.Quantity,
+			Description: item.Description,
+			Modifiers:   item.Modifiers,
+			Notes:       item.Notes,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,299 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/printing"
+)
+
+// recordingPrinter keeps every job it gets, failing them while err is set
+type recordingPrinter struct {
+	jobs [][]byte
+	err  error
+}
+
+func (p *recordingPrinter) Print(_ context.Context, document []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.jobs = append(p.jobs, document)
+	return nil
+}
+
+// testPrintRetry retries a failed print once, a second later
+var testPrintRetry = entities.KitchenPrintRetry{MaxAttempts: 2, InitialBackoff: time.Second, MaxBackoff: time.Minute}
+
+func (f *orderTestFixture) kitchenTicketUseCase(printer output.Printer) input.KitchenTicketUseCase {
+	return NewKitchenTicketUseCase(f.orderGateway, f.orderItemGateway, f.productGateway, f.ticketGateway,
+		printing.NewESCPOSRenderer(48, time.UTC), printer, testPrintRetry, logging.Discard())
+}
+
+// printDue runs the printer worker once at now
+func printDue(t *testing.T, kitchenTicketUseCase input.KitchenTicketUseCase, now time.Time) {
+	t.Helper()
+	if _, err := kitchenTicketUseCase.PrintDueTickets(ctx, now, 100); err != nil {
+		t.Fatalf("Failed to print due tickets: %v", err)
+	}
+}
+
+// createKitchenOrder creates an order of a burger without onions and a soda
+func (f *orderTestFixture) createKitchenOrder(t *testing.T) *dto.OrderResponse {
+	t.Helper()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	soda := f.seedProduct(t, "Refrigerante", 6)
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{
+		{ProductID: burger.ID, Quantity: 2, Modifiers: []string{"sem cebola", "bacon extra"}, Notes: "bem passado"},
+		{ProductID: soda.ID, Quantity: 1},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	return order
+}
+
+func TestKitchenTicketUseCase_PrintsOnceWhenOrderIsReceived(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	printer := &recordingPrinter{}
+	kitchenTicketUseCase := f.kitchenTicketUseCase(printer)
+	order := f.createKitchenOrder(t)
+	f.publishPending(t, kitchenTicketUseCase)
+	f.payOrder(t, order.ID)
+
+	// Act
+	f.publishPending(t, kitchenTicketUseCase)
+	f.publishPending(t, kitchenTicketUseCase)
+	printDue(t, kitchenTicketUseCase, time.Now())
+	printDue(t, kitchenTicketUseCase, time.Now())
+
+	// Assert
+	if len(printer.jobs) != 1 {
+		t.Fatalf("Expected a single ticket once the order was received, got %d", len(printer.jobs))
+	}
+	job := printer.jobs[0]
+	for _, expected := range []string{"2x X-BURGER", "  + sem cebola", "  + bacon extra", "  OBS: bem passado", "1x REFRIGERANTE", "3 ITENS"} {
+		if !bytes.Contains(job, []byte(expected+"\n")) {
+			t.Errorf("Expected the ticket to contain %q, got\n%s", expected, job)
+		}
+	}
+	if bytes.Contains(job, []byte("REIMPRESSAO")) {
+		t.Errorf("Expected the first copy not to be marked as a reprint, got\n%s", job)
+	}
+	tickets, _ := f.ticketGateway.ListByOrderID(ctx, order.ID)
+	if len(tickets) != 1 || tickets[0].Copy != 1 || tickets[0].Status != entities.KitchenTicketPrinted || tickets[0].PrintedAt == nil {
+		t.Errorf("Expected copy 1 to be recorded as printed, got %+v", tickets)
+	}
+}
+
+// rendezvousDueTickets holds each of the first two due lists until the
+// other one was read or a short wait passes, so two replicas both see the
+// same copies due
+type rendezvousDueTickets struct {
+	output.KitchenTicketGateway
+	lists atomic.Int32
+	both  chan struct{}
+}
+
+func (g *rendezvousDueTickets) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KitchenTicket, error) {
+	due, err := g.KitchenTicketGateway.ListDue(ctx, now, limit)
+	if n := g.lists.Add(1); n == 2 {
+		close(g.both)
+	} else if n == 1 {
+		select {
+		case <-g.both:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return due, err
+}
+
+func TestKitchenTicketUseCase_EachCopyIsPrintedByOneReplica(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	order := f.createKitchenOrder(t)
+	f.payOrder(t, order.ID)
+	f.publishPending(t, f.kitchenTicketUseCase(&recordingPrinter{}))
+	tickets := &rendezvousDueTickets{KitchenTicketGateway: f.ticketGateway, both: make(chan struct{})}
+	printers := []*recordingPrinter{{}, {}}
+
+	// Act
+	var wg sync.WaitGroup
+	for _, printer := range printers {
+		replica := NewKitchenTicketUseCase(f.orderGateway, f.orderItemGateway, f.productGateway, tickets,
+			printing.NewESCPOSRenderer(48, time.UTC), printer, testPrintRetry, logging.Discard())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			printDue(t, replica, time.Now())
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if printed := len(printers[0].jobs) + len(printers[1].jobs); printed != 1 {
+		t.Errorf("Expected the copy to be printed once across replicas, got %d", printed)
+	}
+}
+
+func TestKitchenTicketUseCase_SkipsOrdersAlreadyPastTheKitchen(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	printer := &recordingPrinter{}
+	kitchenTicketUseCase := f.kitchenTicketUseCase(printer)
+	order := f.createKitchenOrder(t)
+	f.payOrder(t, order.ID)
+	f.advanceOrder(t, order.ID, entities.OrderInProgress, entities.OrderReady)
+
+	// Act
+	f.publishPending(t, kitchenTicketUseCase)
+	printDue(t, kitchenTicketUseCase, time.Now())
+
+	// Assert
+	if len(printer.jobs) != 0 {
+		t.Errorf("Expected no ticket for an order that is already ready, got %d", len(printer.jobs))
+	}
+}
+
+// receivedEvent returns the order.status_changed event of an order turning
+// received
+func (f *orderTestFixture) receivedEvent(t *testing.T) *entities.OutboxEvent {
+	t.Helper()
+	events, _ := f.outboxGateway.ListPending(ctx, time.Now().Add(time.Second), 100)
+	for _, event := range events {
+		if bytes.Contains(event.Payload, []byte(`"received"`)) {
+			return event
+		}
+	}
+	t.Fatal("Expected an event of the order turning received")
+	return nil
+}
+
+func TestKitchenTicketUseCase_PrintFailureIsRetried(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	printer := &recordingPrinter{err: errors.New("connection refused")}
+	kitchenTicketUseCase := f.kitchenTicketUseCase(printer)
+	order := f.createKitchenOrder(t)
+	f.payOrder(t, order.ID)
+
+	// Act
+	err := kitchenTicketUseCase.Publish(ctx, f.receivedEvent(t))
+	now := time.Now()
+	printDue(t, kitchenTicketUseCase, now)
+	failed, _ := f.ticketGateway.ListByOrderID(ctx, order.ID)
+	printer.err = nil
+	printDue(t, kitchenTicketUseCase, now)
+	jobsBeforeDue := len(printer.jobs)
+	printDue(t, kitchenTicketUseCase, now.Add(time.Minute))
+	printed, _ := f.ticketGateway.ListByOrderID(ctx, order.ID)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected the relay not to see printer failures, got %v", err)
+	}
+	if len(failed) != 1 || failed[0].Status != entities.KitchenTicketPending || failed[0].Attempts != 1 || failed[0].LastError != "connection refused" {
+		t.Errorf("Expected the failure to be recorded on a pending ticket, got %+v", failed)
+	}
+	if jobsBeforeDue != 0 {
+		t.Errorf("Expected no retry before the backoff, got %d jobs", jobsBeforeDue)
+	}
+	if len(printer.jobs) != 1 || len(printed) != 1 || printed[0].Status != entities.KitchenTicketPrinted || printed[0].Attempts != 2 || printed[0].LastError != "" {
+		t.Errorf("Expected the retry to print the ticket once, got %d jobs and %+v", len(printer.jobs), printed)
+	}
+}
+
+func TestKitchenTicketUseCase_PrintGivesUpAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	printer := &recordingPrinter{err: errors.New("paper out")}
+	kitchenTicketUseCase := f.kitchenTicketUseCase(printer)
+	order := f.createKitchenOrder(t)
+	f.payOrder(t, order.ID)
+	f.publishPending(t, kitchenTicketUseCase)
+	now := time.Now()
+
+	// Act
+	printDue(t, kitchenTicketUseCase, now)
+	printDue(t, kitchenTicketUseCase, now.Add(time.Minute))
+	printer.err = nil
+	printDue(t, kitchenTicketUseCase, now.Add(time.Hour))
+
+	// Assert
+	tickets, _ := f.ticketGateway.ListByOrderID(ctx, order.ID)
+	if len(tickets) != 1 || tickets[0].Status != entities.KitchenTicketFailed || tickets[0].Attempts != testPrintRetry.MaxAttempts || tickets[0].PrintedAt != nil {
+		t.Errorf("Expected the ticket to be given up after %d attempts, got %+v", testPrintRetry.MaxAttempts, tickets)
+	}
+	if len(printer.jobs) != 0 {
+		t.Errorf("Expected a failed ticket not to be printed again, got %d jobs", len(printer.jobs))
+	}
+}
+
+func TestKitchenTicketUseCase_ReprintTicket(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	printer := &recordingPrinter{}
+	kitchenTicketUseCase := f.kitchenTicketUseCase(printer)
+	order := f.createKitchenOrder(t)
+	f.payOrder(t, order.ID)
+	f.publishPending(t, kitchenTicketUseCase)
+	printDue(t, kitchenTicketUseCase, time.Now())
+
+	// Act
+	ticket, err := kitchenTicketUseCase.ReprintTicket(ctx, order.ID)
+	printDue(t, kitchenTicketUseCase, time.Now())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ticket.Copy != 2 || !ticket.Reprint || ticket.OrderID != order.ID || ticket.Status != string(entities.KitchenTicketPending) {
+		t.Errorf("Expected copy 2 of order %d queued as a reprint, got %+v", order.ID, ticket)
+	}
+	if len(ticket.Items) != 2 || ticket.Items[0].Description != "X-Burger" || len(ticket.Items[0].Modifiers) != 2 || ticket.Items[0].Notes != "bem passado" {
+		t.Errorf("Expected the order's items with their modifiers and notes, got %+v", ticket.Items)
+	}
+	if len(printer.jobs) != 2 || !bytes.Contains(printer.jobs[1], []byte("*** REIMPRESSAO - VIA 2 ***")) {
+		t.Errorf("Expected the reprint to be printed with its banner, got %d jobs", len(printer.jobs))
+	}
+}
+
+func TestKitchenTicketUseCase_ReprintTicket_Errors(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	unpaid := f.createKitchenOrder(t)
+
+	tests := []struct {
+		name    string
+		printer output.Printer
+		orderID uint64
+		code    string
+	}{
+		{"no printer", nil, unpaid.ID, errs.CodeKitchenPrinterDisabled},
+		{"order not found", &recordingPrinter{}, 999, errs.CodeOrderNotFound},
+		{"order not paid", &recordingPrinter{}, unpaid.ID, errs.CodeOrderNotPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := f.kitchenTicketUseCase(tt.printer).ReprintTicket(ctx, tt.orderID)
+
+			// Assert
+			if errs.CodeOf(err) != tt.code {
+				t.Errorf("Expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type loyaltyUseCase struct {
+	orderGateway    output.OrderGateway
+	customerGateway output.CustomerGateway
+	loyaltyGateway  output.LoyaltyGateway
+	paymentGateway  output.PaymentGateway
+	transactions    output.TransactionManager
+	program         entities.LoyaltyProgram
+	logger          *slog.Logger
+}
+
+func NewLoyaltyUseCase(
+	orderGateway output.OrderGateway,
+	customerGateway output.CustomerGateway,
+	loyaltyGateway output.LoyaltyGateway,
+	paymentGateway output.PaymentGateway,
+	transactions output.TransactionManager,
+	program entities.LoyaltyProgram,
+	logger *slog.Logger,
+) input.LoyaltyUseCase {
+	return &loyaltyUseCase{
+		orderGateway:    orderGateway,
+		customerGateway: customerGateway,
+		loyaltyGateway:  loyaltyGateway,
+		paymentGateway:  paymentGateway,
+		transactions:    transactions,
+		program:         program,
+		logger:          logger,
+	}
+}
+
+func (uc *loyaltyUseCase) GetCustomerLoyalty(ctx context.Context, customerID uint64) (*dto.LoyaltyResponse, error) {
+	ctx, span := tracer.Start(ctx, "LoyaltyUseCase.GetCustomerLoyalty")
+	defer span.End()
+
+	customer, err := uc.customerGateway.GetByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, errs.NotFound(errs.CodeCustomerNotFound, "customer not found")
+	}
+
+	// A read never writes to the ledger: points that expired are left out of
+	// the balance and written off by the next entry of the account
+	now := time.Now()
+	entries, account, err := replayLoyaltyAccount(ctx, uc.loyaltyGateway, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := account.Balance(now)
+	response := &dto.LoyaltyResponse{
+		CustomerID:   customerID,
+		Balance:      balance,
+		BalanceValue: uc.program.ValueOf(balance),
+		Entries:      []dto.LoyaltyEntryResponse{},
+	}
+	if lot := account.NextExpiry(now); lot != nil {
+		response.NextExpiration = &dto.LoyaltyExpirationResponse{
+			Points:    lot.Points,
+			ExpiresAt: lot.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+	for _, entry := range entries {
+		line := dto.LoyaltyEntryResponse{
+			ID:        entry.ID,
+			Type:      string(entry.Type),
+			Points:    entry.Points,
+			OrderID:   entry.OrderID,
+			PaymentID: entry.PaymentID,
+			CreatedAt: entry.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if entry.ExpiresAt != nil {
+			line.ExpiresAt = entry.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		response.Entries = append(response.Entries, line)
+	}
+
+	return response, nil
+}
+
+func (uc *loyaltyUseCase) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "LoyaltyUseCase.Publish")
+	defer span.End()
+
+	switch event.EventType {
+	case entities.EventOrderStatusChanged:
+		var payload orderEventPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+		}
+		switch entities.OrderStatus(payload.Status) {
+		case entities.OrderCompleted:
+			return uc.earn(ctx, payload)
+		case entities.OrderCancelled:
+			return uc.restore(ctx, payload.OrderID)
+		}
+	case entities.EventPaymentRefunded:
+		var payload paymentEventPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+		}
+		return uc.reverse(ctx, payload)
+	}
+	return nil
+}
+
+// earn credits the points of a completed order to its customer. The relay
+// may deliver the event again, so an order earns at most once.
+func (uc *loyaltyUseCase) earn(ctx context.Context, order orderEventPayload) error {
+	// Events carry no CPF, so an order placed with one is read for it
+	stored, err := uc.orderGateway.GetByID(ctx, order.OrderID)
+	if err != nil || stored == nil {
+		return err
+	}
+	customerID, err := loyaltyCustomerID(ctx, uc.customerGateway, stored.CustomerId, stored.CPF)
+	if err != nil || customerID == 0 {
+		return err
+	}
+
+	// Parts refunded before completion never earn
+	payments, err := uc.paymentGateway.ListByOrderID(ctx, order.OrderID)
+	if err != nil {
+		return err
+	}
+	paid := order.Total
+	for _, payment := range payments {
+		if payment.IsRefunded() {
+			paid -= payment.Amount
+		}
+	}
+	points := uc.program.PointsFor(paid)
+	if points <= 0 {
+		return nil
+	}
+
+	return uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, _, err := lockLoyaltyAccount(ctx, uc.customerGateway, uc.loyaltyGateway, customerID, time.Now()); err != nil {
+			return err
+		}
+		entries, err := uc.loyaltyGateway.ListByOrderID(ctx, order.OrderID)
+		if err != nil {
+			return err
+		}
+		if findLoyaltyEntry(entries, entities.LoyaltyEarn, 0) != nil {
+			return nil
+		}
+
+		entry := entities.NewLoyaltyEntry(customerID, order.OrderID, entities.LoyaltyEarn, points)
+		uc.program.Credit(entry, entry.CreatedAt)
+		if err := uc.loyaltyGateway.Create(ctx, entry); err != nil {
+			return err
+		}
+
+		uc.logger.InfoContext(ctx, "loyalty points earned", "customer_id", customerID, "order_id", order.OrderID, "points", points)
+		return nil
+	})
+}
+
+// restore gives back the points redeemed on a cancelled order
+func (uc *loyaltyUseCase) restore(ctx context.Context, orderID uint64) error {
+	// Read before the transaction only to learn whose account to lock: a
+	// redeem entry never changes
+	entries, err := uc.loyaltyGateway.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	redeemed := findLoyaltyEntry(entries, entities.LoyaltyRedeem, 0)
+	if redeemed == nil {
+		return nil
+	}
+
+	return uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Locked before the first read of the transaction, which on MySQL
+		// fixes what every later read sees, so a second delivery of the
+		// event finds the first one's restore
+		if _, _, err := lockLoyaltyAccount(ctx, uc.customerGateway, uc.loyaltyGateway, redeemed.CustomerID, time.Now()); err != nil {
+			return err
+		}
+		entries, err := uc.loyaltyGateway.ListByOrderID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if findLoyaltyEntry(entries, entities.LoyaltyRestore, 0) != nil {
+			return nil
+		}
+
+		entry := entities.NewLoyaltyEntry(redeemed.CustomerID, orderID, entities.LoyaltyRestore, -redeemed.Points)
+		uc.program.Credit(entry, entry.CreatedAt)
+		if err := uc.loyaltyGateway.Create(ctx, entry); err != nil {
+			return err
+		}
+
+		uc.logger.InfoContext(ctx, "loyalty points restored", "customer_id", entry.CustomerID, "order_id", orderID, "points", entry.Points)
+		return nil
+	})
+}
+
+// reverse takes back the points a refunded payment earned, once per
+// payment. Points the customer already spent are not clawed back below a
+// zero balance.
+func (uc *loyaltyUseCase) reverse(ctx context.Context, payment paymentEventPayload) error {
+	// Read before the transaction only to learn whose account to lock: an
+	// earn entry never changes
+	entries, err := uc.loyaltyGateway.ListByOrderID(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+	earned := findLoyaltyEntry(entries, entities.LoyaltyEarn, 0)
+	if earned == nil {
+		return nil
+	}
+
+	return uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Locked before the first read of the transaction, so refunds
+		// reversed at the same time, or the same refund delivered twice, see
+		// each other
+		now := time.Now()
+		_, account, err := lockLoyaltyAccount(ctx, uc.customerGateway, uc.loyaltyGateway, earned.CustomerID, now)
+		if err != nil {
+			return err
+		}
+		entries, err := uc.loyaltyGateway.ListByOrderID(ctx, payment.OrderID)
+		if err != nil {
+			return err
+		}
+		if findLoyaltyEntry(entries, entities.LoyaltyReverse, payment.PaymentID) != nil {
+			return nil
+		}
+
+		// What earlier refunds of the order have not taken back yet
+		left := earned.Points
+		for _, entry := range entries {
+			if entry.Type == entities.LoyaltyReverse {
+				left += entry.Points
+			}
+		}
+
+		points := min(uc.program.PointsFor(payment.Amount), left, account.Balance(now))
+		if points <= 0 {
+			return nil
+		}
+
+		entry := entities.NewLoyaltyEntry(earned.CustomerID, payment.OrderID, entities.LoyaltyReverse, -points)
+		entry.PaymentID = payment.PaymentID
+		if err := uc.loyaltyGateway.Create(ctx, entry); err != nil {
+			return err
+		}
+
+		uc.logger.InfoContext(ctx, "loyalty points reversed", "customer_id", entry.CustomerID, "order_id", payment.OrderID, "payment_id", payment.PaymentID, "points", points)
+		return nil
+	})
+}
+
+// loyaltyCustomerID returns the registered customer an order belongs to,
+// by ID or else by CPF, or zero for anonymous orders
+func loyaltyCustomerID(ctx context.Context, customerGateway output.CustomerGateway, customerID uint64, cpf string) (uint64, error) {
+	if customerID != 0 {
+		return customerID, nil
+	}
+	if cpf == "" {
+		return 0, nil
+	}
+
+	customer, err := customerGateway.GetByCPF(ctx, entities.NormalizeCPF(cpf))
+	if err != nil || customer == nil {
+		return 0, err
+	}
+	return customer.ID, nil
+}
+
+// replayLoyaltyAccount replays a customer's ledger without writing to it.
+// Points that expired and were not written off yet are left out of the
+// balance all the same.
+func replayLoyaltyAccount(ctx context.Context, loyaltyGateway output.LoyaltyGateway, customerID uint64) ([]*entities.LoyaltyEntry, *entities.LoyaltyAccount, error) {
+	entries, err := loyaltyGateway.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, entities.NewLoyaltyAccount(entries), nil
+}
+
+// lockLoyaltyAccount locks the customer until the transaction in ctx ends
+// and replays their ledger. Points that expired by now are first written off
+// with an expire entry, so the entries written under the lock never spend
+// them. Every write to the ledger takes it, which also makes the write-off
+// happen once. Callers take it before any other read in the transaction: on
+// MySQL at REPEATABLE READ the first plain read fixes the snapshot, and a
+// ledger read under the lock would otherwise miss what was written while
+// waiting for it.
+func lockLoyaltyAccount(ctx context.Context, customerGateway output.CustomerGateway, loyaltyGateway output.LoyaltyGateway, customerID uint64, now time.Time) ([]*entities.LoyaltyEntry, *entities.LoyaltyAccount, error) {
+	if _, err := customerGateway.GetByIDForUpdate(ctx, customerID); err != nil {
+		return nil, nil, err
+	}
+	entries, account, err := replayLoyaltyAccount(ctx, loyaltyGateway, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if due := account.Due(now); due > 0 {
+		expired := entities.NewLoyaltyEntry(customerID, 0, entities.LoyaltyExpire, -due)
+		if err := loyaltyGateway.Create(ctx, expired); err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, expired)
+		account = entities.NewLoyaltyAccount(entries)
+	}
+
+	return entries, account, nil
+}
+
+// findLoyaltyEntry returns the first entry of entryType, for paymentID when
+// it is not zero
+func findLoyaltyEntry(entries []*entities.LoyaltyEntry, entryType entities.LoyaltyEntryType, paymentID uint64) *entities.LoyaltyEntry {
+	for _, entry := range entries {
+		if entry.Type == entryType && (paymentID == 0 || entry.PaymentID == paymentID) {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+// testLoyaltyProgram earns a point per real, redeems them at R$ 0,05 and
+// keeps them for 30 days
+var testLoyaltyProgram = entities.LoyaltyProgram{PointsPerReal: 1, PointValue: 0.05, PointsTTL: 30 * 24 * time.Hour}
+
+func (f *orderTestFixture) loyaltyUseCase() input.LoyaltyUseCase {
+	return NewLoyaltyUseCase(f.orderGateway, f.customerGateway, f.loyaltyGateway, f.paymentGateway, memory.NewTransactionManager(), testLoyaltyProgram, logging.Discard())
+}
+
+func (f *orderTestFixture) seedCustomer(t *testing.T, cpf string) *entities.Customer {
+	t.Helper()
+	customer := entities.NewCustomer("Ana", "Silva", cpf, "ana@example.com")
+	if err := f.customerGateway.Create(ctx, customer); err != nil {
+		t.Fatalf("Failed to seed customer: %v", err)
+	}
+	return customer
+}
+
+func (f *orderTestFixture) seedLoyaltyEntry(t *testing.T, entry *entities.LoyaltyEntry) {
+	t.Helper()
+	if err := f.loyaltyGateway.Create(ctx, entry); err != nil {
+		t.Fatalf("Failed to seed loyalty entry: %v", err)
+	}
+}
+
+// publishPending hands every outbox event to publisher, like the relay
+// would; events already published are handed over again
+func (f *orderTestFixture) publishPending(t *testing.T, publisher output.EventPublisher) {
+	t.Helper()
+	events, err := f.outboxGateway.ListPending(ctx, time.Now().Add(time.Second), 100)
+	if err != nil {
+		t.Fatalf("Failed to list outbox events: %v", err)
+	}
+	for _, event := range events {
+		if err := publisher.Publish(ctx, event); err != nil {
+			t.Fatalf("Failed to publish %s: %v", event.EventType, err)
+		}
+	}
+}
+
+func refundedEvent(t *testing.T, paymentID, orderID uint64, amount float32) *entities.OutboxEvent {
+	t.Helper()
+	event, err := entities.NewOutboxEvent(entities.EventPaymentRefunded, entities.AggregatePayment, paymentID, paymentEventPayload{
+		PaymentID: paymentID,
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    string(entities.PaymentStatusRefunded),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// statementLog records, per transaction, the locks and plain reads the
+// loyalty code makes. On MySQL at REPEATABLE READ the first plain read of a
+// transaction fixes the snapshot every later plain read sees, so a read
+// before a lock hides what another transaction committed while this one
+// waited for it.
+type statementLog struct {
+	mu           sync.Mutex
+	transactions [][]string
+	open         bool
+}
+
+func (l *statementLog) add(statement string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open {
+		last := len(l.transactions) - 1
+		l.transactions[last] = append(l.transactions[last], statement)
+	}
+}
+
+// readBeforeLock returns the first transaction with a plain read before one
+// of its locks, or nil
+func (l *statementLog) readBeforeLock() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, statements := range l.transactions {
+		read := false
+		for _, statement := range statements {
+			if strings.HasPrefix(statement, "lock") && read {
+				return statements
+			}
+			read = read || strings.HasPrefix(statement, "read")
+		}
+	}
+	return nil
+}
+
+type loggedTransactions struct {
+	output.TransactionManager
+	log *statementLog
+}
+
+func (m loggedTransactions) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.log.mu.Lock()
+	m.log.transactions = append(m.log.transactions, nil)
+	m.log.open = true
+	m.log.mu.Unlock()
+	defer func() {
+		m.log.mu.Lock()
+		m.log.open = false
+		m.log.mu.Unlock()
+	}()
+	return m.TransactionManager.WithinTransaction(ctx, fn)
+}
+
+type loggedCustomers struct {
+	output.CustomerGateway
+	log *statementLog
+}
+
+func (g loggedCustomers) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Customer, error) {
+	g.log.add("lock customer")
+	return g.CustomerGateway.GetByIDForUpdate(ctx, id)
+}
+
+type loggedLedger struct {
+	output.LoyaltyGateway
+	log *statementLog
+}
+
+func (g loggedLedger) ListByCustomerID(ctx context.Context, customerID uint64) ([]*entities.LoyaltyEntry, error) {
+	g.log.add("read customer ledger")
+	return g.LoyaltyGateway.ListByCustomerID(ctx, customerID)
+}
+
+func (g loggedLedger) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.LoyaltyEntry, error) {
+	g.log.add("read order ledger")
+	return g.LoyaltyGateway.ListByOrderID(ctx, orderID)
+}
+
+func TestLoyaltyUseCase_CompletedOrderEarnsPointsOnce(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	loyalty := f.loyaltyUseCase()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	burger := f.seedProduct(t, "X-Burger", 20.5)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{CPF: customer.CPF, Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 2}}})
+	f.advanceOrder(t, order.ID, entities.OrderReceived, entities.OrderInProgress, entities.OrderReady, entities.OrderCompleted)
+
+	// Act
+	f.publishPending(t, loyalty)
+	f.publishPending(t, loyalty)
+
+	// Assert
+	account, err := loyalty.GetCustomerLoyalty(ctx, customer.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if account.Balance != 41 || account.BalanceValue != 2.05 {
+		t.Errorf("Expected 41 points worth 2.05, got %d worth %v", account.Balance, account.BalanceValue)
+	}
+	if len(account.Entries) != 1 || account.Entries[0].Type != "earn" || account.Entries[0].OrderID != order.ID || account.Entries[0].ExpiresAt == "" {
+		t.Errorf("Expected one expiring earn entry for the order, got %+v", account.Entries)
+	}
+	if account.NextExpiration == nil || account.NextExpiration.Points != 41 {
+		t.Errorf("Expected the 41 points to expire next, got %+v", account.NextExpiration)
+	}
+}
+
+func TestLoyaltyUseCase_AnonymousOrdersEarnNothing(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	loyalty := f.loyaltyUseCase()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{CPF: "999.999.999-99", Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	f.advanceOrder(t, order.ID, entities.OrderReceived, entities.OrderInProgress, entities.OrderReady, entities.OrderCompleted)
+
+	// Act
+	f.publishPending(t, loyalty)
+
+	// Assert
+	if entries, _ := f.loyaltyGateway.ListByOrderID(ctx, order.ID); len(entries) != 0 {
+		t.Errorf("Expected no ledger entry for a CPF without a customer, got %+v", entries)
+	}
+}
+
+func TestLoyaltyUseCase_RefundReversesEarnedPoints(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	loyalty := f.loyaltyUseCase()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{CustomerId: customer.ID, Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 2}}})
+	f.advanceOrder(t, order.ID, entities.OrderReceived, entities.OrderInProgress, entities.OrderReady, entities.OrderCompleted)
+	f.publishPending(t, loyalty)
+
+	// Act
+	for range 2 {
+		if err := loyalty.Publish(ctx, refundedEvent(t, 9, order.ID, 15)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := loyalty.Publish(ctx, refundedEvent(t, 10, order.ID, 40)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Assert
+	account, _ := loyalty.GetCustomerLoyalty(ctx, customer.ID)
+	if account.Balance != 0 {
+		t.Errorf("Expected every earned point reversed, got %d", account.Balance)
+	}
+	if len(account.Entries) != 3 || account.Entries[1].Points != -15 || account.Entries[1].PaymentID != 9 || account.Entries[2].Points != -25 {
+		t.Errorf("Expected one reversal per payment, capped at what was earned, got %+v", account.Entries)
+	}
+}
+
+func TestLoyaltyUseCase_ExpiredPointsAreWrittenOff(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	loyalty := f.loyaltyUseCase()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	expired, later := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	old := entities.NewLoyaltyEntry(customer.ID, 1, entities.LoyaltyEarn, 100)
+	old.ExpiresAt = &expired
+	recent := entities.NewLoyaltyEntry(customer.ID, 2, entities.LoyaltyEarn, 30)
+	recent.ExpiresAt = &later
+	f.seedLoyaltyEntry(t, old)
+	f.seedLoyaltyEntry(t, recent)
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 3, entities.LoyaltyRedeem, -20))
+	cancelled, err := entities.NewOutboxEvent(entities.EventOrderStatusChanged, entities.AggregateOrder, 3, orderEventPayload{
+		OrderID: 3,
+		Status:  string(entities.OrderCancelled),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	account, err := loyalty.GetCustomerLoyalty(ctx, customer.ID)
+	again, _ := loyalty.GetCustomerLoyalty(ctx, customer.ID)
+	for range 2 {
+		if err := loyalty.Publish(ctx, cancelled); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	restored, _ := loyalty.GetCustomerLoyalty(ctx, customer.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if account.Balance != 30 || again.Balance != 30 {
+		t.Errorf("Expected the redemption to spend the older points and the rest to expire, got %d then %d", account.Balance, again.Balance)
+	}
+	if len(again.Entries) != 3 {
+		t.Errorf("Expected reading the account to leave the ledger alone, got %+v", again.Entries)
+	}
+	if account.NextExpiration == nil || account.NextExpiration.Points != 30 {
+		t.Errorf("Expected the 30 recent points to expire next, got %+v", account.NextExpiration)
+	}
+	// The restore is the next entry: the expired points are written off
+	// before it, once
+	if restored.Balance != 50 || len(restored.Entries) != 5 || restored.Entries[3].Type != "expire" || restored.Entries[3].Points != -80 || restored.Entries[4].Type != "restore" {
+		t.Errorf("Expected one expire entry of 80 points before the restore, got %d and %+v", restored.Balance, restored.Entries)
+	}
+}
+
+func TestLoyaltyUseCase_LocksTheCustomerBeforeReadingTheLedger(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	log := &statementLog{}
+	loyalty := NewLoyaltyUseCase(f.orderGateway, loggedCustomers{f.customerGateway, log}, loggedLedger{f.loyaltyGateway, log}, f.paymentGateway,
+		loggedTransactions{memory.NewTransactionManager(), log}, testLoyaltyProgram, logging.Discard())
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 1, entities.LoyaltyEarn, 40))
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 2, entities.LoyaltyRedeem, -20))
+	cancelled, err := entities.NewOutboxEvent(entities.EventOrderStatusChanged, entities.AggregateOrder, 2, orderEventPayload{
+		OrderID: 2,
+		Status:  string(entities.OrderCancelled),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	for _, event := range []*entities.OutboxEvent{cancelled, refundedEvent(t, 9, 1, 10)} {
+		if err := loyalty.Publish(ctx, event); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// Assert
+	if len(log.transactions) != 2 {
+		t.Fatalf("Expected a restore and a reversal, got %v", log.transactions)
+	}
+	if statements := log.readBeforeLock(); statements != nil {
+		t.Errorf("Expected the customer to be locked before the ledger is read, got %v", statements)
+	}
+}
+
+func TestLoyaltyUseCase_GetCustomerLoyalty_NotFound(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+
+	// Act
+	_, err := f.loyaltyUseCase().GetCustomerLoyalty(ctx, 99)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeCustomerNotFound {
+		t.Errorf("Expected code %s, got %v", errs.CodeCustomerNotFound, err)
+	}
+}
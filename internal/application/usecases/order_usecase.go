@@ -1,12 +1,18 @@
 package usecases
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/services"
 )
 
 type orderUseCase struct {
@@ -14,6 +20,21 @@ type orderUseCase struct {
 	orderItemGateway output.OrderItemGateway
 	productGateway   output.ProductGateway
 	paymentGateway   output.PaymentGateway
+	outboxGateway    output.OutboxGateway
+	promotionGateway output.PromotionGateway
+	discountGateway  output.OrderDiscountGateway
+	taxGateway       output.OrderTaxGateway
+	customerGateway  output.CustomerGateway
+	loyaltyGateway   output.LoyaltyGateway
+	transactions     output.TransactionManager
+	paymentTTL       time.Duration
+	// location is the store's time zone, in which promotion weekdays and
+	// times of day are read
+	location *time.Location
+	loyalty  entities.LoyaltyProgram
+	pricing  *services.PricingService
+	logger   *slog.Logger
+	metrics  output.Metrics
 }
 
 func NewOrderUseCase(
@@ -21,237 +42,607 @@ func NewOrderUseCase(
 	orderItemGateway output.OrderItemGateway,
 	productGateway output.ProductGateway,
 	paymentGateway output.PaymentGateway,
+	outboxGateway output.OutboxGateway,
+	promotionGateway output.PromotionGateway,
+	discountGateway output.OrderDiscountGateway,
+	taxGateway output.OrderTaxGateway,
+	customerGateway output.CustomerGateway,
+	loyaltyGateway output.LoyaltyGateway,
+	transactions output.TransactionManager,
+	paymentTTL time.Duration,
+	location *time.Location,
+	loyalty entities.LoyaltyProgram,
+	pricing *services.PricingService,
+	logger *slog.Logger,
+	metrics output.Metrics,
 ) input.OrderUseCase {
 	return &orderUseCase{
 		orderGateway:     orderGateway,
 		orderItemGateway: orderItemGateway,
 		productGateway:   productGateway,
 		paymentGateway:   paymentGateway,
+		outboxGateway:    outboxGateway,
+		promotionGateway: promotionGateway,
+		discountGateway:  discountGateway,
+		taxGateway:       taxGateway,
+		customerGateway:  customerGateway,
+		loyaltyGateway:   loyaltyGateway,
+		transactions:     transactions,
+		paymentTTL:       paymentTTL,
+		location:         location,
+		loyalty:          loyalty,
+		pricing:          pricing,
+		logger:           logger,
+		metrics:          metrics,
 	}
 }
 
-func (uc *orderUseCase) CreateOrder(request *dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+func (uc *orderUseCase) CreateOrder(ctx context.Context, request *dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.CreateOrder")
+	defer span.End()
+
 	order := entities.NewOrder(request.CustomerId, request.CPF)
 
-	var totalPrice float32
+	// categories scopes category promotions to the order's products
+	categories := make(map[uint64]string)
 	for _, itemReq := range request.Items {
-		product, err := uc.productGateway.GetByID(itemReq.ProductID)
-		if err != nil || product == nil {
-			return nil, errors.New("product not found")
+		product, err := uc.productGateway.GetByID(ctx, itemReq.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, errs.NotFound(errs.CodeProductNotFound, "product not found")
 		}
 
 		orderItem := entities.NewOrderItem(order.ID, itemReq.ProductID, itemReq.Quantity, product.Price)
+		orderItem.Modifiers = itemReq.Modifiers
+		orderItem.Notes = itemReq.Notes
 
 		if !orderItem.IsValid() {
-			return nil, errors.New("invalid order item data")
+			return nil, errs.Validation(errs.CodeInvalidOrderItem, "invalid order item data")
 		}
 
 		order.AddItem(*orderItem)
-		totalPrice += orderItem.CalculateSubtotal()
+		categories[product.ID] = string(product.Category)
 	}
 
 	if !order.IsValid() {
-		return nil, errors.New("invalid order data")
+		return nil, errs.Validation(errs.CodeInvalidOrder, "invalid order data")
 	}
 
-	// Create order first
-	err := uc.orderGateway.Create(order)
+	limited, err := uc.applyPromotions(ctx, order, categories, request.CouponCode)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create order items
-	for _, item := range order.Items {
-		item.OrderID = order.ID
-		err := uc.orderItemGateway.Create(&item)
-		if err != nil {
+	var redemption *entities.LoyaltyEntry
+	if request.RedeemPoints > 0 {
+		if redemption, err = uc.redeemPoints(ctx, order, int64(request.RedeemPoints)); err != nil {
 			return nil, err
 		}
 	}
-
-	// Automatically create payment for the order
-	paymentMethod := request.PaymentMethod
-	if paymentMethod == "" {
-		paymentMethod = "qr_code" // Default payment method
+	// The breakdown is stored with the order, so later rule changes leave
+	// its total alone
+	order.ApplyBreakdown(uc.pricing.Price(order, categories))
+	totalPrice := order.CalculateTotal()
+
+	// A split bill is checked before anything is stored
+	parts := request.Payments
+	if len(parts) > 0 {
+		amounts := make([]float32, len(parts))
+		for i, part := range parts {
+			amounts[i] = part.Amount
+		}
+		if !entities.SumsTo(totalPrice, amounts...) {
+			return nil, errs.Validation(errs.CodeInvalidPaymentSplit, fmt.Sprintf("payment parts must add up to the order total of %.2f", totalPrice))
+		}
+	} else if totalPrice > 0 {
+		// Automatically create one payment for the whole order
+		paymentMethod := request.PaymentMethod
+		if paymentMethod == "" {
+			paymentMethod = "qr_code" // Default payment method
+		}
+		parts = []dto.PaymentPartRequest{{PaymentMethod: paymentMethod, Amount: totalPrice}}
 	}
 
-	payment := entities.NewPayment(order.ID, totalPrice, paymentMethod)
-	err = uc.paymentGateway.Create(payment)
+	// The order, its items, its discounts and taxes, the points it redeems,
+	// its payments and the order.created event are stored together
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Every lock is taken before the first plain read: on MySQL at
+		// REPEATABLE READ that read fixes the snapshot the rechecks see, which
+		// would then miss uses and redemptions committed while waiting. The
+		// redemption recheck locks the customer before reading the ledger
+		promotions, err := uc.lockPromotions(ctx, limited)
+		if err != nil {
+			return err
+		}
+		if redemption != nil {
+			if err := uc.recheckRedemption(ctx, redemption); err != nil {
+				return err
+			}
+		}
+		if err := uc.recheckPromotionLimits(ctx, order, promotions); err != nil {
+			return err
+		}
+		if err := uc.orderGateway.Create(ctx, order); err != nil {
+			return err
+		}
+
+		for _, item := range order.Items {
+			item.OrderID = order.ID
+			if err := uc.orderItemGateway.Create(ctx, &item); err != nil {
+				return err
+			}
+		}
+
+		for i := range order.Discounts {
+			order.Discounts[i].OrderID = order.ID
+			if err := uc.discountGateway.Create(ctx, &order.Discounts[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := range order.Taxes {
+			order.Taxes[i].OrderID = order.ID
+			if err := uc.taxGateway.Create(ctx, &order.Taxes[i]); err != nil {
+				return err
+			}
+		}
+
+		if redemption != nil {
+			redemption.OrderID = order.ID
+			if err := uc.loyaltyGateway.Create(ctx, redemption); err != nil {
+				return err
+			}
+		}
+
+		// An order without its payments would wait for one forever, holding
+		// the points it redeemed, so a failed payment fails the checkout
+		for _, part := range parts {
+			payment := entities.NewPayment(order.ID, part.Amount, part.PaymentMethod)
+			payment.Payer = part.Payer
+			payment.ExpireAfter(uc.paymentTTL)
+			if err := uc.paymentGateway.Create(ctx, payment); err != nil {
+				return fmt.Errorf("failed to create payment: %w", err)
+			}
+		}
+
+		if err := appendOrderEvent(ctx, uc.outboxGateway, entities.EventOrderCreated, order, ""); err != nil {
+			return err
+		}
+
+		// Nothing is left to pay once promotions and points cover the whole
+		// order, and no payment can be approved for 0, so it is received now
+		if totalPrice > 0 {
+			return nil
+		}
+		previous := order.Status
+		order.UpdateStatus(entities.OrderReceived)
+		if err := uc.orderGateway.Update(ctx, order); err != nil {
+			return err
+		}
+		return appendOrderEvent(ctx, uc.outboxGateway, entities.EventOrderStatusChanged, order, previous)
+	})
 	if err != nil {
-		// Log error but don't fail the order creation
-		// In production, you might want to implement a retry mechanism or queue
-		// For now, we'll continue without failing the order
+		return nil, err
 	}
 
+	uc.logger.InfoContext(ctx, "order created", "order_id", order.ID, "items", len(order.Items), "total", totalPrice)
+	uc.metrics.OrderCreated(order.Status)
+
 	return uc.buildOrderResponse(order), nil
 }
 
-func (uc *orderUseCase) GetOrderByID(id uint64) (*dto.OrderResponse, error) {
-	order, err := uc.orderGateway.GetByID(id)
+func (uc *orderUseCase) GetOrderByID(ctx context.Context, id uint64) (*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.GetOrderByID")
+	defer span.End()
+
+	order, err := uc.orderGateway.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if order == nil {
-		return nil, errors.New("order not found")
+		return nil, errs.NotFound(errs.CodeOrderNotFound, "order not found")
 	}
 
-	items, err := uc.orderItemGateway.GetByOrderID(id)
-	if err != nil {
+	if err := uc.loadOrderDetails(ctx, order); err != nil {
 		return nil, err
 	}
 
-	var orderItems []entities.OrderItem
-	for _, item := range items {
-		orderItems = append(orderItems, *item)
-	}
-	order.Items = orderItems
-
 	return uc.buildOrderResponse(order), nil
 }
 
-func (uc *orderUseCase) GetOrdersByCPF(cpf string) ([]*dto.OrderResponse, error) {
-	orders, err := uc.orderGateway.GetByCPF(cpf)
+func (uc *orderUseCase) GetOrdersByCPF(ctx context.Context, cpf string) ([]*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.GetOrdersByCPF")
+	defer span.End()
+
+	orders, err := uc.orderGateway.GetByCPF(ctx, entities.NormalizeCPF(cpf))
 	if err != nil {
 		return nil, err
 	}
 
 	var response []*dto.OrderResponse
 	for _, order := range orders {
-		items, err := uc.orderItemGateway.GetByOrderID(order.ID)
-		if err != nil {
+		if err := uc.loadOrderDetails(ctx, order); err != nil {
 			continue
 		}
 
-		var orderItems []entities.OrderItem
-		for _, item := range items {
-			orderItems = append(orderItems, *item)
-		}
-		order.Items = orderItems
-
 		response = append(response, uc.buildOrderResponse(order))
 	}
 
 	return response, nil
 }
 
-func (uc *orderUseCase) GetOrdersByCustomerID(customerID uint64) ([]*dto.OrderResponse, error) {
-	orders, err := uc.orderGateway.GetByCustomerID(customerID)
+func (uc *orderUseCase) GetOrdersByCustomerID(ctx context.Context, customerID uint64) ([]*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.GetOrdersByCustomerID")
+	defer span.End()
+
+	orders, err := uc.orderGateway.GetByCustomerID(ctx, customerID)
 	if err != nil {
 		return nil, err
 	}
 
 	var response []*dto.OrderResponse
 	for _, order := range orders {
-		items, err := uc.orderItemGateway.GetByOrderID(order.ID)
-		if err != nil {
+		if err := uc.loadOrderDetails(ctx, order); err != nil {
 			continue
 		}
 
-		var orderItems []entities.OrderItem
-		for _, item := range items {
-			orderItems = append(orderItems, *item)
-		}
-		order.Items = orderItems
-
 		response = append(response, uc.buildOrderResponse(order))
 	}
 
 	return response, nil
 }
 
-func (uc *orderUseCase) GetAllOrders() ([]*dto.OrderResponse, error) {
-	orders, err := uc.orderGateway.GetAll()
+func (uc *orderUseCase) GetAllOrders(ctx context.Context) ([]*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.GetAllOrders")
+	defer span.End()
+
+	orders, err := uc.orderGateway.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var response []*dto.OrderResponse
 	for _, order := range orders {
-		items, err := uc.orderItemGateway.GetByOrderID(order.ID)
-		if err != nil {
+		if err := uc.loadOrderDetails(ctx, order); err != nil {
 			continue
 		}
 
-		var orderItems []entities.OrderItem
-		for _, item := range items {
-			orderItems = append(orderItems, *item)
-		}
-		order.Items = orderItems
-
 		response = append(response, uc.buildOrderResponse(order))
 	}
 
 	return response, nil
 }
 
-func (uc *orderUseCase) GetOrdersForKitchen() ([]*dto.OrderResponse, error) {
-	orders, err := uc.orderGateway.GetPendingOrdersForKitchen()
+func (uc *orderUseCase) GetOrdersForKitchen(ctx context.Context) ([]*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.GetOrdersForKitchen")
+	defer span.End()
+
+	orders, err := uc.orderGateway.GetPendingOrdersForKitchen(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var response []*dto.OrderResponse
 	for _, order := range orders {
-		items, err := uc.orderItemGateway.GetByOrderID(order.ID)
-		if err != nil {
+		if err := uc.loadOrderDetails(ctx, order); err != nil {
 			continue
 		}
 
-		var orderItems []entities.OrderItem
-		for _, item := range items {
-			orderItems = append(orderItems, *item)
-		}
-		order.Items = orderItems
-
 		response = append(response, uc.buildOrderResponse(order))
 	}
 
 	return response, nil
 }
 
-func (uc *orderUseCase) UpdateOrderStatus(id uint64, request *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
-	order, err := uc.orderGateway.GetByID(id)
+func (uc *orderUseCase) UpdateOrderStatus(ctx context.Context, id uint64, request *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.UpdateOrderStatus")
+	defer span.End()
+
+	if !entities.IsValidOrderStatus(request.Status) {
+		return nil, errs.Validation(errs.CodeInvalidOrderStatus, "invalid order status")
+	}
+	status := entities.OrderStatus(request.Status)
+
+	// The transition is checked on the locked order, so two updates at once
+	// cannot both move it from the same status
+	var order *entities.Order
+	var previous entities.OrderStatus
+	err := uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = uc.orderGateway.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errs.NotFound(errs.CodeOrderNotFound, "order not found")
+		}
+		if !order.CanTransitionTo(status) {
+			return errs.InvalidTransition(
+				errs.CodeInvalidOrderTransition,
+				fmt.Sprintf("cannot change order status from %s to %s", order.Status, status),
+			)
+		}
+
+		// Loaded first so the event carries the order total
+		if err := uc.loadOrderDetails(ctx, order); err != nil {
+			return err
+		}
+
+		previous = order.Status
+		order.UpdateStatus(status)
+		if err := uc.orderGateway.Update(ctx, order); err != nil {
+			return err
+		}
+		return appendOrderEvent(ctx, uc.outboxGateway, entities.EventOrderStatusChanged, order, previous)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if order == nil {
-		return nil, errors.New("order not found")
+	uc.logger.InfoContext(ctx, "order status changed", "order_id", id, "from", previous, "to", status)
+	uc.metrics.OrderStatusChanged(status)
+
+	return uc.buildOrderResponse(order), nil
+}
+
+func (uc *orderUseCase) DeleteOrder(ctx context.Context, id uint64) error {
+	ctx, span := tracer.Start(ctx, "OrderUseCase.DeleteOrder")
+	defer span.End()
+
+	// The order goes with its payments, loyalty entries and receipt in one
+	// transaction, so a failure leaves nothing half deleted. The customer is
+	// locked like any other ledger write.
+	return uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := uc.orderGateway.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return errs.NotFound(errs.CodeOrderNotFound, "order not found")
+		}
+
+		customerID, err := loyaltyCustomerID(ctx, uc.customerGateway, order.CustomerId, order.CPF)
+		if err != nil {
+			return err
+		}
+		if customerID != 0 {
+			if _, err := uc.customerGateway.GetByIDForUpdate(ctx, customerID); err != nil {
+				return err
+			}
+		}
+
+		return uc.orderGateway.Delete(ctx, id)
+	})
+}
+
+// loadOrderItems fills order.Items, which the order gateway does not load
+func loadOrderItems(ctx context.Context, orderItemGateway output.OrderItemGateway, order *entities.Order) error {
+	items, err := orderItemGateway.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	order.Items = nil
+	for _, item := range items {
+		order.Items = append(order.Items, *item)
+	}
+	return nil
+}
+
+// loadOrderProducts returns the products of the order's items by ID; a
+// product that no longer exists maps to nil
+func loadOrderProducts(ctx context.Context, productGateway output.ProductGateway, order *entities.Order) (map[uint64]*entities.Product, error) {
+	products := make(map[uint64]*entities.Product)
+	for _, item := range order.Items {
+		if _, ok := products[item.ProductID]; ok {
+			continue
+		}
+		product, err := productGateway.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		products[item.ProductID] = product
+	}
+	return products, nil
+}
+
+// loadOrderDetails fills the order's items, discount lines and tax lines
+func (uc *orderUseCase) loadOrderDetails(ctx context.Context, order *entities.Order) error {
+	if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+		return err
 	}
 
-	order.UpdateStatus(entities.OrderStatus(request.Status))
+	discounts, err := uc.discountGateway.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
 
-	err = uc.orderGateway.Update(order)
+	order.Discounts = nil
+	for _, discount := range discounts {
+		order.Discounts = append(order.Discounts, *discount)
+	}
+
+	taxes, err := uc.taxGateway.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	order.Taxes = nil
+	for _, tax := range taxes {
+		order.Taxes = append(order.Taxes, *tax)
+	}
+	return nil
+}
+
+// applyPromotions discounts the order with every automatic promotion in
+// effect and then with the coupon, if one was sent. Together they never
+// take off more than the subtotal. It returns the applied promotions that
+// are limited per customer.
+func (uc *orderUseCase) applyPromotions(ctx context.Context, order *entities.Order, categories map[uint64]string, couponCode string) ([]*entities.Promotion, error) {
+	now := time.Now().In(uc.location)
+
+	promotions, err := uc.promotionGateway.ListAutomatic(ctx)
 	if err != nil {
 		return nil, err
 	}
+	var limited []*entities.Promotion
+	for _, promotion := range promotions {
+		if !promotion.InEffectAt(now) {
+			continue
+		}
+		// A limited promotion needs to know who is ordering; anonymous
+		// orders and customers who used it up simply do not get it
+		allowed, err := uc.customerMayUse(ctx, promotion, order)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			order.ApplyDiscount(promotion.DiscountLine(promotion.Discount(order.Items, categories)))
+			if promotion.MaxUsesPerCustomer > 0 {
+				limited = append(limited, promotion)
+			}
+		}
+	}
 
-	items, err := uc.orderItemGateway.GetByOrderID(id)
+	code := entities.NormalizeCouponCode(couponCode)
+	if code == "" {
+		return limited, nil
+	}
+
+	coupon, err := uc.promotionGateway.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil || !coupon.InEffectAt(now) {
+		return nil, errs.Validation(errs.CodeInvalidCoupon, "coupon code is invalid or not in effect")
+	}
+	if coupon.MaxUsesPerCustomer > 0 && order.CustomerId == 0 && order.CPF == "" {
+		return nil, errs.Validation(errs.CodeCouponRequiresCustomer, "coupon is limited per customer; identify the customer to use it")
+	}
+	allowed, err := uc.customerMayUse(ctx, coupon, order)
 	if err != nil {
 		return nil, err
 	}
+	if !allowed {
+		return nil, errCouponUsageExceeded()
+	}
 
-	var orderItems []entities.OrderItem
-	for _, item := range items {
-		orderItems = append(orderItems, *item)
+	discount := coupon.Discount(order.Items, categories)
+	if discount == 0 {
+		return nil, errs.Validation(errs.CodeInvalidCoupon, "coupon does not apply to any item of the order")
+	}
+	order.ApplyDiscount(coupon.DiscountLine(discount))
+	if coupon.MaxUsesPerCustomer > 0 {
+		limited = append(limited, coupon)
 	}
-	order.Items = orderItems
+	return limited, nil
+}
 
-	return uc.buildOrderResponse(order), nil
+// lockPromotions locks the limited promotions an order uses until the
+// transaction in ctx ends, in ID order so checkouts never wait on each other
+// in a cycle, and returns the ones still there
+func (uc *orderUseCase) lockPromotions(ctx context.Context, limited []*entities.Promotion) ([]*entities.Promotion, error) {
+	ids := make([]uint64, 0, len(limited))
+	for _, promotion := range limited {
+		ids = append(ids, promotion.ID)
+	}
+	slices.Sort(ids)
+
+	var promotions []*entities.Promotion
+	for _, id := range slices.Compact(ids) {
+		promotion, err := uc.promotionGateway.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// One deleted meanwhile is skipped; the discount line keeps its code
+		if promotion != nil {
+			promotions = append(promotions, promotion)
+		}
+	}
+	return promotions, nil
+}
+
+// recheckPromotionLimits counts the customer's uses of each locked promotion
+// again, so two checkouts cannot both take its last use
+func (uc *orderUseCase) recheckPromotionLimits(ctx context.Context, order *entities.Order, promotions []*entities.Promotion) error {
+	for _, promotion := range promotions {
+		allowed, err := uc.customerMayUse(ctx, promotion, order)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errCouponUsageExceeded()
+		}
+	}
+	return nil
+}
+
+func errCouponUsageExceeded() error {
+	return errs.Conflict(errs.CodeCouponUsageExceeded, "customer already used this coupon the maximum number of times")
+}
+
+// redeemPoints discounts the order with the customer's loyalty points, as
+// many of points as fit in what is left to pay, and returns the ledger entry
+// to store with the order (nil when none fit). The balance is read without
+// a lock; recheckRedemption checks it again before the entry is stored.
+func (uc *orderUseCase) redeemPoints(ctx context.Context, order *entities.Order, points int64) (*entities.LoyaltyEntry, error) {
+	customerID, err := loyaltyCustomerID(ctx, uc.customerGateway, order.CustomerId, order.CPF)
+	if err != nil {
+		return nil, err
+	}
+	if customerID == 0 {
+		return nil, errs.Validation(errs.CodeLoyaltyRequiresCustomer, "redeeming points requires a registered customer")
+	}
+
+	now := time.Now()
+	_, account, err := replayLoyaltyAccount(ctx, uc.loyaltyGateway, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if balance := account.Balance(now); points > balance {
+		return nil, errs.Conflict(errs.CodeInsufficientLoyaltyPoints, fmt.Sprintf("customer has %d points", balance))
+	}
+
+	used := min(points, uc.loyalty.PointsCovering(order.CalculateTotal()))
+	if used <= 0 {
+		return nil, nil
+	}
+	order.ApplyDiscount(uc.loyalty.RedemptionLine(used))
+	return entities.NewLoyaltyEntry(customerID, 0, entities.LoyaltyRedeem, -used), nil
 }
 
-func (uc *orderUseCase) DeleteOrder(id uint64) error {
-	order, err := uc.orderGateway.GetByID(id)
+// recheckRedemption checks the balance again with the customer's ledger
+// locked until the order is stored, so two checkouts cannot spend the same
+// points
+func (uc *orderUseCase) recheckRedemption(ctx context.Context, redemption *entities.LoyaltyEntry) error {
+	now := time.Now()
+	_, account, err := lockLoyaltyAccount(ctx, uc.customerGateway, uc.loyaltyGateway, redemption.CustomerID, now)
 	if err != nil {
 		return err
 	}
+	if balance := account.Balance(now); -redemption.Points > balance {
+		return errs.Conflict(errs.CodeInsufficientLoyaltyPoints, fmt.Sprintf("customer has %d points", balance))
+	}
+	return nil
+}
 
-	if order == nil {
-		return errors.New("order not found")
+// customerMayUse returns true if the promotion is unlimited or the order's
+// customer has uses left
+func (uc *orderUseCase) customerMayUse(ctx context.Context, promotion *entities.Promotion, order *entities.Order) (bool, error) {
+	if promotion.MaxUsesPerCustomer == 0 {
+		return true, nil
+	}
+	if order.CustomerId == 0 && order.CPF == "" {
+		return false, nil
 	}
 
-	return uc.orderGateway.Delete(id)
+	uses, err := uc.discountGateway.CountUses(ctx, promotion.ID, order.CustomerId, order.CPF)
+	if err != nil {
+		return false, err
+	}
+	return promotion.AllowsMoreUses(uses), nil
 }
 
 func (uc *orderUseCase) buildOrderResponse(order *entities.Order) *dto.OrderResponse {
@@ -263,6 +654,28 @@ func (uc *orderUseCase) buildOrderResponse(order *entities.Order) *dto.OrderResp
 			Quantity:  item.Quantity,
 			Price:     item.Price,
 			Subtotal:  item.CalculateSubtotal(),
+			Modifiers: item.Modifiers,
+			Notes:     item.Notes,
+		})
+	}
+
+	discounts := []dto.OrderDiscountResponse{}
+	for _, discount := range order.Discounts {
+		discounts = append(discounts, dto.OrderDiscountResponse{
+			PromotionID: discount.PromotionID,
+			Code:        discount.Code,
+			Description: discount.Description,
+			Amount:      discount.Amount,
+		})
+	}
+
+	taxes := []dto.OrderTaxResponse{}
+	for _, tax := range order.Taxes {
+		taxes = append(taxes, dto.OrderTaxResponse{
+			Category: tax.Category,
+			Rate:     tax.Rate,
+			Base:     tax.Base,
+			Amount:   tax.Amount,
 		})
 	}
 
@@ -272,7 +685,13 @@ func (uc *orderUseCase) buildOrderResponse(order *entities.Order) *dto.OrderResp
 		CPF:        order.CPF,
 		Status:     string(order.Status),
 		Items:      items,
+		Subtotal:   order.Subtotal(),
+		Discounts:  discounts,
+		Discount:   order.Discount,
+		ServiceFee: order.ServiceFee,
+		Rounding:   order.Rounding,
 		Total:      order.CalculateTotal(),
+		Taxes:      taxes,
 		CreatedAt:  order.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:  order.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
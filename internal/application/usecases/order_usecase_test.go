@@ -0,0 +1,1200 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/services"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+)
+
+type orderTestFixture struct {
+	useCase          input.OrderUseCase
+	productGateway   output.ProductGateway
+	orderGateway     output.OrderGateway
+	orderItemGateway output.OrderItemGateway
+	paymentGateway   output.PaymentGateway
+	outboxGateway    output.OutboxGateway
+	promotionGateway output.PromotionGateway
+	discountGateway  output.OrderDiscountGateway
+	customerGateway  output.CustomerGateway
+	loyaltyGateway   output.LoyaltyGateway
+	taxGateway       output.OrderTaxGateway
+	receiptGateway   output.ReceiptGateway
+	ticketGateway    output.KitchenTicketGateway
+	stationGateway   output.StationTicketGateway
+}
+
+func newOrderTestFixture() *orderTestFixture {
+	return newOrderTestFixtureWith(nil, logging.Discard())
+}
+
+// newOrderTestFixtureWith lets a test replace the payment gateway (nil keeps
+// the in-memory one) and capture logs
+func newOrderTestFixtureWith(paymentGateway output.PaymentGateway, logger *slog.Logger) *orderTestFixture {
+	store := memory.NewStore()
+	if paymentGateway == nil {
+		paymentGateway = memory.NewPaymentGateway(store)
+	}
+	f := &orderTestFixture{
+		productGateway:   memory.NewProductGateway(store),
+		orderGateway:     memory.NewOrderGateway(store),
+		orderItemGateway: memory.NewOrderItemGateway(store),
+		paymentGateway:   paymentGateway,
+		outboxGateway:    memory.NewOutboxGateway(store),
+		promotionGateway: memory.NewPromotionGateway(store),
+		discountGateway:  memory.NewOrderDiscountGateway(store),
+		customerGateway:  memory.NewCustomerGateway(store),
+		loyaltyGateway:   memory.NewLoyaltyGateway(store),
+		taxGateway:       memory.NewOrderTaxGateway(store),
+		receiptGateway:   memory.NewReceiptGateway(store),
+		ticketGateway:    memory.NewKitchenTicketGateway(store),
+		stationGateway:   memory.NewStationTicketGateway(store),
+	}
+	f.useCase = f.pricedUseCase(services.PricingRules{}, logger)
+	return f
+}
+
+// pricedUseCase builds an order use case over the fixture's gateways that
+// prices orders with rules
+func (f *orderTestFixture) pricedUseCase(rules services.PricingRules, logger *slog.Logger) input.OrderUseCase {
+	return NewOrderUseCase(f.orderGateway, f.orderItemGateway, f.productGateway, f.paymentGateway, f.outboxGateway, f.promotionGateway, f.discountGateway, f.taxGateway, f.customerGateway, f.loyaltyGateway, memory.NewTransactionManager(), 0, time.UTC, testLoyaltyProgram, services.NewPricingService(rules), logger, telemetry.Discard())
+}
+
+type failingPaymentGateway struct {
+	output.PaymentGateway
+}
+
+func (failingPaymentGateway) Create(context.Context, *entities.Payment) error {
+	return errors.New("payments table is locked")
+}
+
+func (f *orderTestFixture) seedProduct(t *testing.T, name string, price float32) *entities.Product {
+	t.Helper()
+	product := entities.NewProduct(name, name, price, entities.SnackCategory, "")
+	if err := f.productGateway.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to seed product: %v", err)
+	}
+	return product
+}
+
+func (f *orderTestFixture) seedPromotion(t *testing.T, promotion *entities.Promotion) *entities.Promotion {
+	t.Helper()
+	if err := f.promotionGateway.Create(ctx, promotion); err != nil {
+		t.Fatalf("Failed to seed promotion: %v", err)
+	}
+	return promotion
+}
+
+func (f *orderTestFixture) advanceOrder(t *testing.T, id uint64, statuses ...entities.OrderStatus) {
+	t.Helper()
+	for _, status := range statuses {
+		if _, err := f.useCase.UpdateOrderStatus(ctx, id, &dto.UpdateOrderStatusRequest{Status: string(status)}); err != nil {
+			t.Fatalf("Failed to move order %d to %s: %v", id, status, err)
+		}
+	}
+}
+
+func TestOrderUseCase_CreateOrder(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	fries := f.seedProduct(t, "Batata", 10)
+
+	request := &dto.CreateOrderRequest{
+		CPF: "123.456.789-00",
+		Items: []dto.OrderItemRequest{
+			{ProductID: burger.ID, Quantity: 2},
+			{ProductID: fries.ID, Quantity: 1},
+		},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Status != string(entities.OrderAwaitingPayment) {
+		t.Errorf("Expected status awaiting_payment, got %s", response.Status)
+	}
+
+	if response.Total != 50 {
+		t.Errorf("Expected total 50, got %v", response.Total)
+	}
+
+	items, _ := f.orderItemGateway.GetByOrderID(ctx, response.ID)
+	if len(items) != 2 {
+		t.Errorf("Expected 2 persisted items, got %d", len(items))
+	}
+
+	payment, _ := f.paymentGateway.GetLatestByOrderID(ctx, response.ID)
+	if payment == nil {
+		t.Fatal("Expected payment to be created with the order")
+	}
+
+	if payment.Amount != 50 || payment.PaymentMethod != "qr_code" {
+		t.Errorf("Unexpected payment %+v", payment)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_SplitPayments(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	request := &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 3}},
+		Payments: []dto.PaymentPartRequest{
+			{PaymentMethod: "credit_card", Amount: 25, Payer: "Ana"},
+			{PaymentMethod: "pix", Amount: 35, Payer: "Bruno"},
+		},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	payments, _ := f.paymentGateway.ListByOrderID(ctx, response.ID)
+	if len(payments) != 2 {
+		t.Fatalf("Expected one payment per part, got %d", len(payments))
+	}
+	if payments[0].Amount != 25 || payments[0].Payer != "Ana" || payments[0].PaymentMethod != "credit_card" ||
+		payments[1].Amount != 35 || payments[1].Payer != "Bruno" || !payments[1].IsPending() {
+		t.Errorf("Unexpected payments %+v, %+v", payments[0], payments[1])
+	}
+}
+
+func TestOrderUseCase_CreateOrder_SplitMustMatchTotal(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	request := &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+		Payments: []dto.PaymentPartRequest{
+			{PaymentMethod: "pix", Amount: 10},
+			{PaymentMethod: "pix", Amount: 5},
+		},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeInvalidPaymentSplit {
+		t.Fatalf("Expected code %s, got %v", errs.CodeInvalidPaymentSplit, err)
+	}
+	if response != nil {
+		t.Error("Expected nil response")
+	}
+	if orders, _ := f.orderGateway.GetAll(ctx); len(orders) != 0 {
+		t.Errorf("Expected no order to be stored, got %d", len(orders))
+	}
+}
+
+func TestOrderUseCase_CreateOrder_ProductNotFound(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+
+	request := &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: 99, Quantity: 1}},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err == nil || err.Error() != "product not found" {
+		t.Errorf("Expected 'product not found' error, got %v", err)
+	}
+
+	if !errors.Is(err, errs.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if response != nil {
+		t.Error("Expected nil response when product does not exist")
+	}
+}
+
+func TestOrderUseCase_CreateOrder_CategoryPercentagePromotion(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	pudding := entities.NewProduct("Pudim", "Pudim", 10, entities.DessertCategory, "")
+	f.productGateway.Create(ctx, pudding)
+	promotion := entities.NewPromotion("Sobremesa pela metade", "", entities.PromotionPercentage, 50)
+	promotion.Category = string(entities.DessertCategory)
+	f.seedPromotion(t, promotion)
+
+	request := &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{
+			{ProductID: burger.ID, Quantity: 1},
+			{ProductID: pudding.ID, Quantity: 2},
+		},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Subtotal != 40 || response.Discount != 10 || response.Total != 30 {
+		t.Errorf("Expected subtotal 40, discount 10 and total 30, got %v, %v and %v", response.Subtotal, response.Discount, response.Total)
+	}
+	if len(response.Discounts) != 1 || response.Discounts[0].PromotionID != promotion.ID || response.Discounts[0].Amount != 10 {
+		t.Errorf("Unexpected discount lines %+v", response.Discounts)
+	}
+
+	stored, _ := f.useCase.GetOrderByID(ctx, response.ID)
+	if stored.Total != 30 || len(stored.Discounts) != 1 || stored.Discounts[0].Description != "Sobremesa pela metade" {
+		t.Errorf("Expected the discount to be stored with the order, got %+v", stored)
+	}
+	payment, _ := f.paymentGateway.GetLatestByOrderID(ctx, response.ID)
+	if payment == nil || payment.Amount != 30 {
+		t.Errorf("Expected the payment to charge the discounted total, got %+v", payment)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_BuyXGetYCoupon(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	cheese := f.seedProduct(t, "X-Salada", 18)
+	promotion := entities.NewPromotion("Leve 3 pague 2", "leve3", entities.PromotionBuyXGetY, 0)
+	promotion.BuyQuantity = 2
+	promotion.FreeQuantity = 1
+	f.seedPromotion(t, promotion)
+
+	request := &dto.CreateOrderRequest{
+		CouponCode: " Leve3 ",
+		Items: []dto.OrderItemRequest{
+			{ProductID: burger.ID, Quantity: 2},
+			{ProductID: cheese.ID, Quantity: 1},
+		},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Discount != 18 || response.Total != 40 {
+		t.Errorf("Expected the cheapest unit free (discount 18, total 40), got %v and %v", response.Discount, response.Total)
+	}
+	if len(response.Discounts) != 1 || response.Discounts[0].Code != "LEVE3" {
+		t.Errorf("Unexpected discount lines %+v", response.Discounts)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_BuyXGetYCoupon_FreeUnitsSpanLines(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	cheese := f.seedProduct(t, "X-Salada", 18)
+	promotion := entities.NewPromotion("Leve 3 pague 2", "leve3", entities.PromotionBuyXGetY, 0)
+	promotion.BuyQuantity = 2
+	promotion.FreeQuantity = 1
+	f.seedPromotion(t, promotion)
+
+	request := &dto.CreateOrderRequest{
+		CouponCode: "LEVE3",
+		Items: []dto.OrderItemRequest{
+			{ProductID: burger.ID, Quantity: 5},
+			{ProductID: cheese.ID, Quantity: 1},
+		},
+	}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Discount != 38 || response.Total != 80 {
+		t.Errorf("Expected the cheese and one burger free (discount 38, total 80), got %v and %v", response.Discount, response.Total)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_IgnoresPromotionOutsideItsWindow(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	promotion := entities.NewPromotion("Black Friday", "", entities.PromotionFixed, 5)
+	startsAt := time.Now().Add(24 * time.Hour)
+	promotion.StartsAt = &startsAt
+	f.seedPromotion(t, promotion)
+
+	request := &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}}
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Discount != 0 || response.Total != 20 || len(response.Discounts) != 0 {
+		t.Errorf("Expected no discount before the promotion starts, got %+v", response)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_CouponErrors(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	limited := entities.NewPromotion("Primeira compra", "BEMVINDO", entities.PromotionFixed, 5)
+	limited.MaxUsesPerCustomer = 1
+	f.seedPromotion(t, limited)
+	drinks := entities.NewPromotion("Refri grátis", "REFRI", entities.PromotionPercentage, 100)
+	drinks.Category = string(entities.DrinkCategory)
+	f.seedPromotion(t, drinks)
+
+	items := []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}
+	if _, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{CPF: "123.456.789-00", CouponCode: "BEMVINDO", Items: items}); err != nil {
+		t.Fatalf("Expected the first use to succeed, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		request  *dto.CreateOrderRequest
+		expected string
+	}{
+		{"unknown code", &dto.CreateOrderRequest{CouponCode: "NADA", Items: items}, errs.CodeInvalidCoupon},
+		{"no eligible item", &dto.CreateOrderRequest{CouponCode: "REFRI", Items: items}, errs.CodeInvalidCoupon},
+		{"anonymous customer", &dto.CreateOrderRequest{CouponCode: "BEMVINDO", Items: items}, errs.CodeCouponRequiresCustomer},
+		{"used up", &dto.CreateOrderRequest{CPF: "123.456.789-00", CouponCode: "BEMVINDO", Items: items}, errs.CodeCouponUsageExceeded},
+		{"used up, CPF without punctuation", &dto.CreateOrderRequest{CPF: "12345678900", CouponCode: "BEMVINDO", Items: items}, errs.CodeCouponUsageExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := f.useCase.CreateOrder(ctx, tt.request)
+
+			// Assert
+			if errs.CodeOf(err) != tt.expected {
+				t.Errorf("Expected code %s, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestOrderUseCase_CreateOrder_FreeOrderIsReceivedWithoutPayment(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	f.seedPromotion(t, entities.NewPromotion("Cortesia", "CORTESIA", entities.PromotionPercentage, 100))
+
+	// Act
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		CouponCode: "CORTESIA",
+		Items:      []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.Total != 0 || order.Status != string(entities.OrderReceived) {
+		t.Errorf("Expected a free order to be received, got %+v", order)
+	}
+	if payments, _ := f.paymentGateway.ListByOrderID(ctx, order.ID); len(payments) != 0 {
+		t.Errorf("Expected no payment for a free order, got %+v", payments)
+	}
+	if stored, _ := f.orderGateway.GetByID(ctx, order.ID); stored.Status != entities.OrderReceived {
+		t.Errorf("Expected the received status to be stored, got %s", stored.Status)
+	}
+	if got := pendingEvents(t, f.outboxGateway); strings.Join(got, ",") != "order.created,order.status_changed" {
+		t.Errorf("Expected order.created and order.status_changed, got %v", got)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_RedeemsLoyaltyPoints(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	loyalty := f.loyaltyUseCase()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 0, entities.LoyaltyEarn, 500))
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	// Act
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		CPF:          customer.CPF,
+		RedeemPoints: 100,
+		Items:        []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.Discount != 5 || order.Total != 15 || len(order.Discounts) != 1 || order.Discounts[0].Description != "Resgate de 100 pontos" {
+		t.Errorf("Expected 100 points to take 5 off, got %+v", order)
+	}
+	if account, _ := loyalty.GetCustomerLoyalty(ctx, customer.ID); account.Balance != 400 {
+		t.Errorf("Expected 400 points left, got %d", account.Balance)
+	}
+
+	f.advanceOrder(t, order.ID, entities.OrderCancelled)
+	f.publishPending(t, loyalty)
+	f.publishPending(t, loyalty)
+	if account, _ := loyalty.GetCustomerLoyalty(ctx, customer.ID); account.Balance != 500 {
+		t.Errorf("Expected the cancelled order to give the points back once, got %d", account.Balance)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_RedeemsOnlyWhatFitsTheTotal(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 0, entities.LoyaltyEarn, 1000))
+	fries := f.seedProduct(t, "Batata", 10.52)
+
+	// Act
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		CustomerId:   customer.ID,
+		RedeemPoints: 1000,
+		Items:        []dto.OrderItemRequest{{ProductID: fries.ID, Quantity: 1}},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.Discount != 10.5 || order.Total != 0.02 {
+		t.Errorf("Expected 210 points to take 10.50 off, got discount %v and total %v", order.Discount, order.Total)
+	}
+	entries, _ := f.loyaltyGateway.ListByOrderID(ctx, order.ID)
+	if len(entries) != 1 || entries[0].Type != entities.LoyaltyRedeem || entries[0].Points != -210 {
+		t.Errorf("Expected a redeem entry of 210 points, got %+v", entries)
+	}
+}
+
+// rendezvousLedger holds each of the first two ledger reads until the other
+// one was made or a short wait passes, so two checkouts both see the
+// balance before either stores its redemption
+type rendezvousLedger struct {
+	output.LoyaltyGateway
+	reads atomic.Int32
+	both  chan struct{}
+}
+
+func (g *rendezvousLedger) ListByCustomerID(ctx context.Context, customerID uint64) ([]*entities.LoyaltyEntry, error) {
+	entries, err := g.LoyaltyGateway.ListByCustomerID(ctx, customerID)
+	if n := g.reads.Add(1); n == 2 {
+		close(g.both)
+	} else if n > 2 {
+		return entries, err
+	}
+	select {
+	case <-g.both:
+	case <-time.After(100 * time.Millisecond):
+	}
+	return entries, err
+}
+
+func TestOrderUseCase_CreateOrder_ConcurrentRedemptions(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 0, entities.LoyaltyEarn, 100))
+	burger := f.seedProduct(t, "X-Burger", 20)
+	f.loyaltyGateway = &rendezvousLedger{LoyaltyGateway: f.loyaltyGateway, both: make(chan struct{})}
+	useCase := f.pricedUseCase(services.PricingRules{}, logging.Discard())
+
+	// Act
+	var wg sync.WaitGroup
+	orderErrs := make([]error, 2)
+	for i := range orderErrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, orderErrs[i] = useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+				CustomerId:   customer.ID,
+				RedeemPoints: 100,
+				Items:        []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+			})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	var placed, refused int
+	for _, err := range orderErrs {
+		switch {
+		case err == nil:
+			placed++
+		case errs.CodeOf(err) == errs.CodeInsufficientLoyaltyPoints:
+			refused++
+		}
+	}
+	if placed != 1 || refused != 1 {
+		t.Errorf("Expected one order to spend the points and the other to be refused, got %v", orderErrs)
+	}
+	if account, _ := f.loyaltyUseCase().GetCustomerLoyalty(ctx, customer.ID); account.Balance != 0 {
+		t.Errorf("Expected the 100 points to be spent once, got a balance of %d", account.Balance)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_RedeemErrors(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 0, entities.LoyaltyEarn, 50))
+	burger := f.seedProduct(t, "X-Burger", 20)
+	items := []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}
+
+	tests := []struct {
+		name     string
+		request  *dto.CreateOrderRequest
+		expected string
+	}{
+		{"anonymous order", &dto.CreateOrderRequest{RedeemPoints: 10, Items: items}, errs.CodeLoyaltyRequiresCustomer},
+		{"unregistered CPF", &dto.CreateOrderRequest{CPF: "999.999.999-99", RedeemPoints: 10, Items: items}, errs.CodeLoyaltyRequiresCustomer},
+		{"more than the balance", &dto.CreateOrderRequest{CustomerId: customer.ID, RedeemPoints: 51, Items: items}, errs.CodeInsufficientLoyaltyPoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := f.useCase.CreateOrder(ctx, tt.request)
+
+			// Assert
+			if errs.CodeOf(err) != tt.expected {
+				t.Errorf("Expected code %s, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestOrderUseCase_CreateOrder_PointsCoveringTheTotalReceiveTheOrder(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 0, entities.LoyaltyEarn, 1000))
+	fries := f.seedProduct(t, "Batata", 10.5)
+
+	// Act
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		CustomerId:   customer.ID,
+		RedeemPoints: 1000,
+		Items:        []dto.OrderItemRequest{{ProductID: fries.ID, Quantity: 1}},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.Total != 0 || order.Status != string(entities.OrderReceived) {
+		t.Errorf("Expected an order paid with points to be received, got %+v", order)
+	}
+	if payments, _ := f.paymentGateway.ListByOrderID(ctx, order.ID); len(payments) != 0 {
+		t.Errorf("Expected no payment for an order paid with points, got %+v", payments)
+	}
+	entries, _ := f.loyaltyGateway.ListByOrderID(ctx, order.ID)
+	if len(entries) != 1 || entries[0].Points != -210 {
+		t.Errorf("Expected a redeem entry of 210 points, got %+v", entries)
+	}
+}
+
+// rendezvousCountUses holds each of the first two coupon use counts until
+// the other one was made or a short wait passes, so two checkouts both count
+// before either stores its order
+type rendezvousCountUses struct {
+	output.OrderDiscountGateway
+	counts atomic.Int32
+	both   chan struct{}
+}
+
+func (g *rendezvousCountUses) CountUses(ctx context.Context, promotionID, customerID uint64, cpf string) (int, error) {
+	uses, err := g.OrderDiscountGateway.CountUses(ctx, promotionID, customerID, cpf)
+	if n := g.counts.Add(1); n == 2 {
+		close(g.both)
+	} else if n > 2 {
+		return uses, err
+	}
+	select {
+	case <-g.both:
+	case <-time.After(100 * time.Millisecond):
+	}
+	return uses, err
+}
+
+func TestOrderUseCase_CreateOrder_ConcurrentCouponUses(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	f.discountGateway = &rendezvousCountUses{OrderDiscountGateway: f.discountGateway, both: make(chan struct{})}
+	useCase := f.pricedUseCase(services.PricingRules{}, logging.Discard())
+	burger := f.seedProduct(t, "X-Burger", 20)
+	limited := entities.NewPromotion("Primeira compra", "BEMVINDO", entities.PromotionFixed, 5)
+	limited.MaxUsesPerCustomer = 1
+	f.seedPromotion(t, limited)
+
+	// Act
+	var wg sync.WaitGroup
+	orderErrs := make([]error, 2)
+	for i, cpf := range []string{"123.456.789-00", "12345678900"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, orderErrs[i] = useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+				CPF:        cpf,
+				CouponCode: "BEMVINDO",
+				Items:      []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+			})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	var used, refused int
+	for _, err := range orderErrs {
+		switch {
+		case err == nil:
+			used++
+		case errs.CodeOf(err) == errs.CodeCouponUsageExceeded:
+			refused++
+		}
+	}
+	if used != 1 || refused != 1 {
+		t.Errorf("Expected one order to use the coupon and the other to be refused, got %v", orderErrs)
+	}
+	if uses, _ := f.discountGateway.CountUses(ctx, limited.ID, 0, "123.456.789-00"); uses != 1 {
+		t.Errorf("Expected the coupon to be used once, got %d", uses)
+	}
+}
+
+type loggedPromotions struct {
+	output.PromotionGateway
+	log *statementLog
+}
+
+func (g loggedPromotions) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Promotion, error) {
+	g.log.add("lock promotion")
+	return g.PromotionGateway.GetByIDForUpdate(ctx, id)
+}
+
+type loggedDiscounts struct {
+	output.OrderDiscountGateway
+	log *statementLog
+}
+
+func (g loggedDiscounts) CountUses(ctx context.Context, promotionID, customerID uint64, cpf string) (int, error) {
+	g.log.add("read promotion uses")
+	return g.OrderDiscountGateway.CountUses(ctx, promotionID, customerID, cpf)
+}
+
+func TestOrderUseCase_CreateOrder_LocksBeforeRechecking(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	log := &statementLog{}
+	useCase := NewOrderUseCase(f.orderGateway, f.orderItemGateway, f.productGateway, f.paymentGateway, f.outboxGateway,
+		loggedPromotions{f.promotionGateway, log}, loggedDiscounts{f.discountGateway, log}, f.taxGateway,
+		loggedCustomers{f.customerGateway, log}, loggedLedger{f.loyaltyGateway, log}, loggedTransactions{memory.NewTransactionManager(), log},
+		0, time.UTC, testLoyaltyProgram, services.NewPricingService(services.PricingRules{}), logging.Discard(), telemetry.Discard())
+	customer := f.seedCustomer(t, "123.456.789-00")
+	f.seedLoyaltyEntry(t, entities.NewLoyaltyEntry(customer.ID, 0, entities.LoyaltyEarn, 500))
+	burger := f.seedProduct(t, "X-Burger", 20)
+	limited := entities.NewPromotion("Primeira compra", "BEMVINDO", entities.PromotionFixed, 5)
+	limited.MaxUsesPerCustomer = 1
+	f.seedPromotion(t, limited)
+
+	// Act
+	_, err := useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		CustomerId:   customer.ID,
+		CouponCode:   "BEMVINDO",
+		RedeemPoints: 100,
+		Items:        []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if statements := log.readBeforeLock(); statements != nil {
+		t.Errorf("Expected the promotion and the customer to be locked before any read, got %v", statements)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_SplitMustMatchDiscountedTotal(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	f.seedPromotion(t, entities.NewPromotion("Dez por cento", "DEZ", entities.PromotionPercentage, 10))
+
+	request := &dto.CreateOrderRequest{
+		CouponCode: "DEZ",
+		Items:      []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 2}},
+		Payments: []dto.PaymentPartRequest{
+			{PaymentMethod: "pix", Amount: 20},
+			{PaymentMethod: "pix", Amount: 16},
+		},
+	}
+
+	// Act
+	_, err := f.useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected parts adding up to the discounted total to be accepted, got %v", err)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_PriceBreakdown(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	useCase := f.pricedUseCase(services.PricingRules{
+		TaxRates:          map[entities.ProductCategory]float64{entities.SnackCategory: 10, entities.DessertCategory: 5},
+		ServiceFeePercent: 10,
+		RoundingIncrement: 0.05,
+		RoundingMode:      entities.RoundHalfUp,
+	}, logging.Discard())
+	burger := f.seedProduct(t, "X-Burger", 20.33)
+	pudding := entities.NewProduct("Pudim", "Pudim", 10, entities.DessertCategory, "")
+	f.productGateway.Create(ctx, pudding)
+	f.seedPromotion(t, entities.NewPromotion("Dez por cento", "DEZ", entities.PromotionPercentage, 10))
+
+	request := &dto.CreateOrderRequest{
+		CouponCode: "DEZ",
+		Items: []dto.OrderItemRequest{
+			{ProductID: burger.ID, Quantity: 1},
+			{ProductID: pudding.ID, Quantity: 1},
+		},
+	}
+
+	// Act
+	response, err := useCase.CreateOrder(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 30.33 - 3.03 + 2.73 = 30.03, rounded half up to 30.05
+	if response.Subtotal != 30.33 || response.Discount != 3.03 || response.ServiceFee != 2.73 || response.Rounding != 0.02 || response.Total != 30.05 {
+		t.Errorf("Expected 30.33 - 3.03 + 2.73 + 0.02 = 30.05, got %+v", response)
+	}
+	// The discount is shared 1.00 to the dessert and 2.03 to the snack
+	expected := []dto.OrderTaxResponse{
+		{Category: "dessert", Rate: 5, Base: 9, Amount: 0.45},
+		{Category: "snack", Rate: 10, Base: 18.3, Amount: 1.83},
+	}
+	if !reflect.DeepEqual(response.Taxes, expected) {
+		t.Errorf("Expected tax lines %+v, got %+v", expected, response.Taxes)
+	}
+
+	stored, _ := useCase.GetOrderByID(ctx, response.ID)
+	if stored.Total != 30.05 || !reflect.DeepEqual(stored.Taxes, expected) {
+		t.Errorf("Expected the breakdown to be stored with the order, got %+v", stored)
+	}
+	payment, _ := f.paymentGateway.GetLatestByOrderID(ctx, response.ID)
+	if payment == nil || payment.Amount != 30.05 {
+		t.Errorf("Expected the payment to charge the rounded total, got %+v", payment)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_RoundingModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    float32
+		mode     entities.RoundingMode
+		expected float32
+	}{
+		{"HalfUpRoundsUp", 10.03, entities.RoundHalfUp, 10.05},
+		{"HalfUpRoundsDown", 10.02, entities.RoundHalfUp, 10},
+		{"Down", 10.04, entities.RoundDown, 10},
+		{"Up", 10.01, entities.RoundUp, 10.05},
+		{"AlreadyRound", 10.10, entities.RoundUp, 10.10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			f := newOrderTestFixture()
+			useCase := f.pricedUseCase(services.PricingRules{RoundingIncrement: 0.05, RoundingMode: tt.mode}, logging.Discard())
+			burger := f.seedProduct(t, "X-Burger", tt.price)
+
+			// Act
+			response, err := useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+				Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+			})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if response.Total != tt.expected {
+				t.Errorf("Expected total %v, got %v", tt.expected, response.Total)
+			}
+			if len(response.Taxes) != 0 {
+				t.Errorf("Expected no tax lines without tax rates, got %+v", response.Taxes)
+			}
+		})
+	}
+}
+
+func TestOrderUseCase_CreateOrder_BreakdownOutlivesRuleChanges(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	placed, err := f.pricedUseCase(services.PricingRules{
+		TaxRates:          map[entities.ProductCategory]float64{entities.SnackCategory: 10},
+		ServiceFeePercent: 10,
+	}, logging.Discard()).CreateOrder(ctx, &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to place order: %v", err)
+	}
+
+	// Act
+	found, err := f.useCase.GetOrderByID(ctx, placed.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if found.ServiceFee != 2 || found.Total != 22 || len(found.Taxes) != 1 || found.Taxes[0].Amount != 2 {
+		t.Errorf("Expected the fee and taxes the order was placed with, got %+v", found)
+	}
+}
+
+func TestOrderUseCase_GetOrdersForKitchen(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	var ids []uint64
+	for i := 0; i < 3; i++ {
+		response, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+			Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create order: %v", err)
+		}
+		ids = append(ids, response.ID)
+	}
+
+	f.advanceOrder(t, ids[0], entities.OrderReceived)
+	f.advanceOrder(t, ids[1], entities.OrderReceived, entities.OrderInProgress, entities.OrderReady)
+
+	// Act
+	response, err := f.useCase.GetOrdersForKitchen(ctx)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response) != 2 {
+		t.Fatalf("Expected 2 orders in the kitchen, got %d", len(response))
+	}
+
+	if response[0].ID != ids[1] || response[1].ID != ids[0] {
+		t.Errorf("Expected ready order before received order, got %d, %d", response[0].ID, response[1].ID)
+	}
+
+	if len(response[0].Items) != 1 {
+		t.Errorf("Expected order items to be loaded, got %d", len(response[0].Items))
+	}
+}
+
+func TestOrderUseCase_DeleteOrder(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	created, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+
+	// Act
+	err = f.useCase.DeleteOrder(ctx, created.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := f.useCase.GetOrderByID(ctx, created.ID); err == nil || err.Error() != "order not found" {
+		t.Errorf("Expected 'order not found' error, got %v", err)
+	}
+	if payments, _ := f.paymentGateway.ListByOrderID(ctx, created.ID); len(payments) != 0 {
+		t.Errorf("Expected the payment to be deleted with the order, got %d", len(payments))
+	}
+}
+
+func TestOrderUseCase_DeleteOrder_NotFound(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+
+	// Act
+	err := f.useCase.DeleteOrder(ctx, 999)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeOrderNotFound {
+		t.Errorf("Expected %s, got %v", errs.CodeOrderNotFound, err)
+	}
+}
+
+func TestOrderUseCase_UpdateOrderStatus_InvalidTransition(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	created, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+
+	// Act
+	response, err := f.useCase.UpdateOrderStatus(ctx, created.ID, &dto.UpdateOrderStatusRequest{Status: string(entities.OrderReady)})
+
+	// Assert
+	if !errors.Is(err, errs.ErrInvalidTransition) {
+		t.Fatalf("Expected ErrInvalidTransition, got %v", err)
+	}
+
+	if errs.CodeOf(err) != errs.CodeInvalidOrderTransition {
+		t.Errorf("Expected code %s, got %s", errs.CodeInvalidOrderTransition, errs.CodeOf(err))
+	}
+
+	if response != nil {
+		t.Error("Expected nil response for a rejected transition")
+	}
+
+	order, _ := f.orderGateway.GetByID(ctx, created.ID)
+	if order.Status != entities.OrderAwaitingPayment {
+		t.Errorf("Expected status to stay awaiting_payment, got %s", order.Status)
+	}
+}
+
+// rendezvousOrderReads holds each of the first two order reads until the
+// other one was made or a short wait passes, so two status updates both see
+// the status before either stores theirs
+type rendezvousOrderReads struct {
+	output.OrderGateway
+	reads atomic.Int32
+	both  chan struct{}
+}
+
+func (g *rendezvousOrderReads) wait() {
+	if n := g.reads.Add(1); n == 2 {
+		close(g.both)
+	} else if n == 1 {
+		select {
+		case <-g.both:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (g *rendezvousOrderReads) GetByID(ctx context.Context, id uint64) (*entities.Order, error) {
+	order, err := g.OrderGateway.GetByID(ctx, id)
+	g.wait()
+	return order, err
+}
+
+func (g *rendezvousOrderReads) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Order, error) {
+	order, err := g.OrderGateway.GetByIDForUpdate(ctx, id)
+	g.wait()
+	return order, err
+}
+
+func TestOrderUseCase_UpdateOrderStatus_ConcurrentUpdates(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	created, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	f.advanceOrder(t, created.ID, entities.OrderReceived, entities.OrderInProgress)
+	f.orderGateway = &rendezvousOrderReads{OrderGateway: f.orderGateway, both: make(chan struct{})}
+	useCase := f.pricedUseCase(services.PricingRules{}, logging.Discard())
+
+	// Act
+	var wg sync.WaitGroup
+	updateErrs := make([]error, 2)
+	for i, status := range []entities.OrderStatus{entities.OrderReady, entities.OrderCancelled} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, updateErrs[i] = useCase.UpdateOrderStatus(ctx, created.ID, &dto.UpdateOrderStatusRequest{Status: string(status)})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	var moved, refused int
+	for _, err := range updateErrs {
+		switch {
+		case err == nil:
+			moved++
+		case errs.CodeOf(err) == errs.CodeInvalidOrderTransition:
+			refused++
+		}
+	}
+	if moved != 1 || refused != 1 {
+		t.Errorf("Expected one update to win and the other to be refused, got %v", updateErrs)
+	}
+	if got := pendingEvents(t, f.outboxGateway); len(got) != 4 {
+		t.Errorf("Expected a single status change event for the race, got %v", got)
+	}
+}
+
+func TestOrderUseCase_UpdateOrderStatus_UnknownStatus(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	created, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+
+	// Act
+	_, err = f.useCase.UpdateOrderStatus(ctx, created.ID, &dto.UpdateOrderStatusRequest{Status: "delivered"})
+
+	// Assert
+	if !errors.Is(err, errs.ErrValidation) {
+		t.Errorf("Expected ErrValidation, got %v", err)
+	}
+}
+
+func TestOrderUseCase_CreateOrder_FailsWhenPaymentIsNotStored(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixtureWith(failingPaymentGateway{}, logging.Discard())
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	// Act
+	response, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{
+		Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}},
+	})
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "payments table is locked") {
+		t.Errorf("Expected the payment error to fail the checkout, got %v", err)
+	}
+	if response != nil {
+		t.Errorf("Expected no order, got %+v", response)
+	}
+}
+
+// pendingEvents returns the event types waiting in the outbox, oldest first
+func pendingEvents(t *testing.T, outbox output.OutboxGateway) []string {
+	t.Helper()
+	events, err := outbox.ListPending(ctx, time.Now().Add(time.Second), 100)
+	if err != nil {
+		t.Fatalf("Failed to list outbox events: %v", err)
+	}
+	var types []string
+	for _, event := range events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+func TestOrderUseCase_AppendsOutboxEvents(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+
+	// Act
+	f.advanceOrder(t, order.ID, entities.OrderReceived)
+
+	// Assert
+	events, _ := f.outboxGateway.ListPending(ctx, time.Now().Add(time.Second), 100)
+	if got := pendingEvents(t, f.outboxGateway); strings.Join(got, ",") != "order.created,order.status_changed" {
+		t.Fatalf("Expected order.created and order.status_changed, got %v", got)
+	}
+
+	var payload map[string]any
+	json.Unmarshal(events[1].Payload, &payload)
+	if events[1].AggregateID != order.ID || payload["status"] != "received" || payload["previous_status"] != "awaiting_payment" || payload["total"] != 20.0 {
+		t.Errorf("Unexpected status change event %+v with payload %v", events[1], payload)
+	}
+}
+
+func TestOrderUseCase_OrderEventsLeaveTheCPFOut(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+
+	// Act
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{CPF: "123.456.789-00", Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	f.advanceOrder(t, order.ID, entities.OrderReceived)
+
+	// Assert
+	events, _ := f.outboxGateway.ListPending(ctx, time.Now().Add(time.Second), 100)
+	if len(events) != 2 {
+		t.Fatalf("Expected order.created and order.status_changed, got %d events", len(events))
+	}
+	for _, event := range events {
+		if strings.Contains(string(event.Payload), "cpf") || strings.Contains(string(event.Payload), "456.789") {
+			t.Errorf("Expected no CPF in %s, got %s", event.EventType, event.Payload)
+		}
+	}
+}
+
+type failingOutboxGateway struct {
+	output.OutboxGateway
+}
+
+func (failingOutboxGateway) Append(context.Context, *entities.OutboxEvent) error {
+	return errors.New("outbox_events is locked")
+}
+
+func TestOrderUseCase_UpdateOrderStatus_FailsWhenEventIsNotStored(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	useCase := NewOrderUseCase(f.orderGateway, f.orderItemGateway, f.productGateway, f.paymentGateway, failingOutboxGateway{}, f.promotionGateway, f.discountGateway, f.taxGateway, f.customerGateway, f.loyaltyGateway, memory.NewTransactionManager(), 0, time.UTC, testLoyaltyProgram, services.NewPricingService(services.PricingRules{}), logging.Discard(), telemetry.Discard())
+
+	// Act
+	_, err := useCase.UpdateOrderStatus(ctx, order.ID, &dto.UpdateOrderStatusRequest{Status: string(entities.OrderReceived)})
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "order.status_changed") {
+		t.Errorf("Expected the outbox error to fail the update, got %v", err)
+	}
+}
@@ -1,153 +1,518 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
 )
 
 type paymentUseCase struct {
-	paymentGateway output.PaymentGateway
-	orderGateway   output.OrderGateway
+	paymentGateway   output.PaymentGateway
+	orderGateway     output.OrderGateway
+	orderItemGateway output.OrderItemGateway
+	outboxGateway    output.OutboxGateway
+	transactions     output.TransactionManager
+	paymentTTL       time.Duration
+	logger           *slog.Logger
+	metrics          output.Metrics
 }
 
-func NewPaymentUseCase(paymentGateway output.PaymentGateway, orderGateway output.OrderGateway) input.PaymentUseCase {
+func NewPaymentUseCase(
+	paymentGateway output.PaymentGateway,
+	orderGateway output.OrderGateway,
+	orderItemGateway output.OrderItemGateway,
+	outboxGateway output.OutboxGateway,
+	transactions output.TransactionManager,
+	paymentTTL time.Duration,
+	logger *slog.Logger,
+	metrics output.Metrics,
+) input.PaymentUseCase {
 	return &paymentUseCase{
-		paymentGateway: paymentGateway,
-		orderGateway:   orderGateway,
+		paymentGateway:   paymentGateway,
+		orderGateway:     orderGateway,
+		orderItemGateway: orderItemGateway,
+		outboxGateway:    outboxGateway,
+		transactions:     transactions,
+		paymentTTL:       paymentTTL,
+		logger:           logger,
+		metrics:          metrics,
 	}
 }
 
-func (uc *paymentUseCase) CreatePayment(request *dto.CreatePaymentRequest) (*dto.PaymentResponse, error) {
-	// Validate if order exists
-	order, err := uc.orderGateway.GetByID(request.OrderID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get order: %w", err)
-	}
-	if order == nil {
-		return nil, errors.New("order not found")
-	}
+func (uc *paymentUseCase) CreatePayment(ctx context.Context, request *dto.CreatePaymentRequest) (*dto.PaymentResponse, error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.CreatePayment")
+	defer span.End()
 
-	// Check if payment already exists for this order
-	existingPayment, _ := uc.paymentGateway.GetByOrderID(request.OrderID)
-	if existingPayment != nil {
-		// Return existing payment instead of creating a new one
-		return &dto.PaymentResponse{
-			ID:            existingPayment.ID,
-			OrderID:       existingPayment.OrderID,
-			Amount:        existingPayment.Amount,
-			Status:        string(existingPayment.Status),
-			PaymentMethod: existingPayment.PaymentMethod,
-			TransactionID: existingPayment.TransactionID,
-			CreatedAt:     existingPayment.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:     existingPayment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-		}, nil
-	}
+	// Checking the balance and creating the part happen together, with the
+	// order locked so concurrent parts are checked one at a time and the
+	// active parts never cover more than the order total
+	var payment *entities.Payment
+	created := false
+	err := uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := uc.orderGateway.GetByIDForUpdate(ctx, request.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		if order == nil {
+			return errs.NotFound(errs.CodeOrderNotFound, "order not found")
+		}
+		if order.Status != entities.OrderAwaitingPayment {
+			return errs.Conflict(errs.CodeOrderNotAwaitingPayment, fmt.Sprintf("order is %s, not awaiting payment", order.Status))
+		}
+
+		if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+			return fmt.Errorf("failed to get order items: %w", err)
+		}
+		attempts, err := uc.paymentGateway.ListByOrderID(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list payments: %w", err)
+		}
 
-	// Create payment only if it doesn't exist
-	payment := entities.NewPayment(request.OrderID, request.Amount, request.PaymentMethod)
+		// A retried request gets its active attempt back; a share is only
+		// attempted again once its previous attempt was rejected or canceled
+		for _, attempt := range attempts {
+			if attempt.IsActive() && attempt.Payer == request.Payer && entities.SumsTo(attempt.Amount, request.Amount) {
+				payment = attempt
+				return nil
+			}
+		}
 
-	err = uc.paymentGateway.Create(payment)
+		summary := entities.SummarizePayments(order.CalculateTotal(), attempts)
+		if request.Amount > summary.Outstanding() {
+			return errs.Conflict(errs.CodePaymentExceedsBalance,
+				fmt.Sprintf("payment of %.2f exceeds the %.2f not yet covered by other payments", request.Amount, summary.Outstanding()))
+		}
+
+		payment = entities.NewPayment(request.OrderID, request.Amount, request.PaymentMethod)
+		payment.Payer = request.Payer
+		payment.ExpireAfter(uc.paymentTTL)
+		if err := uc.paymentGateway.Create(ctx, payment); err != nil {
+			return fmt.Errorf("failed to create payment: %w", err)
+		}
+		created = true
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create payment: %w", err)
+		return nil, err
 	}
 
-	return &dto.PaymentResponse{
-		ID:            payment.ID,
-		OrderID:       payment.OrderID,
-		Amount:        payment.Amount,
-		Status:        string(payment.Status),
-		PaymentMethod: payment.PaymentMethod,
-		TransactionID: payment.TransactionID,
-		CreatedAt:     payment.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:     payment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-	}, nil
+	if created {
+		uc.logger.InfoContext(ctx, "payment attempt created",
+			"payment_id", payment.ID,
+			"order_id", payment.OrderID,
+			"payment_method", payment.PaymentMethod,
+			"amount", payment.Amount,
+		)
+	}
+
+	return uc.buildPaymentResponse(payment), nil
 }
 
-func (uc *paymentUseCase) GetPaymentStatus(orderID uint64) (*dto.PaymentStatusResponse, error) {
-	payment, err := uc.paymentGateway.GetByOrderID(orderID)
+func (uc *paymentUseCase) GetPaymentStatus(ctx context.Context, orderID uint64) (*dto.PaymentStatusResponse, error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.GetPaymentStatus")
+	defer span.End()
+
+	attempts, err := uc.paymentGateway.ListByOrderID(ctx, orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
-	if payment == nil {
-		return nil, errors.New("payment not found for this order")
+	if len(attempts) == 0 {
+		return nil, errs.NotFound(errs.CodePaymentNotFound, "payment not found for this order")
 	}
+	payment := attempts[len(attempts)-1]
+
+	var total float32
+	order, err := uc.orderGateway.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order != nil {
+		if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+			return nil, fmt.Errorf("failed to get order items: %w", err)
+		}
+		total = order.CalculateTotal()
+	}
+	summary := entities.SummarizePayments(total, attempts)
 
 	return &dto.PaymentStatusResponse{
 		ID:            payment.ID,
 		OrderID:       payment.OrderID,
 		Status:        string(payment.Status),
 		Amount:        payment.Amount,
+		Payer:         payment.Payer,
 		TransactionID: payment.TransactionID,
+		ExpiresAt:     formatExpiresAt(payment),
+		OrderTotal:    summary.Total,
+		AmountPaid:    summary.Paid,
+		AmountDue:     summary.Due(),
 		UpdatedAt:     payment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}, nil
 }
 
-func (uc *paymentUseCase) GetPaymentByTransactionID(transactionID string) (*dto.PaymentResponse, error) {
-	payment, err := uc.paymentGateway.GetByTransactionID(transactionID)
+func (uc *paymentUseCase) GetPaymentAttempts(ctx context.Context, orderID uint64) ([]*dto.PaymentResponse, error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.GetPaymentAttempts")
+	defer span.End()
+
+	order, err := uc.orderGateway.GetByID(ctx, orderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get payment: %w", err)
+		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
-	if payment == nil {
-		return nil, errors.New("payment not found")
+	if order == nil {
+		return nil, errs.NotFound(errs.CodeOrderNotFound, "order not found")
 	}
 
-	return &dto.PaymentResponse{
-		ID:            payment.ID,
-		OrderID:       payment.OrderID,
-		Amount:        payment.Amount,
-		Status:        string(payment.Status),
-		PaymentMethod: payment.PaymentMethod,
-		TransactionID: payment.TransactionID,
-		CreatedAt:     payment.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:     payment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-	}, nil
+	payments, err := uc.paymentGateway.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	responses := make([]*dto.PaymentResponse, 0, len(payments))
+	for _, payment := range payments {
+		responses = append(responses, uc.buildPaymentResponse(payment))
+	}
+
+	return responses, nil
 }
 
-func (uc *paymentUseCase) ProcessWebhookPayment(request *dto.WebhookPaymentRequest) error {
-	// Find payment by order ID
-	payment, err := uc.paymentGateway.GetByOrderID(request.OrderID)
+func (uc *paymentUseCase) GetPaymentByTransactionID(ctx context.Context, transactionID string) (*dto.PaymentResponse, error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.GetPaymentByTransactionID")
+	defer span.End()
+
+	payment, err := uc.paymentGateway.GetByTransactionID(ctx, transactionID)
 	if err != nil {
-		return fmt.Errorf("failed to get payment: %w", err)
+		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
 	if payment == nil {
-		return errors.New("payment not found for this order")
+		return nil, errs.NotFound(errs.CodePaymentNotFound, "payment not found")
 	}
 
+	return uc.buildPaymentResponse(payment), nil
+}
+
+func (uc *paymentUseCase) ProcessWebhookPayment(ctx context.Context, request *dto.WebhookPaymentRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.ProcessWebhookPayment")
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		uc.metrics.WebhookProcessed(time.Since(start), err != nil)
+	}()
+
 	// Validate payment status
 	status := entities.PaymentStatus(request.Status)
 	if status != entities.PaymentStatusApproved &&
 		status != entities.PaymentStatusRejected &&
-		status != entities.PaymentStatusCanceled {
-		return errors.New("invalid payment status received")
+		status != entities.PaymentStatusCanceled &&
+		status != entities.PaymentStatusRefunded {
+		uc.logger.WarnContext(ctx, "webhook with invalid payment status", "order_id", request.OrderID, "status", request.Status)
+		return errs.Validation(errs.CodeInvalidPaymentStatus, "invalid payment status received")
+	}
+
+	// The payment, the order it pays for and their events change together.
+	// The order is locked before the payment is read, so every check below
+	// sees what an expiry or another webhook for the order committed; without
+	// it, two parts approved at the same time would each count the other as
+	// pending and leave the order awaiting payment.
+	var payment *entities.Payment
+	var receivedOrder *entities.Order
+	duplicate := false
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := uc.orderGateway.GetByIDForUpdate(ctx, request.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order for status update: %w", err)
+		}
+		payment, err = uc.webhookPayment(ctx, request)
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment == nil {
+			return errs.NotFound(errs.CodePaymentNotFound, "payment not found for this order")
+		}
+
+		// The order of an expired payment was cancelled; the provider has to
+		// refund a late approval
+		if payment.IsExpired() {
+			uc.logger.WarnContext(ctx, "webhook for expired payment", "order_id", request.OrderID, "status", request.Status)
+			return errs.Conflict(errs.CodePaymentExpired, "payment expired and its order was cancelled")
+		}
+
+		// Providers redeliver webhooks; a repeat must not emit events again
+		if payment.Status == status && payment.TransactionID == request.TransactionID {
+			duplicate = true
+			return nil
+		}
+
+		// Update payment status; only an approved payment can be refunded,
+		// and only a pending one approved, rejected or canceled
+		switch {
+		case status == entities.PaymentStatusRefunded:
+			if !payment.Refund() {
+				return errs.InvalidTransition(errs.CodePaymentNotRefundable, fmt.Sprintf("payment is %s, only approved payments can be refunded", payment.Status))
+			}
+		case !payment.CanTransitionTo(status):
+			uc.logger.WarnContext(ctx, "webhook with invalid payment transition", "payment_id", payment.ID, "from", payment.Status, "to", status)
+			return errs.InvalidTransition(errs.CodeInvalidPaymentTransition, fmt.Sprintf("payment is %s and cannot become %s", payment.Status, status))
+		default:
+			payment.UpdateStatus(status, request.TransactionID)
+		}
+
+		if err := uc.paymentGateway.Update(ctx, payment); err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		if err := appendPaymentEvent(ctx, uc.outboxGateway, payment); err != nil {
+			return err
+		}
+
+		// The order is received once the approved parts cover its total
+		if !payment.IsApproved() {
+			return nil
+		}
+		if order == nil || order.Status != entities.OrderAwaitingPayment {
+			return nil
+		}
+		if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+			return fmt.Errorf("failed to get order items: %w", err)
+		}
+		attempts, err := uc.paymentGateway.ListByOrderID(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list payments: %w", err)
+		}
+		if !entities.SummarizePayments(order.CalculateTotal(), attempts).IsCovered() {
+			return nil
+		}
+
+		previous := order.Status
+		order.UpdateStatus(entities.OrderReceived)
+		if err := uc.orderGateway.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		receivedOrder = order
+		return appendOrderEvent(ctx, uc.outboxGateway, entities.EventOrderStatusChanged, order, previous)
+	})
+	if err != nil {
+		return err
+	}
+	if duplicate {
+		uc.logger.DebugContext(ctx, "duplicate payment webhook", "payment_id", payment.ID, "status", status)
+		return nil
+	}
+
+	uc.logger.InfoContext(ctx, "payment status updated",
+		"payment_id", payment.ID,
+		"order_id", payment.OrderID,
+		"status", payment.Status,
+		"transaction_id", payment.TransactionID,
+	)
+	uc.metrics.PaymentSettled(payment.Status)
+	if receivedOrder != nil {
+		uc.metrics.OrderStatusChanged(receivedOrder.Status)
 	}
 
-	// Update payment status
-	payment.UpdateStatus(status, request.TransactionID)
+	return nil
+}
 
-	err = uc.paymentGateway.Update(payment)
+// webhookPayment finds the attempt a webhook is about: the one that already
+// has its transaction ID, else the oldest active part for the same amount,
+// else the order's latest attempt. A late webhook for an earlier attempt
+// thus never lands on an active one.
+func (uc *paymentUseCase) webhookPayment(ctx context.Context, request *dto.WebhookPaymentRequest) (*entities.Payment, error) {
+	if request.TransactionID != "" {
+		payment, err := uc.paymentGateway.GetByTransactionID(ctx, request.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		if payment != nil && payment.OrderID == request.OrderID {
+			return payment, nil
+		}
+	}
+
+	attempts, err := uc.paymentGateway.ListByOrderID(ctx, request.OrderID)
+	if err != nil || len(attempts) == 0 {
+		return nil, err
+	}
+	for _, attempt := range attempts {
+		if attempt.IsActive() && entities.SumsTo(attempt.Amount, request.Amount) {
+			return attempt, nil
+		}
+	}
+	return attempts[len(attempts)-1], nil
+}
+
+func (uc *paymentUseCase) RefundPayment(ctx context.Context, paymentID uint64) (*dto.PaymentResponse, error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.RefundPayment")
+	defer span.End()
+
+	// Read first only to find the order to lock; the checks run on the copy
+	// read again under the lock
+	payment, err := uc.paymentGateway.GetByID(ctx, paymentID)
 	if err != nil {
-		return fmt.Errorf("failed to update payment: %w", err)
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment == nil {
+		return nil, errs.NotFound(errs.CodePaymentNotFound, "payment not found")
 	}
 
-	// If payment is approved, update order status
-	if payment.IsApproved() {
-		order, err := uc.orderGateway.GetByID(payment.OrderID)
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.orderGateway.GetByIDForUpdate(ctx, payment.OrderID); err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		var err error
+		payment, err = uc.paymentGateway.GetByID(ctx, paymentID)
 		if err != nil {
-			return fmt.Errorf("failed to get order for status update: %w", err)
+			return fmt.Errorf("failed to get payment: %w", err)
 		}
-		if order != nil {
-			order.UpdateStatus(entities.OrderReceived)
-			err = uc.orderGateway.Update(order)
-			if err != nil {
-				return fmt.Errorf("failed to update order status: %w", err)
-			}
+		if payment == nil {
+			return errs.NotFound(errs.CodePaymentNotFound, "payment not found")
+		}
+		if !payment.Refund() {
+			return errs.InvalidTransition(errs.CodePaymentNotRefundable, fmt.Sprintf("payment is %s, only approved payments can be refunded", payment.Status))
+		}
+
+		if err := uc.paymentGateway.Update(ctx, payment); err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
 		}
+		return appendPaymentEvent(ctx, uc.outboxGateway, payment)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	uc.logger.InfoContext(ctx, "payment refunded",
+		"payment_id", payment.ID,
+		"order_id", payment.OrderID,
+		"amount", payment.Amount,
+		"transaction_id", payment.TransactionID,
+	)
+	uc.metrics.PaymentSettled(payment.Status)
+
+	return uc.buildPaymentResponse(payment), nil
+}
+
+func (uc *paymentUseCase) ExpirePayments(ctx context.Context, now time.Time, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "PaymentUseCase.ExpirePayments")
+	defer span.End()
+
+	payments, err := uc.paymentGateway.ListExpired(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired payments: %w", err)
+	}
+
+	expired := 0
+	for _, payment := range payments {
+		ok, err := uc.expirePayment(ctx, payment, now)
+		if err != nil {
+			// Left pending, so the next run retries it
+			uc.logger.ErrorContext(ctx, "failed to expire payment", "payment_id", payment.ID, "order_id", payment.OrderID, "error", err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// expirePayment expires one listed payment and cancels its order if it is
+// still awaiting payment. It reports false when the payment was settled since
+// it was listed.
+func (uc *paymentUseCase) expirePayment(ctx context.Context, listed *entities.Payment, now time.Time) (bool, error) {
+	var payment *entities.Payment
+	var cancelledOrder *entities.Order
+
+	err := uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Locked before the payment is read again, as webhooks do, so an
+		// approval of another part is either seen here or sees the expiry
+		order, err := uc.orderGateway.GetByIDForUpdate(ctx, listed.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get order for cancellation: %w", err)
+		}
+		payment, err = uc.paymentGateway.GetByID(ctx, listed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment == nil || !payment.IsDue(now) {
+			payment = nil
+			return nil
+		}
+
+		payment.Expire()
+		if err := uc.paymentGateway.Update(ctx, payment); err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		if err := appendPaymentEvent(ctx, uc.outboxGateway, payment); err != nil {
+			return err
+		}
+
+		if order == nil || order.Status != entities.OrderAwaitingPayment {
+			return nil
+		}
+		// Once part of a split bill is paid, the order waits for the missing
+		// share to be paid again instead of being cancelled
+		attempts, err := uc.paymentGateway.ListByOrderID(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list payments: %w", err)
+		}
+		if entities.SummarizePayments(0, attempts).Paid > 0 {
+			return nil
+		}
+		if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+			return fmt.Errorf("failed to get order items: %w", err)
+		}
+
+		previous := order.Status
+		order.UpdateStatus(entities.OrderCancelled)
+		if err := uc.orderGateway.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to cancel order: %w", err)
+		}
+		cancelledOrder = order
+		return appendOrderEvent(ctx, uc.outboxGateway, entities.EventOrderStatusChanged, order, previous)
+	})
+	if err != nil || payment == nil {
+		return false, err
+	}
+
+	uc.logger.InfoContext(ctx, "payment expired",
+		"payment_id", payment.ID,
+		"order_id", payment.OrderID,
+		"expires_at", payment.ExpiresAt,
+		"order_cancelled", cancelledOrder != nil,
+	)
+	uc.metrics.PaymentSettled(payment.Status)
+	if cancelledOrder != nil {
+		uc.metrics.OrderStatusChanged(cancelledOrder.Status)
+	}
+
+	return true, nil
+}
+
+func (uc *paymentUseCase) buildPaymentResponse(payment *entities.Payment) *dto.PaymentResponse {
+	return &dto.PaymentResponse{
+		ID:            payment.ID,
+		OrderID:       payment.OrderID,
+		Amount:        payment.Amount,
+		Status:        string(payment.Status),
+		PaymentMethod: payment.PaymentMethod,
+		Payer:         payment.Payer,
+		TransactionID: payment.TransactionID,
+		ExpiresAt:     formatExpiresAt(payment),
+		CreatedAt:     payment.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     payment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// formatExpiresAt renders the expiry in UTC, so a totem can count down to it
+// whatever the server's time zone; empty when the payment never expires
+func formatExpiresAt(payment *entities.Payment) string {
+	if payment.ExpiresAt == nil {
+		return ""
+	}
+	return payment.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z")
 }
@@ -0,0 +1,699 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+)
+
+func TestPaymentUseCase_ProcessWebhookPayment_Approved(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	outboxGateway := memory.NewOutboxGateway(store)
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), outboxGateway, memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	paymentGateway.Create(ctx, entities.NewPayment(order.ID, 30, "qr_code"))
+
+	request := &dto.WebhookPaymentRequest{
+		TransactionID: "tx-1",
+		OrderID:       order.ID,
+		Status:        string(entities.PaymentStatusApproved),
+		Amount:        30,
+	}
+
+	// Act
+	err := useCase.ProcessWebhookPayment(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updatedOrder, _ := orderGateway.GetByID(ctx, order.ID)
+	if updatedOrder.Status != entities.OrderReceived {
+		t.Errorf("Expected order status received, got %s", updatedOrder.Status)
+	}
+
+	if got := pendingEvents(t, outboxGateway); strings.Join(got, ",") != "payment.approved,order.status_changed" {
+		t.Errorf("Expected payment.approved and order.status_changed events, got %v", got)
+	}
+
+	status, err := useCase.GetPaymentStatus(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if status.Status != string(entities.PaymentStatusApproved) || status.TransactionID != "tx-1" {
+		t.Errorf("Unexpected payment status %+v", status)
+	}
+}
+
+func TestPaymentUseCase_ProcessWebhookPayment_Rejected(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	paymentGateway.Create(ctx, entities.NewPayment(order.ID, 30, "qr_code"))
+
+	// Act
+	err := useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{
+		TransactionID: "tx-2",
+		OrderID:       order.ID,
+		Status:        string(entities.PaymentStatusRejected),
+		Amount:        30,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	updatedOrder, _ := orderGateway.GetByID(ctx, order.ID)
+	if updatedOrder.Status != entities.OrderAwaitingPayment {
+		t.Errorf("Expected order to stay awaiting_payment, got %s", updatedOrder.Status)
+	}
+}
+
+func TestPaymentUseCase_GetPaymentStatus_NotFound(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	useCase := NewPaymentUseCase(memory.NewPaymentGateway(store), memory.NewOrderGateway(store), memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	// Act
+	response, err := useCase.GetPaymentStatus(ctx, 7)
+
+	// Assert
+	if err == nil || err.Error() != "payment not found for this order" {
+		t.Errorf("Expected 'payment not found for this order' error, got %v", err)
+	}
+
+	if errs.CodeOf(err) != errs.CodePaymentNotFound {
+		t.Errorf("Expected code %s, got %q", errs.CodePaymentNotFound, errs.CodeOf(err))
+	}
+
+	if response != nil {
+		t.Error("Expected nil response when payment does not exist")
+	}
+}
+
+type recordingMetrics struct {
+	output.Metrics
+	settled  []entities.PaymentStatus
+	webhooks []bool
+}
+
+func (r *recordingMetrics) PaymentSettled(status entities.PaymentStatus) {
+	r.settled = append(r.settled, status)
+}
+
+func (r *recordingMetrics) OrderStatusChanged(entities.OrderStatus) {}
+
+func (r *recordingMetrics) WebhookProcessed(_ time.Duration, failed bool) {
+	r.webhooks = append(r.webhooks, failed)
+}
+
+func TestPaymentUseCase_ProcessWebhookPayment_RecordsMetrics(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	metrics := &recordingMetrics{}
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), 0, logging.Discard(), metrics)
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	paymentGateway.Create(ctx, entities.NewPayment(order.ID, 30, "qr_code"))
+
+	// Act
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{OrderID: order.ID, Status: string(entities.PaymentStatusApproved)})
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{OrderID: order.ID + 1, Status: string(entities.PaymentStatusApproved)})
+
+	// Assert
+	if len(metrics.settled) != 1 || metrics.settled[0] != entities.PaymentStatusApproved {
+		t.Errorf("Expected one approved settlement, got %v", metrics.settled)
+	}
+
+	if len(metrics.webhooks) != 2 || metrics.webhooks[0] || !metrics.webhooks[1] {
+		t.Errorf("Expected a successful then a failed webhook, got %v", metrics.webhooks)
+	}
+}
+
+func TestPaymentUseCase_CreatePayment_ExposesExpiry(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	orderItemGateway := memory.NewOrderItemGateway(store)
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, orderItemGateway, memory.NewOutboxGateway(store), memory.NewTransactionManager(), 15*time.Minute, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	orderItemGateway.Create(ctx, entities.NewOrderItem(order.ID, 1, 1, 30))
+
+	// Act
+	response, err := useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 30, PaymentMethod: "qr_code"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	payment, _ := paymentGateway.GetByID(ctx, response.ID)
+	if payment.ExpiresAt == nil || !payment.ExpiresAt.Equal(payment.CreatedAt.Add(15*time.Minute)) {
+		t.Fatalf("Expected payment to expire 15m after creation, got %v", payment.ExpiresAt)
+	}
+	if want := payment.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"); response.ExpiresAt != want {
+		t.Errorf("Expected expires_at %s, got %q", want, response.ExpiresAt)
+	}
+
+	status, _ := useCase.GetPaymentStatus(ctx, order.ID)
+	if status.ExpiresAt != response.ExpiresAt {
+		t.Errorf("Expected payment status to expose expires_at %s, got %q", response.ExpiresAt, status.ExpiresAt)
+	}
+}
+
+func TestPaymentUseCase_ExpirePayments(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	outboxGateway := memory.NewOutboxGateway(store)
+	metrics := &recordingMetrics{}
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), outboxGateway, memory.NewTransactionManager(), 15*time.Minute, logging.Discard(), metrics)
+
+	createPayment := func(age time.Duration) (*entities.Order, *entities.Payment) {
+		order := entities.NewOrder(0, "")
+		orderGateway.Create(ctx, order)
+		payment := entities.NewPayment(order.ID, 30, "qr_code")
+		payment.CreatedAt = payment.CreatedAt.Add(-age)
+		payment.ExpireAfter(15 * time.Minute)
+		paymentGateway.Create(ctx, payment)
+		return order, payment
+	}
+	staleOrder, stalePayment := createPayment(20 * time.Minute)
+	freshOrder, freshPayment := createPayment(time.Minute)
+
+	// Act
+	expired, err := useCase.ExpirePayments(ctx, time.Now(), 10)
+
+	// Assert
+	if err != nil || expired != 1 {
+		t.Fatalf("Expected 1 expired payment, got %d, %v", expired, err)
+	}
+
+	if payment, _ := paymentGateway.GetByID(ctx, stalePayment.ID); !payment.IsExpired() {
+		t.Errorf("Expected stale payment to be expired, got %s", payment.Status)
+	}
+	if order, _ := orderGateway.GetByID(ctx, staleOrder.ID); order.Status != entities.OrderCancelled {
+		t.Errorf("Expected stale order to be cancelled, got %s", order.Status)
+	}
+	if payment, _ := paymentGateway.GetByID(ctx, freshPayment.ID); !payment.IsPending() {
+		t.Errorf("Expected fresh payment to stay pending, got %s", payment.Status)
+	}
+	if order, _ := orderGateway.GetByID(ctx, freshOrder.ID); order.Status != entities.OrderAwaitingPayment {
+		t.Errorf("Expected fresh order to stay awaiting_payment, got %s", order.Status)
+	}
+
+	if got := pendingEvents(t, outboxGateway); strings.Join(got, ",") != "payment.expired,order.status_changed" {
+		t.Errorf("Expected payment.expired and order.status_changed events, got %v", got)
+	}
+	if len(metrics.settled) != 1 || metrics.settled[0] != entities.PaymentStatusExpired {
+		t.Errorf("Expected one expired payment metric, got %v", metrics.settled)
+	}
+
+	// Act
+	expired, err = useCase.ExpirePayments(ctx, time.Now(), 10)
+
+	// Assert
+	if err != nil || expired != 0 {
+		t.Errorf("Expected nothing left to expire, got %d, %v", expired, err)
+	}
+}
+
+func TestPaymentUseCase_ProcessWebhookPayment_ExpiredPayment(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), time.Minute, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	payment := entities.NewPayment(order.ID, 30, "qr_code")
+	payment.ExpireAfter(time.Minute)
+	paymentGateway.Create(ctx, payment)
+	useCase.ExpirePayments(ctx, time.Now().Add(time.Hour), 10)
+
+	// Act
+	err := useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{
+		TransactionID: "tx-late",
+		OrderID:       order.ID,
+		Status:        string(entities.PaymentStatusApproved),
+		Amount:        30,
+	})
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodePaymentExpired {
+		t.Fatalf("Expected code %s, got %v", errs.CodePaymentExpired, err)
+	}
+
+	if updatedOrder, _ := orderGateway.GetByID(ctx, order.ID); updatedOrder.Status != entities.OrderCancelled {
+		t.Errorf("Expected order to stay cancelled, got %s", updatedOrder.Status)
+	}
+	if updated, _ := paymentGateway.GetByID(ctx, payment.ID); !updated.IsExpired() || updated.TransactionID != "" {
+		t.Errorf("Expected payment to stay expired, got %+v", updated)
+	}
+}
+
+func TestPaymentUseCase_CreatePayment_RetriesAfterRejection(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	orderItemGateway := memory.NewOrderItemGateway(store)
+	outboxGateway := memory.NewOutboxGateway(store)
+	useCase := NewPaymentUseCase(memory.NewPaymentGateway(store), orderGateway, orderItemGateway, outboxGateway, memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	orderItemGateway.Create(ctx, entities.NewOrderItem(order.ID, 1, 1, 30))
+	request := &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 30, PaymentMethod: "credit_card"}
+	first, _ := useCase.CreatePayment(ctx, request)
+
+	// Act
+	repeated, err := useCase.CreatePayment(ctx, request)
+
+	// Assert
+	if err != nil || repeated.ID != first.ID {
+		t.Fatalf("Expected the active attempt %d back, got %+v, %v", first.ID, repeated, err)
+	}
+
+	// Arrange
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-declined", OrderID: order.ID, Status: string(entities.PaymentStatusRejected), Amount: 30})
+
+	// Act
+	retry, err := useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 30, PaymentMethod: "qr_code"})
+
+	// Assert
+	if err != nil || retry.ID == first.ID || retry.Status != string(entities.PaymentStatusPending) {
+		t.Fatalf("Expected a new pending attempt, got %+v, %v", retry, err)
+	}
+	if updatedOrder, _ := orderGateway.GetByID(ctx, order.ID); updatedOrder.Status != entities.OrderAwaitingPayment {
+		t.Errorf("Expected order to stay awaiting_payment, got %s", updatedOrder.Status)
+	}
+	if status, _ := useCase.GetPaymentStatus(ctx, order.ID); status.ID != retry.ID {
+		t.Errorf("Expected status of the latest attempt %d, got %+v", retry.ID, status)
+	}
+
+	// Act
+	err = useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-declined", OrderID: order.ID, Status: string(entities.PaymentStatusRejected), Amount: 30})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected a redelivered webhook to be accepted, got %v", err)
+	}
+	if status, _ := useCase.GetPaymentStatus(ctx, order.ID); status.Status != string(entities.PaymentStatusPending) {
+		t.Errorf("Expected the redelivery to leave the active attempt pending, got %s", status.Status)
+	}
+
+	// Act
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-paid", OrderID: order.ID, Status: string(entities.PaymentStatusApproved), Amount: 30})
+	_, err = useCase.CreatePayment(ctx, request)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeOrderNotAwaitingPayment {
+		t.Errorf("Expected code %s once paid, got %v", errs.CodeOrderNotAwaitingPayment, err)
+	}
+
+	attempts, err := useCase.GetPaymentAttempts(ctx, order.ID)
+	if err != nil || len(attempts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d, %v", len(attempts), err)
+	}
+	if attempts[0].Status != string(entities.PaymentStatusRejected) || attempts[0].PaymentMethod != "credit_card" ||
+		attempts[1].Status != string(entities.PaymentStatusApproved) || attempts[1].TransactionID != "tx-paid" {
+		t.Errorf("Unexpected attempts %+v, %+v", attempts[0], attempts[1])
+	}
+
+	if got := pendingEvents(t, outboxGateway); strings.Join(got, ",") != "payment.rejected,payment.approved,order.status_changed" {
+		t.Errorf("Expected one event per settled attempt, got %v", got)
+	}
+}
+
+func TestPaymentUseCase_GetPaymentAttempts_OrderNotFound(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	useCase := NewPaymentUseCase(memory.NewPaymentGateway(store), memory.NewOrderGateway(store), memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	// Act
+	attempts, err := useCase.GetPaymentAttempts(ctx, 7)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeOrderNotFound {
+		t.Errorf("Expected code %s, got %v", errs.CodeOrderNotFound, err)
+	}
+	if attempts != nil {
+		t.Error("Expected nil attempts")
+	}
+}
+
+func TestPaymentUseCase_SplitPayments_ReceiveOrderOnceCovered(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	orderItemGateway := memory.NewOrderItemGateway(store)
+	outboxGateway := memory.NewOutboxGateway(store)
+	useCase := NewPaymentUseCase(memory.NewPaymentGateway(store), orderGateway, orderItemGateway, outboxGateway, memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	orderItemGateway.Create(ctx, entities.NewOrderItem(order.ID, 1, 2, 25))
+	ana, _ := useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 20, PaymentMethod: "credit_card", Payer: "Ana"})
+	bruno, _ := useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 30, PaymentMethod: "pix", Payer: "Bruno"})
+
+	// Act
+	_, err := useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 5, PaymentMethod: "pix", Payer: "Carla"})
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodePaymentExceedsBalance {
+		t.Fatalf("Expected code %s, got %v", errs.CodePaymentExceedsBalance, err)
+	}
+
+	// Act
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-ana", OrderID: order.ID, Status: string(entities.PaymentStatusApproved), Amount: 20})
+
+	// Assert
+	if updated, _ := orderGateway.GetByID(ctx, order.ID); updated.Status != entities.OrderAwaitingPayment {
+		t.Errorf("Expected order to await the remaining share, got %s", updated.Status)
+	}
+	status, _ := useCase.GetPaymentStatus(ctx, order.ID)
+	if status.ID != bruno.ID || status.OrderTotal != 50 || status.AmountPaid != 20 || status.AmountDue != 30 {
+		t.Errorf("Unexpected payment status %+v", status)
+	}
+
+	// Act
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-bruno", OrderID: order.ID, Status: string(entities.PaymentStatusApproved), Amount: 30})
+
+	// Assert
+	if updated, _ := orderGateway.GetByID(ctx, order.ID); updated.Status != entities.OrderReceived {
+		t.Errorf("Expected order to be received once covered, got %s", updated.Status)
+	}
+	attempts, _ := useCase.GetPaymentAttempts(ctx, order.ID)
+	if len(attempts) != 2 || attempts[0].ID != ana.ID || attempts[0].TransactionID != "tx-ana" || attempts[1].TransactionID != "tx-bruno" {
+		t.Errorf("Expected each webhook to settle its own share, got %+v", attempts)
+	}
+	if got := pendingEvents(t, outboxGateway); strings.Join(got, ",") != "payment.approved,payment.approved,order.status_changed" {
+		t.Errorf("Expected one approval per share and one status change, got %v", got)
+	}
+}
+
+// rendezvousPayments holds each payment update until another one was made
+// or a short wait passes, so two webhooks interleave the way concurrent
+// requests can: both parts approved before either webhook counts them
+type rendezvousPayments struct {
+	output.PaymentGateway
+	updates atomic.Int32
+	both    chan struct{}
+}
+
+func (g *rendezvousPayments) Update(ctx context.Context, payment *entities.Payment) error {
+	if err := g.PaymentGateway.Update(ctx, payment); err != nil {
+		return err
+	}
+	if g.updates.Add(1) == 2 {
+		close(g.both)
+	}
+	select {
+	case <-g.both:
+	case <-time.After(100 * time.Millisecond):
+	}
+	return nil
+}
+
+func TestPaymentUseCase_SplitPayments_ConcurrentApprovals(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	orderItemGateway := memory.NewOrderItemGateway(store)
+	outboxGateway := memory.NewOutboxGateway(store)
+	paymentGateway := &rendezvousPayments{PaymentGateway: memory.NewPaymentGateway(store), both: make(chan struct{})}
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, orderItemGateway, outboxGateway, memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	orderItemGateway.Create(ctx, entities.NewOrderItem(order.ID, 1, 2, 20))
+	useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 20, PaymentMethod: "credit_card", Payer: "Ana"})
+	useCase.CreatePayment(ctx, &dto.CreatePaymentRequest{OrderID: order.ID, Amount: 20, PaymentMethod: "pix", Payer: "Bruno"})
+
+	// Act
+	var wg sync.WaitGroup
+	webhookErrs := make([]error, 2)
+	for i, transactionID := range []string{"tx-ana", "tx-bruno"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			webhookErrs[i] = useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: transactionID, OrderID: order.ID, Status: string(entities.PaymentStatusApproved), Amount: 20})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if webhookErrs[0] != nil || webhookErrs[1] != nil {
+		t.Fatalf("Expected both webhooks to succeed, got %v", webhookErrs)
+	}
+	if updated, _ := orderGateway.GetByID(ctx, order.ID); updated.Status != entities.OrderReceived {
+		t.Errorf("Expected order to be received once both parts were approved, got %s", updated.Status)
+	}
+	if got := pendingEvents(t, outboxGateway); strings.Join(got, ",") != "payment.approved,payment.approved,order.status_changed" {
+		t.Errorf("Expected one approval per share and a single status change, got %v", got)
+	}
+}
+
+func TestPaymentUseCase_RefundPayment(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	outboxGateway := memory.NewOutboxGateway(store)
+	metrics := &recordingMetrics{}
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), outboxGateway, memory.NewTransactionManager(), 0, logging.Discard(), metrics)
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	paid := entities.NewPayment(order.ID, 20, "credit_card")
+	paid.UpdateStatus(entities.PaymentStatusApproved, "tx-paid")
+	paymentGateway.Create(ctx, paid)
+	pending := entities.NewPayment(order.ID, 10, "pix")
+	paymentGateway.Create(ctx, pending)
+
+	// Act
+	response, err := useCase.RefundPayment(ctx, paid.ID)
+
+	// Assert
+	if err != nil || response.Status != string(entities.PaymentStatusRefunded) || response.TransactionID != "tx-paid" {
+		t.Fatalf("Expected the payment to be refunded, got %+v, %v", response, err)
+	}
+	if got := pendingEvents(t, outboxGateway); strings.Join(got, ",") != "payment.refunded" {
+		t.Errorf("Expected a payment.refunded event, got %v", got)
+	}
+	if len(metrics.settled) != 1 || metrics.settled[0] != entities.PaymentStatusRefunded {
+		t.Errorf("Expected one refunded payment metric, got %v", metrics.settled)
+	}
+
+	// Act
+	_, again := useCase.RefundPayment(ctx, paid.ID)
+	_, unpaid := useCase.RefundPayment(ctx, pending.ID)
+	_, missing := useCase.RefundPayment(ctx, 99)
+
+	// Assert
+	if errs.CodeOf(again) != errs.CodePaymentNotRefundable || errs.CodeOf(unpaid) != errs.CodePaymentNotRefundable {
+		t.Errorf("Expected code %s, got %v and %v", errs.CodePaymentNotRefundable, again, unpaid)
+	}
+	if errs.CodeOf(missing) != errs.CodePaymentNotFound {
+		t.Errorf("Expected code %s, got %v", errs.CodePaymentNotFound, missing)
+	}
+}
+
+func TestPaymentUseCase_ProcessWebhookPayment_Refunded(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	payment := entities.NewPayment(order.ID, 30, "qr_code")
+	paymentGateway.Create(ctx, payment)
+	refund := &dto.WebhookPaymentRequest{TransactionID: "tx-1", OrderID: order.ID, Status: string(entities.PaymentStatusRefunded), Amount: 30}
+
+	// Act
+	err := useCase.ProcessWebhookPayment(ctx, refund)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodePaymentNotRefundable {
+		t.Fatalf("Expected code %s for a pending payment, got %v", errs.CodePaymentNotRefundable, err)
+	}
+
+	// Arrange
+	useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-1", OrderID: order.ID, Status: string(entities.PaymentStatusApproved), Amount: 30})
+
+	// Act
+	err = useCase.ProcessWebhookPayment(ctx, refund)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated, _ := paymentGateway.GetByID(ctx, payment.ID); !updated.IsRefunded() {
+		t.Errorf("Expected payment to be refunded, got %s", updated.Status)
+	}
+	if updated, _ := orderGateway.GetByID(ctx, order.ID); updated.Status != entities.OrderReceived {
+		t.Errorf("Expected order to keep its status, got %s", updated.Status)
+	}
+}
+
+func TestPaymentUseCase_ExpirePayments_KeepsPartlyPaidOrder(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := memory.NewPaymentGateway(store)
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), time.Minute, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	paid := entities.NewPayment(order.ID, 20, "credit_card")
+	paid.UpdateStatus(entities.PaymentStatusApproved, "tx-paid")
+	paymentGateway.Create(ctx, paid)
+	share := entities.NewPayment(order.ID, 10, "pix")
+	share.ExpireAfter(time.Minute)
+	paymentGateway.Create(ctx, share)
+
+	// Act
+	expired, err := useCase.ExpirePayments(ctx, time.Now().Add(time.Hour), 10)
+
+	// Assert
+	if err != nil || expired != 1 {
+		t.Fatalf("Expected 1 expired payment, got %d, %v", expired, err)
+	}
+	if updated, _ := orderGateway.GetByID(ctx, order.ID); updated.Status != entities.OrderAwaitingPayment {
+		t.Errorf("Expected partly paid order to keep awaiting_payment, got %s", updated.Status)
+	}
+}
+
+func TestPaymentUseCase_ProcessWebhookPayment_InvalidTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		current entities.PaymentStatus
+		webhook entities.PaymentStatus
+	}{
+		{"approved to rejected", entities.PaymentStatusApproved, entities.PaymentStatusRejected},
+		{"approved to canceled", entities.PaymentStatusApproved, entities.PaymentStatusCanceled},
+		{"refunded to approved", entities.PaymentStatusRefunded, entities.PaymentStatusApproved},
+		{"rejected to approved", entities.PaymentStatusRejected, entities.PaymentStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := memory.NewStore()
+			orderGateway := memory.NewOrderGateway(store)
+			paymentGateway := memory.NewPaymentGateway(store)
+			outboxGateway := memory.NewOutboxGateway(store)
+			useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), outboxGateway, memory.NewTransactionManager(), 0, logging.Discard(), telemetry.Discard())
+
+			order := entities.NewOrder(0, "")
+			orderGateway.Create(ctx, order)
+			payment := entities.NewPayment(order.ID, 30, "qr_code")
+			payment.UpdateStatus(tt.current, "tx-1")
+			paymentGateway.Create(ctx, payment)
+
+			// Act
+			err := useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-1", OrderID: order.ID, Status: string(tt.webhook), Amount: 30})
+
+			// Assert
+			if errs.CodeOf(err) != errs.CodeInvalidPaymentTransition {
+				t.Fatalf("Expected code %s, got %v", errs.CodeInvalidPaymentTransition, err)
+			}
+			if updated, _ := paymentGateway.GetByID(ctx, payment.ID); updated.Status != tt.current {
+				t.Errorf("Expected the payment to stay %s, got %s", tt.current, updated.Status)
+			}
+			if got := pendingEvents(t, outboxGateway); len(got) != 0 {
+				t.Errorf("Expected no events, got %v", got)
+			}
+		})
+	}
+}
+
+// expiryDuringWebhook holds the webhook's payment lookup until the expirer
+// finished or a short wait passes, so an expiry lands between the webhook
+// reading the payment and writing it whenever nothing prevents it
+type expiryDuringWebhook struct {
+	output.PaymentGateway
+	once     sync.Once
+	lookedUp chan struct{}
+	expired  chan struct{}
+}
+
+func (g *expiryDuringWebhook) GetByTransactionID(ctx context.Context, transactionID string) (*entities.Payment, error) {
+	payment, err := g.PaymentGateway.GetByTransactionID(ctx, transactionID)
+	g.once.Do(func() {
+		close(g.lookedUp)
+		select {
+		case <-g.expired:
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+	return payment, err
+}
+
+func TestPaymentUseCase_ProcessWebhookPayment_RacingExpiry(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	orderGateway := memory.NewOrderGateway(store)
+	paymentGateway := &expiryDuringWebhook{PaymentGateway: memory.NewPaymentGateway(store), lookedUp: make(chan struct{}), expired: make(chan struct{})}
+	useCase := NewPaymentUseCase(paymentGateway, orderGateway, memory.NewOrderItemGateway(store), memory.NewOutboxGateway(store), memory.NewTransactionManager(), time.Minute, logging.Discard(), telemetry.Discard())
+
+	order := entities.NewOrder(0, "")
+	orderGateway.Create(ctx, order)
+	payment := entities.NewPayment(order.ID, 30, "qr_code")
+	payment.ExpireAfter(time.Minute)
+	paymentGateway.Create(ctx, payment)
+
+	// Act
+	webhookErr := make(chan error, 1)
+	go func() {
+		webhookErr <- useCase.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{TransactionID: "tx-1", OrderID: order.ID, Status: string(entities.PaymentStatusApproved), Amount: 30})
+	}()
+	<-paymentGateway.lookedUp
+	go func() {
+		useCase.ExpirePayments(ctx, time.Now().Add(time.Hour), 10)
+		close(paymentGateway.expired)
+	}()
+	err := <-webhookErr
+	<-paymentGateway.expired
+
+	// Assert
+	updatedPayment, _ := paymentGateway.GetByID(ctx, payment.ID)
+	updatedOrder, _ := orderGateway.GetByID(ctx, order.ID)
+	if err != nil || !updatedPayment.IsApproved() || updatedOrder.Status != entities.OrderReceived {
+		t.Errorf("Expected the webhook to win the order lock and the expiry to skip the paid payment, got %v, payment %s, order %s", err, updatedPayment.Status, updatedOrder.Status)
+	}
+}
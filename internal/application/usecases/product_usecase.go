@@ -1,10 +1,10 @@
 package usecases
 
 import (
-	"errors"
-
+	"context"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
 )
@@ -19,9 +19,12 @@ func NewProductUseCase(productGateway output.ProductGateway) input.ProductUseCas
 	}
 }
 
-func (uc *productUseCase) CreateProduct(request *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+func (uc *productUseCase) CreateProduct(ctx context.Context, request *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductUseCase.CreateProduct")
+	defer span.End()
+
 	if !entities.IsValidCategory(request.Category) {
-		return nil, errors.New("invalid product category")
+		return nil, errs.Validation(errs.CodeInvalidProductCategory, "invalid product category")
 	}
 
 	product := entities.NewProduct(
@@ -33,10 +36,10 @@ func (uc *productUseCase) CreateProduct(request *dto.CreateProductRequest) (*dto
 	)
 
 	if !product.IsValid() {
-		return nil, errors.New("invalid product data")
+		return nil, errs.Validation(errs.CodeInvalidProduct, "invalid product data")
 	}
 
-	err := uc.productGateway.Create(product)
+	err := uc.productGateway.Create(ctx, product)
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +56,17 @@ func (uc *productUseCase) CreateProduct(request *dto.CreateProductRequest) (*dto
 	}, nil
 }
 
-func (uc *productUseCase) GetProductByID(id uint64) (*dto.ProductResponse, error) {
-	product, err := uc.productGateway.GetByID(id)
+func (uc *productUseCase) GetProductByID(ctx context.Context, id uint64) (*dto.ProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductUseCase.GetProductByID")
+	defer span.End()
+
+	product, err := uc.productGateway.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if product == nil {
-		return nil, errors.New("product not found")
+		return nil, errs.NotFound(errs.CodeProductNotFound, "product not found")
 	}
 
 	return &dto.ProductResponse{
@@ -75,12 +81,15 @@ func (uc *productUseCase) GetProductByID(id uint64) (*dto.ProductResponse, error
 	}, nil
 }
 
-func (uc *productUseCase) GetProductsByCategory(category string) ([]*dto.ProductResponse, error) {
+func (uc *productUseCase) GetProductsByCategory(ctx context.Context, category string) ([]*dto.ProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductUseCase.GetProductsByCategory")
+	defer span.End()
+
 	if !entities.IsValidCategory(category) {
-		return nil, errors.New("invalid product category")
+		return nil, errs.Validation(errs.CodeInvalidProductCategory, "invalid product category")
 	}
 
-	products, err := uc.productGateway.GetByCategory(category)
+	products, err := uc.productGateway.GetByCategory(ctx, category)
 	if err != nil {
 		return nil, err
 	}
@@ -102,8 +111,11 @@ func (uc *productUseCase) GetProductsByCategory(category string) ([]*dto.Product
 	return response, nil
 }
 
-func (uc *productUseCase) GetAllProducts() ([]*dto.ProductResponse, error) {
-	products, err := uc.productGateway.GetAll()
+func (uc *productUseCase) GetAllProducts(ctx context.Context) ([]*dto.ProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductUseCase.GetAllProducts")
+	defer span.End()
+
+	products, err := uc.productGateway.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -125,18 +137,21 @@ func (uc *productUseCase) GetAllProducts() ([]*dto.ProductResponse, error) {
 	return response, nil
 }
 
-func (uc *productUseCase) UpdateProduct(id uint64, request *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+func (uc *productUseCase) UpdateProduct(ctx context.Context, id uint64, request *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductUseCase.UpdateProduct")
+	defer span.End()
+
 	if !entities.IsValidCategory(request.Category) {
-		return nil, errors.New("invalid product category")
+		return nil, errs.Validation(errs.CodeInvalidProductCategory, "invalid product category")
 	}
 
-	product, err := uc.productGateway.GetByID(id)
+	product, err := uc.productGateway.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if product == nil {
-		return nil, errors.New("product not found")
+		return nil, errs.NotFound(errs.CodeProductNotFound, "product not found")
 	}
 
 	product.UpdateProduct(
@@ -148,10 +163,10 @@ func (uc *productUseCase) UpdateProduct(id uint64, request *dto.UpdateProductReq
 	)
 
 	if !product.IsValid() {
-		return nil, errors.New("invalid product data")
+		return nil, errs.Validation(errs.CodeInvalidProduct, "invalid product data")
 	}
 
-	err = uc.productGateway.Update(product)
+	err = uc.productGateway.Update(ctx, product)
 	if err != nil {
 		return nil, err
 	}
@@ -168,15 +183,18 @@ func (uc *productUseCase) UpdateProduct(id uint64, request *dto.UpdateProductReq
 	}, nil
 }
 
-func (uc *productUseCase) DeleteProduct(id uint64) error {
-	product, err := uc.productGateway.GetByID(id)
+func (uc *productUseCase) DeleteProduct(ctx context.Context, id uint64) error {
+	ctx, span := tracer.Start(ctx, "ProductUseCase.DeleteProduct")
+	defer span.End()
+
+	product, err := uc.productGateway.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if product == nil {
-		return errors.New("product not found")
+		return errs.NotFound(errs.CodeProductNotFound, "product not found")
 	}
 
-	return uc.productGateway.Delete(id)
+	return uc.productGateway.Delete(ctx, id)
 }
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+func TestProductUseCase_CreateProduct(t *testing.T) {
+	// Arrange
+	useCase := NewProductUseCase(memory.NewProductGateway(memory.NewStore()))
+
+	request := &dto.CreateProductRequest{
+		Name:        "X-Burger",
+		Description: "Pão, carne e queijo",
+		Price:       19.90,
+		Category:    "snack",
+	}
+
+	// Act
+	response, err := useCase.CreateProduct(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.ID == 0 {
+		t.Error("Expected generated ID")
+	}
+
+	if response.Category != "snack" {
+		t.Errorf("Expected category 'snack', got %s", response.Category)
+	}
+}
+
+func TestProductUseCase_CreateProduct_InvalidCategory(t *testing.T) {
+	// Arrange
+	useCase := NewProductUseCase(memory.NewProductGateway(memory.NewStore()))
+
+	request := &dto.CreateProductRequest{
+		Name:        "Pizza",
+		Description: "Calabresa",
+		Price:       40,
+		Category:    "pizza",
+	}
+
+	// Act
+	response, err := useCase.CreateProduct(ctx, request)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for invalid category, got nil")
+	}
+
+	if response != nil {
+		t.Error("Expected nil response for invalid category")
+	}
+}
+
+func TestProductUseCase_GetProductsByCategory(t *testing.T) {
+	// Arrange
+	useCase := NewProductUseCase(memory.NewProductGateway(memory.NewStore()))
+
+	for _, request := range []*dto.CreateProductRequest{
+		{Name: "Suco", Description: "Laranja", Price: 8, Category: "drink"},
+		{Name: "Água", Description: "Sem gás", Price: 3, Category: "drink"},
+		{Name: "Brownie", Description: "Chocolate", Price: 8, Category: "dessert"},
+	} {
+		if _, err := useCase.CreateProduct(ctx, request); err != nil {
+			t.Fatalf("Failed to seed product: %v", err)
+		}
+	}
+
+	// Act
+	response, err := useCase.GetProductsByCategory(ctx, "drink")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response) != 2 {
+		t.Fatalf("Expected 2 drinks, got %d", len(response))
+	}
+
+	if response[0].Name != "Suco" && response[0].Name != "Água" {
+		t.Errorf("Unexpected product %s", response[0].Name)
+	}
+}
+
+func TestProductUseCase_DeleteProduct_NotFound(t *testing.T) {
+	// Arrange
+	useCase := NewProductUseCase(memory.NewProductGateway(memory.NewStore()))
+
+	// Act
+	err := useCase.DeleteProduct(ctx, 42)
+
+	// Assert
+	if err == nil || err.Error() != "product not found" {
+		t.Errorf("Expected 'product not found' error, got %v", err)
+	}
+}
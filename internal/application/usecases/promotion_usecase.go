@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type promotionUseCase struct {
+	promotionGateway output.PromotionGateway
+}
+
+func NewPromotionUseCase(promotionGateway output.PromotionGateway) input.PromotionUseCase {
+	return &promotionUseCase{
+		promotionGateway: promotionGateway,
+	}
+}
+
+func (uc *promotionUseCase) CreatePromotion(ctx context.Context, request *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUseCase.CreatePromotion")
+	defer span.End()
+
+	promotion := entities.NewPromotion(request.Name, request.Code, entities.PromotionType(request.Type), request.Value)
+	if err := applyPromotionRequest(promotion, request); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureCodeAvailable(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	if err := uc.promotionGateway.Create(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	return buildPromotionResponse(promotion), nil
+}
+
+func (uc *promotionUseCase) GetPromotionByID(ctx context.Context, id uint64) (*dto.PromotionResponse, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUseCase.GetPromotionByID")
+	defer span.End()
+
+	promotion, err := uc.getPromotion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildPromotionResponse(promotion), nil
+}
+
+func (uc *promotionUseCase) GetAllPromotions(ctx context.Context) ([]*dto.PromotionResponse, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUseCase.GetAllPromotions")
+	defer span.End()
+
+	promotions, err := uc.promotionGateway.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*dto.PromotionResponse
+	for _, promotion := range promotions {
+		response = append(response, buildPromotionResponse(promotion))
+	}
+
+	return response, nil
+}
+
+func (uc *promotionUseCase) UpdatePromotion(ctx context.Context, id uint64, request *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUseCase.UpdatePromotion")
+	defer span.End()
+
+	promotion, err := uc.getPromotion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.Name = request.Name
+	promotion.Code = entities.NormalizeCouponCode(request.Code)
+	promotion.Type = entities.PromotionType(request.Type)
+	promotion.Value = request.Value
+	if err := applyPromotionRequest(promotion, request); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureCodeAvailable(ctx, promotion); err != nil {
+		return nil, err
+	}
+	promotion.UpdatedAt = time.Now()
+
+	if err := uc.promotionGateway.Update(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	return buildPromotionResponse(promotion), nil
+}
+
+func (uc *promotionUseCase) DeletePromotion(ctx context.Context, id uint64) error {
+	ctx, span := tracer.Start(ctx, "PromotionUseCase.DeletePromotion")
+	defer span.End()
+
+	if _, err := uc.getPromotion(ctx, id); err != nil {
+		return err
+	}
+
+	return uc.promotionGateway.Delete(ctx, id)
+}
+
+func (uc *promotionUseCase) getPromotion(ctx context.Context, id uint64) (*entities.Promotion, error) {
+	promotion, err := uc.promotionGateway.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil {
+		return nil, errs.NotFound(errs.CodePromotionNotFound, "promotion not found")
+	}
+	return promotion, nil
+}
+
+// ensureCodeAvailable rejects a coupon code already used by another
+// promotion, since orders look coupons up by code
+func (uc *promotionUseCase) ensureCodeAvailable(ctx context.Context, promotion *entities.Promotion) error {
+	if !promotion.IsCoupon() {
+		return nil
+	}
+	existing, err := uc.promotionGateway.GetByCode(ctx, promotion.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != promotion.ID {
+		return errs.Conflict(errs.CodeCouponCodeTaken, "coupon code already taken")
+	}
+	return nil
+}
+
+// applyPromotionRequest copies the rule parameters and time window of
+// request onto promotion and validates the result
+func applyPromotionRequest(promotion *entities.Promotion, request *dto.PromotionRequest) error {
+	promotion.BuyQuantity = request.BuyQuantity
+	promotion.FreeQuantity = request.FreeQuantity
+	promotion.Category = request.Category
+	promotion.DailyStart = request.DailyStart
+	promotion.DailyEnd = request.DailyEnd
+	promotion.MaxUsesPerCustomer = request.MaxUsesPerCustomer
+	promotion.Active = request.Active == nil || *request.Active
+
+	startsAt, err := parsePromotionTime("starts_at", request.StartsAt)
+	if err != nil {
+		return err
+	}
+	endsAt, err := parsePromotionTime("ends_at", request.EndsAt)
+	if err != nil {
+		return err
+	}
+	promotion.StartsAt = startsAt
+	promotion.EndsAt = endsAt
+
+	promotion.Weekdays = nil
+	for _, day := range request.Weekdays {
+		weekday, ok := entities.ParseWeekday(day)
+		if !ok {
+			return errs.Validation(errs.CodeInvalidPromotion, fmt.Sprintf("invalid weekday %q", day))
+		}
+		promotion.Weekdays = append(promotion.Weekdays, weekday)
+	}
+
+	if !promotion.IsValid() {
+		return errs.Validation(errs.CodeInvalidPromotion,
+			"invalid promotion: check the type's value or quantities, the category, that ends_at is after starts_at and that daily_start and daily_end are HH:MM with start before end")
+	}
+	return nil
+}
+
+func parsePromotionTime(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errs.Validation(errs.CodeInvalidPromotion, fmt.Sprintf("%s must be an RFC 3339 time", field))
+	}
+	return &parsed, nil
+}
+
+func buildPromotionResponse(promotion *entities.Promotion) *dto.PromotionResponse {
+	response := &dto.PromotionResponse{
+		ID:                 promotion.ID,
+		Name:               promotion.Name,
+		Code:               promotion.Code,
+		Type:               string(promotion.Type),
+		Value:              promotion.Value,
+		BuyQuantity:        promotion.BuyQuantity,
+		FreeQuantity:       promotion.FreeQuantity,
+		Category:           promotion.Category,
+		Weekdays:           []string{},
+		DailyStart:         promotion.DailyStart,
+		DailyEnd:           promotion.DailyEnd,
+		MaxUsesPerCustomer: promotion.MaxUsesPerCustomer,
+		Active:             promotion.Active,
+		CreatedAt:          promotion.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:          promotion.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if promotion.StartsAt != nil {
+		response.StartsAt = promotion.StartsAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	if promotion.EndsAt != nil {
+		response.EndsAt = promotion.EndsAt.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	for _, weekday := range promotion.Weekdays {
+		response.Weekdays = append(response.Weekdays, strings.ToLower(weekday.String()))
+	}
+	return response
+}
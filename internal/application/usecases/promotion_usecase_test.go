@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+func newPromotionUseCase() input.PromotionUseCase {
+	return NewPromotionUseCase(memory.NewPromotionGateway(memory.NewStore()))
+}
+
+func TestPromotionUseCase_CreatePromotion(t *testing.T) {
+	// Arrange
+	useCase := newPromotionUseCase()
+	request := &dto.PromotionRequest{
+		Name:       "Sobremesa de terça",
+		Code:       "terca10",
+		Type:       "percentage",
+		Value:      10,
+		Category:   "dessert",
+		StartsAt:   "2026-11-01T00:00:00Z",
+		Weekdays:   []string{"Tuesday"},
+		DailyStart: "11:00",
+		DailyEnd:   "15:00",
+	}
+
+	// Act
+	created, err := useCase.CreatePromotion(ctx, request)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	found, err := useCase.GetPromotionByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Expected the promotion to be stored, got %v", err)
+	}
+	if found.Code != "TERCA10" || !found.Active || found.StartsAt != "2026-11-01T00:00:00Z" ||
+		len(found.Weekdays) != 1 || found.Weekdays[0] != "tuesday" {
+		t.Errorf("Unexpected promotion %+v", found)
+	}
+}
+
+func TestPromotionUseCase_CreatePromotion_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		request dto.PromotionRequest
+	}{
+		{"percentage over 100", dto.PromotionRequest{Name: "x", Type: "percentage", Value: 120}},
+		{"buy x get y without quantities", dto.PromotionRequest{Name: "x", Type: "buy_x_get_y"}},
+		{"unknown category", dto.PromotionRequest{Name: "x", Type: "fixed", Value: 5, Category: "pizza"}},
+		{"unknown weekday", dto.PromotionRequest{Name: "x", Type: "fixed", Value: 5, Weekdays: []string{"someday"}}},
+		{"bad start time", dto.PromotionRequest{Name: "x", Type: "fixed", Value: 5, StartsAt: "tomorrow"}},
+		{"window ends before it starts", dto.PromotionRequest{Name: "x", Type: "fixed", Value: 5, StartsAt: "2026-11-02T00:00:00Z", EndsAt: "2026-11-01T00:00:00Z"}},
+		{"daily window reversed", dto.PromotionRequest{Name: "x", Type: "fixed", Value: 5, DailyStart: "18:00", DailyEnd: "11:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			useCase := newPromotionUseCase()
+
+			// Act
+			_, err := useCase.CreatePromotion(ctx, &tt.request)
+
+			// Assert
+			if errs.CodeOf(err) != errs.CodeInvalidPromotion {
+				t.Errorf("Expected code %s, got %v", errs.CodeInvalidPromotion, err)
+			}
+		})
+	}
+}
+
+func TestPromotionUseCase_CouponCodeTaken(t *testing.T) {
+	// Arrange
+	useCase := newPromotionUseCase()
+	first, _ := useCase.CreatePromotion(ctx, &dto.PromotionRequest{Name: "Cinco", Code: "CINCO", Type: "fixed", Value: 5})
+	second, _ := useCase.CreatePromotion(ctx, &dto.PromotionRequest{Name: "Dez", Code: "DEZ", Type: "fixed", Value: 10})
+
+	// Act
+	_, createErr := useCase.CreatePromotion(ctx, &dto.PromotionRequest{Name: "Outro", Code: "cinco", Type: "fixed", Value: 1})
+	_, updateErr := useCase.UpdatePromotion(ctx, second.ID, &dto.PromotionRequest{Name: "Dez", Code: "CINCO", Type: "fixed", Value: 10})
+	_, keepErr := useCase.UpdatePromotion(ctx, first.ID, &dto.PromotionRequest{Name: "Cinco", Code: "CINCO", Type: "fixed", Value: 6})
+
+	// Assert
+	if errs.CodeOf(createErr) != errs.CodeCouponCodeTaken || errs.CodeOf(updateErr) != errs.CodeCouponCodeTaken {
+		t.Errorf("Expected code %s, got %v and %v", errs.CodeCouponCodeTaken, createErr, updateErr)
+	}
+	if keepErr != nil {
+		t.Errorf("Expected a promotion to keep its own code, got %v", keepErr)
+	}
+}
+
+func TestPromotionUseCase_DeletePromotion_NotFound(t *testing.T) {
+	// Arrange
+	useCase := newPromotionUseCase()
+
+	// Act
+	err := useCase.DeletePromotion(ctx, 99)
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodePromotionNotFound {
+		t.Errorf("Expected code %s, got %v", errs.CodePromotionNotFound, err)
+	}
+}
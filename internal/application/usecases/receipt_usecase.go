@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type receiptUseCase struct {
+	orderGateway     output.OrderGateway
+	orderItemGateway output.OrderItemGateway
+	taxGateway       output.OrderTaxGateway
+	productGateway   output.ProductGateway
+	paymentGateway   output.PaymentGateway
+	receiptGateway   output.ReceiptGateway
+	issuer           entities.ReceiptIssuer
+	xmlRenderer      output.ReceiptRenderer
+	textRenderer     output.ReceiptRenderer
+	logger           *slog.Logger
+}
+
+func NewReceiptUseCase(
+	orderGateway output.OrderGateway,
+	orderItemGateway output.OrderItemGateway,
+	taxGateway output.OrderTaxGateway,
+	productGateway output.ProductGateway,
+	paymentGateway output.PaymentGateway,
+	receiptGateway output.ReceiptGateway,
+	issuer entities.ReceiptIssuer,
+	xmlRenderer output.ReceiptRenderer,
+	textRenderer output.ReceiptRenderer,
+	logger *slog.Logger,
+) input.ReceiptUseCase {
+	return &receiptUseCase{
+		orderGateway:     orderGateway,
+		orderItemGateway: orderItemGateway,
+		taxGateway:       taxGateway,
+		productGateway:   productGateway,
+		paymentGateway:   paymentGateway,
+		receiptGateway:   receiptGateway,
+		issuer:           issuer,
+		xmlRenderer:      xmlRenderer,
+		textRenderer:     textRenderer,
+		logger:           logger,
+	}
+}
+
+func (uc *receiptUseCase) GetOrderReceipt(ctx context.Context, orderID uint64) (*dto.ReceiptResponse, error) {
+	ctx, span := tracer.Start(ctx, "ReceiptUseCase.GetOrderReceipt")
+	defer span.End()
+
+	receipt, err := uc.receiptGateway.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders paid before receipts existed, or whose event the relay has not
+	// handled yet, get theirs now
+	if receipt == nil {
+		order, err := uc.orderGateway.GetByID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil {
+			return nil, errs.NotFound(errs.CodeOrderNotFound, "order not found")
+		}
+		if !order.IsPaid() {
+			return nil, errs.Conflict(errs.CodeOrderNotPaid, fmt.Sprintf("order is %s; receipts are issued once it is paid", order.Status))
+		}
+		if receipt, err = uc.issue(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	return buildReceiptResponse(receipt), nil
+}
+
+func (uc *receiptUseCase) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "ReceiptUseCase.Publish")
+	defer span.End()
+
+	if event.EventType != entities.EventOrderStatusChanged {
+		return nil
+	}
+	var payload orderEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+	}
+	if entities.OrderStatus(payload.Status) != entities.OrderReceived {
+		return nil
+	}
+
+	// The relay may deliver the event again, or a GET may have issued the
+	// receipt first
+	existing, err := uc.receiptGateway.GetByOrderID(ctx, payload.OrderID)
+	if err != nil || existing != nil {
+		return err
+	}
+	order, err := uc.orderGateway.GetByID(ctx, payload.OrderID)
+	if err != nil || order == nil || !order.IsPaid() {
+		return err
+	}
+
+	_, err = uc.issue(ctx, order)
+	return err
+}
+
+// issue builds, renders and stores the receipt of a paid order. When another
+// call stored one first, that one is returned.
+func (uc *receiptUseCase) issue(ctx context.Context, order *entities.Order) (*entities.Receipt, error) {
+	if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+		return nil, err
+	}
+	taxes, err := uc.taxGateway.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tax := range taxes {
+		order.Taxes = append(order.Taxes, *tax)
+	}
+
+	products, err := loadOrderProducts(ctx, uc.productGateway, order)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := uc.paymentGateway.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := entities.NewReceipt(uc.issuer, order, products, payments)
+	if receipt.XML, err = uc.xmlRenderer.Render(receipt); err != nil {
+		return nil, fmt.Errorf("failed to render receipt XML: %w", err)
+	}
+	if receipt.Text, err = uc.textRenderer.Render(receipt); err != nil {
+		return nil, fmt.Errorf("failed to render receipt text: %w", err)
+	}
+
+	created, err := uc.receiptGateway.Create(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return uc.receiptGateway.GetByOrderID(ctx, order.ID)
+	}
+
+	uc.logger.InfoContext(ctx, "receipt issued", "order_id", order.ID, "receipt_id", receipt.ID, "total", receipt.Total)
+	return receipt, nil
+}
+
+func buildReceiptResponse(receipt *entities.Receipt) *dto.ReceiptResponse {
+	response := &dto.ReceiptResponse{
+		ID:     receipt.ID,
+		Number: receipt.OrderID,
+		Issuer: dto.ReceiptIssuerResponse{
+			Name:    receipt.Issuer.Name,
+			CNPJ:    receipt.Issuer.CNPJ,
+			Address: receipt.Issuer.Address,
+		},
+		CPF:        receipt.CPF,
+		Items:      []dto.ReceiptItemResponse{},
+		Subtotal:   receipt.Subtotal,
+		Discount:   receipt.Discount,
+		ServiceFee: receipt.ServiceFee,
+		Rounding:   receipt.Rounding,
+		Total:      receipt.Total,
+		Taxes:      []dto.ReceiptTaxResponse{},
+		TaxTotal:   receipt.TaxTotal,
+		Payments:   []dto.ReceiptPaymentResponse{},
+		IssuedAt:   receipt.IssuedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		XML:        receipt.XML,
+		Text:       receipt.Text,
+	}
+
+	for _, item := range receipt.Items {
+		response.Items = append(response.Items, dto.ReceiptItemResponse{
+			Number:      item.Number,
+			ProductID:   item.ProductID,
+			Description: item.Description,
+			Category:    item.Category,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Total:       item.Total,
+		})
+	}
+	for _, tax := range receipt.Taxes {
+		response.Taxes = append(response.Taxes, dto.ReceiptTaxResponse{
+			Category: tax.Category,
+			Rate:     tax.Rate,
+			Amount:   tax.Amount,
+		})
+	}
+	for _, payment := range receipt.Payments {
+		response.Payments = append(response.Payments, dto.ReceiptPaymentResponse{
+			Method:        payment.Method,
+			Payer:         payment.Payer,
+			Amount:        payment.Amount,
+			TransactionID: payment.TransactionID,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/services"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/receipts"
+)
+
+var testReceiptIssuer = entities.ReceiptIssuer{Name: "Fast Food", CNPJ: "12345678000190", Address: "Rua das Flores, 100"}
+
+func (f *orderTestFixture) receiptUseCase() input.ReceiptUseCase {
+	return NewReceiptUseCase(f.orderGateway, f.orderItemGateway, f.taxGateway, f.productGateway, f.paymentGateway, f.receiptGateway,
+		testReceiptIssuer, receipts.NewXMLRenderer(time.UTC), receipts.NewTextRenderer(48, time.UTC), logging.Discard())
+}
+
+// payOrder approves the order's payment and moves the order to received, as
+// the payment webhook would
+func (f *orderTestFixture) payOrder(t *testing.T, orderID uint64) {
+	t.Helper()
+	payment, err := f.paymentGateway.GetLatestByOrderID(ctx, orderID)
+	if err != nil || payment == nil {
+		t.Fatalf("Failed to find the payment of order %d: %v", orderID, err)
+	}
+	payment.UpdateStatus(entities.PaymentStatusApproved, "tx-1")
+	if err := f.paymentGateway.Update(ctx, payment); err != nil {
+		t.Fatalf("Failed to approve payment: %v", err)
+	}
+	f.advanceOrder(t, orderID, entities.OrderReceived)
+}
+
+func TestReceiptUseCase_IssuesReceiptOnceWhenOrderIsPaid(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	f.useCase = f.pricedUseCase(services.PricingRules{
+		TaxRates: map[entities.ProductCategory]float64{entities.SnackCategory: 10},
+	}, logging.Discard())
+	receiptUseCase := f.receiptUseCase()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{CPF: "123.456.789-00", Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 2}}})
+	f.payOrder(t, order.ID)
+
+	// Act
+	f.publishPending(t, receiptUseCase)
+	f.publishPending(t, receiptUseCase)
+
+	// Assert
+	stored, _ := f.receiptGateway.GetByOrderID(ctx, order.ID)
+	if stored == nil {
+		t.Fatal("Expected the receipt to be issued when the order was received")
+	}
+	receipt, err := receiptUseCase.GetOrderReceipt(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if receipt.ID != stored.ID || receipt.Number != order.ID || receipt.CPF != "123.456.789-00" || receipt.Total != 40 {
+		t.Errorf("Expected the stored receipt of order %d for CPF 123.456.789-00 totalling 40, got %+v", order.ID, receipt)
+	}
+	if len(receipt.Items) != 1 || receipt.Items[0].Description != "X-Burger" || receipt.Items[0].Quantity != 2 || receipt.Items[0].Total != 40 {
+		t.Errorf("Expected one line of 2 X-Burger, got %+v", receipt.Items)
+	}
+	if len(receipt.Taxes) != 1 || receipt.Taxes[0].Category != "snack" || receipt.Taxes[0].Amount != 4 || receipt.TaxTotal != 4 {
+		t.Errorf("Expected 4.00 of snack taxes, got %+v", receipt.Taxes)
+	}
+	if len(receipt.Payments) != 1 || receipt.Payments[0].Amount != 40 || receipt.Payments[0].TransactionID != "tx-1" {
+		t.Errorf("Expected the approved payment, got %+v", receipt.Payments)
+	}
+	if !bytes.Contains(receipt.XML, []byte("<CPF>12345678900</CPF>")) || !bytes.Contains(receipt.Text, []byte("CONSUMIDOR CPF 123.456.789-00")) {
+		t.Errorf("Expected both renditions to identify the customer, got\n%s\n%s", receipt.XML, receipt.Text)
+	}
+}
+
+func TestReceiptUseCase_GetOrderReceipt_IssuesMissingReceipt(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	receiptUseCase := f.receiptUseCase()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	f.payOrder(t, order.ID)
+
+	// Act
+	first, err := receiptUseCase.GetOrderReceipt(ctx, order.ID)
+	second, _ := receiptUseCase.GetOrderReceipt(ctx, order.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first.ID == 0 || second.ID != first.ID || first.IssuedAt != second.IssuedAt {
+		t.Errorf("Expected the first read to issue the receipt and the second to return it, got %+v and %+v", first, second)
+	}
+	if first.CPF != "" || !bytes.Contains(first.Text, []byte("CONSUMIDOR NÃO IDENTIFICADO")) {
+		t.Errorf("Expected an anonymous receipt, got CPF %q\n%s", first.CPF, first.Text)
+	}
+	if stored, _ := f.receiptGateway.GetByOrderID(ctx, order.ID); stored == nil || stored.ID != first.ID {
+		t.Errorf("Expected the receipt to be stored, got %+v", stored)
+	}
+}
+
+func TestReceiptUseCase_ListsOnlyApprovedPayments(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	receiptUseCase := f.receiptUseCase()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+	rejected := entities.NewPayment(order.ID, 20, "credit_card")
+	rejected.UpdateStatus(entities.PaymentStatusRejected, "tx-0")
+	f.paymentGateway.Create(ctx, rejected)
+	f.payOrder(t, order.ID)
+
+	// Act
+	receipt, err := receiptUseCase.GetOrderReceipt(ctx, order.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(receipt.Payments) != 1 || receipt.Payments[0].TransactionID != "tx-1" {
+		t.Errorf("Expected only the approved payment, got %+v", receipt.Payments)
+	}
+}
+
+func TestReceiptUseCase_GetOrderReceipt_Errors(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	receiptUseCase := f.receiptUseCase()
+	burger := f.seedProduct(t, "X-Burger", 20)
+	unpaid, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}}})
+
+	tests := []struct {
+		name    string
+		orderID uint64
+		code    string
+	}{
+		{"unknown order", unpaid.ID + 1, errs.CodeOrderNotFound},
+		{"unpaid order", unpaid.ID, errs.CodeOrderNotPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := receiptUseCase.GetOrderReceipt(ctx, tt.orderID)
+
+			// Assert
+			if errs.CodeOf(err) != tt.code {
+				t.Errorf("Expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
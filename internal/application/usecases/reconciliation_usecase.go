@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// errNoPaymentProvider is returned by the operations that call the provider
+// when none is configured
+var errNoPaymentProvider = errors.New("payment provider not configured")
+
+type reconciliationUseCase struct {
+	payments              input.PaymentUseCase
+	paymentGateway        output.PaymentGateway
+	reconciliationGateway output.ReconciliationGateway
+	provider              output.PaymentProvider
+	transactions          output.TransactionManager
+	logger                *slog.Logger
+}
+
+// NewReconciliationUseCase applies provider statuses through payments, the
+// same path as the payment webhook. provider may be nil, in which case only
+// stored reports can be read.
+func NewReconciliationUseCase(
+	payments input.PaymentUseCase,
+	paymentGateway output.PaymentGateway,
+	reconciliationGateway output.ReconciliationGateway,
+	provider output.PaymentProvider,
+	transactions output.TransactionManager,
+	logger *slog.Logger,
+) input.ReconciliationUseCase {
+	return &reconciliationUseCase{
+		payments:              payments,
+		paymentGateway:        paymentGateway,
+		reconciliationGateway: reconciliationGateway,
+		provider:              provider,
+		transactions:          transactions,
+		logger:                logger,
+	}
+}
+
+func (uc *reconciliationUseCase) ReconcilePendingPayments(ctx context.Context, createdBefore time.Time, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "ReconciliationUseCase.ReconcilePendingPayments")
+	defer span.End()
+
+	if uc.provider == nil {
+		return 0, errNoPaymentProvider
+	}
+
+	pending, err := uc.paymentGateway.ListPendingCreatedBefore(ctx, createdBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending payments: %w", err)
+	}
+
+	// A split bill has several pending parts and one provider record per
+	// part, so the records of an order are read once and each goes to a
+	// single part
+	records := make(map[uint64][]*entities.ProviderPayment)
+	matched := make(map[string]bool)
+	updated := 0
+	for _, payment := range pending {
+		orderRecords, ok := records[payment.OrderID]
+		if !ok {
+			orderRecords, err = uc.provider.ListPaymentsByOrderID(ctx, payment.OrderID)
+			if err != nil {
+				// The provider is likely down; the next run starts over
+				return updated, fmt.Errorf("failed to list provider payments for order %d: %w", payment.OrderID, err)
+			}
+			records[payment.OrderID] = orderRecords
+		}
+		record, err := uc.matchProviderPayment(ctx, payment, orderRecords, matched)
+		if err != nil {
+			return updated, err
+		}
+		if record == nil {
+			continue
+		}
+		matched[record.TransactionID] = true
+
+		err = uc.payments.ProcessWebhookPayment(ctx, &dto.WebhookPaymentRequest{
+			TransactionID: record.TransactionID,
+			OrderID:       record.OrderID,
+			Status:        string(record.Status),
+			Amount:        record.Amount,
+			PaymentMethod: record.PaymentMethod,
+			Timestamp:     record.CreatedAt.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "failed to apply provider payment status",
+				"payment_id", payment.ID,
+				"order_id", payment.OrderID,
+				"status", record.Status,
+				"error", err,
+			)
+			continue
+		}
+
+		uc.logger.InfoContext(ctx, "payment reconciled with provider",
+			"payment_id", payment.ID,
+			"order_id", payment.OrderID,
+			"status", record.Status,
+			"transaction_id", record.TransactionID,
+		)
+		updated++
+	}
+
+	return updated, nil
+}
+
+// matchProviderPayment returns the settled provider record of a pending part:
+// the one with its transaction ID, else the oldest one for the same amount
+// that no other payment of ours has taken, or nil
+func (uc *reconciliationUseCase) matchProviderPayment(ctx context.Context, payment *entities.Payment, records []*entities.ProviderPayment, matched map[string]bool) (*entities.ProviderPayment, error) {
+	var match *entities.ProviderPayment
+	for _, record := range records {
+		if matched[record.TransactionID] || !record.IsSettled() {
+			continue
+		}
+		if payment.TransactionID != "" && record.TransactionID == payment.TransactionID {
+			return record, nil
+		}
+		if match != nil || !entities.SumsTo(payment.Amount, record.Amount) {
+			continue
+		}
+		// Earlier attempts of the same order keep their records
+		known, err := uc.paymentGateway.GetByTransactionID(ctx, record.TransactionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payment by transaction: %w", err)
+		}
+		if known == nil {
+			match = record
+		}
+	}
+	return match, nil
+}
+
+func (uc *reconciliationUseCase) GenerateReport(ctx context.Context, date time.Time) (*dto.ReconciliationReportResponse, error) {
+	ctx, span := tracer.Start(ctx, "ReconciliationUseCase.GenerateReport")
+	defer span.End()
+
+	if uc.provider == nil {
+		return nil, errNoPaymentProvider
+	}
+
+	day := entities.ReportDay(date)
+	next := day.AddDate(0, 0, 1)
+
+	local, err := uc.paymentGateway.ListCreatedBetween(ctx, day, next)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+	records, err := uc.provider.ListPayments(ctx, day, next)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider payments: %w", err)
+	}
+
+	report := entities.NewReconciliationReport(day, local, records)
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		return uc.reconciliationGateway.SaveReport(ctx, report)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reconciliation report: %w", err)
+	}
+
+	uc.logger.InfoContext(ctx, "reconciliation report generated",
+		"date", day.Format(time.DateOnly),
+		"payments", report.PaymentsChecked,
+		"provider_payments", report.ProviderPayments,
+		"mismatches", len(report.Mismatches),
+	)
+
+	return uc.buildReportResponse(report), nil
+}
+
+func (uc *reconciliationUseCase) GetReport(ctx context.Context, date time.Time) (*dto.ReconciliationReportResponse, error) {
+	ctx, span := tracer.Start(ctx, "ReconciliationUseCase.GetReport")
+	defer span.End()
+
+	report, err := uc.reconciliationGateway.GetReport(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation report: %w", err)
+	}
+	if report == nil {
+		return nil, errs.NotFound(errs.CodeReconciliationReportNotFound, "no reconciliation report for this date")
+	}
+
+	return uc.buildReportResponse(report), nil
+}
+
+func (uc *reconciliationUseCase) buildReportResponse(report *entities.ReconciliationReport) *dto.ReconciliationReportResponse {
+	mismatches := make([]dto.ReconciliationMismatchResponse, 0, len(report.Mismatches))
+	for _, mismatch := range report.Mismatches {
+		mismatches = append(mismatches, dto.ReconciliationMismatchResponse{
+			Kind:           string(mismatch.Kind),
+			PaymentID:      mismatch.PaymentID,
+			OrderID:        mismatch.OrderID,
+			TransactionID:  mismatch.TransactionID,
+			LocalStatus:    string(mismatch.LocalStatus),
+			ProviderStatus: string(mismatch.ProviderStatus),
+			LocalAmount:    mismatch.LocalAmount,
+			ProviderAmount: mismatch.ProviderAmount,
+		})
+	}
+
+	return &dto.ReconciliationReportResponse{
+		Date:             report.Date.Format(time.DateOnly),
+		PaymentsChecked:  report.PaymentsChecked,
+		ProviderPayments: report.ProviderPayments,
+		Mismatches:       mismatches,
+		GeneratedAt:      report.GeneratedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+)
+
+// fakeProvider serves the provider records it was given
+type fakeProvider struct {
+	records []*entities.ProviderPayment
+}
+
+func (f *fakeProvider) ListPaymentsByOrderID(_ context.Context, orderID uint64) ([]*entities.ProviderPayment, error) {
+	var records []*entities.ProviderPayment
+	for _, record := range f.records {
+		if record.OrderID == orderID {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (f *fakeProvider) ListPayments(_ context.Context, from, to time.Time) ([]*entities.ProviderPayment, error) {
+	var records []*entities.ProviderPayment
+	for _, record := range f.records {
+		if !record.CreatedAt.Before(from) && record.CreatedAt.Before(to) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+type reconciliationFixture struct {
+	orderGateway     output.OrderGateway
+	orderItemGateway output.OrderItemGateway
+	paymentGateway   output.PaymentGateway
+	provider         *fakeProvider
+	useCase          input.ReconciliationUseCase
+}
+
+func newReconciliationFixture() *reconciliationFixture {
+	store := memory.NewStore()
+	f := &reconciliationFixture{
+		orderGateway:     memory.NewOrderGateway(store),
+		orderItemGateway: memory.NewOrderItemGateway(store),
+		paymentGateway:   memory.NewPaymentGateway(store),
+		provider:         &fakeProvider{},
+	}
+	transactions := memory.NewTransactionManager()
+	payments := NewPaymentUseCase(f.paymentGateway, f.orderGateway, f.orderItemGateway, memory.NewOutboxGateway(store), transactions, 0, logging.Discard(), telemetry.Discard())
+	f.useCase = NewReconciliationUseCase(payments, f.paymentGateway, memory.NewReconciliationGateway(store), f.provider, transactions, logging.Discard())
+	return f
+}
+
+// createPayment stores an order and its payment created at createdAt
+func (f *reconciliationFixture) createPayment(createdAt time.Time, amount float32, status entities.PaymentStatus, transactionID string) *entities.Payment {
+	order := entities.NewOrder(0, "")
+	f.orderGateway.Create(ctx, order)
+	payment := entities.NewPayment(order.ID, amount, "qr_code")
+	payment.CreatedAt = createdAt
+	payment.UpdateStatus(status, transactionID)
+	f.paymentGateway.Create(ctx, payment)
+	return payment
+}
+
+func TestReconciliationUseCase_ReconcilePendingPayments(t *testing.T) {
+	// Arrange
+	f := newReconciliationFixture()
+	now := time.Now()
+	approved := f.createPayment(now.Add(-time.Hour), 30, entities.PaymentStatusPending, "")
+	stillPending := f.createPayment(now.Add(-time.Hour), 30, entities.PaymentStatusPending, "")
+	recent := f.createPayment(now, 30, entities.PaymentStatusPending, "")
+	f.provider.records = []*entities.ProviderPayment{
+		{TransactionID: "mp-1", OrderID: approved.OrderID, Status: entities.PaymentStatusApproved, Amount: 30},
+		{TransactionID: "mp-2", OrderID: stillPending.OrderID, Status: entities.PaymentStatusPending, Amount: 30},
+		{TransactionID: "mp-3", OrderID: recent.OrderID, Status: entities.PaymentStatusApproved, Amount: 30},
+	}
+
+	// Act
+	updated, err := f.useCase.ReconcilePendingPayments(ctx, now.Add(-10*time.Minute), 10)
+
+	// Assert
+	if err != nil || updated != 1 {
+		t.Fatalf("Expected 1 reconciled payment, got %d, %v", updated, err)
+	}
+
+	if payment, _ := f.paymentGateway.GetByID(ctx, approved.ID); !payment.IsApproved() || payment.TransactionID != "mp-1" {
+		t.Errorf("Expected payment approved with the provider transaction, got %+v", payment)
+	}
+	if order, _ := f.orderGateway.GetByID(ctx, approved.OrderID); order.Status != entities.OrderReceived {
+		t.Errorf("Expected the paid order to be received, got %s", order.Status)
+	}
+	for _, payment := range []*entities.Payment{stillPending, recent} {
+		if found, _ := f.paymentGateway.GetByID(ctx, payment.ID); !found.IsPending() {
+			t.Errorf("Expected payment %d to stay pending, got %s", payment.ID, found.Status)
+		}
+	}
+}
+
+func TestReconciliationUseCase_ReconcilePendingPayments_SplitBill(t *testing.T) {
+	// Arrange
+	f := newReconciliationFixture()
+	now := time.Now()
+	order := entities.NewOrder(0, "")
+	f.orderGateway.Create(ctx, order)
+	f.orderItemGateway.Create(ctx, entities.NewOrderItem(order.ID, 1, 2, 25))
+	var parts []*entities.Payment
+	for _, amount := range []float32{20, 30} {
+		part := entities.NewPayment(order.ID, amount, "pix")
+		part.CreatedAt = now.Add(-time.Hour)
+		f.paymentGateway.Create(ctx, part)
+		parts = append(parts, part)
+	}
+	f.provider.records = []*entities.ProviderPayment{
+		{TransactionID: "mp-1", OrderID: order.ID, Status: entities.PaymentStatusApproved, Amount: 20},
+		{TransactionID: "mp-2", OrderID: order.ID, Status: entities.PaymentStatusApproved, Amount: 30},
+	}
+
+	// Act
+	updated, err := f.useCase.ReconcilePendingPayments(ctx, now.Add(-10*time.Minute), 10)
+
+	// Assert
+	if err != nil || updated != 2 {
+		t.Fatalf("Expected both parts reconciled, got %d, %v", updated, err)
+	}
+	for i, want := range []string{"mp-1", "mp-2"} {
+		if payment, _ := f.paymentGateway.GetByID(ctx, parts[i].ID); !payment.IsApproved() || payment.TransactionID != want {
+			t.Errorf("Expected part %d approved with %s, got %+v", i, want, payment)
+		}
+	}
+	if stored, _ := f.orderGateway.GetByID(ctx, order.ID); stored.Status != entities.OrderReceived {
+		t.Errorf("Expected the covered order to be received, got %s", stored.Status)
+	}
+}
+
+func TestReconciliationUseCase_GenerateReport(t *testing.T) {
+	// Arrange
+	f := newReconciliationFixture()
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	at := day.Add(12 * time.Hour)
+
+	f.createPayment(at, 20, entities.PaymentStatusApproved, "mp-ok")
+	amount := f.createPayment(at, 20, entities.PaymentStatusApproved, "mp-amount")
+	lostWebhook := f.createPayment(at, 15, entities.PaymentStatusPending, "")
+	unknown := f.createPayment(at, 10, entities.PaymentStatusApproved, "mp-unknown")
+	expired := f.createPayment(at, 10, entities.PaymentStatusExpired, "")
+	f.createPayment(day.Add(-time.Hour), 10, entities.PaymentStatusApproved, "mp-yesterday")
+	f.provider.records = []*entities.ProviderPayment{
+		{TransactionID: "mp-ok", OrderID: 1, Status: entities.PaymentStatusApproved, Amount: 20, CreatedAt: at},
+		{TransactionID: "mp-amount", OrderID: amount.OrderID, Status: entities.PaymentStatusApproved, Amount: 25, CreatedAt: at},
+		{TransactionID: "mp-lost", OrderID: lostWebhook.OrderID, Status: entities.PaymentStatusApproved, Amount: 15, CreatedAt: at},
+		{TransactionID: "mp-expired", OrderID: expired.OrderID, Status: entities.PaymentStatusCanceled, Amount: 10, CreatedAt: at},
+		{TransactionID: "mp-foreign", OrderID: 999, Status: entities.PaymentStatusApproved, Amount: 5, CreatedAt: at},
+	}
+
+	// Act
+	report, err := f.useCase.GenerateReport(ctx, at)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Date != "2026-10-18" || report.PaymentsChecked != 5 || report.ProviderPayments != 5 {
+		t.Errorf("Unexpected report summary %+v", report)
+	}
+
+	kinds := map[string]uint64{}
+	for _, mismatch := range report.Mismatches {
+		kinds[mismatch.Kind] = mismatch.OrderID
+	}
+	want := map[string]uint64{
+		string(entities.MismatchAmount):            amount.OrderID,
+		string(entities.MismatchStatus):            lostWebhook.OrderID,
+		string(entities.MismatchMissingLocally):    999,
+		string(entities.MismatchMissingAtProvider): unknown.OrderID,
+	}
+	if len(report.Mismatches) != len(want) {
+		t.Fatalf("Expected %d mismatches, got %+v", len(want), report.Mismatches)
+	}
+	for kind, orderID := range want {
+		if kinds[kind] != orderID {
+			t.Errorf("Expected a %s mismatch for order %d, got %+v", kind, orderID, report.Mismatches)
+		}
+	}
+
+	stored, err := f.useCase.GetReport(ctx, day)
+	if err != nil || len(stored.Mismatches) != len(want) {
+		t.Errorf("Expected the stored report, got %+v, %v", stored, err)
+	}
+}
+
+func TestReconciliationUseCase_GetReport_NotFound(t *testing.T) {
+	// Arrange
+	f := newReconciliationFixture()
+
+	// Act
+	report, err := f.useCase.GetReport(ctx, time.Now())
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeReconciliationReportNotFound {
+		t.Errorf("Expected code %s, got %v", errs.CodeReconciliationReportNotFound, err)
+	}
+	if report != nil {
+		t.Error("Expected nil report")
+	}
+}
+
+func TestReconciliationUseCase_RequiresProvider(t *testing.T) {
+	// Arrange
+	store := memory.NewStore()
+	useCase := NewReconciliationUseCase(nil, memory.NewPaymentGateway(store), memory.NewReconciliationGateway(store), nil, memory.NewTransactionManager(), logging.Discard())
+
+	// Act
+	_, reconcileErr := useCase.ReconcilePendingPayments(ctx, time.Now(), 10)
+	_, reportErr := useCase.GenerateReport(ctx, time.Now())
+
+	// Assert
+	if reconcileErr == nil || reportErr == nil {
+		t.Errorf("Expected errors without a provider, got %v and %v", reconcileErr, reportErr)
+	}
+}
@@ -0,0 +1,307 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type stationTicketUseCase struct {
+	orderGateway         output.OrderGateway
+	orderItemGateway     output.OrderItemGateway
+	productGateway       output.ProductGateway
+	stationTicketGateway output.StationTicketGateway
+	outboxGateway        output.OutboxGateway
+	transactions         output.TransactionManager
+	stations             []entities.KitchenStation
+	logger               *slog.Logger
+	metrics              output.Metrics
+}
+
+// NewStationTicketUseCase creates the use case; with no stations orders are
+// not split and the kitchen works from a single queue
+func NewStationTicketUseCase(
+	orderGateway output.OrderGateway,
+	orderItemGateway output.OrderItemGateway,
+	productGateway output.ProductGateway,
+	stationTicketGateway output.StationTicketGateway,
+	outboxGateway output.OutboxGateway,
+	transactions output.TransactionManager,
+	stations []entities.KitchenStation,
+	logger *slog.Logger,
+	metrics output.Metrics,
+) input.StationTicketUseCase {
+	return &stationTicketUseCase{
+		orderGateway:         orderGateway,
+		orderItemGateway:     orderItemGateway,
+		productGateway:       productGateway,
+		stationTicketGateway: stationTicketGateway,
+		outboxGateway:        outboxGateway,
+		transactions:         transactions,
+		stations:             stations,
+		logger:               logger,
+		metrics:              metrics,
+	}
+}
+
+func (uc *stationTicketUseCase) GetStations(ctx context.Context) ([]*dto.KitchenStationResponse, error) {
+	response := []*dto.KitchenStationResponse{}
+	for _, station := range uc.stations {
+		categories := []string{}
+		for _, category := range station.Categories {
+			categories = append(categories, string(category))
+		}
+		response = append(response, &dto.KitchenStationResponse{
+			Name:       station.Name,
+			Categories: categories,
+		})
+	}
+
+	return response, nil
+}
+
+func (uc *stationTicketUseCase) GetStationQueue(ctx context.Context, station string) ([]*dto.StationTicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "StationTicketUseCase.GetStationQueue")
+	defer span.End()
+
+	if !uc.hasStation(station) {
+		return nil, errs.NotFound(errs.CodeKitchenStationNotFound, "kitchen station not found")
+	}
+
+	tickets, err := uc.stationTicketGateway.ListOpenByStation(ctx, station)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildStationTicketResponses(tickets), nil
+}
+
+func (uc *stationTicketUseCase) GetOrderStationTickets(ctx context.Context, orderID uint64) ([]*dto.StationTicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "StationTicketUseCase.GetOrderStationTickets")
+	defer span.End()
+
+	order, err := uc.orderGateway.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errs.NotFound(errs.CodeOrderNotFound, "order not found")
+	}
+
+	tickets, err := uc.stationTicketGateway.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildStationTicketResponses(tickets), nil
+}
+
+func (uc *stationTicketUseCase) UpdateStationTicketStatus(ctx context.Context, id uint64, request *dto.UpdateStationTicketStatusRequest) (*dto.StationTicketResponse, error) {
+	ctx, span := tracer.Start(ctx, "StationTicketUseCase.UpdateStationTicketStatus")
+	defer span.End()
+
+	ticket, err := uc.stationTicketGateway.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, errs.NotFound(errs.CodeStationTicketNotFound, "station ticket not found")
+	}
+
+	if !entities.IsValidStationTicketStatus(request.Status) {
+		return nil, errs.Validation(errs.CodeInvalidStationTicketStatus, "invalid station ticket status")
+	}
+
+	status := entities.StationTicketStatus(request.Status)
+
+	// The ticket, the order it belongs to and the order's events change
+	// together; changes holds each status the order left and the one it
+	// moved to. The order is locked first and the ticket read again under
+	// it, so the last two stations finishing at once see each other's
+	// tickets done and the order moves to ready once.
+	var order *entities.Order
+	var previous entities.StationTicketStatus
+	var changes [][2]entities.OrderStatus
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if order, err = uc.orderGateway.GetByIDForUpdate(ctx, ticket.OrderID); err != nil {
+			return err
+		}
+		if ticket, err = uc.stationTicketGateway.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if ticket == nil {
+			return errs.NotFound(errs.CodeStationTicketNotFound, "station ticket not found")
+		}
+
+		if !ticket.CanTransitionTo(status) {
+			return errs.InvalidTransition(
+				errs.CodeInvalidStationTicketTransition,
+				fmt.Sprintf("cannot change station ticket status from %s to %s", ticket.Status, status),
+			)
+		}
+		if order == nil || (order.Status != entities.OrderReceived && order.Status != entities.OrderInProgress) {
+			current := "deleted"
+			if order != nil {
+				current = string(order.Status)
+			}
+			return errs.Conflict(errs.CodeOrderNotInKitchen, fmt.Sprintf("order is %s; its station tickets are closed", current))
+		}
+		// Loaded first so the order's events carry its total
+		if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+			return err
+		}
+
+		previous = ticket.Status
+		ticket.UpdateStatus(status)
+		if err := uc.stationTicketGateway.Update(ctx, ticket); err != nil {
+			return fmt.Errorf("failed to update station ticket: %w", err)
+		}
+
+		var next []entities.OrderStatus
+		switch status {
+		case entities.StationTicketPreparing:
+			// The order is in progress once the first station starts on it
+			next = []entities.OrderStatus{entities.OrderInProgress}
+		case entities.StationTicketDone:
+			tickets, err := uc.stationTicketGateway.ListByOrderID(ctx, order.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list station tickets: %w", err)
+			}
+			if entities.AllStationTicketsDone(tickets) {
+				next = []entities.OrderStatus{entities.OrderInProgress, entities.OrderReady}
+			}
+		}
+
+		for _, orderStatus := range next {
+			if order.Status == orderStatus || !order.CanTransitionTo(orderStatus) {
+				continue
+			}
+			from := order.Status
+			order.UpdateStatus(orderStatus)
+			if err := uc.orderGateway.Update(ctx, order); err != nil {
+				return fmt.Errorf("failed to update order status: %w", err)
+			}
+			if err := appendOrderEvent(ctx, uc.outboxGateway, entities.EventOrderStatusChanged, order, from); err != nil {
+				return err
+			}
+			changes = append(changes, [2]entities.OrderStatus{from, orderStatus})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.InfoContext(ctx, "station ticket status changed", "ticket_id", id, "order_id", order.ID, "station", ticket.Station, "from", previous, "to", status)
+	for _, change := range changes {
+		uc.logger.InfoContext(ctx, "order status changed", "order_id", order.ID, "from", change[0], "to", change[1], "station", ticket.Station)
+		uc.metrics.OrderStatusChanged(change[1])
+	}
+
+	return buildStationTicketResponse(ticket), nil
+}
+
+func (uc *stationTicketUseCase) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "StationTicketUseCase.Publish")
+	defer span.End()
+
+	if len(uc.stations) == 0 || event.EventType != entities.EventOrderStatusChanged {
+		return nil
+	}
+	var payload orderEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+	}
+	if entities.OrderStatus(payload.Status) != entities.OrderReceived {
+		return nil
+	}
+
+	// Orders the kitchen already finished or dropped need no tickets
+	order, err := uc.orderGateway.GetByID(ctx, payload.OrderID)
+	if err != nil || order == nil {
+		return err
+	}
+	if order.Status != entities.OrderReceived && order.Status != entities.OrderInProgress {
+		return nil
+	}
+	if err := loadOrderItems(ctx, uc.orderItemGateway, order); err != nil {
+		return err
+	}
+	products, err := loadOrderProducts(ctx, uc.productGateway, order)
+	if err != nil {
+		return err
+	}
+
+	// The relay may deliver the event again; stations that already have
+	// their ticket are skipped
+	tickets := entities.SplitStationTickets(order, products, uc.stations)
+	created := 0
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, ticket := range tickets {
+			ok, err := uc.stationTicketGateway.Create(ctx, ticket)
+			if err != nil {
+				return fmt.Errorf("failed to create %s ticket: %w", ticket.Station, err)
+			}
+			if ok {
+				created++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if created > 0 {
+		uc.logger.InfoContext(ctx, "order split into station tickets", "order_id", order.ID, "tickets", created)
+	}
+	return nil
+}
+
+func (uc *stationTicketUseCase) hasStation(name string) bool {
+	for _, station := range uc.stations {
+		if station.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func buildStationTicketResponses(tickets []*entities.StationTicket) []*dto.StationTicketResponse {
+	response := []*dto.StationTicketResponse{}
+	for _, ticket := range tickets {
+		response = append(response, buildStationTicketResponse(ticket))
+	}
+	return response
+}
+
+func buildStationTicketResponse(ticket *entities.StationTicket) *dto.StationTicketResponse {
+	response := &dto.StationTicketResponse{
+		ID:        ticket.ID,
+		OrderID:   ticket.OrderID,
+		Station:   ticket.Station,
+		Status:    string(ticket.Status),
+		Items:     []dto.KitchenTicketItemResponse{},
+		CreatedAt: ticket.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: ticket.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+
+	for _, item := range ticket.Items {
+		response.Items = append(response.Items, dto.KitchenTicketItemResponse{
+			Quantity:    item.Quantity,
+			Description: item.Description,
+			Modifiers:   item.Modifiers,
+			Notes:       item.Notes,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,300 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+)
+
+// testStations leave desserts to the first station
+var testStations = []entities.KitchenStation{
+	{Name: "grill", Categories: []entities.ProductCategory{entities.SnackCategory}},
+	{Name: "fryer", Categories: []entities.ProductCategory{entities.SideCategory}},
+	{Name: "drinks", Categories: []entities.ProductCategory{entities.DrinkCategory}},
+}
+
+func (f *orderTestFixture) stationTicketUseCase(stations []entities.KitchenStation) input.StationTicketUseCase {
+	return NewStationTicketUseCase(f.orderGateway, f.orderItemGateway, f.productGateway, f.stationGateway, f.outboxGateway,
+		memory.NewTransactionManager(), stations, logging.Discard(), telemetry.Discard())
+}
+
+func (f *orderTestFixture) seedCategoryProduct(t *testing.T, name string, category entities.ProductCategory) *entities.Product {
+	t.Helper()
+	product := entities.NewProduct(name, name, 10, category, "")
+	if err := f.productGateway.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to seed product: %v", err)
+	}
+	return product
+}
+
+// createStationOrder creates and pays for an order of a burger, fries, a
+// soda and a pudding
+func (f *orderTestFixture) createStationOrder(t *testing.T) *dto.OrderResponse {
+	t.Helper()
+	burger := f.seedCategoryProduct(t, "X-Burger", entities.SnackCategory)
+	fries := f.seedCategoryProduct(t, "Batata", entities.SideCategory)
+	soda := f.seedCategoryProduct(t, "Refrigerante", entities.DrinkCategory)
+	pudding := f.seedCategoryProduct(t, "Pudim", entities.DessertCategory)
+	order, err := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{
+		{ProductID: burger.ID, Quantity: 2, Modifiers: []string{"sem cebola"}},
+		{ProductID: fries.ID, Quantity: 1},
+		{ProductID: soda.ID, Quantity: 1, Notes: "sem gelo"},
+		{ProductID: pudding.ID, Quantity: 1},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	f.payOrder(t, order.ID)
+	return order
+}
+
+// orderStatusEvents returns the statuses of the order.status_changed events
+// in the outbox, oldest first
+func (f *orderTestFixture) orderStatusEvents(t *testing.T) []string {
+	t.Helper()
+	events, err := f.outboxGateway.ListPending(ctx, time.Now().Add(time.Second), 100)
+	if err != nil {
+		t.Fatalf("Failed to list outbox events: %v", err)
+	}
+	var statuses []string
+	for _, event := range events {
+		if event.EventType != entities.EventOrderStatusChanged {
+			continue
+		}
+		var payload orderEventPayload
+		json.Unmarshal(event.Payload, &payload)
+		statuses = append(statuses, payload.Status)
+	}
+	return statuses
+}
+
+func TestStationTicketUseCase_SplitsReceivedOrders(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	stationUseCase := f.stationTicketUseCase(testStations)
+	order := f.createStationOrder(t)
+
+	// Act
+	f.publishPending(t, stationUseCase)
+	f.publishPending(t, stationUseCase)
+
+	// Assert
+	tickets, err := stationUseCase.GetOrderStationTickets(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tickets) != 3 {
+		t.Fatalf("Expected one ticket per station, got %+v", tickets)
+	}
+	grill, fryer, drinks := tickets[0], tickets[1], tickets[2]
+	if grill.Station != "grill" || len(grill.Items) != 2 || grill.Items[0].Description != "X-Burger" || grill.Items[0].Quantity != 2 || grill.Items[1].Description != "Pudim" {
+		t.Errorf("Expected the burger and the unrouted pudding on the grill, got %+v", grill)
+	}
+	if len(grill.Items[0].Modifiers) != 1 || grill.Items[0].Modifiers[0] != "sem cebola" {
+		t.Errorf("Expected the burger's modifiers on its ticket, got %+v", grill.Items[0])
+	}
+	if fryer.Station != "fryer" || len(fryer.Items) != 1 || fryer.Items[0].Description != "Batata" {
+		t.Errorf("Expected the fries on the fryer, got %+v", fryer)
+	}
+	if drinks.Station != "drinks" || len(drinks.Items) != 1 || drinks.Items[0].Notes != "sem gelo" {
+		t.Errorf("Expected the soda with its notes on the drinks station, got %+v", drinks)
+	}
+	for _, ticket := range tickets {
+		if ticket.Status != "pending" || ticket.OrderID != order.ID {
+			t.Errorf("Expected pending tickets of order %d, got %+v", order.ID, ticket)
+		}
+	}
+}
+
+func TestStationTicketUseCase_OrderFollowsItsTickets(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	stationUseCase := f.stationTicketUseCase(testStations[:2])
+	burger := f.seedCategoryProduct(t, "X-Burger", entities.SnackCategory)
+	fries := f.seedCategoryProduct(t, "Batata", entities.SideCategory)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}, {ProductID: fries.ID, Quantity: 1}}})
+	f.payOrder(t, order.ID)
+	f.publishPending(t, stationUseCase)
+	tickets, _ := stationUseCase.GetOrderStationTickets(ctx, order.ID)
+	grill, fryer := tickets[0].ID, tickets[1].ID
+	move := func(id uint64, status entities.StationTicketStatus) *dto.StationTicketResponse {
+		t.Helper()
+		ticket, err := stationUseCase.UpdateStationTicketStatus(ctx, id, &dto.UpdateStationTicketStatusRequest{Status: string(status)})
+		if err != nil {
+			t.Fatalf("Failed to move ticket %d to %s: %v", id, status, err)
+		}
+		return ticket
+	}
+	orderStatus := func() entities.OrderStatus {
+		stored, _ := f.orderGateway.GetByID(ctx, order.ID)
+		return stored.Status
+	}
+
+	// Act & Assert
+	move(grill, entities.StationTicketPreparing)
+	if status := orderStatus(); status != entities.OrderInProgress {
+		t.Fatalf("Expected the order in progress once the grill started, got %s", status)
+	}
+	move(fryer, entities.StationTicketPreparing)
+	done := move(grill, entities.StationTicketDone)
+	if done.Status != "done" || orderStatus() != entities.OrderInProgress {
+		t.Fatalf("Expected the order to wait for the fryer, got ticket %s and order %s", done.Status, orderStatus())
+	}
+	if queue, _ := stationUseCase.GetStationQueue(ctx, "grill"); len(queue) != 0 {
+		t.Errorf("Expected the grill queue to be empty, got %+v", queue)
+	}
+	if queue, _ := stationUseCase.GetStationQueue(ctx, "fryer"); len(queue) != 1 || queue[0].Status != "preparing" {
+		t.Errorf("Expected the fryer ticket being prepared, got %+v", queue)
+	}
+	move(fryer, entities.StationTicketDone)
+	if status := orderStatus(); status != entities.OrderReady {
+		t.Fatalf("Expected the order ready once every station is done, got %s", status)
+	}
+	if got := f.orderStatusEvents(t); strings.Join(got, ",") != "received,in_progress,ready" {
+		t.Errorf("Expected an event for each status the order went through, got %v", got)
+	}
+}
+
+// rendezvousStationTickets holds each of the first two ticket updates until
+// the other one was made or a short wait passes, so two stations finishing
+// at once both mark their ticket done before either looks at the order's
+// other tickets
+type rendezvousStationTickets struct {
+	output.StationTicketGateway
+	updates atomic.Int32
+	both    chan struct{}
+}
+
+func (g *rendezvousStationTickets) Update(ctx context.Context, ticket *entities.StationTicket) error {
+	if err := g.StationTicketGateway.Update(ctx, ticket); err != nil {
+		return err
+	}
+	if n := g.updates.Add(1); n == 2 {
+		close(g.both)
+	} else if n > 2 {
+		return nil
+	}
+	select {
+	case <-g.both:
+	case <-time.After(100 * time.Millisecond):
+	}
+	return nil
+}
+
+func TestStationTicketUseCase_LastStationsFinishingTogether(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	stationUseCase := f.stationTicketUseCase(testStations[:2])
+	burger := f.seedCategoryProduct(t, "X-Burger", entities.SnackCategory)
+	fries := f.seedCategoryProduct(t, "Batata", entities.SideCategory)
+	order, _ := f.useCase.CreateOrder(ctx, &dto.CreateOrderRequest{Items: []dto.OrderItemRequest{{ProductID: burger.ID, Quantity: 1}, {ProductID: fries.ID, Quantity: 1}}})
+	f.payOrder(t, order.ID)
+	f.publishPending(t, stationUseCase)
+	tickets, _ := stationUseCase.GetOrderStationTickets(ctx, order.ID)
+	for _, ticket := range tickets {
+		if _, err := stationUseCase.UpdateStationTicketStatus(ctx, ticket.ID, &dto.UpdateStationTicketStatusRequest{Status: string(entities.StationTicketPreparing)}); err != nil {
+			t.Fatalf("Failed to start ticket %d: %v", ticket.ID, err)
+		}
+	}
+	f.stationGateway = &rendezvousStationTickets{StationTicketGateway: f.stationGateway, both: make(chan struct{})}
+	stationUseCase = f.stationTicketUseCase(testStations[:2])
+
+	// Act
+	var wg sync.WaitGroup
+	updateErrs := make([]error, len(tickets))
+	for i, ticket := range tickets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, updateErrs[i] = stationUseCase.UpdateStationTicketStatus(ctx, ticket.ID, &dto.UpdateStationTicketStatusRequest{Status: string(entities.StationTicketDone)})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if updateErrs[0] != nil || updateErrs[1] != nil {
+		t.Fatalf("Expected both tickets to be done, got %v", updateErrs)
+	}
+	if stored, _ := f.orderGateway.GetByID(ctx, order.ID); stored.Status != entities.OrderReady {
+		t.Errorf("Expected the order ready once both stations are done, got %s", stored.Status)
+	}
+	if got := f.orderStatusEvents(t); strings.Join(got, ",") != "received,in_progress,ready" {
+		t.Errorf("Expected the order to move to ready once, got %v", got)
+	}
+}
+
+func TestStationTicketUseCase_UpdateStationTicketStatus_Errors(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	stationUseCase := f.stationTicketUseCase(testStations)
+	order := f.createStationOrder(t)
+	cancelled := f.createStationOrder(t)
+	f.publishPending(t, stationUseCase)
+	f.advanceOrder(t, cancelled.ID, entities.OrderCancelled)
+	tickets, _ := stationUseCase.GetOrderStationTickets(ctx, order.ID)
+	closed, _ := stationUseCase.GetOrderStationTickets(ctx, cancelled.ID)
+
+	tests := []struct {
+		name   string
+		id     uint64
+		status string
+		code   string
+	}{
+		{"ticket not found", 999, "preparing", errs.CodeStationTicketNotFound},
+		{"unknown status", tickets[0].ID, "burnt", errs.CodeInvalidStationTicketStatus},
+		{"skips preparing", tickets[0].ID, "done", errs.CodeInvalidStationTicketTransition},
+		{"order cancelled", closed[0].ID, "preparing", errs.CodeOrderNotInKitchen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := stationUseCase.UpdateStationTicketStatus(ctx, tt.id, &dto.UpdateStationTicketStatusRequest{Status: tt.status})
+
+			// Assert
+			if errs.CodeOf(err) != tt.code {
+				t.Errorf("Expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+	if queue, _ := stationUseCase.GetStationQueue(ctx, "grill"); len(queue) != 1 || queue[0].OrderID != order.ID {
+		t.Errorf("Expected only the open order in the grill queue, got %+v", queue)
+	}
+}
+
+func TestStationTicketUseCase_WithoutStations(t *testing.T) {
+	// Arrange
+	f := newOrderTestFixture()
+	stationUseCase := f.stationTicketUseCase(nil)
+	order := f.createStationOrder(t)
+
+	// Act
+	f.publishPending(t, stationUseCase)
+
+	// Assert
+	if tickets, err := stationUseCase.GetOrderStationTickets(ctx, order.ID); err != nil || len(tickets) != 0 {
+		t.Errorf("Expected the order not to be split, got %+v, %v", tickets, err)
+	}
+	if stations, _ := stationUseCase.GetStations(ctx); len(stations) != 0 {
+		t.Errorf("Expected no stations, got %+v", stations)
+	}
+	if _, err := stationUseCase.GetStationQueue(ctx, "grill"); errs.CodeOf(err) != errs.CodeKitchenStationNotFound {
+		t.Errorf("Expected %s, got %v", errs.CodeKitchenStationNotFound, err)
+	}
+	if _, err := stationUseCase.GetOrderStationTickets(ctx, 999); errs.CodeOf(err) != errs.CodeOrderNotFound {
+		t.Errorf("Expected %s, got %v", errs.CodeOrderNotFound, err)
+	}
+}
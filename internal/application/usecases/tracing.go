@@ -0,0 +1,7 @@
+package usecases
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts one span per use case method, named "<UseCase>.<Method>".
+// It uses the global provider, so spans are no-ops until tracing is set up.
+var tracer = otel.Tracer("github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/usecases")
@@ -0,0 +1,272 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// webhookSecretPrefix marks generated secrets so they are easy to recognise
+// in partner configuration
+const webhookSecretPrefix = "whsec_"
+
+// webhookEnvelope is the body of every webhook delivery. ID is the outbox
+// event ID, which partners use to discard duplicates.
+type webhookEnvelope struct {
+	ID            uint64          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint64          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type webhookUseCase struct {
+	subscriptionGateway output.WebhookSubscriptionGateway
+	deliveryGateway     output.WebhookDeliveryGateway
+}
+
+func NewWebhookUseCase(subscriptionGateway output.WebhookSubscriptionGateway, deliveryGateway output.WebhookDeliveryGateway) input.WebhookUseCase {
+	return &webhookUseCase{
+		subscriptionGateway: subscriptionGateway,
+		deliveryGateway:     deliveryGateway,
+	}
+}
+
+func (uc *webhookUseCase) CreateSubscription(ctx context.Context, request *dto.CreateWebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.CreateSubscription")
+	defer span.End()
+
+	secret := request.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	subscription := entities.NewWebhookSubscription(request.URL, secret, request.EventTypes)
+	if !subscription.IsValid() {
+		return nil, errs.Validation(errs.CodeInvalidWebhookSubscription,
+			fmt.Sprintf("invalid webhook subscription: url must be http(s) and event_types one of %v", entities.WebhookEventTypes()))
+	}
+
+	if err := uc.subscriptionGateway.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	response := uc.buildSubscriptionResponse(subscription)
+	// The secret is shown once; partners need it to verify signatures
+	response.Secret = subscription.Secret
+	return response, nil
+}
+
+func (uc *webhookUseCase) GetSubscriptionByID(ctx context.Context, id uint64) (*dto.WebhookSubscriptionResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.GetSubscriptionByID")
+	defer span.End()
+
+	subscription, err := uc.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.buildSubscriptionResponse(subscription), nil
+}
+
+func (uc *webhookUseCase) GetAllSubscriptions(ctx context.Context) ([]*dto.WebhookSubscriptionResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.GetAllSubscriptions")
+	defer span.End()
+
+	subscriptions, err := uc.subscriptionGateway.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*dto.WebhookSubscriptionResponse
+	for _, subscription := range subscriptions {
+		response = append(response, uc.buildSubscriptionResponse(subscription))
+	}
+
+	return response, nil
+}
+
+func (uc *webhookUseCase) DeleteSubscription(ctx context.Context, id uint64) error {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.DeleteSubscription")
+	defer span.End()
+
+	if _, err := uc.getSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	return uc.subscriptionGateway.Delete(ctx, id)
+}
+
+func (uc *webhookUseCase) GetDeliveries(ctx context.Context, subscriptionID uint64) ([]*dto.WebhookDeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.GetDeliveries")
+	defer span.End()
+
+	if _, err := uc.getSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := uc.deliveryGateway.GetBySubscriptionID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*dto.WebhookDeliveryResponse
+	for _, delivery := range deliveries {
+		response = append(response, uc.buildDeliveryResponse(delivery))
+	}
+
+	return response, nil
+}
+
+func (uc *webhookUseCase) Redeliver(ctx context.Context, subscriptionID, deliveryID uint64) (*dto.WebhookDeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.Redeliver")
+	defer span.End()
+
+	delivery, err := uc.deliveryGateway.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery == nil || delivery.SubscriptionID != subscriptionID {
+		return nil, errs.NotFound(errs.CodeWebhookDeliveryNotFound, "webhook delivery not found")
+	}
+
+	delivery.Redeliver()
+	if err := uc.deliveryGateway.Update(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return uc.buildDeliveryResponse(delivery), nil
+}
+
+func (uc *webhookUseCase) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	ctx, span := tracer.Start(ctx, "WebhookUseCase.Publish")
+	defer span.End()
+
+	subscriptions, err := uc.subscriptionGateway.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	eventTypes, err := webhookEventTypes(event)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		eventType, ok := matchEventType(subscription, eventTypes)
+		if !ok {
+			continue
+		}
+
+		body, err := json.Marshal(webhookEnvelope{
+			ID:            event.ID,
+			Type:          eventType,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			OccurredAt:    event.CreatedAt.UTC(),
+			Payload:       json.RawMessage(event.Payload),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Create skips subscriptions already queued, so a retried event is
+		// not delivered twice
+		delivery := entities.NewWebhookDelivery(subscription.ID, event.ID, eventType, body)
+		if _, err := uc.deliveryGateway.Create(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to queue event %d for webhook subscription %d: %w", event.ID, subscription.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (uc *webhookUseCase) getSubscription(ctx context.Context, id uint64) (*entities.WebhookSubscription, error) {
+	subscription, err := uc.subscriptionGateway.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription == nil {
+		return nil, errs.NotFound(errs.CodeWebhookSubscriptionNotFound, "webhook subscription not found")
+	}
+
+	return subscription, nil
+}
+
+// webhookEventTypes lists the webhook event types an outbox event stands
+// for, most specific first: order.status_changed to ready is also order.ready
+func webhookEventTypes(event *entities.OutboxEvent) ([]string, error) {
+	if event.EventType != entities.EventOrderStatusChanged {
+		return []string{event.EventType}, nil
+	}
+
+	var payload orderEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+	}
+
+	return []string{entities.OrderStatusEventType(entities.OrderStatus(payload.Status)), event.EventType}, nil
+}
+
+// matchEventType returns the first of eventTypes the subscription asked for
+func matchEventType(subscription *entities.WebhookSubscription, eventTypes []string) (string, bool) {
+	for _, eventType := range eventTypes {
+		if subscription.Subscribes(eventType) {
+			return eventType, true
+		}
+	}
+	return "", false
+}
+
+func generateWebhookSecret() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(raw), nil
+}
+
+func (uc *webhookUseCase) buildSubscriptionResponse(subscription *entities.WebhookSubscription) *dto.WebhookSubscriptionResponse {
+	return &dto.WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  subscription.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func (uc *webhookUseCase) buildDeliveryResponse(delivery *entities.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	response := &dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt.Format("2006-01-02T15:04:05Z"),
+		CreatedAt:      delivery.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if delivery.DeliveredAt != nil {
+		response.DeliveredAt = delivery.DeliveredAt.Format("2006-01-02T15:04:05Z")
+	}
+	return response
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+func newWebhookFixture() (*webhookUseCase, output.WebhookDeliveryGateway) {
+	store := memory.NewStore()
+	deliveryGateway := memory.NewWebhookDeliveryGateway(store)
+	useCase := NewWebhookUseCase(memory.NewWebhookSubscriptionGateway(store), deliveryGateway)
+	return useCase.(*webhookUseCase), deliveryGateway
+}
+
+func mustSubscribe(t *testing.T, useCase *webhookUseCase, eventTypes ...string) *dto.WebhookSubscriptionResponse {
+	t.Helper()
+	subscription, err := useCase.CreateSubscription(ctx, &dto.CreateWebhookSubscriptionRequest{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	return subscription
+}
+
+func statusChangedEvent(t *testing.T, id uint64, status entities.OrderStatus) *entities.OutboxEvent {
+	t.Helper()
+	event, err := entities.NewOutboxEvent(entities.EventOrderStatusChanged, entities.AggregateOrder, 7, orderEventPayload{OrderID: 7, Status: string(status)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.ID = id
+	return event
+}
+
+func TestWebhookUseCase_CreateSubscription_GeneratesSecret(t *testing.T) {
+	// Arrange
+	useCase, _ := newWebhookFixture()
+
+	// Act
+	created := mustSubscribe(t, useCase, "order.ready")
+	found, err := useCase.GetSubscriptionByID(ctx, created.ID)
+
+	// Assert
+	if !strings.HasPrefix(created.Secret, webhookSecretPrefix) {
+		t.Errorf("Expected a generated secret, got %q", created.Secret)
+	}
+	if err != nil || found.Secret != "" {
+		t.Errorf("Expected the secret hidden after creation, got %q, %v", found.Secret, err)
+	}
+}
+
+func TestWebhookUseCase_CreateSubscription_RejectsUnknownEventType(t *testing.T) {
+	// Arrange
+	useCase, _ := newWebhookFixture()
+
+	// Act
+	_, err := useCase.CreateSubscription(ctx, &dto.CreateWebhookSubscriptionRequest{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{"order.eaten"},
+	})
+
+	// Assert
+	if errs.CodeOf(err) != errs.CodeInvalidWebhookSubscription {
+		t.Errorf("Expected code %s, got %s", errs.CodeInvalidWebhookSubscription, errs.CodeOf(err))
+	}
+}
+
+func TestWebhookUseCase_Publish_QueuesMatchingSubscriptions(t *testing.T) {
+	// Arrange
+	useCase, deliveryGateway := newWebhookFixture()
+	ready := mustSubscribe(t, useCase, "order.ready")
+	everyChange := mustSubscribe(t, useCase, entities.EventOrderStatusChanged)
+	completed := mustSubscribe(t, useCase, "order.completed")
+
+	// Act
+	err := useCase.Publish(ctx, statusChangedEvent(t, 1, entities.OrderReady))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for subscriptionID, want := range map[uint64]string{ready.ID: "order.ready", everyChange.ID: entities.EventOrderStatusChanged} {
+		deliveries, _ := deliveryGateway.GetBySubscriptionID(ctx, subscriptionID)
+		if len(deliveries) != 1 || deliveries[0].EventType != want {
+			t.Fatalf("Expected one %s delivery for subscription %d, got %+v", want, subscriptionID, deliveries)
+		}
+
+		var envelope webhookEnvelope
+		json.Unmarshal(deliveries[0].Payload, &envelope)
+		if envelope.ID != 1 || envelope.Type != want || !strings.Contains(string(envelope.Payload), `"status":"ready"`) {
+			t.Errorf("Unexpected envelope %+v", envelope)
+		}
+	}
+
+	if deliveries, _ := deliveryGateway.GetBySubscriptionID(ctx, completed.ID); len(deliveries) != 0 {
+		t.Errorf("Expected no delivery for order.completed, got %d", len(deliveries))
+	}
+}
+
+func TestWebhookUseCase_Publish_IsIdempotent(t *testing.T) {
+	// Arrange
+	useCase, deliveryGateway := newWebhookFixture()
+	subscription := mustSubscribe(t, useCase, "order.ready")
+	event := statusChangedEvent(t, 1, entities.OrderReady)
+	useCase.Publish(ctx, event)
+
+	// Act
+	err := useCase.Publish(ctx, event)
+
+	// Assert
+	deliveries, _ := deliveryGateway.GetBySubscriptionID(ctx, subscription.ID)
+	if err != nil || len(deliveries) != 1 {
+		t.Errorf("Expected the retried event queued once, got %d deliveries, %v", len(deliveries), err)
+	}
+}
+
+func TestWebhookUseCase_Redeliver(t *testing.T) {
+	// Arrange
+	useCase, deliveryGateway := newWebhookFixture()
+	subscription := mustSubscribe(t, useCase, "order.ready")
+	other := mustSubscribe(t, useCase, "order.ready")
+	useCase.Publish(ctx, statusChangedEvent(t, 1, entities.OrderReady))
+
+	deliveries, _ := deliveryGateway.GetBySubscriptionID(ctx, subscription.ID)
+	failed := deliveries[0]
+	failed.MarkFailed(http.StatusInternalServerError, errors.New("endpoint answered 500"), nil)
+	deliveryGateway.Update(ctx, failed)
+
+	// Act
+	redelivered, err := useCase.Redeliver(ctx, subscription.ID, failed.ID)
+	_, wrongSubscription := useCase.Redeliver(ctx, other.ID, failed.ID)
+
+	// Assert
+	if err != nil || redelivered.Status != string(entities.WebhookDeliveryPending) || redelivered.Attempts != 0 {
+		t.Errorf("Expected the delivery queued again, got %+v, %v", redelivered, err)
+	}
+
+	if errs.CodeOf(wrongSubscription) != errs.CodeWebhookDeliveryNotFound {
+		t.Errorf("Expected code %s for another subscription's delivery, got %s", errs.CodeWebhookDeliveryNotFound, errs.CodeOf(wrongSubscription))
+	}
+}
@@ -1,6 +1,9 @@
 package entities
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Customer struct {
 	ID        uint64    `json:"id"`
@@ -16,13 +19,37 @@ func NewCustomer(firstName, lastName, cpf, email string) *Customer {
 	return &Customer{
 		FirstName: firstName,
 		LastName:  lastName,
-		CPF:       cpf,
+		CPF:       NormalizeCPF(cpf),
 		Email:     email,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 }
 
+// NormalizeCPF writes a CPF as 000.000.000-00 whatever punctuation it was
+// sent with, so a customer matches the same orders and coupon uses either
+// way. Anything that is not 11 digits is only trimmed.
+func NormalizeCPF(cpf string) string {
+	cpf = strings.TrimSpace(cpf)
+
+	var digits strings.Builder
+	for _, r := range cpf {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '.' || r == '-' || r == ' ':
+		default:
+			return cpf
+		}
+	}
+	if digits.Len() != 11 {
+		return cpf
+	}
+
+	d := digits.String()
+	return d[:3] + "." + d[3:6] + "." + d[6:9] + "-" + d[9:]
+}
+
 func (c *Customer) UpdateCustomer(firstName, lastName, email string) {
 	c.FirstName = firstName
 	c.LastName = lastName
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+)
+
+// IdempotencyRecord remembers a request sent with an Idempotency-Key: the
+// hash of the body it was first used with and, once handled, the response
+// to replay on retries
+type IdempotencyRecord struct {
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	RequestHash string    `json:"request_hash"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewIdempotencyRecord creates a record for a request that is about to run
+func NewIdempotencyRecord(scope, key, requestHash string) *IdempotencyRecord {
+	return &IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Complete stores the response the request produced
+func (r *IdempotencyRecord) Complete(statusCode int, contentType string, body []byte) {
+	r.StatusCode = statusCode
+	r.ContentType = contentType
+	r.Body = body
+}
+
+// IsCompleted returns false while the first request is still running
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
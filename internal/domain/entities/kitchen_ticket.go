@@ -0,0 +1,120 @@
+package entities
+
+import "time"
+
+// KitchenTicketStatus represents the possible print statuses of a ticket
+type KitchenTicketStatus string
+
+const (
+	KitchenTicketPending KitchenTicketStatus = "pending"
+	KitchenTicketPrinted KitchenTicketStatus = "printed"
+	KitchenTicketFailed  KitchenTicketStatus = "failed"
+)
+
+// KitchenTicket is a copy of an order's items for the kitchen. Copy 1 is
+// recorded when the order is received and later copies are reprints. A copy
+// is recorded pending and printed in the background, which keeps the
+// attempt count and the error of the last attempt. Items and PrintedAt, the
+// time the paper shows, are set again for every attempt.
+type KitchenTicket struct {
+	ID            uint64              `json:"id"`
+	OrderID       uint64              `json:"order_id"`
+	Copy          int                 `json:"copy"`
+	OrderedAt     time.Time           `json:"ordered_at"`
+	Items         []KitchenTicketItem `json:"items"`
+	Status        KitchenTicketStatus `json:"status"`
+	Attempts      int                 `json:"attempts"`
+	LastError     string              `json:"last_error"`
+	NextAttemptAt time.Time           `json:"next_attempt_at"`
+	PrintedAt     *time.Time          `json:"printed_at"`
+}
+
+// KitchenTicketItem is a line of a kitchen ticket
+type KitchenTicketItem struct {
+	Quantity    uint32   `json:"quantity"`
+	Description string   `json:"description"`
+	Modifiers   []string `json:"modifiers"`
+	Notes       string   `json:"notes"`
+}
+
+// NewKitchenTicket builds copy copyNumber of the ticket of an order with its
+// items loaded, naming them after the products they are for (by ID). The
+// copy is pending and due immediately.
+func NewKitchenTicket(order *Order, products map[uint64]*Product, copyNumber int) *KitchenTicket {
+	ticket := &KitchenTicket{
+		OrderID:       order.ID,
+		Copy:          copyNumber,
+		Status:        KitchenTicketPending,
+		NextAttemptAt: time.Now(),
+	}
+	ticket.Fill(order, products)
+	return ticket
+}
+
+// Fill rebuilds the ticket's items from an order with its items loaded
+func (t *KitchenTicket) Fill(order *Order, products map[uint64]*Product) {
+	t.OrderedAt = order.CreatedAt
+	t.Items = []KitchenTicketItem{}
+
+	for _, item := range order.Items {
+		line := KitchenTicketItem{
+			Quantity:  item.Quantity,
+			Modifiers: item.Modifiers,
+			Notes:     item.Notes,
+		}
+		if product := products[item.ProductID]; product != nil {
+			line.Description = product.Name
+		}
+		t.Items = append(t.Items, line)
+	}
+}
+
+// IsReprint returns true for every copy after the first
+func (t *KitchenTicket) IsReprint() bool {
+	return t.Copy > 1
+}
+
+// MarkPrinted records an attempt the printer took
+func (t *KitchenTicket) MarkPrinted(at time.Time) {
+	t.Attempts++
+	t.Status = KitchenTicketPrinted
+	t.LastError = ""
+	t.PrintedAt = &at
+}
+
+// MarkFailed records a failed attempt. Without a retryAt the ticket gives
+// up and becomes failed.
+func (t *KitchenTicket) MarkFailed(err error, retryAt *time.Time) {
+	t.Attempts++
+	t.LastError = err.Error()
+	t.PrintedAt = nil
+	if retryAt == nil {
+		t.Status = KitchenTicketFailed
+		return
+	}
+	t.Status = KitchenTicketPending
+	t.NextAttemptAt = *retryAt
+}
+
+// KitchenPrintRetry spaces out the attempts to print a ticket. The first
+// retry waits InitialBackoff and each following one twice as long, up to
+// MaxBackoff; the ticket is failed after MaxAttempts.
+type KitchenPrintRetry struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Next returns when to try again after failedAttempts, counting the one
+// that just failed, or nil to give up
+func (r KitchenPrintRetry) Next(failedAttempts int, now time.Time) *time.Time {
+	if failedAttempts >= r.MaxAttempts {
+		return nil
+	}
+	delay := r.InitialBackoff
+	for i := 1; i < failedAttempts && delay < r.MaxBackoff; i++ {
+		delay *= 2
+	}
+	next := now.Add(min(delay, r.MaxBackoff))
+	return &next
+}
@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type LoyaltyEntryType string
+
+const (
+	// LoyaltyEarn credits the points of a completed order
+	LoyaltyEarn LoyaltyEntryType = "earn"
+	// LoyaltyRedeem debits the points spent as a discount at checkout
+	LoyaltyRedeem LoyaltyEntryType = "redeem"
+	// LoyaltyRestore credits back the points redeemed on a cancelled order
+	LoyaltyRestore LoyaltyEntryType = "restore"
+	// LoyaltyReverse debits the points earned on a refunded payment
+	LoyaltyReverse LoyaltyEntryType = "reverse"
+	// LoyaltyExpire debits the points left in lots past their expiry
+	LoyaltyExpire LoyaltyEntryType = "expire"
+)
+
+// LoyaltyEntry is a line of a customer's points ledger. Credits (earn,
+// restore) have positive Points and may expire; debits are negative.
+type LoyaltyEntry struct {
+	ID         uint64           `json:"id"`
+	CustomerID uint64           `json:"customer_id"`
+	OrderID    uint64           `json:"order_id"`
+	PaymentID  uint64           `json:"payment_id"`
+	Type       LoyaltyEntryType `json:"type"`
+	Points     int64            `json:"points"`
+	ExpiresAt  *time.Time       `json:"expires_at"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+func NewLoyaltyEntry(customerID, orderID uint64, entryType LoyaltyEntryType, points int64) *LoyaltyEntry {
+	return &LoyaltyEntry{
+		CustomerID: customerID,
+		OrderID:    orderID,
+		Type:       entryType,
+		Points:     points,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// IsCredit returns true if the entry adds points
+func (e *LoyaltyEntry) IsCredit() bool {
+	return e.Type == LoyaltyEarn || e.Type == LoyaltyRestore
+}
+
+// LoyaltyProgram holds the rules for earning, redeeming and expiring points
+type LoyaltyProgram struct {
+	// PointsPerReal is how many points each R$ 1,00 paid earns
+	PointsPerReal float64
+	// PointValue is how much one point takes off an order, in reais
+	PointValue float64
+	// PointsTTL is how long credited points last; zero means forever
+	PointsTTL time.Duration
+}
+
+// PointsFor returns the points earned by paying amount, rounded down
+func (p LoyaltyProgram) PointsFor(amount float32) int64 {
+	if amount <= 0 {
+		return 0
+	}
+	// Rounded to cents first so 0.1+0.2 style errors do not lose a point
+	return int64(math.Floor(math.Round(float64(amount)*100) * p.PointsPerReal / 100))
+}
+
+// ValueOf returns how much points take off an order
+func (p LoyaltyProgram) ValueOf(points int64) float32 {
+	return roundCents(float64(points) * p.PointValue)
+}
+
+// PointsCovering returns how many points, at most, fit in amount without
+// exceeding it
+func (p LoyaltyProgram) PointsCovering(amount float32) int64 {
+	if amount <= 0 || p.PointValue <= 0 {
+		return 0
+	}
+	return int64(math.Floor(math.Round(float64(amount)*100) / math.Round(p.PointValue*100)))
+}
+
+// Credit stamps entry with the expiry of points credited at now
+func (p LoyaltyProgram) Credit(entry *LoyaltyEntry, now time.Time) {
+	if p.PointsTTL > 0 {
+		expiresAt := now.Add(p.PointsTTL)
+		entry.ExpiresAt = &expiresAt
+	}
+}
+
+// RedemptionLine builds the order discount line for redeeming points
+func (p LoyaltyProgram) RedemptionLine(points int64) OrderDiscount {
+	return OrderDiscount{
+		Description: fmt.Sprintf("Resgate de %d pontos", points),
+		Amount:      p.ValueOf(points),
+	}
+}
+
+// LoyaltyLot is what is left of a credit; ExpiresAt is nil for points that
+// never expire
+type LoyaltyLot struct {
+	Points    int64
+	ExpiresAt *time.Time
+}
+
+// LoyaltyAccount is a customer's points, replayed from the ledger
+type LoyaltyAccount struct {
+	Lots []LoyaltyLot
+}
+
+// NewLoyaltyAccount replays entries, oldest first. Credits open lots and
+// debits consume the lots that expire first, so points are spent before
+// they are lost.
+func NewLoyaltyAccount(entries []*LoyaltyEntry) *LoyaltyAccount {
+	account := &LoyaltyAccount{}
+	for _, entry := range entries {
+		if entry.IsCredit() {
+			account.Lots = append(account.Lots, LoyaltyLot{Points: entry.Points, ExpiresAt: entry.ExpiresAt})
+			sort.SliceStable(account.Lots, func(i, j int) bool {
+				return expiresBefore(account.Lots[i].ExpiresAt, account.Lots[j].ExpiresAt)
+			})
+			continue
+		}
+		account.consume(-entry.Points)
+	}
+	return account
+}
+
+func (a *LoyaltyAccount) consume(points int64) {
+	for i := range a.Lots {
+		if points <= 0 {
+			break
+		}
+		used := min(points, a.Lots[i].Points)
+		a.Lots[i].Points -= used
+		points -= used
+	}
+}
+
+// Balance returns the points still usable at now
+func (a *LoyaltyAccount) Balance(now time.Time) int64 {
+	var balance int64
+	for _, lot := range a.Lots {
+		if lot.ExpiresAt == nil || now.Before(*lot.ExpiresAt) {
+			balance += lot.Points
+		}
+	}
+	return balance
+}
+
+// Due returns the points left in lots that expired by now, which an expire
+// entry should debit
+func (a *LoyaltyAccount) Due(now time.Time) int64 {
+	var due int64
+	for _, lot := range a.Lots {
+		if lot.ExpiresAt != nil && !now.Before(*lot.ExpiresAt) {
+			due += lot.Points
+		}
+	}
+	return due
+}
+
+// NextExpiry returns the earliest lot that still has points and expires
+// after now, or nil when no points are due to expire
+func (a *LoyaltyAccount) NextExpiry(now time.Time) *LoyaltyLot {
+	for _, lot := range a.Lots {
+		if lot.Points > 0 && lot.ExpiresAt != nil && now.Before(*lot.ExpiresAt) {
+			return &lot
+		}
+	}
+	return nil
+}
+
+// expiresBefore orders expiry dates, nil (never) last
+func expiresBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.Before(*b)
+}
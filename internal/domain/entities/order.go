@@ -10,6 +10,15 @@ type Order struct {
 	CreatedAt  time.Time   `json:"created_at"`
 	UpdatedAt  time.Time   `json:"updated_at"`
 	Items      []OrderItem `json:"items"`
+	// Discount is the sum of the discount lines, kept on the order so its
+	// total does not need them
+	Discount  float32         `json:"discount"`
+	Discounts []OrderDiscount `json:"discounts"`
+	// ServiceFee, Rounding and Taxes are the rest of the price breakdown,
+	// fixed when the order is placed
+	ServiceFee float32    `json:"service_fee"`
+	Rounding   float32    `json:"rounding"`
+	Taxes      []OrderTax `json:"taxes"`
 }
 
 type OrderStatus string
@@ -23,10 +32,27 @@ const (
 	OrderCancelled       OrderStatus = "cancelled"
 )
 
+// orderTransitions lists the statuses each status can move to. Completed and
+// cancelled orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderAwaitingPayment: {OrderReceived, OrderCancelled},
+	OrderReceived:        {OrderInProgress, OrderCancelled},
+	OrderInProgress:      {OrderReady, OrderCancelled},
+	OrderReady:           {OrderCompleted},
+}
+
+func IsValidOrderStatus(status string) bool {
+	switch OrderStatus(status) {
+	case OrderAwaitingPayment, OrderReceived, OrderInProgress, OrderReady, OrderCompleted, OrderCancelled:
+		return true
+	}
+	return false
+}
+
 func NewOrder(customerId uint64, cpf string) *Order {
 	return &Order{
 		CustomerId: customerId,
-		CPF:        cpf,
+		CPF:        NormalizeCPF(cpf),
 		Status:     OrderAwaitingPayment,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
@@ -44,12 +70,60 @@ func (o *Order) UpdateStatus(status OrderStatus) {
 	o.UpdatedAt = time.Now()
 }
 
-func (o *Order) CalculateTotal() float32 {
-	var total float32
+// IsPaid returns true once the order's payments covered it and until it is
+// cancelled
+func (o *Order) IsPaid() bool {
+	switch o.Status {
+	case OrderReceived, OrderInProgress, OrderReady, OrderCompleted:
+		return true
+	}
+	return false
+}
+
+func (o *Order) CanTransitionTo(status OrderStatus) bool {
+	for _, next := range orderTransitions[o.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyDiscount adds a discount line, lowered so the discounts never exceed
+// the subtotal. Lines that end up empty are dropped.
+func (o *Order) ApplyDiscount(line OrderDiscount) {
+	line.Amount = min(line.Amount, o.CalculateTotal())
+	if line.Amount <= 0 {
+		return
+	}
+	o.Discounts = append(o.Discounts, line)
+	o.Discount = roundCents(float64(o.Discount + line.Amount))
+}
+
+// Subtotal is the price of the items before discounts
+func (o *Order) Subtotal() float32 {
+	var subtotal float32
 	for _, item := range o.Items {
-		total += item.Price * float32(item.Quantity)
+		subtotal += item.Price * float32(item.Quantity)
+	}
+	return subtotal
+}
+
+// CalculateTotal is the amount to pay: the subtotal minus the discounts,
+// plus the service fee and the rounding
+func (o *Order) CalculateTotal() float32 {
+	if o.Discount == 0 && o.ServiceFee == 0 && o.Rounding == 0 {
+		return o.Subtotal()
 	}
-	return total
+	return nonNegativeCents(float64(o.Subtotal() - o.Discount + o.ServiceFee + o.Rounding))
+}
+
+// ApplyBreakdown stores the service fee, rounding and taxes of a priced
+// breakdown on the order
+func (o *Order) ApplyBreakdown(breakdown PriceBreakdown) {
+	o.ServiceFee = breakdown.ServiceFee
+	o.Rounding = breakdown.Rounding
+	o.Taxes = breakdown.Taxes
 }
 
 func (o *Order) IsValid() bool {
@@ -3,11 +3,15 @@ package entities
 import "time"
 
 type OrderItem struct {
-	ID        uint64    `json:"id"`
-	OrderID   uint64    `json:"order_id"`
-	ProductID uint64    `json:"product_id"`
-	Quantity  uint32    `json:"quantity"`
-	Price     float32   `json:"price"`
+	ID        uint64  `json:"id"`
+	OrderID   uint64  `json:"order_id"`
+	ProductID uint64  `json:"product_id"`
+	Quantity  uint32  `json:"quantity"`
+	Price     float32 `json:"price"`
+	// Modifiers and Notes tell the kitchen how to prepare the item, e.g.
+	// "sem cebola"; they do not change its price
+	Modifiers []string  `json:"modifiers"`
+	Notes     string    `json:"notes"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
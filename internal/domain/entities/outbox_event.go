@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types written to the outbox. They are part of the public contract
+// with consumers, so existing values must not change.
+const (
+	EventOrderCreated       = "order.created"
+	EventOrderStatusChanged = "order.status_changed"
+	EventPaymentApproved    = "payment.approved"
+	EventPaymentRejected    = "payment.rejected"
+	EventPaymentCanceled    = "payment.canceled"
+	EventPaymentExpired     = "payment.expired"
+	EventPaymentRefunded    = "payment.refunded"
+)
+
+// Aggregate types an event can refer to
+const (
+	AggregateOrder   = "order"
+	AggregatePayment = "payment"
+)
+
+// OutboxEvent is a domain event stored with the state change that produced
+// it and published afterwards by the relay. Delivery is at least once, so
+// consumers should deduplicate by ID.
+type OutboxEvent struct {
+	ID            uint64     `json:"id"`
+	EventType     string     `json:"event_type"`
+	AggregateType string     `json:"aggregate_type"`
+	AggregateID   uint64     `json:"aggregate_id"`
+	Payload       []byte     `json:"payload"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	PublishedAt   *time.Time `json:"published_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NewOutboxEvent creates an event whose payload is payload encoded as JSON
+func NewOutboxEvent(eventType, aggregateType string, aggregateID uint64, payload any) (*OutboxEvent, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       raw,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkPublished records a successful delivery
+func (e *OutboxEvent) MarkPublished(at time.Time) {
+	e.Attempts++
+	e.LastError = ""
+	e.PublishedAt = &at
+}
+
+// MarkFailed records a failed delivery and when to try again
+func (e *OutboxEvent) MarkFailed(err error, retryAt time.Time) {
+	e.Attempts++
+	e.LastError = err.Error()
+	e.NextAttemptAt = retryAt
+}
+
+// IsPublished returns true once the event was delivered
+func (e *OutboxEvent) IsPublished() bool {
+	return e.PublishedAt != nil
+}
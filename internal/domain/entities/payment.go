@@ -12,18 +12,33 @@ const (
 	PaymentStatusApproved PaymentStatus = "approved"
 	PaymentStatusRejected PaymentStatus = "rejected"
 	PaymentStatusCanceled PaymentStatus = "canceled"
+	PaymentStatusExpired  PaymentStatus = "expired"
+	PaymentStatusRefunded PaymentStatus = "refunded"
 )
 
-// Payment represents a payment entity
+// paymentTransitions lists the statuses each payment status can move to.
+// Rejected, canceled, expired and refunded payments are final.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending:  {PaymentStatusApproved, PaymentStatusRejected, PaymentStatusCanceled, PaymentStatusExpired},
+	PaymentStatusApproved: {PaymentStatusRefunded},
+}
+
+// Payment represents a payment entity. An order may be paid in several
+// parts, each a Payment for a share of the total; Payer optionally names who
+// pays the share.
 type Payment struct {
 	ID            uint64        `json:"id"`
 	OrderID       uint64        `json:"order_id"`
 	Amount        float32       `json:"amount"`
 	Status        PaymentStatus `json:"status"`
 	PaymentMethod string        `json:"payment_method"`
+	Payer         string        `json:"payer,omitempty"`
 	TransactionID string        `json:"transaction_id"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+	// ExpiresAt is when a pending payment stops being payable; nil means it
+	// never expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 // NewPayment creates a new payment instance
@@ -39,6 +54,47 @@ func NewPayment(orderID uint64, amount float32, paymentMethod string) *Payment {
 	}
 }
 
+// ExpireAfter sets the payment to expire ttl after it was created. A ttl of
+// zero or less leaves it without expiry.
+func (p *Payment) ExpireAfter(ttl time.Duration) {
+	if ttl <= 0 {
+		p.ExpiresAt = nil
+		return
+	}
+	expiresAt := p.CreatedAt.Add(ttl)
+	p.ExpiresAt = &expiresAt
+}
+
+// IsDue returns true if the payment is still pending past its expiry time
+func (p *Payment) IsDue(now time.Time) bool {
+	return p.IsPending() && p.ExpiresAt != nil && !p.ExpiresAt.After(now)
+}
+
+// Expire marks the payment as expired, keeping its transaction ID
+func (p *Payment) Expire() {
+	p.UpdateStatus(PaymentStatusExpired, p.TransactionID)
+}
+
+// Refund marks an approved payment as refunded, keeping its transaction ID.
+// It returns false, leaving the payment untouched, when it was not approved.
+func (p *Payment) Refund() bool {
+	if !p.IsApproved() {
+		return false
+	}
+	p.UpdateStatus(PaymentStatusRefunded, p.TransactionID)
+	return true
+}
+
+// CanTransitionTo returns true if the payment may move to status
+func (p *Payment) CanTransitionTo(status PaymentStatus) bool {
+	for _, next := range paymentTransitions[p.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateStatus updates the payment status and transaction ID
 func (p *Payment) UpdateStatus(status PaymentStatus, transactionID string) {
 	p.Status = status
@@ -56,7 +112,23 @@ func (p *Payment) IsRejected() bool {
 	return p.Status == PaymentStatusRejected
 }
 
+// IsActive returns true while the attempt can still be paid. An order has at
+// most one active attempt; a new one is only made after it failed.
+func (p *Payment) IsActive() bool {
+	return p.IsPending()
+}
+
 // IsPending returns true if the payment is pending
 func (p *Payment) IsPending() bool {
 	return p.Status == PaymentStatusPending
 }
+
+// IsRefunded returns true if the payment was refunded after approval
+func (p *Payment) IsRefunded() bool {
+	return p.Status == PaymentStatusRefunded
+}
+
+// IsExpired returns true if the payment expired before being paid
+func (p *Payment) IsExpired() bool {
+	return p.Status == PaymentStatusExpired
+}
@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// ProviderPayment is a payment as the payment provider records it. OrderID
+// is the reference the provider keeps for our order.
+type ProviderPayment struct {
+	TransactionID string        `json:"transaction_id"`
+	OrderID       uint64        `json:"order_id"`
+	Status        PaymentStatus `json:"status"`
+	Amount        float32       `json:"amount"`
+	PaymentMethod string        `json:"payment_method"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// IsSettled returns true once the provider reached a final status we can
+// apply to our payment
+func (p *ProviderPayment) IsSettled() bool {
+	switch p.Status {
+	case PaymentStatusApproved, PaymentStatusRejected, PaymentStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// MismatchKind tells how our payment and the provider's record disagree
+type MismatchKind string
+
+const (
+	MismatchStatus            MismatchKind = "status"
+	MismatchAmount            MismatchKind = "amount"
+	MismatchMissingAtProvider MismatchKind = "missing_at_provider"
+	MismatchMissingLocally    MismatchKind = "missing_locally"
+)
+
+// ReconciliationMismatch is one disagreement found by a reconciliation.
+// PaymentID is zero when the payment is missing locally; the provider fields
+// are empty when it is missing at the provider.
+type ReconciliationMismatch struct {
+	Kind           MismatchKind  `json:"kind"`
+	PaymentID      uint64        `json:"payment_id"`
+	OrderID        uint64        `json:"order_id"`
+	TransactionID  string        `json:"transaction_id"`
+	LocalStatus    PaymentStatus `json:"local_status"`
+	ProviderStatus PaymentStatus `json:"provider_status"`
+	LocalAmount    float32       `json:"local_amount"`
+	ProviderAmount float32       `json:"provider_amount"`
+}
+
+// ReconciliationReport compares the payments created on one UTC day with
+// the provider's records of that day
+type ReconciliationReport struct {
+	Date             time.Time                `json:"date"`
+	PaymentsChecked  int                      `json:"payments_checked"`
+	ProviderPayments int                      `json:"provider_payments"`
+	Mismatches       []ReconciliationMismatch `json:"mismatches"`
+	GeneratedAt      time.Time                `json:"generated_at"`
+}
+
+// ReportDay truncates t to the start of its UTC day, the key of a report
+func ReportDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NewReconciliationReport compares local and provider payments of the day.
+// A provider record matches the local payment with its transaction ID or,
+// failing that, a local payment of the same order without a transaction ID.
+func NewReconciliationReport(day time.Time, local []*Payment, provider []*ProviderPayment) *ReconciliationReport {
+	report := &ReconciliationReport{
+		Date:             ReportDay(day),
+		PaymentsChecked:  len(local),
+		ProviderPayments: len(provider),
+		Mismatches:       []ReconciliationMismatch{},
+		GeneratedAt:      time.Now(),
+	}
+
+	matched := make(map[uint64]bool, len(local))
+	for _, record := range provider {
+		payment := matchProviderPayment(local, matched, record)
+		if payment == nil {
+			report.Mismatches = append(report.Mismatches, ReconciliationMismatch{
+				Kind:           MismatchMissingLocally,
+				OrderID:        record.OrderID,
+				TransactionID:  record.TransactionID,
+				ProviderStatus: record.Status,
+				ProviderAmount: record.Amount,
+			})
+			continue
+		}
+		matched[payment.ID] = true
+
+		mismatch := ReconciliationMismatch{
+			PaymentID:      payment.ID,
+			OrderID:        payment.OrderID,
+			TransactionID:  record.TransactionID,
+			LocalStatus:    payment.Status,
+			ProviderStatus: record.Status,
+			LocalAmount:    payment.Amount,
+			ProviderAmount: record.Amount,
+		}
+		if !statusesAgree(payment.Status, record.Status) {
+			mismatch.Kind = MismatchStatus
+			report.Mismatches = append(report.Mismatches, mismatch)
+		}
+		if math.Abs(float64(payment.Amount-record.Amount)) >= 0.01 {
+			mismatch.Kind = MismatchAmount
+			report.Mismatches = append(report.Mismatches, mismatch)
+		}
+	}
+
+	// Pending and expired payments were never paid, so the provider may
+	// rightly have no record of them
+	for _, payment := range local {
+		if matched[payment.ID] || payment.IsPending() || payment.IsExpired() {
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, ReconciliationMismatch{
+			Kind:          MismatchMissingAtProvider,
+			PaymentID:     payment.ID,
+			OrderID:       payment.OrderID,
+			TransactionID: payment.TransactionID,
+			LocalStatus:   payment.Status,
+			LocalAmount:   payment.Amount,
+		})
+	}
+
+	return report
+}
+
+func matchProviderPayment(local []*Payment, matched map[uint64]bool, record *ProviderPayment) *Payment {
+	for _, payment := range local {
+		if !matched[payment.ID] && payment.TransactionID != "" && payment.TransactionID == record.TransactionID {
+			return payment
+		}
+	}
+	for _, payment := range local {
+		if !matched[payment.ID] && payment.TransactionID == "" && payment.OrderID == record.OrderID {
+			return payment
+		}
+	}
+	return nil
+}
+
+// statusesAgree treats an expired payment as agreeing with a provider record
+// that was never paid
+func statusesAgree(local, provider PaymentStatus) bool {
+	if local == PaymentStatusExpired {
+		return provider == PaymentStatusPending || provider == PaymentStatusCanceled
+	}
+	return local == provider
+}
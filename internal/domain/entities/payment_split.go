@@ -0,0 +1,68 @@
+package entities
+
+import "math"
+
+// PaymentSummary adds up the payment parts of an order. Amounts are rounded
+// to cents, so shares like 3 x 33.33 cover 99.99 exactly.
+type PaymentSummary struct {
+	Total   float32
+	Paid    float32
+	Pending float32
+}
+
+// SummarizePayments totals the approved and the pending parts among
+// payments for an order worth total. Failed and refunded parts count for
+// nothing.
+func SummarizePayments(total float32, payments []*Payment) PaymentSummary {
+	var paid, pending float64
+	for _, payment := range payments {
+		switch {
+		case payment.IsApproved():
+			paid += float64(payment.Amount)
+		case payment.IsActive():
+			pending += float64(payment.Amount)
+		}
+	}
+
+	return PaymentSummary{
+		Total:   roundCents(float64(total)),
+		Paid:    roundCents(paid),
+		Pending: roundCents(pending),
+	}
+}
+
+// Due is what is left to be paid, whether or not a pending part covers it
+func (s PaymentSummary) Due() float32 {
+	return nonNegativeCents(float64(s.Total) - float64(s.Paid))
+}
+
+// Outstanding is what no approved or pending part covers yet, the most a new
+// part may be for
+func (s PaymentSummary) Outstanding() float32 {
+	return nonNegativeCents(float64(s.Total) - float64(s.Paid) - float64(s.Pending))
+}
+
+// IsCovered returns true once the approved parts pay the whole total
+func (s PaymentSummary) IsCovered() bool {
+	return s.Due() == 0
+}
+
+// SumsTo returns true if the amounts add up to total, to the cent
+func SumsTo(total float32, amounts ...float32) bool {
+	var sum float64
+	for _, amount := range amounts {
+		sum += float64(amount)
+	}
+	return roundCents(sum) == roundCents(float64(total))
+}
+
+func roundCents(amount float64) float32 {
+	return float32(math.Round(amount*100) / 100)
+}
+
+func nonNegativeCents(amount float64) float32 {
+	if rounded := roundCents(amount); rounded > 0 {
+		return rounded
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package entities
+
+// OrderTax is a tax line of an order: the approximate taxes included in the
+// price of its items of one category, as shown on fiscal receipts
+type OrderTax struct {
+	ID       uint64 `json:"id"`
+	OrderID  uint64 `json:"order_id"`
+	Category string `json:"category"`
+	// Rate is the tax rate in percent
+	Rate float32 `json:"rate"`
+	// Base is what the category's items cost after discounts
+	Base   float32 `json:"base"`
+	Amount float32 `json:"amount"`
+}
+
+// RoundingMode is how an order total is rounded to the rounding increment
+type RoundingMode string
+
+const (
+	RoundHalfUp RoundingMode = "half_up"
+	RoundDown   RoundingMode = "down"
+	RoundUp     RoundingMode = "up"
+)
+
+func IsValidRoundingMode(mode string) bool {
+	switch RoundingMode(mode) {
+	case RoundHalfUp, RoundDown, RoundUp:
+		return true
+	}
+	return false
+}
+
+// PriceBreakdown is how an order's total was reached. It is stored on the
+// order when it is placed, so later changes to the pricing rules do not
+// change past totals.
+type PriceBreakdown struct {
+	Subtotal   float32
+	Discount   float32
+	ServiceFee float32
+	// Rounding is what rounding the total added, negative when it took off
+	Rounding float32
+	Taxes    []OrderTax
+	Total    float32
+}
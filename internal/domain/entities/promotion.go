@@ -0,0 +1,239 @@
+package entities
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type PromotionType string
+
+const (
+	// PromotionPercentage takes Value percent off the eligible items
+	PromotionPercentage PromotionType = "percentage"
+	// PromotionFixed takes Value off, up to the eligible items' subtotal
+	PromotionFixed PromotionType = "fixed"
+	// PromotionBuyXGetY makes FreeQuantity of every BuyQuantity+FreeQuantity
+	// eligible units free, the cheapest ones first
+	PromotionBuyXGetY PromotionType = "buy_x_get_y"
+)
+
+func IsValidPromotionType(promotionType string) bool {
+	switch PromotionType(promotionType) {
+	case PromotionPercentage, PromotionFixed, PromotionBuyXGetY:
+		return true
+	}
+	return false
+}
+
+// clockPattern matches a time of day in 24h HH:MM format
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Promotion is a discount rule. Promotions without a Code apply to every
+// order placed while they are in effect; the others only to orders that
+// carry the coupon code.
+type Promotion struct {
+	ID           uint64        `json:"id"`
+	Name         string        `json:"name"`
+	Code         string        `json:"code"`
+	Type         PromotionType `json:"type"`
+	Value        float32       `json:"value"`
+	BuyQuantity  uint32        `json:"buy_quantity"`
+	FreeQuantity uint32        `json:"free_quantity"`
+	// Category limits the promotion to products of one category; empty
+	// means every product
+	Category string `json:"category"`
+
+	// StartsAt and EndsAt bound the promotion; nil leaves that side open
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	// Weekdays, DailyStart and DailyEnd narrow it to days of the week and a
+	// time of day (HH:MM, end exclusive) in the store's time zone. Empty
+	// values mean every day and all day.
+	Weekdays   []time.Weekday `json:"weekdays"`
+	DailyStart string         `json:"daily_start"`
+	DailyEnd   string         `json:"daily_end"`
+
+	// MaxUsesPerCustomer caps how many orders of one customer may use the
+	// promotion; zero means unlimited
+	MaxUsesPerCustomer uint32    `json:"max_uses_per_customer"`
+	Active             bool      `json:"active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// OrderDiscount is a discount line of an order: what a promotion took off
+// when the order was placed
+type OrderDiscount struct {
+	ID          uint64  `json:"id"`
+	OrderID     uint64  `json:"order_id"`
+	PromotionID uint64  `json:"promotion_id"`
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Amount      float32 `json:"amount"`
+}
+
+func NewPromotion(name, code string, promotionType PromotionType, value float32) *Promotion {
+	now := time.Now()
+	return &Promotion{
+		Name:      name,
+		Code:      NormalizeCouponCode(code),
+		Type:      promotionType,
+		Value:     value,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NormalizeCouponCode makes coupon codes case-insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValid checks the rule parameters of the promotion's type and its time
+// window
+func (p *Promotion) IsValid() bool {
+	if p.Name == "" || !IsValidPromotionType(string(p.Type)) {
+		return false
+	}
+	if p.Category != "" && !IsValidCategory(p.Category) {
+		return false
+	}
+
+	switch p.Type {
+	case PromotionPercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return false
+		}
+	case PromotionFixed:
+		if p.Value <= 0 {
+			return false
+		}
+	case PromotionBuyXGetY:
+		if p.BuyQuantity == 0 || p.FreeQuantity == 0 {
+			return false
+		}
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return false
+	}
+	if (p.DailyStart == "") != (p.DailyEnd == "") {
+		return false
+	}
+	if p.DailyStart != "" && (!clockPattern.MatchString(p.DailyStart) || !clockPattern.MatchString(p.DailyEnd) || p.DailyStart >= p.DailyEnd) {
+		return false
+	}
+	return true
+}
+
+// IsCoupon returns true if the promotion needs a coupon code
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != ""
+}
+
+// InEffectAt returns true if the promotion is active and now falls in its
+// time window. Weekdays and times of day are read in now's location.
+func (p *Promotion) InEffectAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	if len(p.Weekdays) > 0 && !slices.Contains(p.Weekdays, now.Weekday()) {
+		return false
+	}
+	if p.DailyStart != "" {
+		clock := now.Format("15:04")
+		if clock < p.DailyStart || clock >= p.DailyEnd {
+			return false
+		}
+	}
+	return true
+}
+
+// Discount returns how much the promotion takes off items, rounded to
+// cents. categories maps each product ID to its category.
+func (p *Promotion) Discount(items []OrderItem, categories map[uint64]string) float32 {
+	var eligible []OrderItem
+	var subtotal float32
+	for _, item := range items {
+		if p.Category != "" && categories[item.ProductID] != p.Category {
+			continue
+		}
+		eligible = append(eligible, item)
+		subtotal += item.CalculateSubtotal()
+	}
+	if len(eligible) == 0 {
+		return 0
+	}
+
+	var discount float32
+	switch p.Type {
+	case PromotionPercentage:
+		discount = subtotal * p.Value / 100
+	case PromotionFixed:
+		discount = min(p.Value, subtotal)
+	case PromotionBuyXGetY:
+		discount = p.freeUnitsValue(eligible)
+	}
+	return roundCents(float64(discount))
+}
+
+// freeUnitsValue adds up the price of the free units: of every group of
+// BuyQuantity+FreeQuantity units, the FreeQuantity cheapest are free. Units
+// are counted per line, never one by one.
+func (p *Promotion) freeUnitsValue(items []OrderItem) float32 {
+	lines := slices.Clone(items)
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Price < lines[j].Price })
+
+	var units uint64
+	for _, item := range lines {
+		units += uint64(item.Quantity)
+	}
+	free := units / uint64(p.BuyQuantity+p.FreeQuantity) * uint64(p.FreeQuantity)
+
+	var value float32
+	for _, item := range lines {
+		if free == 0 {
+			break
+		}
+		taken := min(free, uint64(item.Quantity))
+		value += item.Price * float32(taken)
+		free -= taken
+	}
+	return value
+}
+
+// DiscountLine builds the order discount line for amount
+func (p *Promotion) DiscountLine(amount float32) OrderDiscount {
+	return OrderDiscount{
+		PromotionID: p.ID,
+		Code:        p.Code,
+		Description: p.Name,
+		Amount:      amount,
+	}
+}
+
+// AllowsMoreUses returns true if a customer who used the promotion uses
+// times may use it again
+func (p *Promotion) AllowsMoreUses(uses int) bool {
+	return p.MaxUsesPerCustomer == 0 || uses < int(p.MaxUsesPerCustomer)
+}
+
+// ParseWeekday returns the weekday named day, sunday to saturday in any case
+func ParseWeekday(day string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(day, weekday.String()) {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
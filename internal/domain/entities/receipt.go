@@ -0,0 +1,114 @@
+package entities
+
+import "time"
+
+// ReceiptIssuer identifies the store on its receipts
+type ReceiptIssuer struct {
+	Name    string `json:"name"`
+	CNPJ    string `json:"cnpj"`
+	Address string `json:"address"`
+}
+
+// Receipt is the fiscal-style receipt of a paid order, numbered by the order
+// ID. The document is fixed when it is issued and kept even if the order is
+// deleted; XML and Text are its renditions, stored with it.
+type Receipt struct {
+	ID         uint64           `json:"id"`
+	OrderID    uint64           `json:"order_id"`
+	Issuer     ReceiptIssuer    `json:"issuer"`
+	CPF        string           `json:"cpf"`
+	Items      []ReceiptItem    `json:"items"`
+	Subtotal   float32          `json:"subtotal"`
+	Discount   float32          `json:"discount"`
+	ServiceFee float32          `json:"service_fee"`
+	Rounding   float32          `json:"rounding"`
+	Total      float32          `json:"total"`
+	Taxes      []ReceiptTax     `json:"taxes"`
+	TaxTotal   float32          `json:"tax_total"`
+	Payments   []ReceiptPayment `json:"payments"`
+	IssuedAt   time.Time        `json:"issued_at"`
+	XML        []byte           `json:"-"`
+	Text       []byte           `json:"-"`
+}
+
+// ReceiptItem is a line of a receipt; Number counts from 1
+type ReceiptItem struct {
+	Number      int     `json:"number"`
+	ProductID   uint64  `json:"product_id"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Quantity    uint32  `json:"quantity"`
+	UnitPrice   float32 `json:"unit_price"`
+	Total       float32 `json:"total"`
+}
+
+// ReceiptTax is the approximate tax included in a category's items
+type ReceiptTax struct {
+	Category string  `json:"category"`
+	Rate     float32 `json:"rate"`
+	Amount   float32 `json:"amount"`
+}
+
+// ReceiptPayment is an approved payment of the order
+type ReceiptPayment struct {
+	Method        string  `json:"method"`
+	Payer         string  `json:"payer,omitempty"`
+	Amount        float32 `json:"amount"`
+	TransactionID string  `json:"transaction_id,omitempty"`
+}
+
+// NewReceipt builds the receipt of a paid order from its items, the products
+// they are for (by ID) and its payments, of which only approved ones are
+// listed. The order must have its items and tax lines loaded.
+func NewReceipt(issuer ReceiptIssuer, order *Order, products map[uint64]*Product, payments []*Payment) *Receipt {
+	receipt := &Receipt{
+		OrderID:    order.ID,
+		Issuer:     issuer,
+		CPF:        order.CPF,
+		Items:      []ReceiptItem{},
+		Subtotal:   order.Subtotal(),
+		Discount:   order.Discount,
+		ServiceFee: order.ServiceFee,
+		Rounding:   order.Rounding,
+		Total:      order.CalculateTotal(),
+		Taxes:      []ReceiptTax{},
+		Payments:   []ReceiptPayment{},
+		IssuedAt:   time.Now(),
+	}
+
+	for i, item := range order.Items {
+		line := ReceiptItem{
+			Number:    i + 1,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			Total:     item.CalculateSubtotal(),
+		}
+		if product := products[item.ProductID]; product != nil {
+			line.Description = product.Name
+			line.Category = product.Category
+		}
+		receipt.Items = append(receipt.Items, line)
+	}
+
+	var taxTotal float64
+	for _, tax := range order.Taxes {
+		receipt.Taxes = append(receipt.Taxes, ReceiptTax{Category: tax.Category, Rate: tax.Rate, Amount: tax.Amount})
+		taxTotal += float64(tax.Amount)
+	}
+	receipt.TaxTotal = roundCents(taxTotal)
+
+	for _, payment := range payments {
+		if !payment.IsApproved() {
+			continue
+		}
+		receipt.Payments = append(receipt.Payments, ReceiptPayment{
+			Method:        payment.PaymentMethod,
+			Payer:         payment.Payer,
+			Amount:        payment.Amount,
+			TransactionID: payment.TransactionID,
+		})
+	}
+
+	return receipt
+}
@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+// KitchenStation is a part of the kitchen, like the grill or the drinks
+// counter, that prepares the products of some categories
+type KitchenStation struct {
+	Name       string            `json:"name"`
+	Categories []ProductCategory `json:"categories"`
+}
+
+// Handles returns true when the station prepares products of category
+func (s KitchenStation) Handles(category ProductCategory) bool {
+	return slices.Contains(s.Categories, category)
+}
+
+type StationTicketStatus string
+
+const (
+	StationTicketPending   StationTicketStatus = "pending"
+	StationTicketPreparing StationTicketStatus = "preparing"
+	StationTicketDone      StationTicketStatus = "done"
+)
+
+// stationTicketTransitions lists the statuses each status can move to; done
+// tickets are final
+var stationTicketTransitions = map[StationTicketStatus][]StationTicketStatus{
+	StationTicketPending:   {StationTicketPreparing},
+	StationTicketPreparing: {StationTicketDone},
+}
+
+func IsValidStationTicketStatus(status string) bool {
+	switch StationTicketStatus(status) {
+	case StationTicketPending, StationTicketPreparing, StationTicketDone:
+		return true
+	}
+	return false
+}
+
+// StationTicket is the part of an order one kitchen station prepares. Its
+// items are fixed when the order is split, so changing the stations later
+// does not move items of orders already in the kitchen.
+type StationTicket struct {
+	ID        uint64              `json:"id"`
+	OrderID   uint64              `json:"order_id"`
+	Station   string              `json:"station"`
+	Status    StationTicketStatus `json:"status"`
+	Items     []KitchenTicketItem `json:"items"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// SplitStationTickets splits the items of an order with its items loaded into
+// one pending ticket per station that has something to prepare. Items are
+// routed by the category of their product (by ID); those no station handles,
+// or whose product no longer exists, go to the first station.
+func SplitStationTickets(order *Order, products map[uint64]*Product, stations []KitchenStation) []*StationTicket {
+	if len(stations) == 0 {
+		return nil
+	}
+
+	byStation := make(map[string]*StationTicket)
+	var tickets []*StationTicket
+	for _, item := range order.Items {
+		station := stations[0]
+		line := KitchenTicketItem{
+			Quantity:  item.Quantity,
+			Modifiers: item.Modifiers,
+			Notes:     item.Notes,
+		}
+		if product := products[item.ProductID]; product != nil {
+			line.Description = product.Name
+			for _, candidate := range stations {
+				if candidate.Handles(ProductCategory(product.Category)) {
+					station = candidate
+					break
+				}
+			}
+		}
+
+		ticket, ok := byStation[station.Name]
+		if !ok {
+			ticket = &StationTicket{
+				OrderID:   order.ID,
+				Station:   station.Name,
+				Status:    StationTicketPending,
+				Items:     []KitchenTicketItem{},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+			byStation[station.Name] = ticket
+			tickets = append(tickets, ticket)
+		}
+		ticket.Items = append(ticket.Items, line)
+	}
+
+	return tickets
+}
+
+func (t *StationTicket) CanTransitionTo(status StationTicketStatus) bool {
+	return slices.Contains(stationTicketTransitions[t.Status], status)
+}
+
+func (t *StationTicket) UpdateStatus(status StationTicketStatus) {
+	t.Status = status
+	t.UpdatedAt = time.Now()
+}
+
+// AllStationTicketsDone returns true when an order was split into tickets and
+// every one of them is done
+func AllStationTicketsDone(tickets []*StationTicket) bool {
+	if len(tickets) == 0 {
+		return false
+	}
+	for _, ticket := range tickets {
+		if ticket.Status != StationTicketDone {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package entities
+
+import "time"
+
+// WebhookDeliveryStatus represents the possible delivery statuses
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent to one subscription. It doubles as the
+// delivery log: it keeps the attempt count and the outcome of the last
+// attempt. Payload is the exact body sent, so a redelivery is identical.
+type WebhookDelivery struct {
+	ID             uint64                `json:"id"`
+	SubscriptionID uint64                `json:"subscription_id"`
+	EventID        uint64                `json:"event_id"`
+	EventType      string                `json:"event_type"`
+	Payload        []byte                `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code"`
+	LastError      string                `json:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// NewWebhookDelivery creates a delivery due immediately
+func NewWebhookDelivery(subscriptionID, eventID uint64, eventType string, payload []byte) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// MarkSucceeded records an attempt the endpoint accepted
+func (d *WebhookDelivery) MarkSucceeded(statusCode int, at time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliverySucceeded
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = &at
+	d.UpdatedAt = at
+}
+
+// MarkFailed records a failed attempt. statusCode is zero when no response
+// arrived. Without a retryAt the delivery gives up and becomes failed.
+func (d *WebhookDelivery) MarkFailed(statusCode int, err error, retryAt *time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = err.Error()
+	d.UpdatedAt = time.Now()
+	if retryAt == nil {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = *retryAt
+}
+
+// Redeliver queues the delivery again with a fresh retry budget, whatever
+// its status
+func (d *WebhookDelivery) Redeliver() {
+	now := time.Now()
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+}
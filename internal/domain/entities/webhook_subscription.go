@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"net/url"
+	"slices"
+	"time"
+)
+
+// WebhookSubscription registers a partner endpoint for outgoing webhooks.
+// Deliveries are signed with Secret, so partners can verify they came from
+// us.
+type WebhookSubscription struct {
+	ID         uint64    `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OrderStatusEventType is the webhook event type sent when an order reaches
+// status, e.g. order.ready. It lets partners subscribe to one status instead
+// of every order.status_changed.
+func OrderStatusEventType(status OrderStatus) string {
+	return "order." + string(status)
+}
+
+// WebhookEventTypes lists the event types a subscription can ask for: every
+// outbox event plus one per status an order can move to
+func WebhookEventTypes() []string {
+	return []string{
+		EventOrderCreated,
+		EventOrderStatusChanged,
+		OrderStatusEventType(OrderReceived),
+		OrderStatusEventType(OrderInProgress),
+		OrderStatusEventType(OrderReady),
+		OrderStatusEventType(OrderCompleted),
+		OrderStatusEventType(OrderCancelled),
+		EventPaymentApproved,
+		EventPaymentRejected,
+		EventPaymentCanceled,
+		EventPaymentExpired,
+		EventPaymentRefunded,
+	}
+}
+
+func IsValidWebhookEventType(eventType string) bool {
+	return slices.Contains(WebhookEventTypes(), eventType)
+}
+
+func NewWebhookSubscription(url, secret string, eventTypes []string) *WebhookSubscription {
+	now := time.Now()
+	return &WebhookSubscription{
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// IsValid requires an absolute http(s) URL, a secret and at least one known
+// event type
+func (s *WebhookSubscription) IsValid() bool {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	if s.Secret == "" || len(s.EventTypes) == 0 {
+		return false
+	}
+	for _, eventType := range s.EventTypes {
+		if !IsValidWebhookEventType(eventType) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscribes returns true if the subscription asked for eventType
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	return slices.Contains(s.EventTypes, eventType)
+}
@@ -0,0 +1,67 @@
+package errs
+
+// Error codes are part of the public API: clients may switch on them, so
+// existing values must not change.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeInternal       = "internal_error"
+	CodeRouteNotFound  = "route_not_found"
+	CodeNotAcceptable  = "not_acceptable"
+	CodeRequestTimeout = "request_timeout"
+	CodeRateLimited    = "rate_limited"
+
+	CodeInvalidIdempotencyKey = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused  = "idempotency_key_reused"
+	CodeIdempotencyInProgress = "idempotency_request_in_progress"
+
+	CodeCustomerNotFound      = "customer_not_found"
+	CodeCustomerAlreadyExists = "customer_already_exists"
+	CodeInvalidCustomer       = "invalid_customer"
+
+	CodeProductNotFound        = "product_not_found"
+	CodeInvalidProduct         = "invalid_product"
+	CodeInvalidProductCategory = "invalid_product_category"
+
+	CodeOrderNotFound          = "order_not_found"
+	CodeInvalidOrder           = "invalid_order"
+	CodeInvalidOrderItem       = "invalid_order_item"
+	CodeInvalidOrderStatus     = "invalid_order_status"
+	CodeInvalidOrderTransition = "invalid_order_transition"
+
+	CodePaymentNotFound          = "payment_not_found"
+	CodeInvalidPaymentStatus     = "invalid_payment_status"
+	CodeInvalidPaymentTransition = "invalid_payment_transition"
+	CodePaymentExpired           = "payment_expired"
+	CodeInvalidPaymentSplit      = "invalid_payment_split"
+	CodePaymentExceedsBalance    = "payment_exceeds_balance"
+	CodePaymentNotRefundable     = "payment_not_refundable"
+
+	CodeOrderNotAwaitingPayment = "order_not_awaiting_payment"
+
+	CodeReconciliationReportNotFound = "reconciliation_report_not_found"
+
+	CodeWebhookSubscriptionNotFound = "webhook_subscription_not_found"
+	CodeInvalidWebhookSubscription  = "invalid_webhook_subscription"
+	CodeWebhookDeliveryNotFound     = "webhook_delivery_not_found"
+
+	CodePromotionNotFound      = "promotion_not_found"
+	CodeInvalidPromotion       = "invalid_promotion"
+	CodeCouponCodeTaken        = "coupon_code_taken"
+	CodeInvalidCoupon          = "invalid_coupon"
+	CodeCouponRequiresCustomer = "coupon_requires_customer"
+	CodeCouponUsageExceeded    = "coupon_usage_exceeded"
+
+	CodeLoyaltyRequiresCustomer   = "loyalty_requires_customer"
+	CodeInsufficientLoyaltyPoints = "insufficient_loyalty_points"
+
+	CodeOrderNotPaid = "order_not_paid"
+
+	CodeKitchenPrinterDisabled  = "kitchen_printer_disabled"
+	CodeKitchenTicketInProgress = "kitchen_ticket_in_progress"
+
+	CodeKitchenStationNotFound         = "kitchen_station_not_found"
+	CodeStationTicketNotFound          = "station_ticket_not_found"
+	CodeInvalidStationTicketStatus     = "invalid_station_ticket_status"
+	CodeInvalidStationTicketTransition = "invalid_station_ticket_transition"
+	CodeOrderNotInKitchen              = "order_not_in_kitchen"
+)
@@ -0,0 +1,58 @@
+// Package errs holds the error kinds use cases return so adapters can react
+// to them without comparing messages.
+package errs
+
+import "errors"
+
+// Error kinds. Use errors.Is to check which kind an error belongs to.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("conflict")
+	ErrValidation        = errors.New("validation failed")
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+// Error is a domain error with a stable code that is exposed to clients
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NotFound returns an error of kind ErrNotFound
+func NotFound(code, message string) error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// Conflict returns an error of kind ErrConflict
+func Conflict(code, message string) error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// Validation returns an error of kind ErrValidation
+func Validation(code, message string) error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+// InvalidTransition returns an error of kind ErrInvalidTransition
+func InvalidTransition(code, message string) error {
+	return &Error{Kind: ErrInvalidTransition, Code: code, Message: message}
+}
+
+// CodeOf returns the code of the first *Error in the chain, or "" if there is
+// none
+func CodeOf(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// KitchenTicketUseCase defines the contract for printed kitchen tickets
+type KitchenTicketUseCase interface {
+	// ReprintTicket queues the next copy of a paid order's kitchen ticket
+	ReprintTicket(ctx context.Context, orderID uint64) (*dto.KitchenTicketResponse, error)
+	// Publish queues an order's kitchen ticket once it is received. It
+	// satisfies output.EventPublisher, so the outbox relay drives it.
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+	// PrintDueTickets prints up to limit queued copies due at now, recording
+	// each failure for a later retry. It returns how many it went through.
+	PrintDueTickets(ctx context.Context, now time.Time, limit int) (int, error)
+}
@@ -0,0 +1,18 @@
+package input
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// LoyaltyUseCase defines the contract for the loyalty points program
+type LoyaltyUseCase interface {
+	GetCustomerLoyalty(ctx context.Context, customerID uint64) (*dto.LoyaltyResponse, error)
+	// Publish keeps the ledger in step with orders and payments: completed
+	// orders earn points, cancelled ones get redeemed points back and
+	// refunds take earned points away. It satisfies output.EventPublisher,
+	// so the outbox relay drives it.
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+}
@@ -1,15 +1,19 @@
 package input
 
-import "github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
 
 // OrderUseCase defines the contract for order business operations
 type OrderUseCase interface {
-	CreateOrder(request *dto.CreateOrderRequest) (*dto.OrderResponse, error)
-	GetOrderByID(id uint64) (*dto.OrderResponse, error)
-	GetOrdersByCPF(cpf string) ([]*dto.OrderResponse, error)
-	GetOrdersByCustomerID(customerID uint64) ([]*dto.OrderResponse, error)
-	GetAllOrders() ([]*dto.OrderResponse, error)
-	GetOrdersForKitchen() ([]*dto.OrderResponse, error)
-	UpdateOrderStatus(id uint64, request *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error)
-	DeleteOrder(id uint64) error
+	CreateOrder(ctx context.Context, request *dto.CreateOrderRequest) (*dto.OrderResponse, error)
+	GetOrderByID(ctx context.Context, id uint64) (*dto.OrderResponse, error)
+	GetOrdersByCPF(ctx context.Context, cpf string) ([]*dto.OrderResponse, error)
+	GetOrdersByCustomerID(ctx context.Context, customerID uint64) ([]*dto.OrderResponse, error)
+	GetAllOrders(ctx context.Context) ([]*dto.OrderResponse, error)
+	GetOrdersForKitchen(ctx context.Context) ([]*dto.OrderResponse, error)
+	UpdateOrderStatus(ctx context.Context, id uint64, request *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error)
+	DeleteOrder(ctx context.Context, id uint64) error
 }
@@ -1,11 +1,28 @@
 package input
 
-import "github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
 
 // PaymentUseCase defines the contract for payment business operations
 type PaymentUseCase interface {
-	CreatePayment(request *dto.CreatePaymentRequest) (*dto.PaymentResponse, error)
-	GetPaymentStatus(orderID uint64) (*dto.PaymentStatusResponse, error)
-	GetPaymentByTransactionID(transactionID string) (*dto.PaymentResponse, error)
-	ProcessWebhookPayment(request *dto.WebhookPaymentRequest) error
+	// CreatePayment starts a payment attempt for an order awaiting payment,
+	// or returns the attempt that is still active
+	CreatePayment(ctx context.Context, request *dto.CreatePaymentRequest) (*dto.PaymentResponse, error)
+	// GetPaymentStatus reports the order's latest payment attempt
+	GetPaymentStatus(ctx context.Context, orderID uint64) (*dto.PaymentStatusResponse, error)
+	// GetPaymentAttempts lists the order's payment attempts, oldest first
+	GetPaymentAttempts(ctx context.Context, orderID uint64) ([]*dto.PaymentResponse, error)
+	GetPaymentByTransactionID(ctx context.Context, transactionID string) (*dto.PaymentResponse, error)
+	ProcessWebhookPayment(ctx context.Context, request *dto.WebhookPaymentRequest) error
+	// RefundPayment records the refund of an approved payment part. The
+	// order keeps its status; the money is returned through the provider.
+	RefundPayment(ctx context.Context, paymentID uint64) (*dto.PaymentResponse, error)
+	// ExpirePayments expires up to limit pending payments past their expiry
+	// at now and cancels the orders still awaiting them. It returns how many
+	// payments were expired.
+	ExpirePayments(ctx context.Context, now time.Time, limit int) (int, error)
 }
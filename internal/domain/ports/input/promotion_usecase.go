@@ -0,0 +1,16 @@
+package input
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+// PromotionUseCase defines the contract for promotion and coupon operations
+type PromotionUseCase interface {
+	CreatePromotion(ctx context.Context, request *dto.PromotionRequest) (*dto.PromotionResponse, error)
+	GetPromotionByID(ctx context.Context, id uint64) (*dto.PromotionResponse, error)
+	GetAllPromotions(ctx context.Context) ([]*dto.PromotionResponse, error)
+	UpdatePromotion(ctx context.Context, id uint64, request *dto.PromotionRequest) (*dto.PromotionResponse, error)
+	DeletePromotion(ctx context.Context, id uint64) error
+}
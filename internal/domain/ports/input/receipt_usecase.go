@@ -0,0 +1,18 @@
+package input
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// ReceiptUseCase defines the contract for order receipts
+type ReceiptUseCase interface {
+	// GetOrderReceipt returns the receipt of a paid order, issuing it first
+	// if the order has none yet
+	GetOrderReceipt(ctx context.Context, orderID uint64) (*dto.ReceiptResponse, error)
+	// Publish issues the receipt of an order as soon as it is paid. It
+	// satisfies output.EventPublisher, so the outbox relay drives it.
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+}
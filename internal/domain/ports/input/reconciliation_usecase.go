@@ -0,0 +1,22 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+// ReconciliationUseCase defines the contract for reconciling payments with
+// the payment provider
+type ReconciliationUseCase interface {
+	// ReconcilePendingPayments asks the provider about up to limit pending
+	// payments created before createdBefore and applies the settled ones as
+	// a payment webhook would. It returns how many payments were updated.
+	ReconcilePendingPayments(ctx context.Context, createdBefore time.Time, limit int) (int, error)
+	// GenerateReport compares the payments created on the UTC day of date
+	// with the provider's records and stores the mismatches, replacing any
+	// earlier report of that day
+	GenerateReport(ctx context.Context, date time.Time) (*dto.ReconciliationReportResponse, error)
+	GetReport(ctx context.Context, date time.Time) (*dto.ReconciliationReportResponse, error)
+}
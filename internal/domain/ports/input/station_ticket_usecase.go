@@ -0,0 +1,24 @@
+package input
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// StationTicketUseCase defines the contract for the kitchen stations and
+// the part of each order they prepare
+type StationTicketUseCase interface {
+	GetStations(ctx context.Context) ([]*dto.KitchenStationResponse, error)
+	// GetStationQueue returns the station's open tickets, oldest first
+	GetStationQueue(ctx context.Context, station string) ([]*dto.StationTicketResponse, error)
+	GetOrderStationTickets(ctx context.Context, orderID uint64) ([]*dto.StationTicketResponse, error)
+	// UpdateStationTicketStatus moves a ticket along pending, preparing and
+	// done. The order follows: it is in progress once a station starts on
+	// it and ready once every station is done.
+	UpdateStationTicketStatus(ctx context.Context, id uint64, request *dto.UpdateStationTicketStatusRequest) (*dto.StationTicketResponse, error)
+	// Publish splits an order into station tickets once it is received. It
+	// satisfies output.EventPublisher, so the outbox relay drives it.
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+}
@@ -0,0 +1,22 @@
+package input
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// WebhookUseCase defines the contract for outgoing webhook operations
+type WebhookUseCase interface {
+	CreateSubscription(ctx context.Context, request *dto.CreateWebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error)
+	GetSubscriptionByID(ctx context.Context, id uint64) (*dto.WebhookSubscriptionResponse, error)
+	GetAllSubscriptions(ctx context.Context) ([]*dto.WebhookSubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, id uint64) error
+	GetDeliveries(ctx context.Context, subscriptionID uint64) ([]*dto.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID uint64) (*dto.WebhookDeliveryResponse, error)
+	// Publish queues a delivery of the outbox event for every subscription
+	// that asked for it. It satisfies output.EventPublisher, so the outbox
+	// relay drives the webhooks.
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+}
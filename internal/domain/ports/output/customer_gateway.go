@@ -1,12 +1,22 @@
 package output
 
-import "github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+import (
+	"context"
 
-// CustomerGateway defines the contract for customer data access operations
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// CustomerGateway defines the contract for customer data access operations.
+// Lookups return nil, nil when no customer matches.
 type CustomerGateway interface {
-	Create(customer *entities.Customer) error
-	GetByCPF(cpf string) (*entities.Customer, error)
-	GetByID(id uint64) (*entities.Customer, error)
-	Update(customer *entities.Customer) error
-	Delete(id uint64) error
+	Create(ctx context.Context, customer *entities.Customer) error
+	GetByCPF(ctx context.Context, cpf string) (*entities.Customer, error)
+	GetByID(ctx context.Context, id uint64) (*entities.Customer, error)
+	// GetByIDForUpdate is GetByID that also locks the customer until the
+	// transaction in ctx ends. Writes to the customer's loyalty ledger take
+	// it first, so balance checks and the entries they guard are applied one
+	// at a time.
+	GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Customer, error)
+	Update(ctx context.Context, customer *entities.Customer) error
+	Delete(ctx context.Context, id uint64) error
 }
@@ -0,0 +1,111 @@
+package gatewaytest
+
+import (
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunCustomerGateway checks a CustomerGateway implementation
+func RunCustomerGateway(t *testing.T, newGateways Factory) {
+	t.Run("CreateAssignsID", func(t *testing.T) {
+		gw := newGateways(t).Customer
+
+		first := entities.NewCustomer("João", "Silva", "111.111.111-11", "joao@email.com")
+		second := entities.NewCustomer("Maria", "Santos", "222.222.222-22", "maria@email.com")
+		mustCreate(t, gw.Create(ctx, first))
+		mustCreate(t, gw.Create(ctx, second))
+
+		if first.ID == 0 || second.ID == 0 || first.ID == second.ID {
+			t.Errorf("Expected distinct non-zero IDs, got %d and %d", first.ID, second.ID)
+		}
+	})
+
+	t.Run("GetRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Customer
+
+		customer := entities.NewCustomer("João", "Silva", "111.111.111-11", "joao@email.com")
+		mustCreate(t, gw.Create(ctx, customer))
+
+		byID, err := gw.GetByID(ctx, customer.ID)
+		if err != nil || byID == nil {
+			t.Fatalf("GetByID: expected customer, got %v, %v", byID, err)
+		}
+		byCPF, err := gw.GetByCPF(ctx, customer.CPF)
+		if err != nil || byCPF == nil {
+			t.Fatalf("GetByCPF: expected customer, got %v, %v", byCPF, err)
+		}
+		locked, err := gw.GetByIDForUpdate(ctx, customer.ID)
+		if err != nil || locked == nil || locked.ID != customer.ID || locked.CPF != customer.CPF {
+			t.Fatalf("GetByIDForUpdate: expected customer, got %v, %v", locked, err)
+		}
+
+		for _, found := range []*entities.Customer{byID, byCPF} {
+			if found.ID != customer.ID || found.FirstName != customer.FirstName ||
+				found.LastName != customer.LastName || found.CPF != customer.CPF ||
+				found.Email != customer.Email {
+				t.Errorf("Expected %+v, got %+v", customer, found)
+			}
+			assertSameInstant(t, "CreatedAt", customer.CreatedAt, found.CreatedAt)
+			assertSameInstant(t, "UpdatedAt", customer.UpdatedAt, found.UpdatedAt)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		gw := newGateways(t).Customer
+
+		if found, err := gw.GetByID(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetByID: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByIDForUpdate(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetByIDForUpdate: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByCPF(ctx, "000.000.000-00"); found != nil || err != nil {
+			t.Errorf("GetByCPF: expected nil, nil, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		gw := newGateways(t).Customer
+
+		customer := entities.NewCustomer("João", "Silva", "111.111.111-11", "joao@email.com")
+		customer.UpdatedAt = past(0)
+		mustCreate(t, gw.Create(ctx, customer))
+
+		customer.UpdateCustomer("João", "Souza", "joao.souza@email.com")
+		customer.UpdatedAt = customer.UpdatedAt.Add(timestampTolerance * 5)
+		if err := gw.Update(ctx, customer); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		found, _ := gw.GetByID(ctx, customer.ID)
+		if found == nil || found.LastName != "Souza" || found.Email != "joao.souza@email.com" {
+			t.Fatalf("Expected updated customer, got %+v", found)
+		}
+		assertSameInstant(t, "UpdatedAt", customer.UpdatedAt, found.UpdatedAt)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		gw := newGateways(t).Customer
+
+		customer := entities.NewCustomer("João", "Silva", "111.111.111-11", "joao@email.com")
+		mustCreate(t, gw.Create(ctx, customer))
+
+		if err := gw.Delete(ctx, customer.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if found, err := gw.GetByID(ctx, customer.ID); found != nil || err != nil {
+			t.Errorf("Expected nil, nil after delete, got %v, %v", found, err)
+		}
+		if err := gw.Delete(ctx, customer.ID); err != nil {
+			t.Errorf("Expected deleting a missing customer to succeed, got %v", err)
+		}
+	})
+}
+
+func mustCreate(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
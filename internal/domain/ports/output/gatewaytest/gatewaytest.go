@@ -0,0 +1,87 @@
+// Package gatewaytest is a conformance suite for the output ports. Every
+// gateway backend runs it from its own tests to prove it honours the same
+// contract: generated IDs, nil, nil on lookups that match nothing, result
+// ordering and timestamp round-trips.
+package gatewaytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// timestampTolerance absorbs backends that store timestamps with second
+// precision (MySQL TIMESTAMP rounds fractional seconds)
+const timestampTolerance = time.Second
+
+// Gateways is the set of output ports a backend must provide
+type Gateways struct {
+	Customer  output.CustomerGateway
+	Product   output.ProductGateway
+	Order     output.OrderGateway
+	OrderItem output.OrderItemGateway
+	Payment   output.PaymentGateway
+
+	Idempotency output.IdempotencyGateway
+	Outbox      output.OutboxGateway
+
+	WebhookSubscription output.WebhookSubscriptionGateway
+	WebhookDelivery     output.WebhookDeliveryGateway
+
+	Reconciliation output.ReconciliationGateway
+
+	Promotion     output.PromotionGateway
+	OrderDiscount output.OrderDiscountGateway
+	Loyalty       output.LoyaltyGateway
+	OrderTax      output.OrderTaxGateway
+	Receipt       output.ReceiptGateway
+	KitchenTicket output.KitchenTicketGateway
+	StationTicket output.StationTicketGateway
+}
+
+// Factory returns gateways backed by an empty store. It is called once per
+// test case, so cases never see each other's data.
+type Factory func(t *testing.T) Gateways
+
+// Run executes the conformance suite for every output port
+func Run(t *testing.T, newGateways Factory) {
+	t.Run("CustomerGateway", func(t *testing.T) { RunCustomerGateway(t, newGateways) })
+	t.Run("ProductGateway", func(t *testing.T) { RunProductGateway(t, newGateways) })
+	t.Run("OrderGateway", func(t *testing.T) { RunOrderGateway(t, newGateways) })
+	t.Run("OrderItemGateway", func(t *testing.T) { RunOrderItemGateway(t, newGateways) })
+	t.Run("PaymentGateway", func(t *testing.T) { RunPaymentGateway(t, newGateways) })
+	t.Run("IdempotencyGateway", func(t *testing.T) { RunIdempotencyGateway(t, newGateways) })
+	t.Run("OutboxGateway", func(t *testing.T) { RunOutboxGateway(t, newGateways) })
+	t.Run("WebhookSubscriptionGateway", func(t *testing.T) { RunWebhookSubscriptionGateway(t, newGateways) })
+	t.Run("WebhookDeliveryGateway", func(t *testing.T) { RunWebhookDeliveryGateway(t, newGateways) })
+	t.Run("ReconciliationGateway", func(t *testing.T) { RunReconciliationGateway(t, newGateways) })
+	t.Run("PromotionGateway", func(t *testing.T) { RunPromotionGateway(t, newGateways) })
+	t.Run("OrderDiscountGateway", func(t *testing.T) { RunOrderDiscountGateway(t, newGateways) })
+	t.Run("LoyaltyGateway", func(t *testing.T) { RunLoyaltyGateway(t, newGateways) })
+	t.Run("OrderTaxGateway", func(t *testing.T) { RunOrderTaxGateway(t, newGateways) })
+	t.Run("ReceiptGateway", func(t *testing.T) { RunReceiptGateway(t, newGateways) })
+	t.Run("KitchenTicketGateway", func(t *testing.T) { RunKitchenTicketGateway(t, newGateways) })
+	t.Run("StationTicketGateway", func(t *testing.T) { RunStationTicketGateway(t, newGateways) })
+}
+
+func assertSameInstant(t *testing.T, field string, want, got time.Time) {
+	t.Helper()
+	diff := got.Sub(want)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > timestampTolerance {
+		t.Errorf("Expected %s %v, got %v", field, want, got)
+	}
+}
+
+// past returns a second-aligned instant, so ordering by timestamp is not
+// affected by backends that round fractional seconds
+func past(d time.Duration) time.Time {
+	return time.Now().Add(-d).Truncate(time.Second)
+}
+
+// ctx is passed to every gateway call; the suite does not test cancellation
+var ctx = context.Background()
@@ -0,0 +1,101 @@
+package gatewaytest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// RunIdempotencyGateway checks an IdempotencyGateway implementation
+func RunIdempotencyGateway(t *testing.T, newGateways Factory) {
+	t.Run("ReserveReturnsExisting", func(t *testing.T) {
+		gw := newGateways(t).Idempotency
+
+		first := entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-a")
+		if existing, err := gw.Reserve(ctx, first); existing != nil || err != nil {
+			t.Fatalf("Reserve: expected nil, nil for a new key, got %v, %v", existing, err)
+		}
+
+		existing, err := gw.Reserve(ctx, entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-b"))
+		if err != nil || existing == nil {
+			t.Fatalf("Reserve: expected the existing record, got %v, %v", existing, err)
+		}
+		if existing.RequestHash != "hash-a" || existing.IsCompleted() {
+			t.Errorf("Expected the pending record with hash-a, got %+v", existing)
+		}
+		assertSameInstant(t, "CreatedAt", first.CreatedAt, existing.CreatedAt)
+	})
+
+	t.Run("ScopesAreIndependent", func(t *testing.T) {
+		gw := newGateways(t).Idempotency
+
+		mustReserve(t, gw, entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-a"))
+		existing, err := gw.Reserve(ctx, entities.NewIdempotencyRecord("POST /api/v1/payments", "key-1", "hash-a"))
+		if existing != nil || err != nil {
+			t.Errorf("Reserve: expected the key to be free in another scope, got %v, %v", existing, err)
+		}
+	})
+
+	t.Run("CompleteStoresResponse", func(t *testing.T) {
+		gw := newGateways(t).Idempotency
+
+		record := entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-a")
+		mustReserve(t, gw, record)
+
+		body := []byte(`{"data":{"id":1}}`)
+		record.Complete(201, "application/json; charset=utf-8", body)
+		if err := gw.Complete(ctx, record); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+
+		existing, err := gw.Reserve(ctx, entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-a"))
+		if err != nil || existing == nil {
+			t.Fatalf("Reserve: expected the completed record, got %v, %v", existing, err)
+		}
+		if existing.StatusCode != 201 || existing.ContentType != "application/json; charset=utf-8" || !bytes.Equal(existing.Body, body) {
+			t.Errorf("Expected the stored response, got %+v", existing)
+		}
+	})
+
+	t.Run("ReleaseFreesKey", func(t *testing.T) {
+		gw := newGateways(t).Idempotency
+
+		mustReserve(t, gw, entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-a"))
+		if err := gw.Release(ctx, "POST /api/v1/orders", "key-1"); err != nil {
+			t.Fatalf("Release: %v", err)
+		}
+
+		existing, err := gw.Reserve(ctx, entities.NewIdempotencyRecord("POST /api/v1/orders", "key-1", "hash-b"))
+		if existing != nil || err != nil {
+			t.Errorf("Reserve: expected the released key to be free, got %v, %v", existing, err)
+		}
+	})
+
+	t.Run("DeleteCreatedBefore", func(t *testing.T) {
+		gw := newGateways(t).Idempotency
+
+		old := entities.NewIdempotencyRecord("POST /api/v1/orders", "old", "hash-a")
+		old.CreatedAt = past(48 * time.Hour)
+		mustReserve(t, gw, old)
+		mustReserve(t, gw, entities.NewIdempotencyRecord("POST /api/v1/orders", "recent", "hash-a"))
+
+		deleted, err := gw.DeleteCreatedBefore(ctx, past(24*time.Hour))
+		if err != nil || deleted != 1 {
+			t.Fatalf("DeleteCreatedBefore: expected 1 deleted, got %d, %v", deleted, err)
+		}
+
+		if existing, _ := gw.Reserve(ctx, entities.NewIdempotencyRecord("POST /api/v1/orders", "recent", "hash-a")); existing == nil {
+			t.Error("Expected the recent key to be kept")
+		}
+	})
+}
+
+func mustReserve(t *testing.T, gw output.IdempotencyGateway, record *entities.IdempotencyRecord) {
+	t.Helper()
+	if existing, err := gw.Reserve(ctx, record); existing != nil || err != nil {
+		t.Fatalf("Reserve: expected nil, nil, got %v, %v", existing, err)
+	}
+}
@@ -0,0 +1,188 @@
+package gatewaytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunKitchenTicketGateway checks a KitchenTicketGateway implementation
+func RunKitchenTicketGateway(t *testing.T, newGateways Factory) {
+	t.Run("ListByOrderIDInCopyOrder", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		other := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, other))
+
+		firstDueAt := past(time.Hour)
+		for _, copyNumber := range []int{2, 1} {
+			ticket := pendingTicket(order.ID, copyNumber)
+			if copyNumber == 1 {
+				ticket.NextAttemptAt = firstDueAt
+			}
+			if created, err := gws.KitchenTicket.Create(ctx, ticket); err != nil || !created || ticket.ID == 0 {
+				t.Fatalf("Create: expected a new ticket with an ID, got %v, %v, ID %d", created, err, ticket.ID)
+			}
+		}
+		if _, err := gws.KitchenTicket.Create(ctx, pendingTicket(other.ID, 1)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		tickets, err := gws.KitchenTicket.ListByOrderID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("ListByOrderID: %v", err)
+		}
+		if len(tickets) != 2 || tickets[0].Copy != 1 || tickets[1].Copy != 2 || tickets[0].OrderID != order.ID {
+			t.Fatalf("Expected copies 1 and 2 of order %d, got %+v", order.ID, tickets)
+		}
+		if tickets[0].Status != entities.KitchenTicketPending || tickets[0].Attempts != 0 || tickets[0].PrintedAt != nil {
+			t.Errorf("Expected a pending copy, got %+v", tickets[0])
+		}
+		assertSameInstant(t, "NextAttemptAt", firstDueAt, tickets[0].NextAttemptAt)
+	})
+
+	t.Run("OneRecordPerCopy", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+
+		first := pendingTicket(order.ID, 1)
+		if created, err := gws.KitchenTicket.Create(ctx, first); err != nil || !created {
+			t.Fatalf("Create: expected a new ticket, got %v, %v", created, err)
+		}
+		again := pendingTicket(order.ID, 1)
+
+		created, err := gws.KitchenTicket.Create(ctx, again)
+		if err != nil || created || again.ID != 0 {
+			t.Fatalf("Create: expected the same copy to be skipped, got %v, %v, ID %d", created, err, again.ID)
+		}
+		if tickets, _ := gws.KitchenTicket.ListByOrderID(ctx, order.ID); len(tickets) != 1 {
+			t.Errorf("Expected one ticket, got %+v", tickets)
+		}
+	})
+
+	t.Run("DeletedWithOrder", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		if _, err := gws.KitchenTicket.Create(ctx, pendingTicket(order.ID, 1)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		if err := gws.Order.Delete(ctx, order.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		if tickets, err := gws.KitchenTicket.ListByOrderID(ctx, order.ID); err != nil || len(tickets) != 0 {
+			t.Errorf("Expected no tickets after the order is deleted, got %+v, %v", tickets, err)
+		}
+	})
+
+	t.Run("UpdateControlsDue", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		gw := gws.KitchenTicket
+
+		tickets := make([]*entities.KitchenTicket, 4)
+		for i := range tickets {
+			tickets[i] = pendingTicket(order.ID, i+1)
+			if _, err := gw.Create(ctx, tickets[i]); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		printed, gaveUp, retrying, due := tickets[0], tickets[1], tickets[2], tickets[3]
+		printed.MarkPrinted(past(0))
+		gaveUp.MarkFailed(errors.New("paper out"), nil)
+		retryAt := past(-time.Hour)
+		retrying.MarkFailed(errors.New("connection refused"), &retryAt)
+		for _, ticket := range []*entities.KitchenTicket{printed, gaveUp, retrying} {
+			if err := gw.Update(ctx, ticket); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+		}
+
+		found, err := gw.ListDue(ctx, time.Now().Add(time.Second), 10)
+		if err != nil || len(found) != 1 || found[0].ID != due.ID {
+			t.Fatalf("ListDue: expected only copy %d due now, got %+v, %v", due.Copy, found, err)
+		}
+		if found[0].OrderID != order.ID || found[0].Copy != due.Copy || found[0].Status != entities.KitchenTicketPending {
+			t.Errorf("Expected the due copy, got %+v", found[0])
+		}
+
+		found, err = gw.ListDue(ctx, time.Now().Add(2*time.Hour), 10)
+		if err != nil || len(found) != 2 || found[0].ID != retrying.ID || found[1].ID != due.ID {
+			t.Fatalf("ListDue: expected the retrying copy once due, oldest first, got %+v, %v", found, err)
+		}
+		if found[0].Attempts != 1 || found[0].LastError != "connection refused" {
+			t.Errorf("Expected the retrying copy state, got %+v", found[0])
+		}
+		if found, err := gw.ListDue(ctx, time.Now().Add(2*time.Hour), 1); err != nil || len(found) != 1 {
+			t.Errorf("ListDue: expected the limit to apply, got %d, %v", len(found), err)
+		}
+
+		copies, err := gw.ListByOrderID(ctx, order.ID)
+		if err != nil || len(copies) != 4 {
+			t.Fatalf("ListByOrderID: expected 4 copies, got %d, %v", len(copies), err)
+		}
+		if copies[0].Status != entities.KitchenTicketPrinted || copies[0].Attempts != 1 || copies[0].PrintedAt == nil {
+			t.Errorf("Expected the printed copy state, got %+v", copies[0])
+		} else {
+			assertSameInstant(t, "PrintedAt", *printed.PrintedAt, *copies[0].PrintedAt)
+		}
+		if copies[1].Status != entities.KitchenTicketFailed || copies[1].LastError != "paper out" || copies[1].PrintedAt != nil {
+			t.Errorf("Expected the failed copy state, got %+v", copies[1])
+		}
+	})
+
+	t.Run("ClaimOnce", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		gw := gws.KitchenTicket
+		due, printed := pendingTicket(order.ID, 1), pendingTicket(order.ID, 2)
+		for _, ticket := range []*entities.KitchenTicket{due, printed} {
+			if _, err := gw.Create(ctx, ticket); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		printed.MarkPrinted(past(0))
+		if err := gw.Update(ctx, printed); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		now, until := past(0), past(-time.Minute)
+
+		claimed, err := gw.Claim(ctx, due, now, until)
+		if err != nil || !claimed {
+			t.Fatalf("Claim: expected the due copy to be claimed, got %v, %v", claimed, err)
+		}
+		assertSameInstant(t, "NextAttemptAt", until, due.NextAttemptAt)
+		other := *due
+		other.NextAttemptAt = past(time.Minute)
+		if claimed, err := gw.Claim(ctx, &other, now, until); err != nil || claimed {
+			t.Errorf("Claim: expected a claimed copy not to be claimed again, got %v, %v", claimed, err)
+		}
+		if claimed, err := gw.Claim(ctx, printed, now, until); err != nil || claimed {
+			t.Errorf("Claim: expected a printed copy not to be claimed, got %v, %v", claimed, err)
+		}
+
+		if found, err := gw.ListDue(ctx, now, 10); err != nil || len(found) != 0 {
+			t.Errorf("ListDue: expected the claimed copy to be hidden, got %+v, %v", found, err)
+		}
+		if found, err := gw.ListDue(ctx, until, 10); err != nil || len(found) != 1 || found[0].ID != due.ID {
+			t.Errorf("ListDue: expected the claimed copy due again once the claim lapses, got %+v, %v", found, err)
+		}
+	})
+}
+
+func pendingTicket(orderID uint64, copyNumber int) *entities.KitchenTicket {
+	return &entities.KitchenTicket{
+		OrderID:       orderID,
+		Copy:          copyNumber,
+		Status:        entities.KitchenTicketPending,
+		NextAttemptAt: past(time.Minute),
+	}
+}
@@ -0,0 +1,63 @@
+package gatewaytest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunLoyaltyGateway checks a LoyaltyGateway implementation
+func RunLoyaltyGateway(t *testing.T, newGateways Factory) {
+	t.Run("ListByCustomerIDRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Loyalty
+
+		expiresAt := past(-365 * 24 * time.Hour)
+		earn := entities.NewLoyaltyEntry(7, 10, entities.LoyaltyEarn, 150)
+		earn.ExpiresAt = &expiresAt
+		reverse := entities.NewLoyaltyEntry(7, 10, entities.LoyaltyReverse, -40)
+		reverse.PaymentID = 3
+		mustCreate(t, gw.Create(ctx, earn))
+		mustCreate(t, gw.Create(ctx, reverse))
+		mustCreate(t, gw.Create(ctx, entities.NewLoyaltyEntry(8, 11, entities.LoyaltyEarn, 5)))
+		if earn.ID == 0 || reverse.ID <= earn.ID {
+			t.Fatalf("Expected increasing generated IDs, got %d and %d", earn.ID, reverse.ID)
+		}
+
+		entries, err := gw.ListByCustomerID(ctx, 7)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("ListByCustomerID: expected 2 entries, got %d, %v", len(entries), err)
+		}
+		first, second := entries[0], entries[1]
+		if first.ID != earn.ID || first.Type != entities.LoyaltyEarn || first.Points != 150 || first.OrderID != 10 || first.CustomerID != 7 {
+			t.Errorf("Expected %+v first, got %+v", earn, first)
+		}
+		if first.ExpiresAt == nil {
+			t.Fatal("Expected the earn entry to keep its expiry")
+		}
+		assertSameInstant(t, "ExpiresAt", expiresAt, *first.ExpiresAt)
+		assertSameInstant(t, "CreatedAt", earn.CreatedAt, first.CreatedAt)
+		if second.Type != entities.LoyaltyReverse || second.Points != -40 || second.PaymentID != 3 || second.ExpiresAt != nil {
+			t.Errorf("Expected %+v second, got %+v", reverse, second)
+		}
+	})
+
+	t.Run("ListByOrderID", func(t *testing.T) {
+		gw := newGateways(t).Loyalty
+		mustCreate(t, gw.Create(ctx, entities.NewLoyaltyEntry(7, 20, entities.LoyaltyRedeem, -100)))
+		mustCreate(t, gw.Create(ctx, entities.NewLoyaltyEntry(7, 21, entities.LoyaltyEarn, 30)))
+		mustCreate(t, gw.Create(ctx, entities.NewLoyaltyEntry(7, 20, entities.LoyaltyRestore, 100)))
+
+		entries, err := gw.ListByOrderID(ctx, 20)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("ListByOrderID: expected 2 entries, got %d, %v", len(entries), err)
+		}
+		if entries[0].Type != entities.LoyaltyRedeem || entries[1].Type != entities.LoyaltyRestore {
+			t.Errorf("Expected redeem then restore, got %s then %s", entries[0].Type, entries[1].Type)
+		}
+
+		if entries, err := gw.ListByCustomerID(ctx, 99); len(entries) != 0 || err != nil {
+			t.Errorf("ListByCustomerID: expected no entries, got %d, %v", len(entries), err)
+		}
+	})
+}
@@ -0,0 +1,317 @@
+package gatewaytest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunOrderGateway checks an OrderGateway implementation
+func RunOrderGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetRoundTrip", func(t *testing.T) {
+		gws := newGateways(t)
+		customer := seedCustomer(t, gws)
+
+		order := entities.NewOrder(customer.ID, customer.CPF)
+		order.Discount = 4.5
+		order.ServiceFee = 2.25
+		order.Rounding = -0.05
+		mustCreate(t, gws.Order.Create(ctx, order))
+		if order.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gws.Order.GetByID(ctx, order.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: expected order, got %v, %v", found, err)
+		}
+		if found.CustomerId != order.CustomerId || found.CPF != order.CPF || found.Status != order.Status || found.Discount != 4.5 ||
+			found.ServiceFee != 2.25 || found.Rounding != -0.05 {
+			t.Errorf("Expected %+v, got %+v", order, found)
+		}
+		if len(found.Items) != 0 {
+			t.Errorf("Expected order without items, got %d", len(found.Items))
+		}
+		assertSameInstant(t, "CreatedAt", order.CreatedAt, found.CreatedAt)
+		assertSameInstant(t, "UpdatedAt", order.UpdatedAt, found.UpdatedAt)
+
+		locked, err := gws.Order.GetByIDForUpdate(ctx, order.ID)
+		if err != nil || locked == nil || locked.ID != order.ID || locked.Status != order.Status || locked.Discount != 4.5 {
+			t.Errorf("GetByIDForUpdate: expected %+v, got %+v, %v", found, locked, err)
+		}
+	})
+
+	t.Run("AnonymousOrder", func(t *testing.T) {
+		gw := newGateways(t).Order
+
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gw.Create(ctx, order))
+
+		found, err := gw.GetByID(ctx, order.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: expected order, got %v, %v", found, err)
+		}
+		if found.CustomerId != 0 || found.CPF != "" {
+			t.Errorf("Expected anonymous order, got %+v", found)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		gw := newGateways(t).Order
+
+		if found, err := gw.GetByID(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetByID: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByIDForUpdate(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetByIDForUpdate: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByCPF(ctx, "000.000.000-00"); len(found) != 0 || err != nil {
+			t.Errorf("GetByCPF: expected no orders, got %v, %v", found, err)
+		}
+		if found, err := gw.GetAll(ctx); len(found) != 0 || err != nil {
+			t.Errorf("GetAll: expected no orders, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("NewestFirst", func(t *testing.T) {
+		gws := newGateways(t)
+		customer := seedCustomer(t, gws)
+
+		oldest := createOrderAt(t, gws, customer, entities.OrderAwaitingPayment, past(3*time.Minute))
+		newest := createOrderAt(t, gws, customer, entities.OrderAwaitingPayment, past(time.Minute))
+		middle := createOrderAt(t, gws, customer, entities.OrderAwaitingPayment, past(2*time.Minute))
+		other := createOrderAt(t, gws, nil, entities.OrderAwaitingPayment, past(0))
+
+		byCPF, err := gws.Order.GetByCPF(ctx, customer.CPF)
+		if err != nil {
+			t.Fatalf("GetByCPF: %v", err)
+		}
+		assertOrderIDs(t, "GetByCPF", byCPF, newest, middle, oldest)
+
+		byCustomer, err := gws.Order.GetByCustomerID(ctx, customer.ID)
+		if err != nil {
+			t.Fatalf("GetByCustomerID: %v", err)
+		}
+		assertOrderIDs(t, "GetByCustomerID", byCustomer, newest, middle, oldest)
+
+		all, err := gws.Order.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		assertOrderIDs(t, "GetAll", all, other, newest, middle, oldest)
+	})
+
+	t.Run("KitchenQueue", func(t *testing.T) {
+		gws := newGateways(t)
+
+		createOrderAt(t, gws, nil, entities.OrderAwaitingPayment, past(10*time.Minute))
+		receivedOld := createOrderAt(t, gws, nil, entities.OrderReceived, past(9*time.Minute))
+		readyNew := createOrderAt(t, gws, nil, entities.OrderReady, past(2*time.Minute))
+		inProgress := createOrderAt(t, gws, nil, entities.OrderInProgress, past(5*time.Minute))
+		receivedNew := createOrderAt(t, gws, nil, entities.OrderReceived, past(time.Minute))
+		readyOld := createOrderAt(t, gws, nil, entities.OrderReady, past(8*time.Minute))
+		createOrderAt(t, gws, nil, entities.OrderCompleted, past(7*time.Minute))
+		createOrderAt(t, gws, nil, entities.OrderCancelled, past(6*time.Minute))
+
+		queue, err := gws.Order.GetPendingOrdersForKitchen(ctx)
+		if err != nil {
+			t.Fatalf("GetPendingOrdersForKitchen: %v", err)
+		}
+		assertOrderIDs(t, "GetPendingOrdersForKitchen", queue, readyOld, readyNew, inProgress, receivedOld, receivedNew)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		gw := newGateways(t).Order
+
+		order := entities.NewOrder(0, "")
+		order.UpdatedAt = past(0)
+		mustCreate(t, gw.Create(ctx, order))
+
+		order.UpdateStatus(entities.OrderInProgress)
+		order.UpdatedAt = order.UpdatedAt.Add(timestampTolerance * 5)
+		if err := gw.Update(ctx, order); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		found, _ := gw.GetByID(ctx, order.ID)
+		if found == nil || found.Status != entities.OrderInProgress {
+			t.Fatalf("Expected status in_progress, got %+v", found)
+		}
+		assertSameInstant(t, "UpdatedAt", order.UpdatedAt, found.UpdatedAt)
+		assertSameInstant(t, "CreatedAt", order.CreatedAt, found.CreatedAt)
+	})
+
+	t.Run("DeleteRemovesChildren", func(t *testing.T) {
+		gws := newGateways(t)
+		product := seedProduct(t, gws)
+
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		mustCreate(t, gws.OrderItem.Create(ctx, entities.NewOrderItem(order.ID, product.ID, 1, product.Price)))
+		payment := entities.NewPayment(order.ID, product.Price, "pix")
+		mustCreate(t, gws.Payment.Create(ctx, payment))
+		mustCreate(t, gws.Loyalty.Create(ctx, entities.NewLoyaltyEntry(7, order.ID, entities.LoyaltyEarn, 10)))
+		if _, err := gws.Receipt.Create(ctx, &entities.Receipt{OrderID: order.ID, Total: product.Price, IssuedAt: past(0), XML: []byte("<NFe/>"), Text: []byte("receipt")}); err != nil {
+			t.Fatalf("Create receipt: %v", err)
+		}
+
+		if err := gws.Order.Delete(ctx, order.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if found, err := gws.Order.GetByID(ctx, order.ID); found != nil || err != nil {
+			t.Errorf("Expected nil, nil after delete, got %v, %v", found, err)
+		}
+		if items, err := gws.OrderItem.GetByOrderID(ctx, order.ID); len(items) != 0 || err != nil {
+			t.Errorf("Expected items to be deleted with the order, got %v, %v", items, err)
+		}
+		if found, err := gws.Payment.GetByID(ctx, payment.ID); found != nil || err != nil {
+			t.Errorf("Expected the payment to be deleted with the order, got %v, %v", found, err)
+		}
+		if entries, err := gws.Loyalty.ListByOrderID(ctx, order.ID); len(entries) != 0 || err != nil {
+			t.Errorf("Expected loyalty entries to be deleted with the order, got %v, %v", entries, err)
+		}
+		if found, err := gws.Receipt.GetByOrderID(ctx, order.ID); found != nil || err != nil {
+			t.Errorf("Expected the receipt to be deleted with the order, got %v, %v", found, err)
+		}
+	})
+}
+
+// RunOrderItemGateway checks an OrderItemGateway implementation
+func RunOrderItemGateway(t *testing.T, newGateways Factory) {
+	t.Run("InsertionOrder", func(t *testing.T) {
+		gws := newGateways(t)
+		product := seedProduct(t, gws)
+
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		otherOrder := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, otherOrder))
+
+		var created []*entities.OrderItem
+		for quantity := uint32(3); quantity > 0; quantity-- {
+			item := entities.NewOrderItem(order.ID, product.ID, quantity, product.Price)
+			mustCreate(t, gws.OrderItem.Create(ctx, item))
+			created = append(created, item)
+		}
+		mustCreate(t, gws.OrderItem.Create(ctx, entities.NewOrderItem(otherOrder.ID, product.ID, 9, product.Price)))
+
+		items, err := gws.OrderItem.GetByOrderID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByOrderID: %v", err)
+		}
+		if len(items) != len(created) {
+			t.Fatalf("Expected %d items, got %d", len(created), len(items))
+		}
+		for i, item := range items {
+			want := created[i]
+			if item.ID != want.ID || item.OrderID != order.ID || item.ProductID != product.ID ||
+				item.Quantity != want.Quantity || item.Price != want.Price {
+				t.Errorf("Expected %+v, got %+v", want, item)
+			}
+			assertSameInstant(t, "CreatedAt", want.CreatedAt, item.CreatedAt)
+		}
+	})
+
+	t.Run("PreparationRoundTrip", func(t *testing.T) {
+		gws := newGateways(t)
+		product := seedProduct(t, gws)
+
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		dressed := entities.NewOrderItem(order.ID, product.ID, 1, product.Price)
+		dressed.Modifiers = []string{"sem cebola", "bacon extra, crocante"}
+		dressed.Notes = "bem passado"
+		plain := entities.NewOrderItem(order.ID, product.ID, 1, product.Price)
+		mustCreate(t, gws.OrderItem.Create(ctx, dressed))
+		mustCreate(t, gws.OrderItem.Create(ctx, plain))
+
+		items, err := gws.OrderItem.GetByOrderID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByOrderID: %v", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("Expected 2 items, got %d", len(items))
+		}
+		if !reflect.DeepEqual(items[0].Modifiers, dressed.Modifiers) || items[0].Notes != "bem passado" {
+			t.Errorf("Expected the modifiers and notes back, got %q and %q", items[0].Modifiers, items[0].Notes)
+		}
+		if len(items[1].Modifiers) != 0 || items[1].Notes != "" {
+			t.Errorf("Expected an item without modifiers or notes, got %q and %q", items[1].Modifiers, items[1].Notes)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		gws := newGateways(t)
+		product := seedProduct(t, gws)
+
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		keep := entities.NewOrderItem(order.ID, product.ID, 1, product.Price)
+		remove := entities.NewOrderItem(order.ID, product.ID, 1, product.Price)
+		mustCreate(t, gws.OrderItem.Create(ctx, keep))
+		mustCreate(t, gws.OrderItem.Create(ctx, remove))
+
+		keep.UpdateQuantity(4)
+		if err := gws.OrderItem.Update(ctx, keep); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if err := gws.OrderItem.Delete(ctx, remove.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		items, err := gws.OrderItem.GetByOrderID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByOrderID: %v", err)
+		}
+		if len(items) != 1 || items[0].ID != keep.ID || items[0].Quantity != 4 {
+			t.Errorf("Expected only the updated item, got %v", items)
+		}
+	})
+}
+
+func seedCustomer(t *testing.T, gws Gateways) *entities.Customer {
+	t.Helper()
+	customer := entities.NewCustomer("João", "Silva", "123.456.789-00", "joao@email.com")
+	mustCreate(t, gws.Customer.Create(ctx, customer))
+	return customer
+}
+
+func seedProduct(t *testing.T, gws Gateways) *entities.Product {
+	t.Helper()
+	product := entities.NewProduct("X-Burger", "Lanche", 19.5, entities.SnackCategory, "")
+	mustCreate(t, gws.Product.Create(ctx, product))
+	return product
+}
+
+// createOrderAt creates an order with the given status and creation time. A
+// nil customer creates an anonymous order.
+func createOrderAt(t *testing.T, gws Gateways, customer *entities.Customer, status entities.OrderStatus, createdAt time.Time) uint64 {
+	t.Helper()
+	order := entities.NewOrder(0, "")
+	if customer != nil {
+		order = entities.NewOrder(customer.ID, customer.CPF)
+	}
+	order.Status = status
+	order.CreatedAt = createdAt
+	order.UpdatedAt = createdAt
+	mustCreate(t, gws.Order.Create(ctx, order))
+	return order.ID
+}
+
+func assertOrderIDs(t *testing.T, method string, orders []*entities.Order, want ...uint64) {
+	t.Helper()
+	var got []uint64
+	for _, order := range orders {
+		got = append(got, order.ID)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %v, got %v", method, want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected %v, got %v", method, want, got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gatewaytest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunOrderTaxGateway checks an OrderTaxGateway implementation
+func RunOrderTaxGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetByOrderIDRoundTrip", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+		other := seedOrder(t, gws)
+
+		first := &entities.OrderTax{OrderID: order.ID, Category: "snack", Rate: 13.45, Base: 39.98, Amount: 5.38}
+		second := &entities.OrderTax{OrderID: order.ID, Category: "drink", Rate: 20.5, Base: 6, Amount: 1.23}
+		for _, tax := range []*entities.OrderTax{first, second, {OrderID: other.ID, Category: "side", Rate: 10, Base: 1, Amount: 0.1}} {
+			mustCreate(t, gws.OrderTax.Create(ctx, tax))
+		}
+		if first.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gws.OrderTax.GetByOrderID(ctx, order.ID)
+		if err != nil || len(found) != 2 {
+			t.Fatalf("GetByOrderID: expected 2 lines, got %v, %v", found, err)
+		}
+		if !reflect.DeepEqual(*found[0], *first) || !reflect.DeepEqual(*found[1], *second) {
+			t.Errorf("Expected %+v and %+v, got %+v and %+v", first, second, found[0], found[1])
+		}
+
+		if none, err := gws.OrderTax.GetByOrderID(ctx, 999); err != nil || len(none) != 0 {
+			t.Errorf("GetByOrderID: expected no lines, got %v, %v", none, err)
+		}
+	})
+
+	t.Run("DeletedWithOrder", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+		mustCreate(t, gws.OrderTax.Create(ctx, &entities.OrderTax{OrderID: order.ID, Category: "snack", Rate: 10, Base: 10, Amount: 1}))
+
+		if err := gws.Order.Delete(ctx, order.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if taxes, err := gws.OrderTax.GetByOrderID(ctx, order.ID); len(taxes) != 0 || err != nil {
+			t.Errorf("Expected tax lines to be deleted with the order, got %v, %v", taxes, err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package gatewaytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// RunOutboxGateway checks an OutboxGateway implementation
+func RunOutboxGateway(t *testing.T, newGateways Factory) {
+	t.Run("AppendRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Outbox
+
+		event := mustAppend(t, gw, entities.EventOrderCreated, 7)
+		if event.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		pending, err := gw.ListPending(ctx, time.Now().Add(time.Second), 10)
+		if err != nil || len(pending) != 1 {
+			t.Fatalf("ListPending: expected 1 event, got %d, %v", len(pending), err)
+		}
+		found := pending[0]
+		if found.ID != event.ID || found.EventType != entities.EventOrderCreated || found.AggregateType != entities.AggregateOrder ||
+			found.AggregateID != 7 || string(found.Payload) != `{"order_id":7}` || found.Attempts != 0 || found.IsPublished() {
+			t.Errorf("Expected %+v, got %+v", event, found)
+		}
+		assertSameInstant(t, "CreatedAt", event.CreatedAt, found.CreatedAt)
+		assertSameInstant(t, "NextAttemptAt", event.NextAttemptAt, found.NextAttemptAt)
+	})
+
+	t.Run("ListPendingOldestFirstWithLimit", func(t *testing.T) {
+		gw := newGateways(t).Outbox
+
+		first := mustAppend(t, gw, entities.EventOrderCreated, 1)
+		second := mustAppend(t, gw, entities.EventOrderStatusChanged, 1)
+		mustAppend(t, gw, entities.EventOrderStatusChanged, 1)
+
+		pending, err := gw.ListPending(ctx, time.Now().Add(time.Second), 2)
+		if err != nil || len(pending) != 2 {
+			t.Fatalf("ListPending: expected 2 events, got %d, %v", len(pending), err)
+		}
+		if pending[0].ID != first.ID || pending[1].ID != second.ID {
+			t.Errorf("Expected events %d and %d, got %d and %d", first.ID, second.ID, pending[0].ID, pending[1].ID)
+		}
+	})
+
+	t.Run("UpdateControlsPending", func(t *testing.T) {
+		gw := newGateways(t).Outbox
+
+		published := mustAppend(t, gw, entities.EventOrderCreated, 1)
+		published.MarkPublished(time.Now())
+		failed := mustAppend(t, gw, entities.EventOrderCreated, 2)
+		failed.MarkFailed(errors.New("connection refused"), past(-time.Hour))
+		for _, event := range []*entities.OutboxEvent{published, failed} {
+			if err := gw.Update(ctx, event); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+		}
+
+		if pending, err := gw.ListPending(ctx, time.Now().Add(time.Second), 10); err != nil || len(pending) != 0 {
+			t.Fatalf("ListPending: expected no event due now, got %d, %v", len(pending), err)
+		}
+
+		pending, err := gw.ListPending(ctx, time.Now().Add(2*time.Hour), 10)
+		if err != nil || len(pending) != 1 {
+			t.Fatalf("ListPending: expected the failed event once due, got %d, %v", len(pending), err)
+		}
+		if pending[0].ID != failed.ID || pending[0].Attempts != 1 || pending[0].LastError != "connection refused" {
+			t.Errorf("Expected the failed delivery state, got %+v", pending[0])
+		}
+	})
+
+	t.Run("ClaimOnce", func(t *testing.T) {
+		gw := newGateways(t).Outbox
+
+		due := mustAppend(t, gw, entities.EventOrderCreated, 1)
+		published := mustAppend(t, gw, entities.EventOrderCreated, 2)
+		published.MarkPublished(time.Now())
+		if err := gw.Update(ctx, published); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		now, until := time.Now().Add(time.Second), past(-time.Minute)
+
+		claimed, err := gw.Claim(ctx, due, now, until)
+		if err != nil || !claimed {
+			t.Fatalf("Claim: expected the due event to be claimed, got %v, %v", claimed, err)
+		}
+		assertSameInstant(t, "NextAttemptAt", until, due.NextAttemptAt)
+		other := *due
+		if claimed, err := gw.Claim(ctx, &other, now, until); err != nil || claimed {
+			t.Errorf("Claim: expected a claimed event not to be claimed again, got %v, %v", claimed, err)
+		}
+		if claimed, err := gw.Claim(ctx, published, now, until); err != nil || claimed {
+			t.Errorf("Claim: expected a published event not to be claimed, got %v, %v", claimed, err)
+		}
+
+		if pending, err := gw.ListPending(ctx, now, 10); err != nil || len(pending) != 0 {
+			t.Errorf("ListPending: expected the claimed event to be hidden, got %d, %v", len(pending), err)
+		}
+		if pending, err := gw.ListPending(ctx, until, 10); err != nil || len(pending) != 1 || pending[0].ID != due.ID {
+			t.Errorf("ListPending: expected the claimed event due again once the claim lapses, got %d, %v", len(pending), err)
+		}
+	})
+}
+
+func mustAppend(t *testing.T, gw output.OutboxGateway, eventType string, orderID uint64) *entities.OutboxEvent {
+	t.Helper()
+	event, err := entities.NewOutboxEvent(eventType, entities.AggregateOrder, orderID, map[string]uint64{"order_id": orderID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.CreatedAt = past(time.Minute)
+	event.NextAttemptAt = event.CreatedAt
+	if err := gw.Append(ctx, event); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return event
+}
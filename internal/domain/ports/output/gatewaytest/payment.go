@@ -0,0 +1,235 @@
+package gatewaytest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunPaymentGateway checks a PaymentGateway implementation
+func RunPaymentGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetRoundTrip", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		payment := entities.NewPayment(order.ID, 25.5, "qr_code")
+		payment.Payer = "Ana"
+		mustCreate(t, gws.Payment.Create(ctx, payment))
+		if payment.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gws.Payment.GetByID(ctx, payment.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: expected payment, got %v, %v", found, err)
+		}
+		if found.OrderID != order.ID || found.Amount != 25.5 || found.Status != entities.PaymentStatusPending ||
+			found.PaymentMethod != "qr_code" || found.Payer != "Ana" || found.TransactionID != "" {
+			t.Errorf("Expected %+v, got %+v", payment, found)
+		}
+		assertSameInstant(t, "CreatedAt", payment.CreatedAt, found.CreatedAt)
+		assertSameInstant(t, "UpdatedAt", payment.UpdatedAt, found.UpdatedAt)
+	})
+
+	t.Run("ExpiresAtRoundTrip", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		withExpiry := entities.NewPayment(order.ID, 10, "qr_code")
+		withExpiry.ExpireAfter(15 * time.Minute)
+		mustCreate(t, gws.Payment.Create(ctx, withExpiry))
+		withoutExpiry := entities.NewPayment(order.ID, 10, "qr_code")
+		mustCreate(t, gws.Payment.Create(ctx, withoutExpiry))
+
+		found, err := gws.Payment.GetByID(ctx, withExpiry.ID)
+		if err != nil || found == nil || found.ExpiresAt == nil {
+			t.Fatalf("GetByID: expected payment with expiry, got %+v, %v", found, err)
+		}
+		assertSameInstant(t, "ExpiresAt", *withExpiry.ExpiresAt, *found.ExpiresAt)
+
+		found, err = gws.Payment.GetByID(ctx, withoutExpiry.ID)
+		if err != nil || found == nil || found.ExpiresAt != nil {
+			t.Errorf("GetByID: expected payment without expiry, got %+v, %v", found, err)
+		}
+	})
+
+	t.Run("ListExpiredPendingOnlyEarliestFirst", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		createExpiring := func(expiresAt time.Time) *entities.Payment {
+			payment := entities.NewPayment(order.ID, 10, "qr_code")
+			payment.ExpiresAt = &expiresAt
+			mustCreate(t, gws.Payment.Create(ctx, payment))
+			return payment
+		}
+		later := createExpiring(past(time.Minute))
+		earlier := createExpiring(past(time.Hour))
+		createExpiring(past(-time.Hour))
+		mustCreate(t, gws.Payment.Create(ctx, entities.NewPayment(order.ID, 10, "qr_code")))
+		approved := createExpiring(past(time.Hour))
+		approved.UpdateStatus(entities.PaymentStatusApproved, "tx-approved")
+		if err := gws.Payment.Update(ctx, approved); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		expired, err := gws.Payment.ListExpired(ctx, time.Now(), 10)
+		if err != nil || len(expired) != 2 {
+			t.Fatalf("ListExpired: expected 2 payments, got %d, %v", len(expired), err)
+		}
+		if expired[0].ID != earlier.ID || expired[1].ID != later.ID {
+			t.Errorf("Expected payments %d and %d, got %d and %d", earlier.ID, later.ID, expired[0].ID, expired[1].ID)
+		}
+
+		if limited, err := gws.Payment.ListExpired(ctx, time.Now(), 1); err != nil || len(limited) != 1 || limited[0].ID != earlier.ID {
+			t.Errorf("ListExpired with limit 1: expected payment %d, got %v, %v", earlier.ID, limited, err)
+		}
+	})
+
+	t.Run("ListPendingCreatedBeforeOldestFirst", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		createAt := func(createdAt time.Time) *entities.Payment {
+			payment := entities.NewPayment(order.ID, 10, "qr_code")
+			payment.CreatedAt = createdAt
+			mustCreate(t, gws.Payment.Create(ctx, payment))
+			return payment
+		}
+		newer := createAt(past(20 * time.Minute))
+		older := createAt(past(time.Hour))
+		createAt(past(time.Minute))
+		approved := createAt(past(2 * time.Hour))
+		approved.UpdateStatus(entities.PaymentStatusApproved, "tx-approved")
+		if err := gws.Payment.Update(ctx, approved); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		pending, err := gws.Payment.ListPendingCreatedBefore(ctx, past(10*time.Minute), 10)
+		if err != nil || len(pending) != 2 {
+			t.Fatalf("ListPendingCreatedBefore: expected 2 payments, got %d, %v", len(pending), err)
+		}
+		if pending[0].ID != older.ID || pending[1].ID != newer.ID {
+			t.Errorf("Expected payments %d and %d, got %d and %d", older.ID, newer.ID, pending[0].ID, pending[1].ID)
+		}
+
+		if limited, err := gws.Payment.ListPendingCreatedBefore(ctx, past(10*time.Minute), 1); err != nil || len(limited) != 1 || limited[0].ID != older.ID {
+			t.Errorf("ListPendingCreatedBefore with limit 1: expected payment %d, got %v, %v", older.ID, limited, err)
+		}
+	})
+
+	t.Run("ListCreatedBetween", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		createAt := func(createdAt time.Time) *entities.Payment {
+			payment := entities.NewPayment(order.ID, 10, "qr_code")
+			payment.CreatedAt = createdAt
+			mustCreate(t, gws.Payment.Create(ctx, payment))
+			return payment
+		}
+		from, to := past(3*time.Hour), past(time.Hour)
+		atStart := createAt(from)
+		inside := createAt(past(2 * time.Hour))
+		createAt(to)
+		createAt(past(4 * time.Hour))
+
+		payments, err := gws.Payment.ListCreatedBetween(ctx, from, to)
+		if err != nil || len(payments) != 2 {
+			t.Fatalf("ListCreatedBetween: expected 2 payments, got %d, %v", len(payments), err)
+		}
+		if payments[0].ID != atStart.ID || payments[1].ID != inside.ID {
+			t.Errorf("Expected payments %d and %d, got %d and %d", atStart.ID, inside.ID, payments[0].ID, payments[1].ID)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		gw := newGateways(t).Payment
+
+		if found, err := gw.GetByID(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetByID: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetLatestByOrderID(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetLatestByOrderID: expected nil, nil, got %v, %v", found, err)
+		}
+		if payments, err := gw.ListByOrderID(ctx, 999999); len(payments) != 0 || err != nil {
+			t.Errorf("ListByOrderID: expected no payments, got %v, %v", payments, err)
+		}
+		if found, err := gw.GetByTransactionID(ctx, "missing"); found != nil || err != nil {
+			t.Errorf("GetByTransactionID: expected nil, nil, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("AttemptsByOrderID", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+		other := seedOrder(t, gws)
+
+		first := entities.NewPayment(order.ID, 10, "credit_card")
+		first.UpdateStatus(entities.PaymentStatusRejected, "tx-declined")
+		mustCreate(t, gws.Payment.Create(ctx, first))
+		mustCreate(t, gws.Payment.Create(ctx, entities.NewPayment(other.ID, 10, "qr_code")))
+		latest := entities.NewPayment(order.ID, 10, "qr_code")
+		mustCreate(t, gws.Payment.Create(ctx, latest))
+
+		found, err := gws.Payment.GetLatestByOrderID(ctx, order.ID)
+		if err != nil || found == nil || found.ID != latest.ID {
+			t.Errorf("GetLatestByOrderID: expected payment %d, got %v, %v", latest.ID, found, err)
+		}
+
+		attempts, err := gws.Payment.ListByOrderID(ctx, order.ID)
+		if err != nil || len(attempts) != 2 {
+			t.Fatalf("ListByOrderID: expected 2 payments, got %d, %v", len(attempts), err)
+		}
+		if attempts[0].ID != first.ID || !attempts[0].IsRejected() || attempts[1].ID != latest.ID {
+			t.Errorf("Expected payments %d then %d, got %+v and %+v", first.ID, latest.ID, attempts[0], attempts[1])
+		}
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		payment := entities.NewPayment(order.ID, 42, "qr_code")
+		payment.UpdatedAt = past(0)
+		mustCreate(t, gws.Payment.Create(ctx, payment))
+
+		payment.UpdateStatus(entities.PaymentStatusApproved, "tx-123")
+		payment.UpdatedAt = payment.UpdatedAt.Add(timestampTolerance * 5)
+		if err := gws.Payment.Update(ctx, payment); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		found, err := gws.Payment.GetByTransactionID(ctx, "tx-123")
+		if err != nil || found == nil {
+			t.Fatalf("GetByTransactionID: expected payment, got %v, %v", found, err)
+		}
+		if found.ID != payment.ID || !found.IsApproved() {
+			t.Errorf("Expected approved payment %d, got %+v", payment.ID, found)
+		}
+		assertSameInstant(t, "UpdatedAt", payment.UpdatedAt, found.UpdatedAt)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+
+		payment := entities.NewPayment(order.ID, 42, "qr_code")
+		mustCreate(t, gws.Payment.Create(ctx, payment))
+
+		if err := gws.Payment.Delete(ctx, payment.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if found, err := gws.Payment.GetByID(ctx, payment.ID); found != nil || err != nil {
+			t.Errorf("Expected nil, nil after delete, got %v, %v", found, err)
+		}
+	})
+}
+
+func seedOrder(t *testing.T, gws Gateways) *entities.Order {
+	t.Helper()
+	order := entities.NewOrder(0, "")
+	mustCreate(t, gws.Order.Create(ctx, order))
+	return order
+}
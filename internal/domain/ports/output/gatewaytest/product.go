@@ -0,0 +1,119 @@
+package gatewaytest
+
+import (
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunProductGateway checks a ProductGateway implementation
+func RunProductGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Product
+
+		product := entities.NewProduct("X-Burger", "Pão, carne e queijo", 19.9, entities.SnackCategory, "https://example.com/x.png")
+		mustCreate(t, gw.Create(ctx, product))
+		if product.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gw.GetByID(ctx, product.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: expected product, got %v, %v", found, err)
+		}
+		if found.Name != product.Name || found.Description != product.Description ||
+			found.Price != product.Price || found.Category != product.Category ||
+			found.ImageUrl != product.ImageUrl {
+			t.Errorf("Expected %+v, got %+v", product, found)
+		}
+		assertSameInstant(t, "CreatedAt", product.CreatedAt, found.CreatedAt)
+		assertSameInstant(t, "UpdatedAt", product.UpdatedAt, found.UpdatedAt)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		gw := newGateways(t).Product
+
+		if found, err := gw.GetByID(ctx, 999999); found != nil || err != nil {
+			t.Errorf("GetByID: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByCategory(ctx, string(entities.DessertCategory)); len(found) != 0 || err != nil {
+			t.Errorf("GetByCategory: expected no products, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		gw := newGateways(t).Product
+
+		for _, product := range []*entities.Product{
+			entities.NewProduct("Suco", "Laranja", 8, entities.DrinkCategory, ""),
+			entities.NewProduct("Pudim", "Leite condensado", 10, entities.DessertCategory, ""),
+			entities.NewProduct("X-Salada", "Lanche", 21, entities.SnackCategory, ""),
+			entities.NewProduct("Agua", "Sem gas", 3, entities.DrinkCategory, ""),
+			entities.NewProduct("Batata", "Frita", 12, entities.SideCategory, ""),
+			entities.NewProduct("Coxinha", "Frango", 8, entities.SnackCategory, ""),
+		} {
+			mustCreate(t, gw.Create(ctx, product))
+		}
+
+		drinks, err := gw.GetByCategory(ctx, string(entities.DrinkCategory))
+		if err != nil {
+			t.Fatalf("GetByCategory: %v", err)
+		}
+		assertProductNames(t, drinks, "Agua", "Suco")
+
+		all, err := gw.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		assertProductNames(t, all, "Coxinha", "X-Salada", "Batata", "Agua", "Suco", "Pudim")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		gw := newGateways(t).Product
+
+		product := entities.NewProduct("Suco", "Laranja", 8, entities.DrinkCategory, "")
+		mustCreate(t, gw.Create(ctx, product))
+
+		product.UpdateProduct("Suco Grande", "Laranja 500ml", 12.5, entities.DrinkCategory, "https://example.com/suco.png")
+		if err := gw.Update(ctx, product); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		found, _ := gw.GetByID(ctx, product.ID)
+		if found == nil || found.Name != "Suco Grande" || found.Price != 12.5 || found.ImageUrl != product.ImageUrl {
+			t.Errorf("Expected updated product, got %+v", found)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		gw := newGateways(t).Product
+
+		product := entities.NewProduct("Suco", "Laranja", 8, entities.DrinkCategory, "")
+		mustCreate(t, gw.Create(ctx, product))
+
+		if err := gw.Delete(ctx, product.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if found, err := gw.GetByID(ctx, product.ID); found != nil || err != nil {
+			t.Errorf("Expected nil, nil after delete, got %v, %v", found, err)
+		}
+	})
+}
+
+// assertProductNames compares names in order. Accents and case sort
+// differently between collations, so ordering fixtures use plain ASCII.
+func assertProductNames(t *testing.T, products []*entities.Product, want ...string) {
+	t.Helper()
+	var got []string
+	for _, product := range products {
+		got = append(got, product.Name)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
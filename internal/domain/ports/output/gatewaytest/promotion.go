@@ -0,0 +1,202 @@
+package gatewaytest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunPromotionGateway checks a PromotionGateway implementation
+func RunPromotionGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Promotion
+
+		startsAt, endsAt := past(24*time.Hour), past(-24*time.Hour)
+		promotion := entities.NewPromotion("Dessert Tuesday", "", entities.PromotionPercentage, 10)
+		promotion.Category = string(entities.DessertCategory)
+		promotion.StartsAt, promotion.EndsAt = &startsAt, &endsAt
+		promotion.Weekdays = []time.Weekday{time.Sunday, time.Tuesday}
+		promotion.DailyStart, promotion.DailyEnd = "11:00", "15:30"
+		promotion.MaxUsesPerCustomer = 2
+		mustCreate(t, gw.Create(ctx, promotion))
+		if promotion.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gw.GetByID(ctx, promotion.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: expected promotion, got %v, %v", found, err)
+		}
+		if found.Name != promotion.Name || found.Code != "" || found.Type != entities.PromotionPercentage || found.Value != 10 ||
+			found.Category != promotion.Category || found.DailyStart != "11:00" || found.DailyEnd != "15:30" ||
+			found.MaxUsesPerCustomer != 2 || !found.Active {
+			t.Errorf("Expected %+v, got %+v", promotion, found)
+		}
+		if !reflect.DeepEqual(found.Weekdays, promotion.Weekdays) {
+			t.Errorf("Expected weekdays %v, got %v", promotion.Weekdays, found.Weekdays)
+		}
+		if found.StartsAt == nil || found.EndsAt == nil {
+			t.Fatalf("Expected a time window, got %v to %v", found.StartsAt, found.EndsAt)
+		}
+		assertSameInstant(t, "StartsAt", startsAt, *found.StartsAt)
+		assertSameInstant(t, "EndsAt", endsAt, *found.EndsAt)
+		assertSameInstant(t, "CreatedAt", promotion.CreatedAt, found.CreatedAt)
+
+		locked, err := gw.GetByIDForUpdate(ctx, promotion.ID)
+		if err != nil || locked == nil || locked.ID != promotion.ID || locked.MaxUsesPerCustomer != 2 {
+			t.Errorf("GetByIDForUpdate: expected %+v, got %+v, %v", found, locked, err)
+		}
+	})
+
+	t.Run("OpenWindowRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Promotion
+
+		promotion := entities.NewPromotion("Combo", "combo", entities.PromotionBuyXGetY, 0)
+		promotion.BuyQuantity, promotion.FreeQuantity = 2, 1
+		mustCreate(t, gw.Create(ctx, promotion))
+
+		found, err := gw.GetByCode(ctx, "COMBO")
+		if err != nil || found == nil || found.ID != promotion.ID {
+			t.Fatalf("GetByCode: expected promotion %d, got %v, %v", promotion.ID, found, err)
+		}
+		if found.StartsAt != nil || found.EndsAt != nil || len(found.Weekdays) != 0 {
+			t.Errorf("Expected no time window, got %+v", found)
+		}
+		if found.BuyQuantity != 2 || found.FreeQuantity != 1 {
+			t.Errorf("Expected buy 2 get 1, got %+v", found)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		gw := newGateways(t).Promotion
+		mustCreate(t, gw.Create(ctx, entities.NewPromotion("Automatic", "", entities.PromotionFixed, 5)))
+
+		if found, err := gw.GetByID(ctx, 999); found != nil || err != nil {
+			t.Errorf("GetByID: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByIDForUpdate(ctx, 999); found != nil || err != nil {
+			t.Errorf("GetByIDForUpdate: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByCode(ctx, "MISSING"); found != nil || err != nil {
+			t.Errorf("GetByCode: expected nil, nil, got %v, %v", found, err)
+		}
+		if found, err := gw.GetByCode(ctx, ""); found != nil || err != nil {
+			t.Errorf("GetByCode: expected no match for an empty code, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("ListAutomaticSkipsCouponsAndInactive", func(t *testing.T) {
+		gw := newGateways(t).Promotion
+
+		first := entities.NewPromotion("First", "", entities.PromotionFixed, 5)
+		coupon := entities.NewPromotion("Coupon", "SAVE5", entities.PromotionFixed, 5)
+		inactive := entities.NewPromotion("Inactive", "", entities.PromotionFixed, 5)
+		inactive.Active = false
+		second := entities.NewPromotion("Second", "", entities.PromotionPercentage, 10)
+		for _, promotion := range []*entities.Promotion{first, coupon, inactive, second} {
+			mustCreate(t, gw.Create(ctx, promotion))
+		}
+
+		automatic, err := gw.ListAutomatic(ctx)
+		if err != nil || len(automatic) != 2 || automatic[0].ID != first.ID || automatic[1].ID != second.ID {
+			t.Errorf("ListAutomatic: expected [%d %d], got %v, %v", first.ID, second.ID, automatic, err)
+		}
+
+		all, err := gw.GetAll(ctx)
+		if err != nil || len(all) != 4 || all[0].ID != first.ID || all[3].ID != second.ID {
+			t.Errorf("GetAll: expected 4 promotions oldest first, got %v, %v", all, err)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		gw := newGateways(t).Promotion
+
+		promotion := entities.NewPromotion("Weekend", "", entities.PromotionFixed, 5)
+		promotion.Weekdays = []time.Weekday{time.Saturday}
+		mustCreate(t, gw.Create(ctx, promotion))
+
+		promotion.Name = "Weekend deal"
+		promotion.Value = 7.5
+		promotion.Weekdays = nil
+		promotion.Active = false
+		if err := gw.Update(ctx, promotion); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		found, _ := gw.GetByID(ctx, promotion.ID)
+		if found == nil || found.Name != "Weekend deal" || found.Value != 7.5 || found.Active || len(found.Weekdays) != 0 {
+			t.Errorf("Expected updated promotion, got %+v", found)
+		}
+
+		if err := gw.Delete(ctx, promotion.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if found, _ := gw.GetByID(ctx, promotion.ID); found != nil {
+			t.Errorf("Expected promotion to be deleted, got %+v", found)
+		}
+	})
+}
+
+// RunOrderDiscountGateway checks an OrderDiscountGateway implementation
+func RunOrderDiscountGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetByOrderIDRoundTrip", func(t *testing.T) {
+		gws := newGateways(t)
+		order := seedOrder(t, gws)
+		other := seedOrder(t, gws)
+
+		first := &entities.OrderDiscount{OrderID: order.ID, PromotionID: 1, Description: "Dessert Tuesday", Amount: 1.5}
+		second := &entities.OrderDiscount{OrderID: order.ID, PromotionID: 2, Code: "SAVE5", Description: "Coupon", Amount: 5}
+		for _, discount := range []*entities.OrderDiscount{first, second, {OrderID: other.ID, PromotionID: 1, Description: "x", Amount: 1}} {
+			mustCreate(t, gws.OrderDiscount.Create(ctx, discount))
+		}
+		if first.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gws.OrderDiscount.GetByOrderID(ctx, order.ID)
+		if err != nil || len(found) != 2 {
+			t.Fatalf("GetByOrderID: expected 2 lines, got %v, %v", found, err)
+		}
+		if !reflect.DeepEqual(*found[0], *first) || !reflect.DeepEqual(*found[1], *second) {
+			t.Errorf("Expected %+v and %+v, got %+v and %+v", first, second, found[0], found[1])
+		}
+
+		if none, err := gws.OrderDiscount.GetByOrderID(ctx, 999); err != nil || len(none) != 0 {
+			t.Errorf("GetByOrderID: expected no lines, got %v, %v", none, err)
+		}
+	})
+
+	t.Run("CountUsesByCustomerOrCPF", func(t *testing.T) {
+		gws := newGateways(t)
+		customer := entities.NewCustomer("Ana", "Silva", "123.456.789-00", "ana@example.com")
+		mustCreate(t, gws.Customer.Create(ctx, customer))
+
+		byID := entities.NewOrder(customer.ID, "")
+		byCPF := entities.NewOrder(0, "123.456.789-00")
+		twice := entities.NewOrder(customer.ID, "123.456.789-00")
+		cancelled := entities.NewOrder(customer.ID, "")
+		cancelled.UpdateStatus(entities.OrderCancelled)
+		stranger := entities.NewOrder(0, "987.654.321-00")
+		for _, order := range []*entities.Order{byID, byCPF, twice, cancelled, stranger} {
+			mustCreate(t, gws.Order.Create(ctx, order))
+			mustCreate(t, gws.OrderDiscount.Create(ctx, &entities.OrderDiscount{OrderID: order.ID, PromotionID: 7, Code: "SAVE5", Description: "Coupon", Amount: 5}))
+		}
+		// A second line of the same promotion on one order counts once
+		mustCreate(t, gws.OrderDiscount.Create(ctx, &entities.OrderDiscount{OrderID: twice.ID, PromotionID: 7, Description: "Coupon", Amount: 1}))
+
+		if uses, err := gws.OrderDiscount.CountUses(ctx, 7, customer.ID, "123.456.789-00"); err != nil || uses != 3 {
+			t.Errorf("CountUses: expected 3, got %d, %v", uses, err)
+		}
+		if uses, err := gws.OrderDiscount.CountUses(ctx, 7, 0, "123.456.789-00"); err != nil || uses != 2 {
+			t.Errorf("CountUses by CPF: expected 2, got %d, %v", uses, err)
+		}
+		if uses, err := gws.OrderDiscount.CountUses(ctx, 7, 0, ""); err != nil || uses != 0 {
+			t.Errorf("CountUses without a customer: expected 0, got %d, %v", uses, err)
+		}
+		if uses, err := gws.OrderDiscount.CountUses(ctx, 8, customer.ID, ""); err != nil || uses != 0 {
+			t.Errorf("CountUses of another promotion: expected 0, got %d, %v", uses, err)
+		}
+	})
+}
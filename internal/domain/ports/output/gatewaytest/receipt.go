@@ -0,0 +1,69 @@
+package gatewaytest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunReceiptGateway checks a ReceiptGateway implementation
+func RunReceiptGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetByOrderIDRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Receipt
+
+		receipt := &entities.Receipt{
+			OrderID:  42,
+			Issuer:   entities.ReceiptIssuer{Name: "Fast Food", CNPJ: "12345678000190"},
+			CPF:      "123.456.789-00",
+			Items:    []entities.ReceiptItem{{Number: 1, ProductID: 7, Description: "X-Burger", Category: "snack", Quantity: 2, UnitPrice: 20, Total: 40}},
+			Subtotal: 40,
+			Discount: 4,
+			Total:    36,
+			Taxes:    []entities.ReceiptTax{{Category: "snack", Rate: 13.45, Amount: 4.84}},
+			TaxTotal: 4.84,
+			Payments: []entities.ReceiptPayment{{Method: "pix", Amount: 36, TransactionID: "tx-1"}},
+			IssuedAt: past(0),
+			XML:      []byte("<NFe/>"),
+			Text:     []byte("FAST FOOD\n"),
+		}
+		created, err := gw.Create(ctx, receipt)
+		if err != nil || !created || receipt.ID == 0 {
+			t.Fatalf("Create: expected a new receipt with an ID, got %v, %v, ID %d", created, err, receipt.ID)
+		}
+
+		found, err := gw.GetByOrderID(ctx, 42)
+		if err != nil || found == nil {
+			t.Fatalf("GetByOrderID: expected receipt, got %v, %v", found, err)
+		}
+		assertSameInstant(t, "IssuedAt", receipt.IssuedAt, found.IssuedAt)
+		found.IssuedAt = receipt.IssuedAt
+		if !reflect.DeepEqual(*found, *receipt) {
+			t.Errorf("Expected %+v, got %+v", receipt, found)
+		}
+
+		if none, err := gw.GetByOrderID(ctx, 999); none != nil || err != nil {
+			t.Errorf("GetByOrderID: expected nil, nil, got %v, %v", none, err)
+		}
+	})
+
+	t.Run("OneReceiptPerOrder", func(t *testing.T) {
+		gw := newGateways(t).Receipt
+
+		first := &entities.Receipt{OrderID: 42, Total: 10, IssuedAt: past(0), XML: []byte("<first/>"), Text: []byte("first")}
+		if created, err := gw.Create(ctx, first); err != nil || !created {
+			t.Fatalf("Create: expected a new receipt, got %v, %v", created, err)
+		}
+		second := &entities.Receipt{OrderID: 42, Total: 20, IssuedAt: past(0), XML: []byte("<second/>"), Text: []byte("second")}
+
+		created, err := gw.Create(ctx, second)
+		if err != nil || created || second.ID != 0 {
+			t.Fatalf("Create: expected the second receipt to be skipped, got %v, %v, ID %d", created, err, second.ID)
+		}
+
+		found, _ := gw.GetByOrderID(ctx, 42)
+		if found == nil || found.ID != first.ID || found.Total != 10 || string(found.XML) != "<first/>" {
+			t.Errorf("Expected the first receipt to be kept, got %+v", found)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package gatewaytest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// RunReconciliationGateway checks a ReconciliationGateway implementation
+func RunReconciliationGateway(t *testing.T, newGateways Factory) {
+	t.Run("GetRoundTrip", func(t *testing.T) {
+		gw := newGateways(t).Reconciliation
+
+		report := &entities.ReconciliationReport{
+			Date:             time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			PaymentsChecked:  3,
+			ProviderPayments: 2,
+			Mismatches: []entities.ReconciliationMismatch{
+				{Kind: entities.MismatchStatus, PaymentID: 1, OrderID: 10, TransactionID: "tx-1",
+					LocalStatus: entities.PaymentStatusPending, ProviderStatus: entities.PaymentStatusApproved, LocalAmount: 20, ProviderAmount: 20},
+				{Kind: entities.MismatchMissingLocally, OrderID: 11, TransactionID: "tx-2",
+					ProviderStatus: entities.PaymentStatusApproved, ProviderAmount: 15.5},
+			},
+			GeneratedAt: past(0),
+		}
+		if err := gw.SaveReport(ctx, report); err != nil {
+			t.Fatalf("SaveReport: %v", err)
+		}
+
+		found, err := gw.GetReport(ctx, report.Date.Add(13*time.Hour))
+		if err != nil || found == nil {
+			t.Fatalf("GetReport: expected report, got %v, %v", found, err)
+		}
+		if !found.Date.Equal(report.Date) || found.PaymentsChecked != 3 || found.ProviderPayments != 2 {
+			t.Errorf("Expected %+v, got %+v", report, found)
+		}
+		if !reflect.DeepEqual(found.Mismatches, report.Mismatches) {
+			t.Errorf("Expected mismatches %+v, got %+v", report.Mismatches, found.Mismatches)
+		}
+		assertSameInstant(t, "GeneratedAt", report.GeneratedAt, found.GeneratedAt)
+	})
+
+	t.Run("SaveReplacesTheDay", func(t *testing.T) {
+		gw := newGateways(t).Reconciliation
+		day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+		first := &entities.ReconciliationReport{Date: day, PaymentsChecked: 1, GeneratedAt: past(time.Hour), Mismatches: []entities.ReconciliationMismatch{
+			{Kind: entities.MismatchAmount, PaymentID: 1, OrderID: 10, LocalAmount: 20, ProviderAmount: 25},
+		}}
+		second := &entities.ReconciliationReport{Date: day, PaymentsChecked: 2, GeneratedAt: past(0), Mismatches: []entities.ReconciliationMismatch{}}
+		other := &entities.ReconciliationReport{Date: day.AddDate(0, 0, -1), PaymentsChecked: 5, GeneratedAt: past(0), Mismatches: []entities.ReconciliationMismatch{
+			{Kind: entities.MismatchMissingAtProvider, PaymentID: 2, OrderID: 11, LocalStatus: entities.PaymentStatusApproved, LocalAmount: 30},
+		}}
+		for _, report := range []*entities.ReconciliationReport{first, other, second} {
+			if err := gw.SaveReport(ctx, report); err != nil {
+				t.Fatalf("SaveReport: %v", err)
+			}
+		}
+
+		found, err := gw.GetReport(ctx, day)
+		if err != nil || found == nil || found.PaymentsChecked != 2 || found.Mismatches == nil || len(found.Mismatches) != 0 {
+			t.Errorf("Expected the second report without mismatches, got %+v, %v", found, err)
+		}
+		found, err = gw.GetReport(ctx, other.Date)
+		if err != nil || found == nil || found.PaymentsChecked != 5 || len(found.Mismatches) != 1 {
+			t.Errorf("Expected the other day untouched, got %+v, %v", found, err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		gw := newGateways(t).Reconciliation
+
+		if found, err := gw.GetReport(ctx, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); found != nil || err != nil {
+			t.Errorf("GetReport: expected nil, nil, got %v, %v", found, err)
+		}
+	})
+}
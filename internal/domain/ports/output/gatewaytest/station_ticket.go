@@ -0,0 +1,156 @@
+package gatewaytest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+func newStationTicket(orderID uint64, station string) *entities.StationTicket {
+	return &entities.StationTicket{
+		OrderID:   orderID,
+		Station:   station,
+		Status:    entities.StationTicketPending,
+		Items:     []entities.KitchenTicketItem{{Quantity: 1, Description: "X-Burger"}},
+		CreatedAt: past(0),
+		UpdatedAt: past(0),
+	}
+}
+
+// RunStationTicketGateway checks a StationTicketGateway implementation
+func RunStationTicketGateway(t *testing.T, newGateways Factory) {
+	t.Run("CreateAndGetByID", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		ticket := newStationTicket(order.ID, "grill")
+		ticket.Items = []entities.KitchenTicketItem{
+			{Quantity: 2, Description: "X-Burger", Modifiers: []string{"sem cebola"}, Notes: "bem passado"},
+			{Quantity: 1, Description: "X-Salada"},
+		}
+		ticket.CreatedAt = past(time.Hour)
+
+		created, err := gws.StationTicket.Create(ctx, ticket)
+		if err != nil || !created || ticket.ID == 0 {
+			t.Fatalf("Create: expected a new ticket with an ID, got %v, %v, ID %d", created, err, ticket.ID)
+		}
+
+		stored, err := gws.StationTicket.GetByID(ctx, ticket.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetByID: expected the ticket, got %v, %v", stored, err)
+		}
+		if stored.OrderID != order.ID || stored.Station != "grill" || stored.Status != entities.StationTicketPending {
+			t.Errorf("Expected a pending grill ticket of order %d, got %+v", order.ID, stored)
+		}
+		if !reflect.DeepEqual(stored.Items, ticket.Items) {
+			t.Errorf("Expected items %+v, got %+v", ticket.Items, stored.Items)
+		}
+		assertSameInstant(t, "CreatedAt", ticket.CreatedAt, stored.CreatedAt)
+		if missing, err := gws.StationTicket.GetByID(ctx, ticket.ID+100); err != nil || missing != nil {
+			t.Errorf("GetByID: expected nil, nil for a missing ticket, got %v, %v", missing, err)
+		}
+	})
+
+	t.Run("OneTicketPerStation", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		if created, err := gws.StationTicket.Create(ctx, newStationTicket(order.ID, "grill")); err != nil || !created {
+			t.Fatalf("Create: expected a new ticket, got %v, %v", created, err)
+		}
+		again := newStationTicket(order.ID, "grill")
+
+		created, err := gws.StationTicket.Create(ctx, again)
+		if err != nil || created || again.ID != 0 {
+			t.Fatalf("Create: expected the same station to be skipped, got %v, %v, ID %d", created, err, again.ID)
+		}
+		if created, err := gws.StationTicket.Create(ctx, newStationTicket(order.ID, "drinks")); err != nil || !created {
+			t.Fatalf("Create: expected another station to get its ticket, got %v, %v", created, err)
+		}
+		if tickets, _ := gws.StationTicket.ListByOrderID(ctx, order.ID); len(tickets) != 2 || tickets[0].Station != "grill" || tickets[1].Station != "drinks" {
+			t.Errorf("Expected the grill and drinks tickets in creation order, got %+v", tickets)
+		}
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		ticket := newStationTicket(order.ID, "grill")
+		if _, err := gws.StationTicket.Create(ctx, ticket); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ticket.Status = entities.StationTicketPreparing
+		ticket.UpdatedAt = past(-time.Minute)
+
+		if err := gws.StationTicket.Update(ctx, ticket); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		stored, _ := gws.StationTicket.GetByID(ctx, ticket.ID)
+		if stored == nil || stored.Status != entities.StationTicketPreparing || len(stored.Items) != 1 {
+			t.Fatalf("Expected the ticket to be preparing with its items, got %+v", stored)
+		}
+		assertSameInstant(t, "UpdatedAt", ticket.UpdatedAt, stored.UpdatedAt)
+	})
+
+	t.Run("ListOpenByStation", func(t *testing.T) {
+		gws := newGateways(t)
+		newOrder := func(status entities.OrderStatus) *entities.Order {
+			order := entities.NewOrder(0, "")
+			order.Status = status
+			mustCreate(t, gws.Order.Create(ctx, order))
+			return order
+		}
+		received := newOrder(entities.OrderReceived)
+		inProgress := newOrder(entities.OrderInProgress)
+		cancelled := newOrder(entities.OrderCancelled)
+
+		openFirst := newStationTicket(received.ID, "grill")
+		openSecond := newStationTicket(inProgress.ID, "grill")
+		openSecond.Status = entities.StationTicketPreparing
+		done := newStationTicket(received.ID, "drinks")
+		done.Status = entities.StationTicketDone
+		for _, ticket := range []*entities.StationTicket{
+			openFirst,
+			openSecond,
+			newStationTicket(cancelled.ID, "grill"),
+			newStationTicket(inProgress.ID, "drinks"),
+			done,
+		} {
+			if _, err := gws.StationTicket.Create(ctx, ticket); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+
+		tickets, err := gws.StationTicket.ListOpenByStation(ctx, "grill")
+		if err != nil {
+			t.Fatalf("ListOpenByStation: %v", err)
+		}
+		if len(tickets) != 2 || tickets[0].ID != openFirst.ID || tickets[1].ID != openSecond.ID {
+			t.Fatalf("Expected the grill tickets of orders still in the kitchen, oldest first, got %+v", tickets)
+		}
+		if drinks, _ := gws.StationTicket.ListOpenByStation(ctx, "drinks"); len(drinks) != 1 || drinks[0].OrderID != inProgress.ID {
+			t.Errorf("Expected only the open drinks ticket, got %+v", drinks)
+		}
+	})
+
+	t.Run("DeletedWithOrder", func(t *testing.T) {
+		gws := newGateways(t)
+		order := entities.NewOrder(0, "")
+		mustCreate(t, gws.Order.Create(ctx, order))
+		if _, err := gws.StationTicket.Create(ctx, newStationTicket(order.ID, "grill")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		if err := gws.Order.Delete(ctx, order.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		if tickets, err := gws.StationTicket.ListByOrderID(ctx, order.ID); err != nil || len(tickets) != 0 {
+			t.Errorf("Expected no tickets after the order is deleted, got %+v, %v", tickets, err)
+		}
+	})
+}
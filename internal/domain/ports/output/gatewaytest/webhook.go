@@ -0,0 +1,229 @@
+package gatewaytest
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// RunWebhookSubscriptionGateway checks a WebhookSubscriptionGateway implementation
+func RunWebhookSubscriptionGateway(t *testing.T, newGateways Factory) {
+	t.Run("CreateAndGetByID", func(t *testing.T) {
+		gw := newGateways(t).WebhookSubscription
+
+		subscription := mustCreateSubscription(t, gw)
+		if subscription.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gw.GetByID(ctx, subscription.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: %v, %v", found, err)
+		}
+		if found.URL != subscription.URL || found.Secret != subscription.Secret || !slices.Equal(found.EventTypes, subscription.EventTypes) {
+			t.Errorf("Expected %+v, got %+v", subscription, found)
+		}
+		assertSameInstant(t, "CreatedAt", subscription.CreatedAt, found.CreatedAt)
+	})
+
+	t.Run("GetByIDMissing", func(t *testing.T) {
+		gw := newGateways(t).WebhookSubscription
+
+		found, err := gw.GetByID(ctx, 999)
+		if found != nil || err != nil {
+			t.Errorf("Expected nil, nil, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("GetAllOldestFirst", func(t *testing.T) {
+		gw := newGateways(t).WebhookSubscription
+
+		first := mustCreateSubscription(t, gw)
+		second := mustCreateSubscription(t, gw)
+
+		all, err := gw.GetAll(ctx)
+		if err != nil || len(all) != 2 {
+			t.Fatalf("GetAll: expected 2 subscriptions, got %d, %v", len(all), err)
+		}
+		if all[0].ID != first.ID || all[1].ID != second.ID {
+			t.Errorf("Expected subscriptions %d and %d, got %d and %d", first.ID, second.ID, all[0].ID, all[1].ID)
+		}
+	})
+
+	t.Run("DeleteRemovesDeliveries", func(t *testing.T) {
+		gateways := newGateways(t)
+
+		subscription := mustCreateSubscription(t, gateways.WebhookSubscription)
+		delivery := mustCreateDelivery(t, gateways.WebhookDelivery, subscription.ID, 1)
+
+		if err := gateways.WebhookSubscription.Delete(ctx, subscription.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		if found, err := gateways.WebhookSubscription.GetByID(ctx, subscription.ID); found != nil || err != nil {
+			t.Errorf("Expected the subscription deleted, got %v, %v", found, err)
+		}
+		if found, err := gateways.WebhookDelivery.GetByID(ctx, delivery.ID); found != nil || err != nil {
+			t.Errorf("Expected the delivery deleted, got %v, %v", found, err)
+		}
+	})
+}
+
+// RunWebhookDeliveryGateway checks a WebhookDeliveryGateway implementation
+func RunWebhookDeliveryGateway(t *testing.T, newGateways Factory) {
+	t.Run("CreateRoundTrip", func(t *testing.T) {
+		gateways := newGateways(t)
+		subscription := mustCreateSubscription(t, gateways.WebhookSubscription)
+		gw := gateways.WebhookDelivery
+
+		delivery := mustCreateDelivery(t, gw, subscription.ID, 7)
+		if delivery.ID == 0 {
+			t.Fatal("Expected generated ID")
+		}
+
+		found, err := gw.GetByID(ctx, delivery.ID)
+		if err != nil || found == nil {
+			t.Fatalf("GetByID: %v, %v", found, err)
+		}
+		if found.SubscriptionID != subscription.ID || found.EventID != 7 || found.EventType != entities.EventOrderCreated ||
+			string(found.Payload) != `{"id":7}` || found.Status != entities.WebhookDeliveryPending || found.DeliveredAt != nil {
+			t.Errorf("Expected %+v, got %+v", delivery, found)
+		}
+		assertSameInstant(t, "NextAttemptAt", delivery.NextAttemptAt, found.NextAttemptAt)
+	})
+
+	t.Run("CreateSkipsDuplicateEvent", func(t *testing.T) {
+		gateways := newGateways(t)
+		subscription := mustCreateSubscription(t, gateways.WebhookSubscription)
+		gw := gateways.WebhookDelivery
+		mustCreateDelivery(t, gw, subscription.ID, 7)
+
+		duplicate := entities.NewWebhookDelivery(subscription.ID, 7, entities.EventOrderCreated, []byte(`{"id":7}`))
+		created, err := gw.Create(ctx, duplicate)
+		if err != nil || created || duplicate.ID != 0 {
+			t.Fatalf("Create: expected the duplicate skipped, got %v, %d, %v", created, duplicate.ID, err)
+		}
+
+		deliveries, err := gw.GetBySubscriptionID(ctx, subscription.ID)
+		if err != nil || len(deliveries) != 1 {
+			t.Errorf("GetBySubscriptionID: expected 1 delivery, got %d, %v", len(deliveries), err)
+		}
+	})
+
+	t.Run("GetBySubscriptionIDNewestFirst", func(t *testing.T) {
+		gateways := newGateways(t)
+		subscription := mustCreateSubscription(t, gateways.WebhookSubscription)
+		other := mustCreateSubscription(t, gateways.WebhookSubscription)
+		gw := gateways.WebhookDelivery
+
+		first := mustCreateDelivery(t, gw, subscription.ID, 1)
+		second := mustCreateDelivery(t, gw, subscription.ID, 2)
+		mustCreateDelivery(t, gw, other.ID, 1)
+
+		deliveries, err := gw.GetBySubscriptionID(ctx, subscription.ID)
+		if err != nil || len(deliveries) != 2 {
+			t.Fatalf("GetBySubscriptionID: expected 2 deliveries, got %d, %v", len(deliveries), err)
+		}
+		if deliveries[0].ID != second.ID || deliveries[1].ID != first.ID {
+			t.Errorf("Expected deliveries %d and %d, got %d and %d", second.ID, first.ID, deliveries[0].ID, deliveries[1].ID)
+		}
+	})
+
+	t.Run("UpdateControlsDue", func(t *testing.T) {
+		gateways := newGateways(t)
+		subscription := mustCreateSubscription(t, gateways.WebhookSubscription)
+		gw := gateways.WebhookDelivery
+
+		succeeded := mustCreateDelivery(t, gw, subscription.ID, 1)
+		succeeded.MarkSucceeded(http.StatusOK, time.Now())
+		gaveUp := mustCreateDelivery(t, gw, subscription.ID, 2)
+		gaveUp.MarkFailed(http.StatusGone, errors.New("endpoint answered 410 Gone"), nil)
+		retrying := mustCreateDelivery(t, gw, subscription.ID, 3)
+		retryAt := past(-time.Hour)
+		retrying.MarkFailed(0, errors.New("connection refused"), &retryAt)
+		for _, delivery := range []*entities.WebhookDelivery{succeeded, gaveUp, retrying} {
+			if err := gw.Update(ctx, delivery); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+		}
+
+		if due, err := gw.ListDue(ctx, time.Now().Add(time.Second), 10); err != nil || len(due) != 0 {
+			t.Fatalf("ListDue: expected no delivery due now, got %d, %v", len(due), err)
+		}
+
+		due, err := gw.ListDue(ctx, time.Now().Add(2*time.Hour), 10)
+		if err != nil || len(due) != 1 {
+			t.Fatalf("ListDue: expected the retrying delivery once due, got %d, %v", len(due), err)
+		}
+		if due[0].ID != retrying.ID || due[0].Attempts != 1 || due[0].LastError != "connection refused" {
+			t.Errorf("Expected the retrying delivery state, got %+v", due[0])
+		}
+
+		found, err := gw.GetByID(ctx, succeeded.ID)
+		if err != nil || found.Status != entities.WebhookDeliverySucceeded || found.LastStatusCode != http.StatusOK || found.DeliveredAt == nil {
+			t.Errorf("Expected the succeeded delivery state, got %+v, %v", found, err)
+		}
+	})
+
+	t.Run("ClaimOnce", func(t *testing.T) {
+		gateways := newGateways(t)
+		subscription := mustCreateSubscription(t, gateways.WebhookSubscription)
+		gw := gateways.WebhookDelivery
+		due := mustCreateDelivery(t, gw, subscription.ID, 1)
+		succeeded := mustCreateDelivery(t, gw, subscription.ID, 2)
+		succeeded.MarkSucceeded(http.StatusOK, time.Now())
+		if err := gw.Update(ctx, succeeded); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		now, until := past(0), past(-time.Minute)
+
+		claimed, err := gw.Claim(ctx, due, now, until)
+		if err != nil || !claimed {
+			t.Fatalf("Claim: expected the due delivery to be claimed, got %v, %v", claimed, err)
+		}
+		assertSameInstant(t, "NextAttemptAt", until, due.NextAttemptAt)
+		other := *due
+		if claimed, err := gw.Claim(ctx, &other, now, until); err != nil || claimed {
+			t.Errorf("Claim: expected a claimed delivery not to be claimed again, got %v, %v", claimed, err)
+		}
+		if claimed, err := gw.Claim(ctx, succeeded, now, until); err != nil || claimed {
+			t.Errorf("Claim: expected a succeeded delivery not to be claimed, got %v, %v", claimed, err)
+		}
+
+		if found, err := gw.ListDue(ctx, now, 10); err != nil || len(found) != 0 {
+			t.Errorf("ListDue: expected the claimed delivery to be hidden, got %+v, %v", found, err)
+		}
+		if found, err := gw.ListDue(ctx, until, 10); err != nil || len(found) != 1 || found[0].ID != due.ID {
+			t.Errorf("ListDue: expected the claimed delivery due again once the claim lapses, got %+v, %v", found, err)
+		}
+	})
+}
+
+func mustCreateSubscription(t *testing.T, gw output.WebhookSubscriptionGateway) *entities.WebhookSubscription {
+	t.Helper()
+	subscription := entities.NewWebhookSubscription("https://partner.example.com/hooks", "whsec_test", []string{entities.EventOrderCreated, "order.ready"})
+	subscription.CreatedAt = past(time.Minute)
+	subscription.UpdatedAt = subscription.CreatedAt
+	if err := gw.Create(ctx, subscription); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return subscription
+}
+
+func mustCreateDelivery(t *testing.T, gw output.WebhookDeliveryGateway, subscriptionID, eventID uint64) *entities.WebhookDelivery {
+	t.Helper()
+	delivery := entities.NewWebhookDelivery(subscriptionID, eventID, entities.EventOrderCreated, []byte(`{"id":7}`))
+	delivery.CreatedAt = past(time.Minute)
+	delivery.UpdatedAt = delivery.CreatedAt
+	delivery.NextAttemptAt = delivery.CreatedAt
+	created, err := gw.Create(ctx, delivery)
+	if err != nil || !created {
+		t.Fatalf("Create: %v, %v", created, err)
+	}
+	return delivery
+}
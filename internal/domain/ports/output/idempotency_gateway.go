@@ -0,0 +1,24 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// IdempotencyGateway defines the contract for idempotency key storage. A
+// key is unique within its scope.
+type IdempotencyGateway interface {
+	// Reserve stores the record unless its scope and key are taken, in which
+	// case nothing is stored and the existing record is returned. It returns
+	// nil, nil when the record was stored.
+	Reserve(ctx context.Context, record *entities.IdempotencyRecord) (*entities.IdempotencyRecord, error)
+	// Complete saves the response of a reserved record
+	Complete(ctx context.Context, record *entities.IdempotencyRecord) error
+	// Release deletes the record so the key can be reserved again
+	Release(ctx context.Context, scope, key string) error
+	// DeleteCreatedBefore removes records created before t and returns how
+	// many were removed
+	DeleteCreatedBefore(ctx context.Context, t time.Time) (int64, error)
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// KitchenTicketGateway records the kitchen ticket copies of each order and
+// their print state
+type KitchenTicketGateway interface {
+	// Create records a copy unless the order already has one with the same
+	// number. It returns false, leaving ticket.ID zero, in that case.
+	Create(ctx context.Context, ticket *entities.KitchenTicket) (bool, error)
+	// ListByOrderID returns the order's copies by copy number, printed or
+	// not; the tickets carry no items
+	ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.KitchenTicket, error)
+	// ListDue returns up to limit pending copies due at now, oldest first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KitchenTicket, error)
+	// Claim moves the next attempt of a copy still pending and due at now to
+	// until, so other replicas listing due copies skip it while this one
+	// prints it. It returns false when another replica got there first.
+	Claim(ctx context.Context, ticket *entities.KitchenTicket, now, until time.Time) (bool, error)
+	// Update saves the print state: status, attempts, last error, next
+	// attempt and print time
+	Update(ctx context.Context, ticket *entities.KitchenTicket) error
+}
+
+// KitchenTicketRenderer renders a kitchen ticket in a printer's language
+type KitchenTicketRenderer interface {
+	Render(ticket *entities.KitchenTicket) ([]byte, error)
+}
@@ -0,0 +1,17 @@
+package output
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// LoyaltyGateway defines the contract for the loyalty points ledger. Entries
+// are never changed once written.
+type LoyaltyGateway interface {
+	Create(ctx context.Context, entry *entities.LoyaltyEntry) error
+	// ListByCustomerID returns the customer's entries, oldest first
+	ListByCustomerID(ctx context.Context, customerID uint64) ([]*entities.LoyaltyEntry, error)
+	// ListByOrderID returns the entries about an order, oldest first
+	ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.LoyaltyEntry, error)
+}
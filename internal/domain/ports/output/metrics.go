@@ -0,0 +1,19 @@
+package output
+
+import (
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// Metrics receives business events for monitoring. Implementations must be
+// safe for concurrent use and must not fail the operation being recorded.
+type Metrics interface {
+	OrderCreated(status entities.OrderStatus)
+	OrderStatusChanged(status entities.OrderStatus)
+	// PaymentSettled is called when a webhook moves a payment to a final status
+	PaymentSettled(status entities.PaymentStatus)
+	// WebhookProcessed records how long a payment webhook took; failed is true
+	// when it returned an error
+	WebhookProcessed(elapsed time.Duration, failed bool)
+}
@@ -1,23 +1,39 @@
 package output
 
-import "github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+import (
+	"context"
 
-// OrderGateway defines the contract for order data access operations
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// OrderGateway defines the contract for order data access operations.
+// GetByID returns nil, nil when no order matches; orders are returned without
+// their items, which are loaded through OrderItemGateway.
 type OrderGateway interface {
-	Create(order *entities.Order) error
-	GetByID(id uint64) (*entities.Order, error)
-	GetByCPF(cpf string) ([]*entities.Order, error)
-	GetByCustomerID(customerID uint64) ([]*entities.Order, error)
-	GetAll() ([]*entities.Order, error)
-	GetPendingOrdersForKitchen() ([]*entities.Order, error)
-	Update(order *entities.Order) error
-	Delete(id uint64) error
+	Create(ctx context.Context, order *entities.Order) error
+	GetByID(ctx context.Context, id uint64) (*entities.Order, error)
+	// GetByIDForUpdate is GetByID that also locks the order until the
+	// transaction in ctx ends. Changes that depend on the order's payments
+	// or tickets take it first, so concurrent ones are applied one at a time.
+	GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Order, error)
+	// GetByCPF, GetByCustomerID and GetAll return the newest orders first
+	GetByCPF(ctx context.Context, cpf string) ([]*entities.Order, error)
+	GetByCustomerID(ctx context.Context, customerID uint64) ([]*entities.Order, error)
+	GetAll(ctx context.Context) ([]*entities.Order, error)
+	// GetPendingOrdersForKitchen returns ready, in_progress and received
+	// orders, in that priority, oldest first within each status
+	GetPendingOrdersForKitchen(ctx context.Context) ([]*entities.Order, error)
+	Update(ctx context.Context, order *entities.Order) error
+	// Delete removes the order together with everything stored for it:
+	// items, discounts, taxes, payments, loyalty entries, receipt and tickets
+	Delete(ctx context.Context, id uint64) error
 }
 
 // OrderItemGateway defines the contract for order item data access operations
 type OrderItemGateway interface {
-	Create(orderItem *entities.OrderItem) error
-	GetByOrderID(orderID uint64) ([]*entities.OrderItem, error)
-	Update(orderItem *entities.OrderItem) error
-	Delete(id uint64) error
+	Create(ctx context.Context, orderItem *entities.OrderItem) error
+	// GetByOrderID returns the items of an order in insertion order
+	GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderItem, error)
+	Update(ctx context.Context, orderItem *entities.OrderItem) error
+	Delete(ctx context.Context, id uint64) error
 }
@@ -0,0 +1,16 @@
+package output
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// OrderTaxGateway defines the contract for order tax line data access
+// operations
+type OrderTaxGateway interface {
+	Create(ctx context.Context, tax *entities.OrderTax) error
+	// GetByOrderID returns the order's tax lines in the order they were
+	// created
+	GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderTax, error)
+}
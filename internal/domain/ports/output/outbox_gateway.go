@@ -0,0 +1,32 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// OutboxGateway defines the contract for the transactional outbox. Append is
+// meant to run inside the transaction of the state change it describes.
+type OutboxGateway interface {
+	Append(ctx context.Context, event *entities.OutboxEvent) error
+	// ListPending returns up to limit unpublished events due at now, oldest
+	// first
+	ListPending(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEvent, error)
+	// Claim moves the next attempt of an event still unpublished and due at
+	// now to until, so other replicas listing pending events skip it while
+	// this one publishes it. It returns false when another replica got there
+	// first.
+	Claim(ctx context.Context, event *entities.OutboxEvent, now, until time.Time) (bool, error)
+	// Update saves the delivery state: attempts, last error, next attempt and
+	// publication time
+	Update(ctx context.Context, event *entities.OutboxEvent) error
+}
+
+// EventPublisher delivers outbox events to other systems (a log, an HTTP
+// endpoint, a message broker). Publish must return an error unless the
+// event was accepted, so the relay retries it.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+}
@@ -1,13 +1,30 @@
 package output
 
-import "github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+import (
+	"context"
+	"time"
 
-// PaymentGateway defines the contract for payment data access operations
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// PaymentGateway defines the contract for payment data access operations.
+// Lookups return nil, nil when no payment matches.
 type PaymentGateway interface {
-	Create(payment *entities.Payment) error
-	GetByID(id uint64) (*entities.Payment, error)
-	GetByOrderID(orderID uint64) (*entities.Payment, error)
-	GetByTransactionID(transactionID string) (*entities.Payment, error)
-	Update(payment *entities.Payment) error
-	Delete(id uint64) error
+	Create(ctx context.Context, payment *entities.Payment) error
+	GetByID(ctx context.Context, id uint64) (*entities.Payment, error)
+	// GetLatestByOrderID returns the order's most recent payment attempt
+	GetLatestByOrderID(ctx context.Context, orderID uint64) (*entities.Payment, error)
+	// ListByOrderID returns every payment attempt of the order, oldest first
+	ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.Payment, error)
+	GetByTransactionID(ctx context.Context, transactionID string) (*entities.Payment, error)
+	// ListExpired returns up to limit pending payments whose expiry is at or
+	// before now, earliest expiry first
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Payment, error)
+	// ListPendingCreatedBefore returns up to limit pending payments created
+	// before the given time, oldest first
+	ListPendingCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Payment, error)
+	// ListCreatedBetween returns the payments created in [from, to), by ID
+	ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*entities.Payment, error)
+	Update(ctx context.Context, payment *entities.Payment) error
+	Delete(ctx context.Context, id uint64) error
 }
@@ -0,0 +1,18 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// PaymentProvider reads the provider's records of our payments, the source
+// of truth when a webhook was lost
+type PaymentProvider interface {
+	// ListPaymentsByOrderID returns every payment the provider has for the
+	// order, oldest first: one per attempt and per part of a split bill
+	ListPaymentsByOrderID(ctx context.Context, orderID uint64) ([]*entities.ProviderPayment, error)
+	// ListPayments returns the payments the provider created in [from, to)
+	ListPayments(ctx context.Context, from, to time.Time) ([]*entities.ProviderPayment, error)
+}
@@ -0,0 +1,9 @@
+package output
+
+import "context"
+
+// Printer sends a rendered document to a printer. Print returns once the
+// printer took the whole document, or with an error.
+type Printer interface {
+	Print(ctx context.Context, document []byte) error
+}
@@ -1,13 +1,21 @@
 package output
 
-import "github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+import (
+	"context"
 
-// ProductGateway defines the contract for product data access operations
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// ProductGateway defines the contract for product data access operations.
+// GetByID returns nil, nil when no product matches.
 type ProductGateway interface {
-	Create(product *entities.Product) error
-	GetByID(id uint64) (*entities.Product, error)
-	GetByCategory(category string) ([]*entities.Product, error)
-	GetAll() ([]*entities.Product, error)
-	Update(product *entities.Product) error
-	Delete(id uint64) error
+	Create(ctx context.Context, product *entities.Product) error
+	GetByID(ctx context.Context, id uint64) (*entities.Product, error)
+	// GetByCategory returns the products of a category ordered by name
+	GetByCategory(ctx context.Context, category string) ([]*entities.Product, error)
+	// GetAll returns every product in menu order (snack, side, drink,
+	// dessert), then by name
+	GetAll(ctx context.Context) ([]*entities.Product, error)
+	Update(ctx context.Context, product *entities.Product) error
+	Delete(ctx context.Context, id uint64) error
 }
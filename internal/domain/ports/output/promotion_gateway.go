@@ -0,0 +1,42 @@
+package output
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// PromotionGateway defines the contract for promotion data access
+// operations. GetByID and GetByCode return nil, nil when nothing matches.
+type PromotionGateway interface {
+	Create(ctx context.Context, promotion *entities.Promotion) error
+	GetByID(ctx context.Context, id uint64) (*entities.Promotion, error)
+	// GetByIDForUpdate is GetByID that also locks the promotion until the
+	// transaction in ctx ends, so per-customer limits are checked one order
+	// at a time
+	GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Promotion, error)
+	// GetByCode returns the promotion with the normalized coupon code
+	GetByCode(ctx context.Context, code string) (*entities.Promotion, error)
+	// GetAll returns every promotion, oldest first
+	GetAll(ctx context.Context) ([]*entities.Promotion, error)
+	// ListAutomatic returns the active promotions without a coupon code,
+	// oldest first
+	ListAutomatic(ctx context.Context) ([]*entities.Promotion, error)
+	Update(ctx context.Context, promotion *entities.Promotion) error
+	// Delete removes the promotion. Discount lines of past orders keep their
+	// code and description.
+	Delete(ctx context.Context, id uint64) error
+}
+
+// OrderDiscountGateway defines the contract for order discount line data
+// access operations
+type OrderDiscountGateway interface {
+	Create(ctx context.Context, discount *entities.OrderDiscount) error
+	// GetByOrderID returns the order's discount lines in the order they were
+	// applied
+	GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderDiscount, error)
+	// CountUses returns how many orders of a customer, matched by customer
+	// ID or CPF (zero and empty match nothing), used the promotion.
+	// Cancelled orders do not count.
+	CountUses(ctx context.Context, promotionID, customerID uint64, cpf string) (int, error)
+}
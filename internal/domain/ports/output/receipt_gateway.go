@@ -0,0 +1,23 @@
+package output
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// ReceiptGateway defines the contract for receipt data access operations.
+// An order has at most one receipt, which is never changed once written.
+type ReceiptGateway interface {
+	// Create stores the receipt with its renditions unless the order already
+	// has one, so issuing twice keeps the first. It returns false, leaving
+	// receipt.ID zero, in that case.
+	Create(ctx context.Context, receipt *entities.Receipt) (bool, error)
+	// GetByOrderID returns nil, nil when the order has no receipt
+	GetByOrderID(ctx context.Context, orderID uint64) (*entities.Receipt, error)
+}
+
+// ReceiptRenderer renders a receipt in one format, e.g. XML or plain text
+type ReceiptRenderer interface {
+	Render(receipt *entities.Receipt) ([]byte, error)
+}
@@ -0,0 +1,17 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// ReconciliationGateway stores one reconciliation report per UTC day
+type ReconciliationGateway interface {
+	// SaveReport replaces the report of report.Date and its mismatches
+	SaveReport(ctx context.Context, report *entities.ReconciliationReport) error
+	// GetReport returns the report of the day of date, or nil, nil when the
+	// day was not reconciled
+	GetReport(ctx context.Context, date time.Time) (*entities.ReconciliationReport, error)
+}
@@ -0,0 +1,24 @@
+package output
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// StationTicketGateway defines the contract for the tickets each kitchen
+// station prepares. GetByID returns nil, nil when no ticket matches.
+type StationTicketGateway interface {
+	// Create stores the ticket unless the order already has one for the
+	// same station. It returns false, leaving ticket.ID zero, in that case.
+	Create(ctx context.Context, ticket *entities.StationTicket) (bool, error)
+	GetByID(ctx context.Context, id uint64) (*entities.StationTicket, error)
+	// ListByOrderID returns the order's tickets in the order they were
+	// created
+	ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.StationTicket, error)
+	// ListOpenByStation returns the station's pending and preparing tickets
+	// of orders still in the kitchen (received or in_progress), oldest first
+	ListOpenByStation(ctx context.Context, station string) ([]*entities.StationTicket, error)
+	// Update saves the ticket's status
+	Update(ctx context.Context, ticket *entities.StationTicket) error
+}
@@ -0,0 +1,11 @@
+package output
+
+import "context"
+
+// TransactionManager runs several gateway calls atomically. Gateways called
+// with the ctx passed to fn take part in the transaction.
+type TransactionManager interface {
+	// WithinTransaction commits when fn returns nil and rolls back when it
+	// returns an error
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// WebhookSubscriptionGateway defines the contract for webhook subscription
+// data access operations. GetByID returns nil, nil when nothing matches.
+type WebhookSubscriptionGateway interface {
+	Create(ctx context.Context, subscription *entities.WebhookSubscription) error
+	GetByID(ctx context.Context, id uint64) (*entities.WebhookSubscription, error)
+	// GetAll returns every subscription, oldest first
+	GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	// Delete removes the subscription and its deliveries
+	Delete(ctx context.Context, id uint64) error
+}
+
+// WebhookDeliveryGateway defines the contract for webhook delivery data
+// access operations. GetByID returns nil, nil when nothing matches.
+type WebhookDeliveryGateway interface {
+	// Create stores the delivery unless the subscription already has one for
+	// the same event, so fanning an event out twice sends it once. It returns
+	// false, leaving delivery.ID zero, in that case.
+	Create(ctx context.Context, delivery *entities.WebhookDelivery) (bool, error)
+	GetByID(ctx context.Context, id uint64) (*entities.WebhookDelivery, error)
+	// GetBySubscriptionID returns the subscription's deliveries, newest first
+	GetBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entities.WebhookDelivery, error)
+	// ListDue returns up to limit pending deliveries due at now, oldest first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error)
+	// Claim moves the next attempt of a delivery still pending and due at now
+	// to until, so other replicas listing due deliveries skip it while this
+	// one sends it. It returns false when another replica got there first.
+	Claim(ctx context.Context, delivery *entities.WebhookDelivery, now, until time.Time) (bool, error)
+	// Update saves the delivery state: status, attempts, last outcome and
+	// next attempt
+	Update(ctx context.Context, delivery *entities.WebhookDelivery) error
+}
@@ -0,0 +1,127 @@
+// Package services holds domain logic that spans several entities
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// PricingRules are the store's rules for pricing an order
+type PricingRules struct {
+	// TaxRates is the approximate tax rate included in the price of each
+	// product category, in percent. Categories without a rate get no tax
+	// line.
+	TaxRates map[entities.ProductCategory]float64
+	// ServiceFeePercent is charged on the subtotal after discounts; zero
+	// charges no fee
+	ServiceFeePercent float64
+	// RoundingIncrement is what the total is rounded to, e.g. 0.05
+	RoundingIncrement float64
+	RoundingMode      entities.RoundingMode
+}
+
+// PricingService computes the price breakdown of an order
+type PricingService struct {
+	rules PricingRules
+}
+
+func NewPricingService(rules PricingRules) *PricingService {
+	return &PricingService{
+		rules: rules,
+	}
+}
+
+// Price computes the breakdown of an order whose items and discounts are
+// already set. categories maps the items' product IDs to their category.
+// Amounts are worked out in cents so they add up exactly.
+func (s *PricingService) Price(order *entities.Order, categories map[uint64]string) entities.PriceBreakdown {
+	subtotal := toCents(order.Subtotal())
+	discount := min(toCents(order.Discount), subtotal)
+	discounted := subtotal - discount
+
+	fee := int64(math.Round(float64(discounted) * s.rules.ServiceFeePercent / 100))
+	unrounded := discounted + fee
+	total := s.round(unrounded)
+
+	return entities.PriceBreakdown{
+		Subtotal:   fromCents(subtotal),
+		Discount:   fromCents(discount),
+		ServiceFee: fromCents(fee),
+		Rounding:   fromCents(total - unrounded),
+		Taxes:      s.taxes(order.Items, categories, subtotal, discount),
+		Total:      fromCents(total),
+	}
+}
+
+// taxes builds a tax line per taxed category. Discounts are shared among the
+// categories in proportion to their subtotal, the last one taking what
+// rounding leaves, so the bases add up to the discounted subtotal.
+func (s *PricingService) taxes(items []entities.OrderItem, categories map[uint64]string, subtotal, discount int64) []entities.OrderTax {
+	byCategory := make(map[string]int64)
+	for _, item := range items {
+		byCategory[categories[item.ProductID]] += toCents(item.CalculateSubtotal())
+	}
+
+	names := make([]string, 0, len(byCategory))
+	for name := range byCategory {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var taxes []entities.OrderTax
+	shared := int64(0)
+	for i, name := range names {
+		share := discount - shared
+		if i < len(names)-1 && subtotal > 0 {
+			share = int64(math.Round(float64(discount) * float64(byCategory[name]) / float64(subtotal)))
+		}
+		shared += share
+
+		rate := s.rules.TaxRates[entities.ProductCategory(name)]
+		base := byCategory[name] - share
+		if rate <= 0 || base <= 0 {
+			continue
+		}
+		taxes = append(taxes, entities.OrderTax{
+			Category: name,
+			Rate:     float32(rate),
+			Base:     fromCents(base),
+			Amount:   fromCents(int64(math.Round(float64(base) * rate / 100))),
+		})
+	}
+	return taxes
+}
+
+// round rounds cents to the rounding increment
+func (s *PricingService) round(cents int64) int64 {
+	increment := int64(math.Round(s.rules.RoundingIncrement * 100))
+	if increment <= 1 {
+		return cents
+	}
+
+	down := cents / increment * increment
+	switch s.rules.RoundingMode {
+	case entities.RoundDown:
+		return down
+	case entities.RoundUp:
+		if down == cents {
+			return down
+		}
+		return down + increment
+	default:
+		if cents-down >= increment-(cents-down) {
+			return down + increment
+		}
+		return down
+	}
+}
+
+func toCents(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+func fromCents(cents int64) float32 {
+	return float32(cents) / 100
+}
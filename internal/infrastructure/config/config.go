@@ -0,0 +1,508 @@
+// Package config loads the server configuration into a typed struct. Values
+// come from, in increasing priority: built-in defaults, an optional YAML
+// file, and environment variables (including those from .env).
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	// Embeds the zone database, so STORE_TIMEZONE loads in minimal images
+	_ "time/tzdata"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/services"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/mercadopago"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/outbox"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/printing"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/ratelimit"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/webhooks"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values when the configuration is printed
+const redacted = "******"
+
+// Config is the complete server configuration. Every leaf field has an env
+// tag naming its environment variable; fields tagged secret are redacted by
+// Redacted and must come from a Kubernetes Secret in the Helm chart.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Log       Log       `yaml:"log"`
+	Telemetry Telemetry `yaml:"telemetry"`
+	Payment   Payment   `yaml:"payment"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+
+	Idempotency Idempotency `yaml:"idempotency"`
+	Outbox      Outbox      `yaml:"outbox"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+
+	Reconciliation Reconciliation `yaml:"reconciliation"`
+	Store          Store          `yaml:"store"`
+	Loyalty        Loyalty        `yaml:"loyalty"`
+	Pricing        Pricing        `yaml:"pricing"`
+	KitchenPrinter KitchenPrinter `yaml:"kitchen_printer"`
+	Kitchen        Kitchen        `yaml:"kitchen"`
+}
+
+type Server struct {
+	Port        string `yaml:"port" env:"PORT"`
+	GinMode     string `yaml:"gin_mode" env:"GIN_MODE"`
+	Environment string `yaml:"environment" env:"ENVIRONMENT"`
+
+	RequestTimeout     time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT"`
+	ReadTimeout        time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout  time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout       time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+
+	// TrustedProxies is a comma separated list of IPs or CIDRs allowed to set
+	// X-Forwarded-For; with none the client IP is the connection's address
+	TrustedProxies string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES"`
+}
+
+type Database struct {
+	Driver   string `yaml:"driver" env:"DB_DRIVER"`
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     string `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+}
+
+type Log struct {
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+type Telemetry struct {
+	TracesExporter string `yaml:"traces_exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName    string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// Payment configures the payment provider and how long a payment stays
+// payable. Pending payments past TTL are expired every ExpirationInterval
+// and their orders cancelled; a TTL of zero disables expiry. ProviderURL is
+// the MercadoPago API used to reconcile payments with AccessToken.
+type Payment struct {
+	AccessToken        string        `yaml:"access_token" env:"ACCESSTOKEN" secret:"true"`
+	ProviderURL        string        `yaml:"provider_url" env:"PAYMENT_PROVIDER_URL"`
+	ProviderHealthURL  string        `yaml:"provider_health_url" env:"PAYMENT_PROVIDER_HEALTH_URL"`
+	TTL                time.Duration `yaml:"ttl" env:"PAYMENT_TTL"`
+	ExpirationInterval time.Duration `yaml:"expiration_interval" env:"PAYMENT_EXPIRATION_INTERVAL"`
+}
+
+// Idempotency configures Idempotency-Key handling. Keys older than KeyTTL
+// are purged, after which a retry runs as a new request.
+type Idempotency struct {
+	KeyTTL time.Duration `yaml:"key_ttl" env:"IDEMPOTENCY_KEY_TTL"`
+}
+
+// Outbox configures the relay that publishes domain events. WebhookURL is
+// required by the http publisher; the log publisher ignores it.
+type Outbox struct {
+	Publisher    string        `yaml:"publisher" env:"OUTBOX_PUBLISHER"`
+	WebhookURL   string        `yaml:"webhook_url" env:"OUTBOX_WEBHOOK_URL"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+	BatchSize    int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env:"OUTBOX_MAX_BACKOFF"`
+}
+
+// RelayConfig returns the relay settings
+func (o Outbox) RelayConfig() outbox.RelayConfig {
+	return outbox.RelayConfig{
+		PollInterval: o.PollInterval,
+		BatchSize:    o.BatchSize,
+		MaxBackoff:   o.MaxBackoff,
+	}
+}
+
+// Webhooks configures delivery of outgoing webhooks to partner
+// subscriptions. A delivery is retried with exponential backoff, from
+// InitialBackoff up to MaxBackoff, and given up after MaxAttempts.
+type Webhooks struct {
+	PollInterval   time.Duration `yaml:"poll_interval" env:"WEBHOOK_DELIVERY_POLL_INTERVAL"`
+	Timeout        time.Duration `yaml:"timeout" env:"WEBHOOK_DELIVERY_TIMEOUT"`
+	MaxAttempts    int           `yaml:"max_attempts" env:"WEBHOOK_DELIVERY_MAX_ATTEMPTS"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"WEBHOOK_DELIVERY_INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"WEBHOOK_DELIVERY_MAX_BACKOFF"`
+}
+
+// DispatcherConfig returns the dispatcher settings
+func (w Webhooks) DispatcherConfig() webhooks.Config {
+	return webhooks.Config{
+		PollInterval:   w.PollInterval,
+		MaxAttempts:    w.MaxAttempts,
+		InitialBackoff: w.InitialBackoff,
+		MaxBackoff:     w.MaxBackoff,
+	}
+}
+
+// Reconciliation configures the worker that asks the payment provider about
+// pending payments older than MinAge every Interval and writes the daily
+// mismatch report. It only runs when ACCESSTOKEN is set.
+type Reconciliation struct {
+	Interval time.Duration `yaml:"interval" env:"RECONCILIATION_INTERVAL"`
+	MinAge   time.Duration `yaml:"min_age" env:"RECONCILIATION_MIN_AGE"`
+}
+
+// Store describes the store itself. Timezone is an IANA zone name; promotion
+// weekdays and times of day are read in it. Name, CNPJ and Address head the
+// receipts, whose plain text rendition is ReceiptWidth columns wide.
+type Store struct {
+	Timezone     string `yaml:"timezone" env:"STORE_TIMEZONE"`
+	Name         string `yaml:"name" env:"STORE_NAME"`
+	CNPJ         string `yaml:"cnpj" env:"STORE_CNPJ"`
+	Address      string `yaml:"address" env:"STORE_ADDRESS"`
+	ReceiptWidth int    `yaml:"receipt_width" env:"RECEIPT_WIDTH"`
+}
+
+// Issuer returns how the store is identified on its receipts
+func (s Store) Issuer() entities.ReceiptIssuer {
+	return entities.ReceiptIssuer{
+		Name:    s.Name,
+		CNPJ:    s.CNPJ,
+		Address: s.Address,
+	}
+}
+
+// Location returns the store's time zone, or UTC when Timezone does not
+// load (Validate rejects that)
+func (s Store) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Loyalty configures the points program: points earned per R$ 1,00 paid on
+// completed orders, what a point is worth when redeemed at checkout and how
+// long points last (zero keeps them forever)
+type Loyalty struct {
+	PointsPerReal float64       `yaml:"points_per_real" env:"LOYALTY_POINTS_PER_REAL"`
+	PointValue    float64       `yaml:"point_value" env:"LOYALTY_POINT_VALUE"`
+	PointsTTL     time.Duration `yaml:"points_ttl" env:"LOYALTY_POINTS_TTL"`
+}
+
+// Program returns the loyalty rules the use cases apply
+func (l Loyalty) Program() entities.LoyaltyProgram {
+	return entities.LoyaltyProgram{
+		PointsPerReal: l.PointsPerReal,
+		PointValue:    l.PointValue,
+		PointsTTL:     l.PointsTTL,
+	}
+}
+
+// Pricing configures the order price breakdown: the approximate tax rate
+// included in each product category's prices and the service fee, both in
+// percent, and how totals are rounded (to RoundingIncrement, by
+// RoundingMode half_up, down or up)
+type Pricing struct {
+	TaxRateSnack      float64 `yaml:"tax_rate_snack" env:"TAX_RATE_SNACK"`
+	TaxRateDrink      float64 `yaml:"tax_rate_drink" env:"TAX_RATE_DRINK"`
+	TaxRateDessert    float64 `yaml:"tax_rate_dessert" env:"TAX_RATE_DESSERT"`
+	TaxRateSide       float64 `yaml:"tax_rate_side" env:"TAX_RATE_SIDE"`
+	ServiceFeePercent float64 `yaml:"service_fee_percent" env:"SERVICE_FEE_PERCENT"`
+	RoundingIncrement float64 `yaml:"rounding_increment" env:"PRICE_ROUNDING_INCREMENT"`
+	RoundingMode      string  `yaml:"rounding_mode" env:"PRICE_ROUNDING_MODE"`
+}
+
+// Rules returns the pricing rules orders are priced with
+func (p Pricing) Rules() services.PricingRules {
+	return services.PricingRules{
+		TaxRates: map[entities.ProductCategory]float64{
+			entities.SnackCategory:   p.TaxRateSnack,
+			entities.DrinkCategory:   p.TaxRateDrink,
+			entities.DessertCategory: p.TaxRateDessert,
+			entities.SideCategory:    p.TaxRateSide,
+		},
+		ServiceFeePercent: p.ServiceFeePercent,
+		RoundingIncrement: p.RoundingIncrement,
+		RoundingMode:      entities.RoundingMode(p.RoundingMode),
+	}
+}
+
+// KitchenPrinter configures the printer kitchen tickets go to when an order
+// is received: none, tcp (a raw socket at Address, usually port 9100) or
+// file (appended to Path, for testing). A job is given up after Timeout;
+// TicketWidth is how many characters fit a line of its paper. A worker
+// looks for queued tickets every PollInterval and retries failed ones with
+// a backoff doubling from InitialBackoff up to MaxBackoff, giving up after
+// MaxAttempts.
+type KitchenPrinter struct {
+	Driver         string        `yaml:"driver" env:"KITCHEN_PRINTER"`
+	Address        string        `yaml:"address" env:"KITCHEN_PRINTER_ADDRESS"`
+	Path           string        `yaml:"path" env:"KITCHEN_PRINTER_PATH"`
+	Timeout        time.Duration `yaml:"timeout" env:"KITCHEN_PRINTER_TIMEOUT"`
+	TicketWidth    int           `yaml:"ticket_width" env:"KITCHEN_TICKET_WIDTH"`
+	PollInterval   time.Duration `yaml:"poll_interval" env:"KITCHEN_PRINTER_POLL_INTERVAL"`
+	MaxAttempts    int           `yaml:"max_attempts" env:"KITCHEN_PRINTER_MAX_ATTEMPTS"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"KITCHEN_PRINTER_INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"KITCHEN_PRINTER_MAX_BACKOFF"`
+}
+
+// Retry returns how failed prints are retried
+func (p KitchenPrinter) Retry() entities.KitchenPrintRetry {
+	return entities.KitchenPrintRetry{
+		MaxAttempts:    p.MaxAttempts,
+		InitialBackoff: p.InitialBackoff,
+		MaxBackoff:     p.MaxBackoff,
+	}
+}
+
+// Kitchen splits the kitchen into stations. Stations lists them as
+// name=category pairs separated by semicolons, with the categories of a
+// station separated by commas: "grill=snack;fryer=side;drinks=drink,dessert".
+// Empty keeps the kitchen a single queue without station tickets.
+type Kitchen struct {
+	Stations string `yaml:"stations" env:"KITCHEN_STATIONS"`
+}
+
+// stationName is what a station may be called; names are part of URLs
+var stationName = regexp.MustCompile(`^[a-z0-9_-]{1,40}$`)
+
+// StationList returns the configured stations, or none when Stations does
+// not parse (Validate rejects that)
+func (k Kitchen) StationList() []entities.KitchenStation {
+	stations, err := parseStations(k.Stations)
+	if err != nil {
+		return nil
+	}
+	return stations
+}
+
+func parseStations(value string) ([]entities.KitchenStation, error) {
+	var stations []entities.KitchenStation
+	names := make(map[string]bool)
+	categories := make(map[entities.ProductCategory]string)
+	for _, entry := range strings.Split(value, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !stationName.MatchString(name) {
+			return nil, fmt.Errorf("%q must be name=category with a name of lowercase letters, digits, - or _", entry)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("station %q is listed twice", name)
+		}
+		names[name] = true
+
+		station := entities.KitchenStation{Name: name}
+		for _, category := range strings.Split(list, ",") {
+			category = strings.TrimSpace(category)
+			if !entities.IsValidCategory(category) {
+				return nil, fmt.Errorf("station %s: %q is not a product category", name, category)
+			}
+			if other, taken := categories[entities.ProductCategory(category)]; taken {
+				return nil, fmt.Errorf("category %s is handled by both %s and %s", category, other, name)
+			}
+			categories[entities.ProductCategory(category)] = name
+			station.Categories = append(station.Categories, entities.ProductCategory(category))
+		}
+		stations = append(stations, station)
+	}
+	return stations, nil
+}
+
+// RateLimit holds the token bucket policy of each rate limited route group.
+// A zero rate or burst disables the group's limit.
+type RateLimit struct {
+	OrdersPerMinute    int `yaml:"orders_per_minute" env:"RATE_LIMIT_ORDERS_PER_MINUTE"`
+	OrdersBurst        int `yaml:"orders_burst" env:"RATE_LIMIT_ORDERS_BURST"`
+	CustomersPerMinute int `yaml:"customers_per_minute" env:"RATE_LIMIT_CUSTOMERS_PER_MINUTE"`
+	CustomersBurst     int `yaml:"customers_burst" env:"RATE_LIMIT_CUSTOMERS_BURST"`
+}
+
+// Default returns the configuration used when nothing overrides it. It
+// holds no credentials: database user, password and name must be set.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:               "8080",
+			GinMode:            "debug",
+			Environment:        "development",
+			RequestTimeout:     10 * time.Second,
+			ReadTimeout:        15 * time.Second,
+			ReadHeaderTimeout:  5 * time.Second,
+			WriteTimeout:       30 * time.Second,
+			IdleTimeout:        60 * time.Second,
+			ShutdownDrainDelay: 0,
+			ShutdownTimeout:    20 * time.Second,
+		},
+		Database: Database{
+			Driver:          persistance.DriverMySQL,
+			Host:            "localhost",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnMaxIdleTime: time.Minute,
+		},
+		Log: Log{
+			Format: logging.FormatText,
+			Level:  "info",
+		},
+		Telemetry: Telemetry{
+			TracesExporter: telemetry.ExporterNone,
+			ServiceName:    telemetry.DefaultServiceName,
+		},
+		Payment: Payment{
+			ProviderURL:        mercadopago.DefaultBaseURL,
+			TTL:                15 * time.Minute,
+			ExpirationInterval: 30 * time.Second,
+		},
+		RateLimit: RateLimit{
+			OrdersPerMinute:    30,
+			OrdersBurst:        10,
+			CustomersPerMinute: 20,
+			CustomersBurst:     5,
+		},
+		Idempotency: Idempotency{
+			KeyTTL: 24 * time.Hour,
+		},
+		Outbox: Outbox{
+			Publisher:    outbox.PublisherLog,
+			PollInterval: outbox.DefaultPollInterval,
+			BatchSize:    outbox.DefaultBatchSize,
+			MaxBackoff:   outbox.DefaultMaxBackoff,
+		},
+		Webhooks: Webhooks{
+			PollInterval:   webhooks.DefaultPollInterval,
+			Timeout:        10 * time.Second,
+			MaxAttempts:    webhooks.DefaultMaxAttempts,
+			InitialBackoff: webhooks.DefaultInitialBackoff,
+			MaxBackoff:     webhooks.DefaultMaxBackoff,
+		},
+		Reconciliation: Reconciliation{
+			Interval: 5 * time.Minute,
+			MinAge:   10 * time.Minute,
+		},
+		Store: Store{
+			Timezone:     "America/Sao_Paulo",
+			Name:         "Fast Food",
+			ReceiptWidth: 48,
+		},
+		Loyalty: Loyalty{
+			PointsPerReal: 1,
+			PointValue:    0.05,
+			PointsTTL:     365 * 24 * time.Hour,
+		},
+		Pricing: Pricing{
+			RoundingIncrement: 0.01,
+			RoundingMode:      string(entities.RoundHalfUp),
+		},
+		KitchenPrinter: KitchenPrinter{
+			Driver:         printing.PrinterNone,
+			Timeout:        5 * time.Second,
+			TicketWidth:    48,
+			PollInterval:   time.Second,
+			MaxAttempts:    10,
+			InitialBackoff: 5 * time.Second,
+			MaxBackoff:     5 * time.Minute,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file at path
+// (skipped when path is empty) and the process environment, then validates
+// it. Callers load .env into the environment beforehand.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("read config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(raw))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil {
+			return cfg, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return cfg, err
+	}
+
+	cfg.Database.applyDriverDefaults()
+
+	return cfg, cfg.Validate()
+}
+
+// applyDriverDefaults fills the port from the driver when it was not set
+func (d *Database) applyDriverDefaults() {
+	if d.Port != "" {
+		return
+	}
+	switch d.Driver {
+	case persistance.DriverPostgres:
+		d.Port = "5432"
+	case persistance.DriverMySQL:
+		d.Port = "3306"
+	}
+}
+
+// Redacted returns a copy with every secret field masked. Empty secrets stay
+// empty so a missing value is still visible.
+func (c Config) Redacted() Config {
+	redact(&c)
+	return c
+}
+
+// YAML renders the redacted configuration, as shown by "config print"
+func (c Config) YAML() ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), encoder.Close()
+}
+
+// TrustedProxyList splits TrustedProxies, dropping empty entries
+func (s Server) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(s.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// Policies returns the rate limit policy of each route group by name
+func (r RateLimit) Policies() map[string]ratelimit.Policy {
+	return map[string]ratelimit.Policy{
+		ratelimit.GroupOrders:    {Name: ratelimit.GroupOrders, RequestsPerMinute: r.OrdersPerMinute, Burst: r.OrdersBurst},
+		ratelimit.GroupCustomers: {Name: ratelimit.GroupCustomers, RequestsPerMinute: r.CustomersPerMinute, Burst: r.CustomersBurst},
+	}
+}
+
+// DSN returns the data source name for the configured SQL driver
+func (d Database) DSN() string {
+	if d.Driver == persistance.DriverPostgres {
+		return "postgres://" + url.UserPassword(d.User, d.Password).String() + "@" + d.Host + ":" + d.Port + "/" + d.Name + "?sslmode=" + d.SSLMode
+	}
+	return d.User + ":" + d.Password + "@tcp(" + d.Host + ":" + d.Port + ")/" + d.Name + "?charset=utf8mb4&parseTime=True&loc=Local"
+}
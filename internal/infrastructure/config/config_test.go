@@ -0,0 +1,272 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// setEnv sets the variables for the test and clears every other variable
+// Load reads, so the developer's own environment cannot leak in
+func setEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	for _, v := range EnvVars() {
+		if value, ok := vars[v.Name]; ok {
+			t.Setenv(v.Name, value)
+			continue
+		}
+		t.Setenv(v.Name, "")
+		os.Unsetenv(v.Name)
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_EnvOverridesFileOverridesDefaults(t *testing.T) {
+	// Arrange
+	setEnv(t, map[string]string{"DB_HOST": "db.internal", "REQUEST_TIMEOUT": "3s"})
+	path := writeFile(t, `
+database:
+  driver: postgres
+  host: file-host
+  user: app
+  name: fastfood
+server:
+  request_timeout: 7s
+  shutdown_timeout: 9s
+`)
+
+	// Act
+	cfg, err := Load(path)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Database.Host != "db.internal" {
+		t.Errorf("Expected env to win for DB_HOST, got %q", cfg.Database.Host)
+	}
+	if cfg.Server.RequestTimeout != 3*time.Second || cfg.Server.ShutdownTimeout != 9*time.Second {
+		t.Errorf("Unexpected timeouts %s / %s", cfg.Server.RequestTimeout, cfg.Server.ShutdownTimeout)
+	}
+	if cfg.Database.Port != "5432" {
+		t.Errorf("Expected the postgres default port, got %q", cfg.Database.Port)
+	}
+	if cfg.Server.ReadTimeout != 15*time.Second {
+		t.Errorf("Expected default read timeout, got %s", cfg.Server.ReadTimeout)
+	}
+}
+
+func TestLoad_RejectsUnknownFileKeys(t *testing.T) {
+	// Arrange
+	setEnv(t, map[string]string{"DB_DRIVER": "memory"})
+	path := writeFile(t, "database:\n  hots: typo\n")
+
+	// Act
+	_, err := Load(path)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "hots") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+}
+
+func TestLoad_ReportsEveryInvalidSetting(t *testing.T) {
+	// Arrange
+	setEnv(t, map[string]string{
+		"PORT":              "http",
+		"DB_MAX_OPEN_CONNS": "ten",
+	})
+
+	// Act
+	_, err := Load("")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "DB_MAX_OPEN_CONNS") {
+		t.Fatalf("Expected a parse error for DB_MAX_OPEN_CONNS, got %v", err)
+	}
+
+	// Arrange
+	setEnv(t, map[string]string{
+		"PORT":                         "http",
+		"LOG_FORMAT":                   "xml",
+		"HTTP_TRUSTED_PROXIES":         "10.0.0.0/8, ingress",
+		"RATE_LIMIT_ORDERS_BURST":      "-1",
+		"OUTBOX_PUBLISHER":             "http",
+		"PAYMENT_TTL":                  "-1m",
+		"RECONCILIATION_MIN_AGE":       "0s",
+		"STORE_TIMEZONE":               "Mars/Olympus",
+		"LOYALTY_POINT_VALUE":          "0",
+		"TAX_RATE_DRINK":               "100",
+		"SERVICE_FEE_PERCENT":          "-5",
+		"PRICE_ROUNDING_INCREMENT":     "0.025",
+		"PRICE_ROUNDING_MODE":          "bankers",
+		"STORE_CNPJ":                   "123",
+		"RECEIPT_WIDTH":                "20",
+		"KITCHEN_PRINTER":              "tcp",
+		"KITCHEN_PRINTER_ADDRESS":      "printer.local",
+		"KITCHEN_PRINTER_MAX_ATTEMPTS": "0",
+		"KITCHEN_STATIONS":             "grill=snack;fryer=snack,side",
+		"WEBHOOK_DELIVERY_TIMEOUT":     "2m",
+	})
+
+	// Act
+	_, err = Load("")
+
+	// Assert
+	for _, name := range []string{"PORT", "DB_USER", "DB_NAME", "LOG_FORMAT", "HTTP_TRUSTED_PROXIES", "RATE_LIMIT_ORDERS_BURST", "OUTBOX_WEBHOOK_URL", "PAYMENT_TTL", "RECONCILIATION_MIN_AGE", "STORE_TIMEZONE", "LOYALTY_POINT_VALUE", "TAX_RATE_DRINK", "SERVICE_FEE_PERCENT", "PRICE_ROUNDING_INCREMENT", "PRICE_ROUNDING_MODE", "STORE_CNPJ", "RECEIPT_WIDTH", "KITCHEN_PRINTER_ADDRESS", "KITCHEN_PRINTER_MAX_ATTEMPTS", "KITCHEN_STATIONS", "WEBHOOK_DELIVERY_TIMEOUT"} {
+		if err == nil || !strings.Contains(err.Error(), name+":") {
+			t.Errorf("Expected %s in validation errors, got %v", name, err)
+		}
+	}
+}
+
+func TestKitchen_StationList(t *testing.T) {
+	// Arrange
+	kitchen := Kitchen{Stations: " grill = snack ; fryer=side;drinks=drink, dessert;"}
+
+	// Act
+	stations := kitchen.StationList()
+
+	// Assert
+	expected := []entities.KitchenStation{
+		{Name: "grill", Categories: []entities.ProductCategory{entities.SnackCategory}},
+		{Name: "fryer", Categories: []entities.ProductCategory{entities.SideCategory}},
+		{Name: "drinks", Categories: []entities.ProductCategory{entities.DrinkCategory, entities.DessertCategory}},
+	}
+	if !reflect.DeepEqual(stations, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, stations)
+	}
+	for _, invalid := range []string{"grill", "Grill=snack", "grill=pizza", "grill=snack;grill=side", "grill=snack;fryer=snack"} {
+		if _, err := parseStations(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestConfig_RedactsSecrets(t *testing.T) {
+	// Arrange
+	cfg := Default()
+	cfg.Database.User = "app"
+	cfg.Database.Password = "s3cret"
+	cfg.Payment.AccessToken = "APP_USR-123"
+
+	// Act
+	out, err := cfg.YAML()
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"s3cret", "APP_USR-123"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("Expected %q to be redacted:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(string(out), "user: app") {
+		t.Errorf("Expected non-secret values to be printed:\n%s", out)
+	}
+	if cfg.Database.Password != "s3cret" {
+		t.Error("Expected Redacted to leave the original untouched")
+	}
+}
+
+func TestDatabase_DSN(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{"mysql", "app:p@ss@tcp(db:3306)/ff?charset=utf8mb4&parseTime=True&loc=Local"},
+		{"postgres", "postgres://app:p%40ss@db:3306/ff?sslmode=disable"},
+	}
+
+	for _, tt := range tests {
+		// Arrange
+		d := Database{Driver: tt.driver, Host: "db", Port: "3306", User: "app", Password: "p@ss", Name: "ff", SSLMode: "disable"}
+
+		// Act
+		dsn := d.DSN()
+
+		// Assert
+		if dsn != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.driver, tt.want, dsn)
+		}
+	}
+}
+
+// helmKey matches "  KEY: {{ .Values.<section>.KEY ..." lines in the chart
+var helmKey = regexp.MustCompile(`(?m)^  ([A-Z_]+): \{\{ \.Values\.(env|secrets)\.`)
+
+// TestHelmChartMatchesConfig keeps the chart in sync with Config: every
+// variable is provided, secrets come from the Secret and nothing unknown is
+// set.
+func TestHelmChartMatchesConfig(t *testing.T) {
+	chart := map[string]string{}
+	for _, file := range []string{"configmap.yaml", "secret.yaml"} {
+		raw, err := os.ReadFile(filepath.Join("../../../helm/fast-food/templates", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range helmKey.FindAllStringSubmatch(string(raw), -1) {
+			chart[match[1]] = match[2]
+		}
+	}
+
+	known := map[string]bool{}
+	for _, v := range EnvVars() {
+		known[v.Name] = true
+
+		section, ok := chart[v.Name]
+		switch {
+		case !ok:
+			t.Errorf("%s is not set by the Helm chart", v.Name)
+		case v.Secret && section != "secrets":
+			t.Errorf("%s is a secret and must come from secret.yaml", v.Name)
+		case !v.Secret && section != "env":
+			t.Errorf("%s is not a secret and belongs in configmap.yaml", v.Name)
+		}
+	}
+
+	deployment, err := os.ReadFile("../../../helm/fast-food/templates/deployment.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range chart {
+		if !known[name] {
+			t.Errorf("Helm sets %s, which the configuration does not read", name)
+		}
+		if !strings.Contains(string(deployment), "key: "+name+"\n") {
+			t.Errorf("%s is in the chart but deployment.yaml does not pass it to the container", name)
+		}
+	}
+}
+
+func TestLoad_ExampleFile(t *testing.T) {
+	// Arrange
+	setEnv(t, nil)
+
+	// Act
+	cfg, err := Load("../../../config.example.yaml")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected config.example.yaml to be valid, got %v", err)
+	}
+	if cfg.Database.User != "app_user" {
+		t.Errorf("Expected values from the file, got user %q", cfg.Database.User)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvVar describes one environment variable understood by Load
+type EnvVar struct {
+	Name   string
+	Secret bool
+}
+
+// EnvVars lists every environment variable in declaration order
+func EnvVars() []EnvVar {
+	var vars []EnvVar
+	walk(reflect.ValueOf(&Config{}).Elem(), func(field reflect.StructField, _ reflect.Value) {
+		vars = append(vars, EnvVar{Name: field.Tag.Get("env"), Secret: field.Tag.Get("secret") == "true"})
+	})
+	return vars
+}
+
+// applyEnv overrides every field whose variable is set, even to an empty
+// string, so the environment can clear a value from the YAML file
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	walk(reflect.ValueOf(cfg).Elem(), func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("env")
+		raw, ok := lookup(name)
+		if !ok {
+			return
+		}
+		if err := setValue(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func redact(cfg *Config) {
+	walk(reflect.ValueOf(cfg).Elem(), func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") == "true" && value.String() != "" {
+			value.SetString(redacted)
+		}
+	})
+}
+
+// walk calls fn for every leaf field with an env tag, descending into the
+// section structs
+func walk(v reflect.Value, fn func(reflect.StructField, reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			walk(value, fn)
+			continue
+		}
+		if field.Tag.Get("env") != "" {
+			fn(field, value)
+		}
+	}
+}
+
+func setValue(value reflect.Value, raw string) error {
+	switch {
+	case value.Type() == durationType:
+		if raw == "" {
+			value.SetInt(0)
+			return nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(d))
+	case value.Kind() == reflect.Int:
+		if raw == "" {
+			value.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(int64(n))
+	case value.Kind() == reflect.Float64:
+		if raw == "" {
+			value.SetFloat(0)
+			return nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(f)
+	case value.Kind() == reflect.String:
+		value.SetString(raw)
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+	return nil
+}
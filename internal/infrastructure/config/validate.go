@@ -0,0 +1,278 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/outbox"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/printing"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/webhooks"
+)
+
+// cnpjPattern matches a CNPJ with or without its punctuation
+var cnpjPattern = regexp.MustCompile(`^(\d{14}|\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2})$`)
+
+// Validate reports every invalid setting at once, so a bad deploy shows the
+// whole list instead of failing one variable at a time
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(name, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{name}, args...)...))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("PORT", "must be a TCP port, got %q", c.Server.Port)
+	}
+	switch c.Server.GinMode {
+	case "debug", "release", "test":
+	default:
+		fail("GIN_MODE", "must be debug, release or test, got %q", c.Server.GinMode)
+	}
+	for _, setting := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"REQUEST_TIMEOUT", c.Server.RequestTimeout},
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SHUTDOWN_DRAIN_DELAY", c.Server.ShutdownDrainDelay},
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"DB_CONN_MAX_LIFETIME", c.Database.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", c.Database.ConnMaxIdleTime},
+	} {
+		if setting.value < 0 {
+			fail(setting.name, "must not be negative")
+		}
+	}
+	if c.Server.WriteTimeout > 0 && c.Server.RequestTimeout > 0 && c.Server.WriteTimeout <= c.Server.RequestTimeout {
+		fail("HTTP_WRITE_TIMEOUT", "must be longer than REQUEST_TIMEOUT (%s) so timeouts can be reported", c.Server.RequestTimeout)
+	}
+
+	for _, proxy := range c.Server.TrustedProxyList() {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				fail("HTTP_TRUSTED_PROXIES", "must list IPs or CIDRs, got %q", proxy)
+			}
+		}
+	}
+
+	switch c.Database.Driver {
+	case persistance.DriverMySQL, persistance.DriverPostgres, persistance.DriverMemory:
+	default:
+		fail("DB_DRIVER", "must be %s, %s or %s, got %q", persistance.DriverMySQL, persistance.DriverPostgres, persistance.DriverMemory, c.Database.Driver)
+	}
+	if persistance.UsesSQL(c.Database.Driver) {
+		for _, setting := range [][2]string{{"DB_HOST", c.Database.Host}, {"DB_USER", c.Database.User}, {"DB_NAME", c.Database.Name}} {
+			if setting[1] == "" {
+				fail(setting[0], "is required with DB_DRIVER=%s", c.Database.Driver)
+			}
+		}
+		if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+			fail("DB_MAX_OPEN_CONNS", "pool sizes must not be negative")
+		}
+		if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+			fail("DB_MAX_IDLE_CONNS", "must not exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxOpenConns)
+		}
+	}
+
+	switch c.Log.Format {
+	case logging.FormatText, logging.FormatJSON:
+	default:
+		fail("LOG_FORMAT", "must be %s or %s, got %q", logging.FormatText, logging.FormatJSON, c.Log.Format)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		fail("LOG_LEVEL", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+
+	switch c.Telemetry.TracesExporter {
+	case telemetry.ExporterNone, telemetry.ExporterStdout, telemetry.ExporterOTLP:
+	default:
+		fail("OTEL_TRACES_EXPORTER", "must be %s, %s or %s, got %q", telemetry.ExporterNone, telemetry.ExporterStdout, telemetry.ExporterOTLP, c.Telemetry.TracesExporter)
+	}
+
+	if u, err := url.Parse(c.Payment.ProviderURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("PAYMENT_PROVIDER_URL", "must be an http(s) URL, got %q", c.Payment.ProviderURL)
+	}
+	if raw := c.Payment.ProviderHealthURL; raw != "" {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("PAYMENT_PROVIDER_HEALTH_URL", "must be an http(s) URL, got %q", raw)
+		}
+	}
+	if c.Payment.TTL < 0 {
+		fail("PAYMENT_TTL", "must not be negative, got %s", c.Payment.TTL)
+	}
+	if c.Payment.ExpirationInterval <= 0 {
+		fail("PAYMENT_EXPIRATION_INTERVAL", "must be positive, got %s", c.Payment.ExpirationInterval)
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value int
+	}{
+		{"RATE_LIMIT_ORDERS_PER_MINUTE", c.RateLimit.OrdersPerMinute},
+		{"RATE_LIMIT_ORDERS_BURST", c.RateLimit.OrdersBurst},
+		{"RATE_LIMIT_CUSTOMERS_PER_MINUTE", c.RateLimit.CustomersPerMinute},
+		{"RATE_LIMIT_CUSTOMERS_BURST", c.RateLimit.CustomersBurst},
+	} {
+		if setting.value < 0 {
+			fail(setting.name, "must not be negative")
+		}
+	}
+
+	if c.Idempotency.KeyTTL <= 0 {
+		fail("IDEMPOTENCY_KEY_TTL", "must be positive, got %s", c.Idempotency.KeyTTL)
+	}
+
+	switch c.Outbox.Publisher {
+	case outbox.PublisherLog:
+	case outbox.PublisherHTTP:
+		if u, err := url.Parse(c.Outbox.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("OUTBOX_WEBHOOK_URL", "must be an http(s) URL with OUTBOX_PUBLISHER=%s, got %q", outbox.PublisherHTTP, c.Outbox.WebhookURL)
+		}
+	default:
+		fail("OUTBOX_PUBLISHER", "must be %s or %s, got %q", outbox.PublisherLog, outbox.PublisherHTTP, c.Outbox.Publisher)
+	}
+	if c.Outbox.PollInterval <= 0 {
+		fail("OUTBOX_POLL_INTERVAL", "must be positive, got %s", c.Outbox.PollInterval)
+	}
+	if c.Outbox.BatchSize <= 0 {
+		fail("OUTBOX_BATCH_SIZE", "must be positive, got %d", c.Outbox.BatchSize)
+	}
+	if c.Outbox.MaxBackoff < c.Outbox.PollInterval {
+		fail("OUTBOX_MAX_BACKOFF", "must not be shorter than OUTBOX_POLL_INTERVAL (%s)", c.Outbox.PollInterval)
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"WEBHOOK_DELIVERY_POLL_INTERVAL", c.Webhooks.PollInterval},
+		{"WEBHOOK_DELIVERY_TIMEOUT", c.Webhooks.Timeout},
+		{"WEBHOOK_DELIVERY_INITIAL_BACKOFF", c.Webhooks.InitialBackoff},
+	} {
+		if setting.value <= 0 {
+			fail(setting.name, "must be positive, got %s", setting.value)
+		}
+	}
+	if c.Webhooks.Timeout >= webhooks.ClaimLease {
+		fail("WEBHOOK_DELIVERY_TIMEOUT", "must be shorter than %s, how long a delivery being sent stays claimed, got %s", webhooks.ClaimLease, c.Webhooks.Timeout)
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		fail("WEBHOOK_DELIVERY_MAX_ATTEMPTS", "must be at least 1, got %d", c.Webhooks.MaxAttempts)
+	}
+	if c.Webhooks.MaxBackoff < c.Webhooks.InitialBackoff {
+		fail("WEBHOOK_DELIVERY_MAX_BACKOFF", "must not be shorter than WEBHOOK_DELIVERY_INITIAL_BACKOFF (%s)", c.Webhooks.InitialBackoff)
+	}
+
+	if c.Reconciliation.Interval <= 0 {
+		fail("RECONCILIATION_INTERVAL", "must be positive, got %s", c.Reconciliation.Interval)
+	}
+	if c.Reconciliation.MinAge <= 0 {
+		fail("RECONCILIATION_MIN_AGE", "must be positive, got %s", c.Reconciliation.MinAge)
+	} else if c.Payment.TTL > 0 && c.Reconciliation.MinAge >= c.Payment.TTL {
+		// Older payments would expire before the provider is ever asked
+		fail("RECONCILIATION_MIN_AGE", "must be shorter than PAYMENT_TTL (%s), got %s", c.Payment.TTL, c.Reconciliation.MinAge)
+	}
+
+	if _, err := time.LoadLocation(c.Store.Timezone); err != nil || c.Store.Timezone == "" {
+		fail("STORE_TIMEZONE", "must be an IANA time zone such as America/Sao_Paulo, got %q", c.Store.Timezone)
+	}
+	if strings.TrimSpace(c.Store.Name) == "" {
+		fail("STORE_NAME", "must not be empty")
+	}
+	if c.Store.CNPJ != "" && !cnpjPattern.MatchString(c.Store.CNPJ) {
+		fail("STORE_CNPJ", "must be 14 digits, optionally as 12.345.678/0001-90, got %q", c.Store.CNPJ)
+	}
+	// 32 columns fit 58 mm paper and 48 fit 80 mm paper
+	if c.Store.ReceiptWidth < 32 || c.Store.ReceiptWidth > 80 {
+		fail("RECEIPT_WIDTH", "must be between 32 and 80 columns, got %d", c.Store.ReceiptWidth)
+	}
+
+	if c.Loyalty.PointsPerReal < 0 {
+		fail("LOYALTY_POINTS_PER_REAL", "must not be negative, got %g", c.Loyalty.PointsPerReal)
+	}
+	// Redemptions are priced in whole cents
+	if c.Loyalty.PointValue < 0.01 {
+		fail("LOYALTY_POINT_VALUE", "must be at least 0.01, got %g", c.Loyalty.PointValue)
+	}
+	if c.Loyalty.PointsTTL < 0 {
+		fail("LOYALTY_POINTS_TTL", "must not be negative, got %s", c.Loyalty.PointsTTL)
+	}
+
+	for _, percent := range []struct {
+		name  string
+		value float64
+	}{
+		{"TAX_RATE_SNACK", c.Pricing.TaxRateSnack},
+		{"TAX_RATE_DRINK", c.Pricing.TaxRateDrink},
+		{"TAX_RATE_DESSERT", c.Pricing.TaxRateDessert},
+		{"TAX_RATE_SIDE", c.Pricing.TaxRateSide},
+		{"SERVICE_FEE_PERCENT", c.Pricing.ServiceFeePercent},
+	} {
+		if percent.value < 0 || percent.value >= 100 {
+			fail(percent.name, "must be a percentage from 0 to under 100, got %g", percent.value)
+		}
+	}
+	// Totals are rounded in whole cents
+	if cents := c.Pricing.RoundingIncrement * 100; cents < 1 || math.Abs(cents-math.Round(cents)) > 1e-9 {
+		fail("PRICE_ROUNDING_INCREMENT", "must be a whole number of cents, at least 0.01, got %g", c.Pricing.RoundingIncrement)
+	}
+	if !entities.IsValidRoundingMode(c.Pricing.RoundingMode) {
+		fail("PRICE_ROUNDING_MODE", "must be half_up, down or up, got %q", c.Pricing.RoundingMode)
+	}
+
+	switch c.KitchenPrinter.Driver {
+	case printing.PrinterNone:
+	case printing.PrinterTCP:
+		if _, port, err := net.SplitHostPort(c.KitchenPrinter.Address); err != nil || port == "" {
+			fail("KITCHEN_PRINTER_ADDRESS", "must be host:port with KITCHEN_PRINTER=%s, got %q", printing.PrinterTCP, c.KitchenPrinter.Address)
+		}
+	case printing.PrinterFile:
+		if c.KitchenPrinter.Path == "" {
+			fail("KITCHEN_PRINTER_PATH", "is required with KITCHEN_PRINTER=%s", printing.PrinterFile)
+		}
+	default:
+		fail("KITCHEN_PRINTER", "must be %s, %s or %s, got %q", printing.PrinterNone, printing.PrinterTCP, printing.PrinterFile, c.KitchenPrinter.Driver)
+	}
+	for _, setting := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"KITCHEN_PRINTER_TIMEOUT", c.KitchenPrinter.Timeout},
+		{"KITCHEN_PRINTER_POLL_INTERVAL", c.KitchenPrinter.PollInterval},
+		{"KITCHEN_PRINTER_INITIAL_BACKOFF", c.KitchenPrinter.InitialBackoff},
+	} {
+		if setting.value <= 0 {
+			fail(setting.name, "must be positive, got %s", setting.value)
+		}
+	}
+	if c.KitchenPrinter.MaxAttempts < 1 {
+		fail("KITCHEN_PRINTER_MAX_ATTEMPTS", "must be at least 1, got %d", c.KitchenPrinter.MaxAttempts)
+	}
+	if c.KitchenPrinter.MaxBackoff < c.KitchenPrinter.InitialBackoff {
+		fail("KITCHEN_PRINTER_MAX_BACKOFF", "must not be shorter than KITCHEN_PRINTER_INITIAL_BACKOFF (%s)", c.KitchenPrinter.InitialBackoff)
+	}
+	if c.KitchenPrinter.TicketWidth < 32 || c.KitchenPrinter.TicketWidth > 80 {
+		fail("KITCHEN_TICKET_WIDTH", "must be between 32 and 80 characters, got %d", c.KitchenPrinter.TicketWidth)
+	}
+	if _, err := parseStations(c.Kitchen.Stations); err != nil {
+		fail("KITCHEN_STATIONS", "%v", err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance"
+)
+
+// DatabaseCheck pings the connection pool
+func DatabaseCheck(db *sql.DB) Check {
+	return Check{
+		Name: "database",
+		Run: func(ctx context.Context) (string, error) {
+			return "", db.PingContext(ctx)
+		},
+	}
+}
+
+// SchemaCheck fails while the database is behind the schema version this
+// build expects, e.g. when a deploy outruns its migration
+func SchemaCheck(db *sql.DB) Check {
+	return Check{
+		Name: "schema",
+		Run: func(ctx context.Context) (string, error) {
+			version, err := persistance.SchemaVersion(ctx, db)
+			if err != nil {
+				return "", err
+			}
+			detail := fmt.Sprintf("version %d", version)
+			if version < persistance.ExpectedSchemaVersion {
+				return detail, fmt.Errorf("schema version %d is older than the required %d", version, persistance.ExpectedSchemaVersion)
+			}
+			return detail, nil
+		},
+	}
+}
+
+// HTTPCheck issues a GET to url and fails on transport errors or 5xx
+// responses. Any other status means the service is reachable.
+func HTTPCheck(name, url string, client *http.Client) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) (string, error) {
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return "", err
+			}
+
+			response, err := client.Do(request)
+			if err != nil {
+				return "", err
+			}
+			response.Body.Close()
+
+			detail := fmt.Sprintf("HTTP %d", response.StatusCode)
+			if response.StatusCode >= http.StatusInternalServerError {
+				return detail, fmt.Errorf("unexpected status %d", response.StatusCode)
+			}
+			return detail, nil
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds a check that does not set its own
+const DefaultTimeout = 2 * time.Second
+
+// Check status values used in reports
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check is a single readiness dependency. Run must honour ctx, which carries
+// the check's timeout.
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Run     func(ctx context.Context) (detail string, err error)
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report aggregates every check; Status is fail when any check failed
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Checker runs the readiness checks concurrently, each under its own timeout
+type Checker struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Drain makes every following Run fail, so the pod leaves the Service
+// endpoints while in-flight requests finish during shutdown
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Run executes every check and returns the results in registration order
+func (c *Checker) Run(ctx context.Context) Report {
+	if c.draining.Load() {
+		return Report{Status: StatusFail, Checks: []Result{{
+			Name:   "shutdown",
+			Status: StatusFail,
+			Error:  "server is shutting down",
+		}}}
+	}
+
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	result := Result{
+		Name:       check.Name,
+		Status:     StatusOK,
+		Detail:     detail,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LiveHandler answers 200 while the process can serve HTTP. It checks no
+// dependencies, so a database outage never gets pods restarted.
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusOK, Checks: []Result{}})
+	})
+}
+
+// ReadyHandler runs the checker and answers 503 when any check fails, which
+// takes the pod out of the Service endpoints until it recovers
+func ReadyHandler(checker *Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, checker.Run(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func okCheck(name string) Check {
+	return Check{Name: name, Run: func(context.Context) (string, error) { return "", nil }}
+}
+
+func TestChecker_ReportsEveryCheckInOrder(t *testing.T) {
+	// Arrange
+	checker := NewChecker(
+		okCheck("database"),
+		Check{Name: "schema", Run: func(context.Context) (string, error) {
+			return "version 0", errors.New("schema version 0 is older than the required 1")
+		}},
+		okCheck("payment_provider"),
+	)
+
+	// Act
+	report := checker.Run(context.Background())
+
+	// Assert
+	if report.Status != StatusFail {
+		t.Errorf("Expected status fail, got %s", report.Status)
+	}
+
+	names := []string{"database", "schema", "payment_provider"}
+	for i, result := range report.Checks {
+		if result.Name != names[i] {
+			t.Errorf("Check %d: expected %s, got %s", i, names[i], result.Name)
+		}
+	}
+
+	if schema := report.Checks[1]; schema.Status != StatusFail || schema.Detail != "version 0" || schema.Error == "" {
+		t.Errorf("Unexpected schema result %+v", schema)
+	}
+}
+
+func TestChecker_AppliesTimeout(t *testing.T) {
+	// Arrange
+	checker := NewChecker(Check{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	})
+
+	// Act
+	report := checker.Run(context.Background())
+
+	// Assert
+	if result := report.Checks[0]; result.Status != StatusFail || result.Error != "timed out after 10ms" {
+		t.Errorf("Expected a timeout failure, got %+v", result)
+	}
+}
+
+func TestReadyHandler_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker *Checker
+		want    int
+	}{
+		{"healthy", NewChecker(okCheck("database")), http.StatusOK},
+		{"unhealthy", NewChecker(Check{Name: "database", Run: func(context.Context) (string, error) {
+			return "", errors.New("connection refused")
+		}}), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			recorder := httptest.NewRecorder()
+
+			// Act
+			ReadyHandler(tt.checker).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			// Assert
+			if recorder.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, recorder.Code)
+			}
+
+			var report Report
+			if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil || len(report.Checks) != 1 {
+				t.Errorf("Expected a report with one check, got %s", recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	// Arrange
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	check := HTTPCheck("payment_provider", server.URL, server.Client())
+
+	// Act
+	detail, err := check.Run(context.Background())
+
+	// Assert
+	if err != nil || detail != "HTTP 200" {
+		t.Errorf("Expected reachable provider, got %q, %v", detail, err)
+	}
+
+	// Act
+	status = http.StatusBadGateway
+	_, err = check.Run(context.Background())
+
+	// Assert
+	if err == nil {
+		t.Error("Expected 5xx to fail the check")
+	}
+}
+
+func TestChecker_DrainFailsReadiness(t *testing.T) {
+	// Arrange
+	checker := NewChecker(okCheck("database"))
+
+	// Act
+	checker.Drain()
+	report := checker.Run(context.Background())
+
+	// Assert
+	if report.Status != StatusFail || len(report.Checks) != 1 || report.Checks[0].Name != "shutdown" {
+		t.Errorf("Expected a shutdown failure, got %+v", report)
+	}
+}
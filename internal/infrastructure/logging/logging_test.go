@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestNew_JSONIncludesCorrelationIDs(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithTraceID(WithRequestID(context.Background(), "req-1"), "trace-1")
+
+	// Act
+	logger.With("component", "test").InfoContext(ctx, "hello", "order_id", 7)
+
+	// Assert
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON line, got %q", buf.String())
+	}
+
+	for key, want := range map[string]any{"msg": "hello", "request_id": "req-1", "trace_id": "trace-1", "component": "test", "order_id": float64(7)} {
+		if line[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, line[key])
+		}
+	}
+}
+
+func TestNew_RespectsLevel(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger, _ := New(&buf, FormatText, "warn")
+
+	// Act
+	logger.Info("hidden")
+
+	// Assert
+	if buf.Len() != 0 {
+		t.Errorf("Expected info to be filtered, got %q", buf.String())
+	}
+}
+
+func TestNew_RejectsInvalidSettings(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", ""); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+	if _, err := New(&bytes.Buffer{}, "", "loud"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
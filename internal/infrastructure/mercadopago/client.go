@@ -0,0 +1,166 @@
+// Package mercadopago reads payments from the MercadoPago API. Payments are
+// matched to orders by external_reference, which holds the order ID.
+package mercadopago
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// DefaultBaseURL is the production MercadoPago API
+const DefaultBaseURL = "https://api.mercadopago.com"
+
+// pageSize is the largest page the search endpoint returns
+const pageSize = 100
+
+// statuses maps MercadoPago statuses to ours. Unlisted statuses (refunded,
+// charged_back) are kept as they are, so they show up as mismatches.
+var statuses = map[string]entities.PaymentStatus{
+	"approved":     entities.PaymentStatusApproved,
+	"rejected":     entities.PaymentStatusRejected,
+	"cancelled":    entities.PaymentStatusCanceled,
+	"pending":      entities.PaymentStatusPending,
+	"in_process":   entities.PaymentStatusPending,
+	"in_mediation": entities.PaymentStatusPending,
+	"authorized":   entities.PaymentStatusPending,
+}
+
+type client struct {
+	baseURL     string
+	accessToken string
+	http        *http.Client
+}
+
+// NewClient returns a PaymentProvider authenticated with accessToken
+func NewClient(baseURL, accessToken string, httpClient *http.Client) output.PaymentProvider {
+	return &client{
+		baseURL:     baseURL,
+		accessToken: accessToken,
+		http:        httpClient,
+	}
+}
+
+type searchResponse struct {
+	Paging struct {
+		Total  int `json:"total"`
+		Offset int `json:"offset"`
+	} `json:"paging"`
+	Results []payment `json:"results"`
+}
+
+type payment struct {
+	ID                int64     `json:"id"`
+	Status            string    `json:"status"`
+	TransactionAmount float32   `json:"transaction_amount"`
+	ExternalReference string    `json:"external_reference"`
+	PaymentMethodID   string    `json:"payment_method_id"`
+	DateCreated       time.Time `json:"date_created"`
+}
+
+func (c *client) ListPaymentsByOrderID(ctx context.Context, orderID uint64) ([]*entities.ProviderPayment, error) {
+	query := url.Values{
+		"external_reference": {strconv.FormatUint(orderID, 10)},
+		"sort":               {"date_created"},
+		"criteria":           {"asc"},
+	}
+
+	return c.searchAll(ctx, query, func(record *entities.ProviderPayment) bool {
+		return record.OrderID == orderID
+	})
+}
+
+// ListPayments pages through the search; its end_date is inclusive, so
+// payments created exactly at to are dropped here
+func (c *client) ListPayments(ctx context.Context, from, to time.Time) ([]*entities.ProviderPayment, error) {
+	query := url.Values{
+		"range":      {"date_created"},
+		"begin_date": {from.UTC().Format(time.RFC3339)},
+		"end_date":   {to.UTC().Format(time.RFC3339)},
+		"sort":       {"date_created"},
+		"criteria":   {"asc"},
+	}
+
+	return c.searchAll(ctx, query, func(record *entities.ProviderPayment) bool {
+		return record.CreatedAt.Before(to)
+	})
+}
+
+// searchAll pages through the search and returns the results keep accepts.
+// Payments without an order reference were not made by us and are skipped.
+func (c *client) searchAll(ctx context.Context, query url.Values, keep func(record *entities.ProviderPayment) bool) ([]*entities.ProviderPayment, error) {
+	var records []*entities.ProviderPayment
+	for offset := 0; ; offset += pageSize {
+		query.Set("limit", strconv.Itoa(pageSize))
+		query.Set("offset", strconv.Itoa(offset))
+
+		response, err := c.search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range response.Results {
+			if record, ok := result.toEntity(); ok && keep(record) {
+				records = append(records, record)
+			}
+		}
+
+		if len(response.Results) < pageSize || offset+pageSize >= response.Paging.Total {
+			return records, nil
+		}
+	}
+}
+
+func (c *client) search(ctx context.Context, query url.Values) (*searchResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/payments/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mercadopago: search payments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mercadopago: search payments: unexpected status %d", resp.StatusCode)
+	}
+
+	var response searchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("mercadopago: decode search response: %w", err)
+	}
+	return &response, nil
+}
+
+// toEntity converts a search result; ok is false when external_reference is
+// not an order ID
+func (p payment) toEntity() (*entities.ProviderPayment, bool) {
+	orderID, err := strconv.ParseUint(p.ExternalReference, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	status, ok := statuses[p.Status]
+	if !ok {
+		status = entities.PaymentStatus(p.Status)
+	}
+
+	return &entities.ProviderPayment{
+		TransactionID: strconv.FormatInt(p.ID, 10),
+		OrderID:       orderID,
+		Status:        status,
+		Amount:        p.TransactionAmount,
+		PaymentMethod: p.PaymentMethodID,
+		CreatedAt:     p.DateCreated,
+	}, true
+}
@@ -0,0 +1,148 @@
+package mercadopago
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+var ctx = context.Background()
+
+func TestClient_ListPaymentsByOrderID(t *testing.T) {
+	// Arrange
+	var query url.Values
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"paging":{"total":3,"offset":0},"results":[
+			{"id":987,"status":"approved","transaction_amount":20.5,"external_reference":"42","payment_method_id":"pix","date_created":"2026-10-18T12:00:00.000-04:00"},
+			{"id":988,"status":"approved","transaction_amount":9.5,"external_reference":"42","payment_method_id":"pix","date_created":"2026-10-18T12:01:00.000-04:00"},
+			{"id":989,"status":"approved","transaction_amount":5,"external_reference":"420","date_created":"2026-10-18T12:02:00.000-04:00"}
+		]}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "APP_USR-123", server.Client())
+
+	// Act
+	records, err := client.ListPaymentsByOrderID(ctx, 42)
+
+	// Assert
+	if err != nil || len(records) != 2 {
+		t.Fatalf("Expected both parts of order 42, got %v, %v", records, err)
+	}
+	record := records[0]
+	if record.TransactionID != "987" || record.OrderID != 42 || record.Status != entities.PaymentStatusApproved ||
+		record.Amount != 20.5 || record.PaymentMethod != "pix" {
+		t.Errorf("Unexpected payment %+v", record)
+	}
+	if !record.CreatedAt.Equal(time.Date(2026, 10, 18, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected created at 16:00 UTC, got %s", record.CreatedAt)
+	}
+	if records[1].TransactionID != "988" {
+		t.Errorf("Expected the second part next, got %+v", records[1])
+	}
+	if authorization != "Bearer APP_USR-123" {
+		t.Errorf("Expected bearer token, got %q", authorization)
+	}
+	if query.Get("external_reference") != "42" || query.Get("criteria") != "asc" || query.Get("offset") != "0" {
+		t.Errorf("Expected a paged search by external_reference, oldest first, got %v", query)
+	}
+}
+
+func TestClient_ListPaymentsByOrderID_NotFound(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"paging":{"total":0,"offset":0},"results":[]}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+
+	// Act
+	records, err := client.ListPaymentsByOrderID(ctx, 42)
+
+	// Assert
+	if len(records) != 0 || err != nil {
+		t.Errorf("Expected no payments, got %v, %v", records, err)
+	}
+}
+
+func TestClient_ListPayments_PagesAndMapsStatuses(t *testing.T) {
+	// Arrange
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		offsets = append(offsets, query.Get("offset"))
+		if query.Get("begin_date") != "2026-10-18T00:00:00Z" || query.Get("end_date") != "2026-10-19T00:00:00Z" {
+			t.Errorf("Unexpected date range %v", query)
+		}
+
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		fmt.Fprintf(w, `{"paging":{"total":%d,"offset":%d},"results":[`, pageSize+3, offset)
+		count := pageSize
+		if offset > 0 {
+			count = 3
+		}
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			reference, status, created := strconv.Itoa(offset+i+1), "in_process", "2026-10-18T10:00:00Z"
+			switch {
+			case offset > 0 && i == 0:
+				status = "cancelled"
+			case offset > 0 && i == 1:
+				reference = "not-an-order"
+			case offset > 0 && i == 2:
+				status, created = "refunded", "2026-10-19T00:00:00Z"
+			}
+			fmt.Fprintf(w, `{"id":%d,"status":%q,"transaction_amount":10,"external_reference":%q,"date_created":%q}`, offset+i+1, status, reference, created)
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token", server.Client())
+
+	// Act
+	records, err := client.ListPayments(ctx, from, to)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(offsets) != 2 || offsets[0] != "0" || offsets[1] != strconv.Itoa(pageSize) {
+		t.Errorf("Expected two pages, got offsets %v", offsets)
+	}
+	if len(records) != pageSize+1 {
+		t.Fatalf("Expected %d payments without the foreign and late ones, got %d", pageSize+1, len(records))
+	}
+	if records[0].Status != entities.PaymentStatusPending || records[pageSize].Status != entities.PaymentStatusCanceled {
+		t.Errorf("Expected in_process as pending and cancelled as canceled, got %s and %s", records[0].Status, records[pageSize].Status)
+	}
+}
+
+func TestClient_FailsOnErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "expired", server.Client())
+
+	// Act
+	_, err := client.ListPaymentsByOrderID(ctx, 42)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error for a 401 response")
+	}
+}
@@ -0,0 +1,135 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// Publishers selectable with OUTBOX_PUBLISHER
+const (
+	PublisherLog  = "log"
+	PublisherHTTP = "http"
+)
+
+// Headers sent with every HTTP delivery
+const (
+	EventIDHeader   = "X-Event-Id"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// Envelope is the JSON document publishers send for an event
+type Envelope struct {
+	ID            uint64          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint64          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope wraps an outbox event for delivery
+func NewEnvelope(event *entities.OutboxEvent) Envelope {
+	return Envelope{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt.UTC(),
+		Payload:       json.RawMessage(event.Payload),
+	}
+}
+
+type logPublisher struct {
+	logger *slog.Logger
+}
+
+// NewLogPublisher writes each event to the log. It never fails, so it suits
+// development and deployments without consumers.
+func NewLogPublisher(logger *slog.Logger) output.EventPublisher {
+	return &logPublisher{
+		logger: logger,
+	}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	p.logger.InfoContext(ctx, "Domain event",
+		"event_id", event.ID,
+		"event_type", event.EventType,
+		"aggregate_type", event.AggregateType,
+		"aggregate_id", event.AggregateID,
+		"payload", json.RawMessage(event.Payload),
+	)
+	return nil
+}
+
+type httpPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher POSTs each event as an Envelope to url. Any status other
+// than 2xx is a failure and the event is retried.
+func NewHTTPPublisher(url string, client *http.Client) output.EventPublisher {
+	return &httpPublisher{
+		url:    url,
+		client: client,
+	}
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	body, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, strconv.FormatUint(event.ID, 10))
+	request.Header.Set(EventTypeHeader, event.EventType)
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("event endpoint answered %s", response.Status)
+	}
+	return nil
+}
+
+type multiPublisher struct {
+	publishers []output.EventPublisher
+}
+
+// NewMultiPublisher hands each event to every publisher. If any fails the
+// event is retried on all of them, so each must tolerate duplicates.
+func NewMultiPublisher(publishers ...output.EventPublisher) output.EventPublisher {
+	return &multiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
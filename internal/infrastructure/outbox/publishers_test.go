@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+func TestHTTPPublisher_SendsEnvelope(t *testing.T) {
+	// Arrange
+	var received Envelope
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event, _ := entities.NewOutboxEvent(entities.EventPaymentApproved, entities.AggregatePayment, 7, map[string]uint64{"payment_id": 7})
+	event.ID = 42
+	publisher := NewHTTPPublisher(server.URL, server.Client())
+
+	// Act
+	err := publisher.Publish(ctx, event)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received.ID != 42 || received.Type != entities.EventPaymentApproved || received.AggregateID != 7 {
+		t.Errorf("Unexpected envelope %+v", received)
+	}
+	if string(received.Payload) != `{"payment_id":7}` {
+		t.Errorf("Expected the payload forwarded, got %s", received.Payload)
+	}
+	if headers.Get(EventIDHeader) != "42" || headers.Get(EventTypeHeader) != entities.EventPaymentApproved {
+		t.Errorf("Expected event headers, got %v", headers)
+	}
+}
+
+func TestHTTPPublisher_FailsOnErrorStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	event, _ := entities.NewOutboxEvent(entities.EventOrderCreated, entities.AggregateOrder, 1, nil)
+	publisher := NewHTTPPublisher(server.URL, server.Client())
+
+	// Act
+	err := publisher.Publish(ctx, event)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error for a 503 answer")
+	}
+}
+
+func TestMultiPublisher_PublishesToEveryPublisher(t *testing.T) {
+	// Arrange
+	failing := &recordingPublisher{err: errors.New("broker unavailable")}
+	working := &recordingPublisher{}
+	event, _ := entities.NewOutboxEvent(entities.EventOrderCreated, entities.AggregateOrder, 1, nil)
+	event.ID = 9
+
+	// Act
+	err := NewMultiPublisher(failing, working).Publish(ctx, event)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected the failure reported so the event is retried")
+	}
+	if len(working.published) != 1 || working.published[0] != 9 {
+		t.Errorf("Expected the other publisher to receive the event, got %v", working.published)
+	}
+}
@@ -0,0 +1,138 @@
+// Package outbox publishes the events the use cases append to the
+// transactional outbox. The relay polls for pending events and hands them to
+// an output.EventPublisher, retrying failures with exponential backoff until
+// they are accepted, so every event is delivered at least once.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// Defaults used when a RelayConfig field is zero
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 100
+	DefaultMaxBackoff   = 5 * time.Minute
+)
+
+// ClaimLease is how long a claimed event is hidden from the other replicas
+// polling the outbox, far longer than a publish can take
+const ClaimLease = time.Minute
+
+// RelayConfig tunes the relay. The first retry waits PollInterval and each
+// following one twice as long, up to MaxBackoff.
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxBackoff   time.Duration
+}
+
+type Relay struct {
+	gateway   output.OutboxGateway
+	publisher output.EventPublisher
+	logger    *slog.Logger
+	config    RelayConfig
+	now       func() time.Time
+}
+
+func NewRelay(gateway output.OutboxGateway, publisher output.EventPublisher, logger *slog.Logger, config RelayConfig) *Relay {
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+
+	return &Relay{
+		gateway:   gateway,
+		publisher: publisher,
+		logger:    logger,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Run relays pending events every PollInterval until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for {
+				relayed, err := r.RelayPending(ctx)
+				if err != nil {
+					r.logger.WarnContext(ctx, "Failed to read the outbox", "error", err)
+				}
+				// A full batch means more events may be waiting
+				if err != nil || relayed < r.config.BatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayPending publishes one batch of due events in order and saves the
+// outcome of each. It returns how many events were due, claimed by this
+// replica or not.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	now := r.now()
+	events, err := r.gateway.ListPending(ctx, now, r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		// Every replica relays, so each event is claimed before it is
+		// published
+		claimed, err := r.gateway.Claim(ctx, event, now, r.now().Add(ClaimLease))
+		if err != nil {
+			return 0, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			retryAt := r.now().Add(r.backoff(event.Attempts))
+			event.MarkFailed(err, retryAt)
+			r.logger.WarnContext(ctx, "Failed to publish outbox event",
+				"event_id", event.ID,
+				"event_type", event.EventType,
+				"attempts", event.Attempts,
+				"retry_at", retryAt,
+				"error", err,
+			)
+		} else {
+			event.MarkPublished(r.now())
+		}
+
+		// If this fails the event stays pending and is published again,
+		// which at-least-once delivery allows
+		if err := r.gateway.Update(context.WithoutCancel(ctx), event); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to save outbox delivery state", "event_id", event.ID, "error", err)
+		}
+	}
+
+	return len(events), nil
+}
+
+// backoff returns how long to wait after the given number of failed attempts
+func (r *Relay) backoff(failedAttempts int) time.Duration {
+	delay := r.config.PollInterval
+	for i := 0; i < failedAttempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxBackoff)
+}
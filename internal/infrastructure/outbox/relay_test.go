@@ -0,0 +1,161 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+var ctx = context.Background()
+
+// recordingPublisher remembers the published event IDs and fails while err is set
+type recordingPublisher struct {
+	published []uint64
+	err       error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func appendEvent(t *testing.T, gateway output.OutboxGateway, aggregateID uint64) *entities.OutboxEvent {
+	t.Helper()
+	event, err := entities.NewOutboxEvent(entities.EventOrderCreated, entities.AggregateOrder, aggregateID, map[string]uint64{"order_id": aggregateID})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	if err := gateway.Append(ctx, event); err != nil {
+		t.Fatalf("Failed to append event: %v", err)
+	}
+	return event
+}
+
+func TestRelay_PublishesPendingEventsInOrder(t *testing.T) {
+	// Arrange
+	gateway := memory.NewOutboxGateway(memory.NewStore())
+	first := appendEvent(t, gateway, 1)
+	second := appendEvent(t, gateway, 2)
+	publisher := &recordingPublisher{}
+	relay := NewRelay(gateway, publisher, logging.Discard(), RelayConfig{})
+
+	// Act
+	relayed, err := relay.RelayPending(ctx)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if relayed != 2 || len(publisher.published) != 2 || publisher.published[0] != first.ID || publisher.published[1] != second.ID {
+		t.Errorf("Expected events %d and %d published in order, got %v", first.ID, second.ID, publisher.published)
+	}
+
+	pending, _ := gateway.ListPending(ctx, time.Now().Add(time.Hour), 10)
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending events, got %d", len(pending))
+	}
+}
+
+func TestRelay_RetriesFailedEventsWithBackoff(t *testing.T) {
+	// Arrange
+	gateway := memory.NewOutboxGateway(memory.NewStore())
+	event := appendEvent(t, gateway, 1)
+	publisher := &recordingPublisher{err: errors.New("broker unavailable")}
+	relay := NewRelay(gateway, publisher, logging.Discard(), RelayConfig{PollInterval: time.Second, MaxBackoff: time.Minute})
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+
+	// Act
+	relay.RelayPending(ctx)
+	beforeRetry, _ := relay.RelayPending(ctx)
+	now = now.Add(2 * time.Second)
+	publisher.err = nil
+	afterRetry, _ := relay.RelayPending(ctx)
+
+	// Assert
+	if beforeRetry != 0 {
+		t.Errorf("Expected the failed event to wait for its retry, relayed %d", beforeRetry)
+	}
+	if afterRetry != 1 || len(publisher.published) != 1 || publisher.published[0] != event.ID {
+		t.Errorf("Expected the event published on retry, got %v", publisher.published)
+	}
+}
+
+// rendezvousPending holds each of the first two pending lists until the
+// other one was read or a short wait passes, so two replicas both see the
+// same events pending
+type rendezvousPending struct {
+	output.OutboxGateway
+	lists atomic.Int32
+	both  chan struct{}
+}
+
+func (g *rendezvousPending) ListPending(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	pending, err := g.OutboxGateway.ListPending(ctx, now, limit)
+	if n := g.lists.Add(1); n == 2 {
+		close(g.both)
+	} else if n == 1 {
+		select {
+		case <-g.both:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return pending, err
+}
+
+func TestRelay_EachEventIsPublishedByOneReplica(t *testing.T) {
+	// Arrange
+	gateway := memory.NewOutboxGateway(memory.NewStore())
+	appendEvent(t, gateway, 1)
+	pending := &rendezvousPending{OutboxGateway: gateway, both: make(chan struct{})}
+	publishers := []*recordingPublisher{{}, {}}
+
+	// Act
+	var wg sync.WaitGroup
+	for _, publisher := range publishers {
+		replica := NewRelay(pending, publisher, logging.Discard(), RelayConfig{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replica.RelayPending(ctx)
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if published := len(publishers[0].published) + len(publishers[1].published); published != 1 {
+		t.Errorf("Expected the event published once across replicas, got %d", published)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(nil, nil, logging.Discard(), RelayConfig{PollInterval: time.Second, MaxBackoff: time.Minute})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{6, time.Minute},
+		{100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
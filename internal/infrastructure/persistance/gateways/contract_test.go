@@ -0,0 +1,153 @@
+package gateways
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output/gatewaytest"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/sirupsen/logrus"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// inlineReferences matches column-level REFERENCES clauses. MySQL parses and
+// ignores them, the embedded server rejects them, so they are dropped.
+var inlineReferences = regexp.MustCompile(`\s+REFERENCES\s+\w+\(\w+\)(\s+ON DELETE CASCADE)?`)
+
+// upgradeStatement matches the four statements of each guarded ALTER in
+// init.sql, once leadingComments are trimmed
+var (
+	leadingComments  = regexp.MustCompile(`^(\s*--[^\n]*\n)+`)
+	upgradeStatement = regexp.MustCompile(`^(SET @ddl|PREPARE ddl|EXECUTE ddl|DEALLOCATE PREPARE ddl)\b`)
+)
+
+// TestGatewayContract runs the shared gateway suite against an in-process
+// MySQL-compatible server, so no container is needed. Every test case gets
+// its own database created from init.sql.
+func TestGatewayContract(t *testing.T) {
+	addr := startEmbeddedMySQL(t)
+	schema := loadSchema(t)
+
+	var databases atomic.Int64
+	gatewaytest.Run(t, func(t *testing.T) gatewaytest.Gateways {
+		name := fmt.Sprintf("contract_%d", databases.Add(1))
+		db := openDatabase(t, addr, name, schema)
+
+		return gatewaytest.Gateways{
+			Customer:  NewCustomerGateway(db),
+			Product:   NewProductGateway(db, logging.Discard()),
+			Order:     NewOrderGateway(db, logging.Discard()),
+			OrderItem: NewOrderItemGateway(db, logging.Discard()),
+			Payment:   NewPaymentGateway(db),
+
+			Idempotency: NewIdempotencyGateway(db),
+			Outbox:      NewOutboxGateway(db),
+
+			WebhookSubscription: NewWebhookSubscriptionGateway(db),
+			WebhookDelivery:     NewWebhookDeliveryGateway(db),
+
+			Reconciliation: NewReconciliationGateway(db),
+
+			Promotion:     NewPromotionGateway(db),
+			OrderDiscount: NewOrderDiscountGateway(db),
+			Loyalty:       NewLoyaltyGateway(db),
+			OrderTax:      NewOrderTaxGateway(db),
+			Receipt:       NewReceiptGateway(db),
+			KitchenTicket: NewKitchenTicketGateway(db),
+			StationTicket: NewStationTicketGateway(db),
+		}
+	})
+}
+
+func startEmbeddedMySQL(t *testing.T) string {
+	t.Helper()
+	logrus.SetOutput(io.Discard)
+
+	provider := memory.NewDBProvider()
+	engine := sqle.NewDefault(provider)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := server.NewServer(server.Config{Protocol: "tcp", Listener: listener}, engine, memory.NewSessionBuilder(provider), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	t.Cleanup(func() { srv.Close() })
+
+	return listener.Addr().String()
+}
+
+// loadSchema returns the statements that create a new database from
+// init.sql. The guarded ALTERs that upgrade older databases are no-ops there
+// and slow on the embedded server, so they are left to TestSchemaUpgrade.
+func loadSchema(t *testing.T) []string {
+	t.Helper()
+	var statements []string
+	for _, stmt := range loadScript(t) {
+		if !upgradeStatement.MatchString(stmt) {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
+
+// loadScript returns every statement of init.sql
+func loadScript(t *testing.T) []string {
+	t.Helper()
+	raw, err := os.ReadFile("../../../../init.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statements []string
+	for _, stmt := range strings.Split(string(raw), ";") {
+		stmt = strings.TrimSpace(leadingComments.ReplaceAllString(stmt, ""))
+		if stmt == "" || strings.HasPrefix(strings.ToUpper(stmt), "USE ") {
+			continue
+		}
+		statements = append(statements, inlineReferences.ReplaceAllString(stmt, ""))
+	}
+	return statements
+}
+
+func openDatabase(t *testing.T, addr, name string, schema []string) *sql.DB {
+	t.Helper()
+
+	admin, err := sql.Open("mysql", "root:@tcp("+addr+")/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	if _, err := admin.Exec("CREATE DATABASE " + name); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same DSN options as cmd/server
+	db, err := sql.Open("mysql", "root:@tcp("+addr+")/"+name+"?charset=utf8mb4&parseTime=True&loc=Local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to apply schema: %v", err)
+		}
+	}
+	return db
+}
@@ -1,11 +1,12 @@
 package gateways
 
 import (
+	"context"
 	"database/sql"
-	"time"
 
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
 )
 
 type customerGateway struct {
@@ -18,13 +19,13 @@ func NewCustomerGateway(db *sql.DB) output.CustomerGateway {
 	}
 }
 
-func (g *customerGateway) Create(customer *entities.Customer) error {
+func (g *customerGateway) Create(ctx context.Context, customer *entities.Customer) error {
 	query := `
 		INSERT INTO customers (first_name, last_name, cpf, email, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := g.db.Exec(query,
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		customer.FirstName,
 		customer.LastName,
 		customer.CPF,
@@ -46,17 +47,16 @@ func (g *customerGateway) Create(customer *entities.Customer) error {
 	return nil
 }
 
-func (g *customerGateway) GetByCPF(cpf string) (*entities.Customer, error) {
+func (g *customerGateway) GetByCPF(ctx context.Context, cpf string) (*entities.Customer, error) {
 	query := `
 		SELECT id, first_name, last_name, cpf, email, created_at, updated_at
 		FROM customers
 		WHERE cpf = ?
 	`
 
-	row := g.db.QueryRow(query, cpf)
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, cpf)
 
 	var customer entities.Customer
-	var createdAt, updatedAt string
 
 	err := row.Scan(
 		&customer.ID,
@@ -64,8 +64,8 @@ func (g *customerGateway) GetByCPF(cpf string) (*entities.Customer, error) {
 		&customer.LastName,
 		&customer.CPF,
 		&customer.Email,
-		&createdAt,
-		&updatedAt,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
 	)
 
 	if err != nil {
@@ -75,23 +75,27 @@ func (g *customerGateway) GetByCPF(cpf string) (*entities.Customer, error) {
 		return nil, err
 	}
 
-	customer.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	customer.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 	return &customer, nil
 }
 
-func (g *customerGateway) GetByID(id uint64) (*entities.Customer, error) {
+func (g *customerGateway) GetByID(ctx context.Context, id uint64) (*entities.Customer, error) {
+	return g.getByID(ctx, id, "")
+}
+
+func (g *customerGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Customer, error) {
+	return g.getByID(ctx, id, "FOR UPDATE")
+}
+
+func (g *customerGateway) getByID(ctx context.Context, id uint64, lock string) (*entities.Customer, error) {
 	query := `
 		SELECT id, first_name, last_name, cpf, email, created_at, updated_at
 		FROM customers
 		WHERE id = ?
-	`
+	` + lock
 
-	row := g.db.QueryRow(query, id)
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id)
 
 	var customer entities.Customer
-	var createdAt, updatedAt string
 
 	err := row.Scan(
 		&customer.ID,
@@ -99,8 +103,8 @@ func (g *customerGateway) GetByID(id uint64) (*entities.Customer, error) {
 		&customer.LastName,
 		&customer.CPF,
 		&customer.Email,
-		&createdAt,
-		&updatedAt,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
 	)
 
 	if err != nil {
@@ -110,20 +114,17 @@ func (g *customerGateway) GetByID(id uint64) (*entities.Customer, error) {
 		return nil, err
 	}
 
-	customer.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	customer.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 	return &customer, nil
 }
 
-func (g *customerGateway) Update(customer *entities.Customer) error {
+func (g *customerGateway) Update(ctx context.Context, customer *entities.Customer) error {
 	query := `
 		UPDATE customers
 		SET first_name = ?, last_name = ?, email = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	_, err := g.db.Exec(query,
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		customer.FirstName,
 		customer.LastName,
 		customer.Email,
@@ -134,8 +135,8 @@ func (g *customerGateway) Update(customer *entities.Customer) error {
 	return err
 }
 
-func (g *customerGateway) Delete(id uint64) error {
+func (g *customerGateway) Delete(ctx context.Context, id uint64) error {
 	query := `DELETE FROM customers WHERE id = ?`
-	_, err := g.db.Exec(query, id)
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
 	return err
 }
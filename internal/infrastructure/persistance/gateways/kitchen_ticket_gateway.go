@@ -0,0 +1,140 @@
+package gateways
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+const kitchenTicketColumns = `id, order_id, copy_number, status, attempts, last_error, next_attempt_at, printed_at`
+
+type kitchenTicketGateway struct {
+	db *sql.DB
+}
+
+func NewKitchenTicketGateway(db *sql.DB) output.KitchenTicketGateway {
+	return &kitchenTicketGateway{
+		db: db,
+	}
+}
+
+func (g *kitchenTicketGateway) Create(ctx context.Context, ticket *entities.KitchenTicket) (bool, error) {
+	// INSERT IGNORE skips the row when the copy was already recorded
+	query := `
+		INSERT IGNORE INTO kitchen_tickets (order_id, copy_number, status, attempts, last_error, next_attempt_at, printed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		ticket.OrderID,
+		ticket.Copy,
+		ticket.Status,
+		ticket.Attempts,
+		ticket.LastError,
+		ticket.NextAttemptAt,
+		ticket.PrintedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	ticket.ID = uint64(id)
+	return true, nil
+}
+
+func (g *kitchenTicketGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.KitchenTicket, error) {
+	query := `SELECT ` + kitchenTicketColumns + ` FROM kitchen_tickets WHERE order_id = ? ORDER BY copy_number`
+
+	return g.query(ctx, query, orderID)
+}
+
+func (g *kitchenTicketGateway) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KitchenTicket, error) {
+	query := `SELECT ` + kitchenTicketColumns + ` FROM kitchen_tickets WHERE status = ? AND next_attempt_at <= ? ORDER BY id LIMIT ?`
+
+	return g.query(ctx, query, entities.KitchenTicketPending, now, limit)
+}
+
+func (g *kitchenTicketGateway) Claim(ctx context.Context, ticket *entities.KitchenTicket, now, until time.Time) (bool, error) {
+	// The conditions are checked again on the locked row, so only one of
+	// two concurrent claims updates it
+	query := `UPDATE kitchen_tickets SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, until, ticket.ID, entities.KitchenTicketPending, now)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	ticket.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *kitchenTicketGateway) Update(ctx context.Context, ticket *entities.KitchenTicket) error {
+	query := `
+		UPDATE kitchen_tickets
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, printed_at = ?
+		WHERE id = ?
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		ticket.Status,
+		ticket.Attempts,
+		ticket.LastError,
+		ticket.NextAttemptAt,
+		ticket.PrintedAt,
+		ticket.ID,
+	)
+
+	return err
+}
+
+func (g *kitchenTicketGateway) query(ctx context.Context, query string, args ...any) ([]*entities.KitchenTicket, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []*entities.KitchenTicket
+	for rows.Next() {
+		var ticket entities.KitchenTicket
+		var printedAt sql.NullTime
+		err := rows.Scan(
+			&ticket.ID,
+			&ticket.OrderID,
+			&ticket.Copy,
+			&ticket.Status,
+			&ticket.Attempts,
+			&ticket.LastError,
+			&ticket.NextAttemptAt,
+			&printedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if printedAt.Valid {
+			ticket.PrintedAt = &printedAt.Time
+		}
+		tickets = append(tickets, &ticket)
+	}
+
+	return tickets, rows.Err()
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output/gatewaytest"
+)
+
+func TestGatewayContract(t *testing.T) {
+	gatewaytest.Run(t, func(t *testing.T) gatewaytest.Gateways {
+		store := NewStore()
+		return gatewaytest.Gateways{
+			Customer:  NewCustomerGateway(store),
+			Product:   NewProductGateway(store),
+			Order:     NewOrderGateway(store),
+			OrderItem: NewOrderItemGateway(store),
+			Payment:   NewPaymentGateway(store),
+
+			Idempotency: NewIdempotencyGateway(store),
+			Outbox:      NewOutboxGateway(store),
+
+			WebhookSubscription: NewWebhookSubscriptionGateway(store),
+			WebhookDelivery:     NewWebhookDeliveryGateway(store),
+
+			Reconciliation: NewReconciliationGateway(store),
+
+			Promotion:     NewPromotionGateway(store),
+			OrderDiscount: NewOrderDiscountGateway(store),
+			Loyalty:       NewLoyaltyGateway(store),
+			OrderTax:      NewOrderTaxGateway(store),
+			Receipt:       NewReceiptGateway(store),
+			KitchenTicket: NewKitchenTicketGateway(store),
+			StationTicket: NewStationTicketGateway(store),
+		}
+	})
+}
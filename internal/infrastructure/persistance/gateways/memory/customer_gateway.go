@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type customerGateway struct {
+	store *Store
+}
+
+func NewCustomerGateway(store *Store) output.CustomerGateway {
+	return &customerGateway{
+		store: store,
+	}
+}
+
+func (g *customerGateway) Create(ctx context.Context, customer *entities.Customer) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for _, existing := range g.store.customers {
+		if existing.CPF == customer.CPF {
+			return errors.New("duplicate customer cpf")
+		}
+		if existing.Email == customer.Email {
+			return errors.New("duplicate customer email")
+		}
+	}
+
+	g.store.nextCustomerID++
+	customer.ID = g.store.nextCustomerID
+	g.store.customers[customer.ID] = *customer
+	return nil
+}
+
+func (g *customerGateway) GetByCPF(ctx context.Context, cpf string) (*entities.Customer, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	for _, customer := range g.store.customers {
+		if customer.CPF == cpf {
+			return &customer, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (g *customerGateway) GetByID(ctx context.Context, id uint64) (*entities.Customer, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	customer, ok := g.store.customers[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &customer, nil
+}
+
+func (g *customerGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Customer, error) {
+	lockRow(ctx, g.store, rowKey{table: "customers", id: id})
+	return g.GetByID(ctx, id)
+}
+
+func (g *customerGateway) Update(ctx context.Context, customer *entities.Customer) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.customers[customer.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.FirstName = customer.FirstName
+	existing.LastName = customer.LastName
+	existing.Email = customer.Email
+	existing.UpdatedAt = customer.UpdatedAt
+	g.store.customers[customer.ID] = existing
+	return nil
+}
+
+func (g *customerGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	delete(g.store.customers, id)
+	return nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// idempotencyKey is the primary key of the idempotency_keys table
+type idempotencyKey struct {
+	scope string
+	key   string
+}
+
+type idempotencyGateway struct {
+	store *Store
+}
+
+func NewIdempotencyGateway(store *Store) output.IdempotencyGateway {
+	return &idempotencyGateway{
+		store: store,
+	}
+}
+
+func (g *idempotencyGateway) Reserve(ctx context.Context, record *entities.IdempotencyRecord) (*entities.IdempotencyRecord, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	id := idempotencyKey{scope: record.Scope, key: record.Key}
+	if existing, ok := g.store.idempotencyKeys[id]; ok {
+		return &existing, nil
+	}
+
+	g.store.idempotencyKeys[id] = *record
+	return nil, nil
+}
+
+func (g *idempotencyGateway) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	id := idempotencyKey{scope: record.Scope, key: record.Key}
+	existing, ok := g.store.idempotencyKeys[id]
+	if !ok {
+		return nil
+	}
+
+	existing.StatusCode = record.StatusCode
+	existing.ContentType = record.ContentType
+	existing.Body = append([]byte(nil), record.Body...)
+	g.store.idempotencyKeys[id] = existing
+	return nil
+}
+
+func (g *idempotencyGateway) Release(ctx context.Context, scope, key string) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	delete(g.store.idempotencyKeys, idempotencyKey{scope: scope, key: key})
+	return nil
+}
+
+func (g *idempotencyGateway) DeleteCreatedBefore(ctx context.Context, t time.Time) (int64, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	var deleted int64
+	for id, record := range g.store.idempotencyKeys {
+		if record.CreatedAt.Before(t) {
+			delete(g.store.idempotencyKeys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
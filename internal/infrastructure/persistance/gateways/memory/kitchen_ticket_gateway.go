@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type kitchenTicketGateway struct {
+	store *Store
+}
+
+func NewKitchenTicketGateway(store *Store) output.KitchenTicketGateway {
+	return &kitchenTicketGateway{
+		store: store,
+	}
+}
+
+func (g *kitchenTicketGateway) Create(ctx context.Context, ticket *entities.KitchenTicket) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for _, existing := range g.store.kitchenTickets {
+		if existing.OrderID == ticket.OrderID && existing.Copy == ticket.Copy {
+			return false, nil
+		}
+	}
+
+	g.store.nextKitchenTicketID++
+	ticket.ID = g.store.nextKitchenTicketID
+	stored := *ticket
+	stored.Items = nil
+	g.store.kitchenTickets[ticket.ID] = stored
+	return true, nil
+}
+
+func (g *kitchenTicketGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.KitchenTicket, error) {
+	tickets := g.filter(func(ticket *entities.KitchenTicket) bool {
+		return ticket.OrderID == orderID
+	})
+
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return tickets[i].Copy < tickets[j].Copy
+	})
+
+	return tickets, nil
+}
+
+func (g *kitchenTicketGateway) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KitchenTicket, error) {
+	tickets := g.filter(func(ticket *entities.KitchenTicket) bool {
+		return ticket.Status == entities.KitchenTicketPending && !ticket.NextAttemptAt.After(now)
+	})
+
+	if len(tickets) > limit {
+		tickets = tickets[:limit]
+	}
+	return tickets, nil
+}
+
+func (g *kitchenTicketGateway) Claim(ctx context.Context, ticket *entities.KitchenTicket, now, until time.Time) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.kitchenTickets[ticket.ID]
+	if !ok || existing.Status != entities.KitchenTicketPending || existing.NextAttemptAt.After(now) {
+		return false, nil
+	}
+
+	existing.NextAttemptAt = until
+	g.store.kitchenTickets[ticket.ID] = existing
+	ticket.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *kitchenTicketGateway) Update(ctx context.Context, ticket *entities.KitchenTicket) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.kitchenTickets[ticket.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Status = ticket.Status
+	existing.Attempts = ticket.Attempts
+	existing.LastError = ticket.LastError
+	existing.NextAttemptAt = ticket.NextAttemptAt
+	existing.PrintedAt = ticket.PrintedAt
+	g.store.kitchenTickets[ticket.ID] = existing
+	return nil
+}
+
+// filter returns the matching tickets, oldest first
+func (g *kitchenTicketGateway) filter(match func(ticket *entities.KitchenTicket) bool) []*entities.KitchenTicket {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var tickets []*entities.KitchenTicket
+	for _, ticket := range g.store.kitchenTickets {
+		if match(&ticket) {
+			tickets = append(tickets, &ticket)
+		}
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].ID < tickets[j].ID
+	})
+
+	return tickets
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type loyaltyGateway struct {
+	store *Store
+}
+
+func NewLoyaltyGateway(store *Store) output.LoyaltyGateway {
+	return &loyaltyGateway{
+		store: store,
+	}
+}
+
+func (g *loyaltyGateway) Create(ctx context.Context, entry *entities.LoyaltyEntry) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextLoyaltyEntryID++
+	entry.ID = g.store.nextLoyaltyEntryID
+	g.store.loyaltyEntries[entry.ID] = storedLoyaltyEntry(entry)
+	return nil
+}
+
+func (g *loyaltyGateway) ListByCustomerID(ctx context.Context, customerID uint64) ([]*entities.LoyaltyEntry, error) {
+	return g.filter(func(entry *entities.LoyaltyEntry) bool {
+		return entry.CustomerID == customerID
+	}), nil
+}
+
+func (g *loyaltyGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.LoyaltyEntry, error) {
+	return g.filter(func(entry *entities.LoyaltyEntry) bool {
+		return entry.OrderID == orderID
+	}), nil
+}
+
+// filter returns copies of the entries matching keep, oldest first
+func (g *loyaltyGateway) filter(keep func(*entities.LoyaltyEntry) bool) []*entities.LoyaltyEntry {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var entries []*entities.LoyaltyEntry
+	for _, entry := range g.store.loyaltyEntries {
+		if keep(&entry) {
+			entry = storedLoyaltyEntry(&entry)
+			entries = append(entries, &entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	return entries
+}
+
+// storedLoyaltyEntry copies entry so callers cannot change the stored expiry
+func storedLoyaltyEntry(entry *entities.LoyaltyEntry) entities.LoyaltyEntry {
+	stored := *entry
+	if entry.ExpiresAt != nil {
+		expiresAt := *entry.ExpiresAt
+		stored.ExpiresAt = &expiresAt
+	}
+	return stored
+}
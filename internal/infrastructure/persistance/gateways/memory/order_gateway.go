@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// kitchenPriority mirrors the CASE ordering used by the SQL gateways
+var kitchenPriority = map[entities.OrderStatus]int{
+	entities.OrderReady:      1,
+	entities.OrderInProgress: 2,
+	entities.OrderReceived:   3,
+}
+
+type orderGateway struct {
+	store *Store
+}
+
+func NewOrderGateway(store *Store) output.OrderGateway {
+	return &orderGateway{
+		store: store,
+	}
+}
+
+func (g *orderGateway) Create(ctx context.Context, order *entities.Order) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextOrderID++
+	order.ID = g.store.nextOrderID
+	g.store.orders[order.ID] = storedOrder(order)
+	return nil
+}
+
+func (g *orderGateway) GetByID(ctx context.Context, id uint64) (*entities.Order, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	order, ok := g.store.orders[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &order, nil
+}
+
+func (g *orderGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Order, error) {
+	lockRow(ctx, g.store, rowKey{table: "orders", id: id})
+	return g.GetByID(ctx, id)
+}
+
+func (g *orderGateway) GetByCPF(ctx context.Context, cpf string) ([]*entities.Order, error) {
+	return g.filterNewestFirst(func(order *entities.Order) bool {
+		return order.CPF == cpf
+	}), nil
+}
+
+func (g *orderGateway) GetByCustomerID(ctx context.Context, customerID uint64) ([]*entities.Order, error) {
+	return g.filterNewestFirst(func(order *entities.Order) bool {
+		return order.CustomerId == customerID
+	}), nil
+}
+
+func (g *orderGateway) GetAll(ctx context.Context) ([]*entities.Order, error) {
+	return g.filterNewestFirst(func(order *entities.Order) bool {
+		return true
+	}), nil
+}
+
+func (g *orderGateway) GetPendingOrdersForKitchen(ctx context.Context) ([]*entities.Order, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var orders []*entities.Order
+	for _, order := range g.store.orders {
+		if _, ok := kitchenPriority[order.Status]; ok {
+			orders = append(orders, &order)
+		}
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		pi, pj := kitchenPriority[orders[i].Status], kitchenPriority[orders[j].Status]
+		if pi != pj {
+			return pi < pj
+		}
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+
+	return orders, nil
+}
+
+func (g *orderGateway) Update(ctx context.Context, order *entities.Order) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.orders[order.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Status = order.Status
+	existing.UpdatedAt = order.UpdatedAt
+	g.store.orders[order.ID] = existing
+	return nil
+}
+
+func (g *orderGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for itemID, item := range g.store.orderItems {
+		if item.OrderID == id {
+			delete(g.store.orderItems, itemID)
+		}
+	}
+	for discountID, discount := range g.store.orderDiscounts {
+		if discount.OrderID == id {
+			delete(g.store.orderDiscounts, discountID)
+		}
+	}
+	for taxID, tax := range g.store.orderTaxes {
+		if tax.OrderID == id {
+			delete(g.store.orderTaxes, taxID)
+		}
+	}
+	for paymentID, payment := range g.store.payments {
+		if payment.OrderID == id {
+			delete(g.store.payments, paymentID)
+		}
+	}
+	for entryID, entry := range g.store.loyaltyEntries {
+		if entry.OrderID == id {
+			delete(g.store.loyaltyEntries, entryID)
+		}
+	}
+	for receiptID, receipt := range g.store.receipts {
+		if receipt.OrderID == id {
+			delete(g.store.receipts, receiptID)
+		}
+	}
+	for ticketID, ticket := range g.store.kitchenTickets {
+		if ticket.OrderID == id {
+			delete(g.store.kitchenTickets, ticketID)
+		}
+	}
+	for ticketID, ticket := range g.store.stationTickets {
+		if ticket.OrderID == id {
+			delete(g.store.stationTickets, ticketID)
+		}
+	}
+
+	delete(g.store.orders, id)
+	return nil
+}
+
+func (g *orderGateway) filterNewestFirst(match func(order *entities.Order) bool) []*entities.Order {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var orders []*entities.Order
+	for _, order := range g.store.orders {
+		if match(&order) {
+			orders = append(orders, &order)
+		}
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].ID > orders[j].ID
+	})
+
+	return orders
+}
+
+// storedOrder strips the items, discount lines and tax lines, which live in
+// their own tables like in SQL
+func storedOrder(order *entities.Order) entities.Order {
+	stored := *order
+	stored.Items = nil
+	stored.Discounts = nil
+	stored.Taxes = nil
+	return stored
+}
+
+type orderItemGateway struct {
+	store *Store
+}
+
+func NewOrderItemGateway(store *Store) output.OrderItemGateway {
+	return &orderItemGateway{
+		store: store,
+	}
+}
+
+func (g *orderItemGateway) Create(ctx context.Context, orderItem *entities.OrderItem) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextOrderItemID++
+	orderItem.ID = g.store.nextOrderItemID
+	stored := *orderItem
+	stored.Modifiers = slices.Clone(orderItem.Modifiers)
+	g.store.orderItems[orderItem.ID] = stored
+	return nil
+}
+
+func (g *orderItemGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderItem, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var items []*entities.OrderItem
+	for _, item := range g.store.orderItems {
+		if item.OrderID == orderID {
+			items = append(items, &item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+func (g *orderItemGateway) Update(ctx context.Context, orderItem *entities.OrderItem) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.orderItems[orderItem.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Quantity = orderItem.Quantity
+	existing.Price = orderItem.Price
+	existing.UpdatedAt = orderItem.UpdatedAt
+	g.store.orderItems[orderItem.ID] = existing
+	return nil
+}
+
+func (g *orderItemGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	delete(g.store.orderItems, id)
+	return nil
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type orderTaxGateway struct {
+	store *Store
+}
+
+func NewOrderTaxGateway(store *Store) output.OrderTaxGateway {
+	return &orderTaxGateway{
+		store: store,
+	}
+}
+
+func (g *orderTaxGateway) Create(ctx context.Context, tax *entities.OrderTax) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextOrderTaxID++
+	tax.ID = g.store.nextOrderTaxID
+	g.store.orderTaxes[tax.ID] = *tax
+	return nil
+}
+
+func (g *orderTaxGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderTax, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var taxes []*entities.OrderTax
+	for _, tax := range g.store.orderTaxes {
+		if tax.OrderID == orderID {
+			taxes = append(taxes, &tax)
+		}
+	}
+
+	sort.Slice(taxes, func(i, j int) bool {
+		return taxes[i].ID < taxes[j].ID
+	})
+
+	return taxes, nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type outboxGateway struct {
+	store *Store
+}
+
+func NewOutboxGateway(store *Store) output.OutboxGateway {
+	return &outboxGateway{
+		store: store,
+	}
+}
+
+func (g *outboxGateway) Append(ctx context.Context, event *entities.OutboxEvent) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextOutboxEventID++
+	event.ID = g.store.nextOutboxEventID
+	g.store.outboxEvents[event.ID] = *event
+	return nil
+}
+
+func (g *outboxGateway) ListPending(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var events []*entities.OutboxEvent
+	for _, event := range g.store.outboxEvents {
+		if !event.IsPublished() && !event.NextAttemptAt.After(now) {
+			events = append(events, &event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+func (g *outboxGateway) Claim(ctx context.Context, event *entities.OutboxEvent, now, until time.Time) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.outboxEvents[event.ID]
+	if !ok || existing.IsPublished() || existing.NextAttemptAt.After(now) {
+		return false, nil
+	}
+
+	existing.NextAttemptAt = until
+	g.store.outboxEvents[event.ID] = existing
+	event.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *outboxGateway) Update(ctx context.Context, event *entities.OutboxEvent) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.outboxEvents[event.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Attempts = event.Attempts
+	existing.LastError = event.LastError
+	existing.NextAttemptAt = event.NextAttemptAt
+	existing.PublishedAt = event.PublishedAt
+	g.store.outboxEvents[event.ID] = existing
+	return nil
+}
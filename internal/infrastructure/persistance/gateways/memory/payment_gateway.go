@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type paymentGateway struct {
+	store *Store
+}
+
+func NewPaymentGateway(store *Store) output.PaymentGateway {
+	return &paymentGateway{
+		store: store,
+	}
+}
+
+func (g *paymentGateway) Create(ctx context.Context, payment *entities.Payment) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextPaymentID++
+	payment.ID = g.store.nextPaymentID
+	g.store.payments[payment.ID] = *payment
+	return nil
+}
+
+func (g *paymentGateway) GetByID(ctx context.Context, id uint64) (*entities.Payment, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	payment, ok := g.store.payments[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &payment, nil
+}
+
+func (g *paymentGateway) GetLatestByOrderID(ctx context.Context, orderID uint64) (*entities.Payment, error) {
+	payments, _ := g.ListByOrderID(ctx, orderID)
+	if len(payments) == 0 {
+		return nil, nil
+	}
+
+	return payments[len(payments)-1], nil
+}
+
+func (g *paymentGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.Payment, error) {
+	payments := g.filter(func(payment *entities.Payment) bool {
+		return payment.OrderID == orderID
+	})
+
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].ID < payments[j].ID
+	})
+
+	return payments, nil
+}
+
+func (g *paymentGateway) GetByTransactionID(ctx context.Context, transactionID string) (*entities.Payment, error) {
+	return g.first(func(payment *entities.Payment) bool {
+		return payment.TransactionID == transactionID
+	}), nil
+}
+
+func (g *paymentGateway) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Payment, error) {
+	payments := g.filter(func(payment *entities.Payment) bool {
+		return payment.IsDue(now)
+	})
+
+	sort.Slice(payments, func(i, j int) bool {
+		if !payments[i].ExpiresAt.Equal(*payments[j].ExpiresAt) {
+			return payments[i].ExpiresAt.Before(*payments[j].ExpiresAt)
+		}
+		return payments[i].ID < payments[j].ID
+	})
+	if len(payments) > limit {
+		payments = payments[:limit]
+	}
+
+	return payments, nil
+}
+
+func (g *paymentGateway) ListPendingCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Payment, error) {
+	payments := g.filter(func(payment *entities.Payment) bool {
+		return payment.IsPending() && payment.CreatedAt.Before(before)
+	})
+
+	sort.Slice(payments, func(i, j int) bool {
+		if !payments[i].CreatedAt.Equal(payments[j].CreatedAt) {
+			return payments[i].CreatedAt.Before(payments[j].CreatedAt)
+		}
+		return payments[i].ID < payments[j].ID
+	})
+	if len(payments) > limit {
+		payments = payments[:limit]
+	}
+
+	return payments, nil
+}
+
+func (g *paymentGateway) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*entities.Payment, error) {
+	payments := g.filter(func(payment *entities.Payment) bool {
+		return !payment.CreatedAt.Before(from) && payment.CreatedAt.Before(to)
+	})
+
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].ID < payments[j].ID
+	})
+
+	return payments, nil
+}
+
+func (g *paymentGateway) Update(ctx context.Context, payment *entities.Payment) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.payments[payment.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Amount = payment.Amount
+	existing.Status = payment.Status
+	existing.PaymentMethod = payment.PaymentMethod
+	existing.TransactionID = payment.TransactionID
+	existing.ExpiresAt = payment.ExpiresAt
+	existing.UpdatedAt = payment.UpdatedAt
+	g.store.payments[payment.ID] = existing
+	return nil
+}
+
+func (g *paymentGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	delete(g.store.payments, id)
+	return nil
+}
+
+// first returns the matching payment with the lowest ID, like the SQL
+// gateways do when several rows match
+func (g *paymentGateway) first(match func(payment *entities.Payment) bool) *entities.Payment {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var found *entities.Payment
+	for _, payment := range g.store.payments {
+		if match(&payment) && (found == nil || payment.ID < found.ID) {
+			found = &payment
+		}
+	}
+
+	return found
+}
+
+// filter returns copies of the payments that match
+func (g *paymentGateway) filter(match func(payment *entities.Payment) bool) []*entities.Payment {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var payments []*entities.Payment
+	for _, payment := range g.store.payments {
+		if match(&payment) {
+			payments = append(payments, &payment)
+		}
+	}
+
+	return payments
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// categoryOrder mirrors the declaration order of the MySQL category ENUM
+var categoryOrder = map[string]int{
+	string(entities.SnackCategory):   1,
+	string(entities.SideCategory):    2,
+	string(entities.DrinkCategory):   3,
+	string(entities.DessertCategory): 4,
+}
+
+type productGateway struct {
+	store *Store
+}
+
+func NewProductGateway(store *Store) output.ProductGateway {
+	return &productGateway{
+		store: store,
+	}
+}
+
+func (g *productGateway) Create(ctx context.Context, product *entities.Product) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextProductID++
+	product.ID = g.store.nextProductID
+	g.store.products[product.ID] = *product
+	return nil
+}
+
+func (g *productGateway) GetByID(ctx context.Context, id uint64) (*entities.Product, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	product, ok := g.store.products[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &product, nil
+}
+
+func (g *productGateway) GetByCategory(ctx context.Context, category string) ([]*entities.Product, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var products []*entities.Product
+	for _, product := range g.store.products {
+		if product.Category == category {
+			products = append(products, &product)
+		}
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		return products[i].Name < products[j].Name
+	})
+
+	return products, nil
+}
+
+func (g *productGateway) GetAll(ctx context.Context) ([]*entities.Product, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var products []*entities.Product
+	for _, product := range g.store.products {
+		products = append(products, &product)
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		if products[i].Category != products[j].Category {
+			return categoryOrder[products[i].Category] < categoryOrder[products[j].Category]
+		}
+		return products[i].Name < products[j].Name
+	})
+
+	return products, nil
+}
+
+func (g *productGateway) Update(ctx context.Context, product *entities.Product) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.products[product.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Name = product.Name
+	existing.Description = product.Description
+	existing.Price = product.Price
+	existing.Category = product.Category
+	existing.ImageUrl = product.ImageUrl
+	existing.UpdatedAt = product.UpdatedAt
+	g.store.products[product.ID] = existing
+	return nil
+}
+
+func (g *productGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	delete(g.store.products, id)
+	return nil
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type promotionGateway struct {
+	store *Store
+}
+
+func NewPromotionGateway(store *Store) output.PromotionGateway {
+	return &promotionGateway{
+		store: store,
+	}
+}
+
+func (g *promotionGateway) Create(ctx context.Context, promotion *entities.Promotion) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextPromotionID++
+	promotion.ID = g.store.nextPromotionID
+	g.store.promotions[promotion.ID] = storedPromotion(promotion)
+	return nil
+}
+
+func (g *promotionGateway) GetByID(ctx context.Context, id uint64) (*entities.Promotion, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	promotion, ok := g.store.promotions[id]
+	if !ok {
+		return nil, nil
+	}
+
+	promotion = storedPromotion(&promotion)
+	return &promotion, nil
+}
+
+func (g *promotionGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Promotion, error) {
+	lockRow(ctx, g.store, rowKey{table: "promotions", id: id})
+	return g.GetByID(ctx, id)
+}
+
+func (g *promotionGateway) GetByCode(ctx context.Context, code string) (*entities.Promotion, error) {
+	promotions := g.filter(func(promotion *entities.Promotion) bool {
+		return code != "" && promotion.Code == code
+	})
+	if len(promotions) == 0 {
+		return nil, nil
+	}
+	return promotions[0], nil
+}
+
+func (g *promotionGateway) GetAll(ctx context.Context) ([]*entities.Promotion, error) {
+	return g.filter(func(*entities.Promotion) bool { return true }), nil
+}
+
+func (g *promotionGateway) ListAutomatic(ctx context.Context) ([]*entities.Promotion, error) {
+	return g.filter(func(promotion *entities.Promotion) bool {
+		return promotion.Active && !promotion.IsCoupon()
+	}), nil
+}
+
+func (g *promotionGateway) Update(ctx context.Context, promotion *entities.Promotion) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	if _, ok := g.store.promotions[promotion.ID]; !ok {
+		return nil
+	}
+
+	g.store.promotions[promotion.ID] = storedPromotion(promotion)
+	return nil
+}
+
+func (g *promotionGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	delete(g.store.promotions, id)
+	return nil
+}
+
+// filter returns the matching promotions, oldest first
+func (g *promotionGateway) filter(match func(promotion *entities.Promotion) bool) []*entities.Promotion {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var promotions []*entities.Promotion
+	for _, promotion := range g.store.promotions {
+		if match(&promotion) {
+			promotion = storedPromotion(&promotion)
+			promotions = append(promotions, &promotion)
+		}
+	}
+
+	sort.Slice(promotions, func(i, j int) bool {
+		return promotions[i].ID < promotions[j].ID
+	})
+
+	return promotions
+}
+
+// storedPromotion copies the promotion so callers never share its slices
+// and pointers with the store
+func storedPromotion(promotion *entities.Promotion) entities.Promotion {
+	stored := *promotion
+	stored.Weekdays = slices.Clone(promotion.Weekdays)
+	if promotion.StartsAt != nil {
+		startsAt := *promotion.StartsAt
+		stored.StartsAt = &startsAt
+	}
+	if promotion.EndsAt != nil {
+		endsAt := *promotion.EndsAt
+		stored.EndsAt = &endsAt
+	}
+	return stored
+}
+
+type orderDiscountGateway struct {
+	store *Store
+}
+
+func NewOrderDiscountGateway(store *Store) output.OrderDiscountGateway {
+	return &orderDiscountGateway{
+		store: store,
+	}
+}
+
+func (g *orderDiscountGateway) Create(ctx context.Context, discount *entities.OrderDiscount) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextOrderDiscountID++
+	discount.ID = g.store.nextOrderDiscountID
+	g.store.orderDiscounts[discount.ID] = *discount
+	return nil
+}
+
+func (g *orderDiscountGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderDiscount, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var discounts []*entities.OrderDiscount
+	for _, discount := range g.store.orderDiscounts {
+		if discount.OrderID == orderID {
+			discounts = append(discounts, &discount)
+		}
+	}
+
+	sort.Slice(discounts, func(i, j int) bool {
+		return discounts[i].ID < discounts[j].ID
+	})
+
+	return discounts, nil
+}
+
+func (g *orderDiscountGateway) CountUses(ctx context.Context, promotionID, customerID uint64, cpf string) (int, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	orders := map[uint64]bool{}
+	for _, discount := range g.store.orderDiscounts {
+		if discount.PromotionID != promotionID {
+			continue
+		}
+		order, ok := g.store.orders[discount.OrderID]
+		if !ok || order.Status == entities.OrderCancelled {
+			continue
+		}
+		if (customerID != 0 && order.CustomerId == customerID) || (cpf != "" && order.CPF == cpf) {
+			orders[order.ID] = true
+		}
+	}
+
+	return len(orders), nil
+}
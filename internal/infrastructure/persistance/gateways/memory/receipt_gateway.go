@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type receiptGateway struct {
+	store *Store
+}
+
+func NewReceiptGateway(store *Store) output.ReceiptGateway {
+	return &receiptGateway{
+		store: store,
+	}
+}
+
+func (g *receiptGateway) Create(ctx context.Context, receipt *entities.Receipt) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for _, existing := range g.store.receipts {
+		if existing.OrderID == receipt.OrderID {
+			return false, nil
+		}
+	}
+
+	g.store.nextReceiptID++
+	receipt.ID = g.store.nextReceiptID
+	g.store.receipts[receipt.ID] = *receipt
+	return true, nil
+}
+
+func (g *receiptGateway) GetByOrderID(ctx context.Context, orderID uint64) (*entities.Receipt, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	for _, receipt := range g.store.receipts {
+		if receipt.OrderID == orderID {
+			return &receipt, nil
+		}
+	}
+	return nil, nil
+}
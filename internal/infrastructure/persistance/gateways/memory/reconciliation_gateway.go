@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type reconciliationGateway struct {
+	store *Store
+}
+
+func NewReconciliationGateway(store *Store) output.ReconciliationGateway {
+	return &reconciliationGateway{
+		store: store,
+	}
+}
+
+func (g *reconciliationGateway) SaveReport(ctx context.Context, report *entities.ReconciliationReport) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	saved := *report
+	saved.Date = entities.ReportDay(report.Date)
+	saved.Mismatches = slices.Clone(report.Mismatches)
+	g.store.reconciliationReports[saved.Date] = saved
+	return nil
+}
+
+func (g *reconciliationGateway) GetReport(ctx context.Context, date time.Time) (*entities.ReconciliationReport, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	report, ok := g.store.reconciliationReports[entities.ReportDay(date)]
+	if !ok {
+		return nil, nil
+	}
+
+	report.Mismatches = append([]entities.ReconciliationMismatch{}, report.Mismatches...)
+	return &report, nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type stationTicketGateway struct {
+	store *Store
+}
+
+func NewStationTicketGateway(store *Store) output.StationTicketGateway {
+	return &stationTicketGateway{
+		store: store,
+	}
+}
+
+func (g *stationTicketGateway) Create(ctx context.Context, ticket *entities.StationTicket) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for _, existing := range g.store.stationTickets {
+		if existing.OrderID == ticket.OrderID && existing.Station == ticket.Station {
+			return false, nil
+		}
+	}
+
+	g.store.nextStationTicketID++
+	ticket.ID = g.store.nextStationTicketID
+	stored := *ticket
+	stored.Items = slices.Clone(ticket.Items)
+	g.store.stationTickets[ticket.ID] = stored
+	return true, nil
+}
+
+func (g *stationTicketGateway) GetByID(ctx context.Context, id uint64) (*entities.StationTicket, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	ticket, ok := g.store.stationTickets[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneStationTicket(ticket), nil
+}
+
+func (g *stationTicketGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.StationTicket, error) {
+	return g.filterOldestFirst(func(ticket *entities.StationTicket) bool {
+		return ticket.OrderID == orderID
+	}), nil
+}
+
+func (g *stationTicketGateway) ListOpenByStation(ctx context.Context, station string) ([]*entities.StationTicket, error) {
+	return g.filterOldestFirst(func(ticket *entities.StationTicket) bool {
+		if ticket.Station != station || ticket.Status == entities.StationTicketDone {
+			return false
+		}
+		order, ok := g.store.orders[ticket.OrderID]
+		return ok && (order.Status == entities.OrderReceived || order.Status == entities.OrderInProgress)
+	}), nil
+}
+
+func (g *stationTicketGateway) Update(ctx context.Context, ticket *entities.StationTicket) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.stationTickets[ticket.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Status = ticket.Status
+	existing.UpdatedAt = ticket.UpdatedAt
+	g.store.stationTickets[ticket.ID] = existing
+	return nil
+}
+
+// filterOldestFirst returns the tickets match accepts by ID, which is the
+// order they were created in. match runs with the store locked.
+func (g *stationTicketGateway) filterOldestFirst(match func(ticket *entities.StationTicket) bool) []*entities.StationTicket {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var tickets []*entities.StationTicket
+	for _, ticket := range g.store.stationTickets {
+		if match(&ticket) {
+			tickets = append(tickets, cloneStationTicket(ticket))
+		}
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].ID < tickets[j].ID
+	})
+	return tickets
+}
+
+func cloneStationTicket(ticket entities.StationTicket) *entities.StationTicket {
+	ticket.Items = slices.Clone(ticket.Items)
+	return &ticket
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// Store holds every table of the in-memory backend. Gateways built from the
+// same Store see each other's data, mirroring a single SQL database.
+type Store struct {
+	mu sync.RWMutex
+
+	customers  map[uint64]entities.Customer
+	products   map[uint64]entities.Product
+	orders     map[uint64]entities.Order
+	orderItems map[uint64]entities.OrderItem
+	payments   map[uint64]entities.Payment
+
+	idempotencyKeys map[idempotencyKey]entities.IdempotencyRecord
+	outboxEvents    map[uint64]entities.OutboxEvent
+
+	webhookSubscriptions map[uint64]entities.WebhookSubscription
+	webhookDeliveries    map[uint64]entities.WebhookDelivery
+
+	// reconciliationReports is keyed by the report's UTC day
+	reconciliationReports map[time.Time]entities.ReconciliationReport
+
+	promotions     map[uint64]entities.Promotion
+	orderDiscounts map[uint64]entities.OrderDiscount
+
+	loyaltyEntries map[uint64]entities.LoyaltyEntry
+
+	orderTaxes map[uint64]entities.OrderTax
+	receipts   map[uint64]entities.Receipt
+
+	kitchenTickets map[uint64]entities.KitchenTicket
+	stationTickets map[uint64]entities.StationTicket
+
+	nextCustomerID  uint64
+	nextProductID   uint64
+	nextOrderID     uint64
+	nextOrderItemID uint64
+	nextPaymentID   uint64
+
+	nextOutboxEventID         uint64
+	nextWebhookSubscriptionID uint64
+	nextWebhookDeliveryID     uint64
+
+	nextPromotionID     uint64
+	nextOrderDiscountID uint64
+
+	nextLoyaltyEntryID uint64
+
+	nextOrderTaxID uint64
+	nextReceiptID  uint64
+
+	nextKitchenTicketID uint64
+	nextStationTicketID uint64
+
+	// rowLocks back the *ForUpdate gateway methods; see lockRow
+	rowLocks map[rowKey]*sync.Mutex
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		customers:  make(map[uint64]entities.Customer),
+		products:   make(map[uint64]entities.Product),
+		orders:     make(map[uint64]entities.Order),
+		orderItems: make(map[uint64]entities.OrderItem),
+		payments:   make(map[uint64]entities.Payment),
+
+		idempotencyKeys: make(map[idempotencyKey]entities.IdempotencyRecord),
+		outboxEvents:    make(map[uint64]entities.OutboxEvent),
+
+		webhookSubscriptions: make(map[uint64]entities.WebhookSubscription),
+		webhookDeliveries:    make(map[uint64]entities.WebhookDelivery),
+
+		reconciliationReports: make(map[time.Time]entities.ReconciliationReport),
+
+		promotions:     make(map[uint64]entities.Promotion),
+		orderDiscounts: make(map[uint64]entities.OrderDiscount),
+
+		loyaltyEntries: make(map[uint64]entities.LoyaltyEntry),
+
+		orderTaxes: make(map[uint64]entities.OrderTax),
+		receipts:   make(map[uint64]entities.Receipt),
+
+		kitchenTickets: make(map[uint64]entities.KitchenTicket),
+		stationTickets: make(map[uint64]entities.StationTicket),
+
+		rowLocks: make(map[rowKey]*sync.Mutex),
+	}
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+func TestStore_ConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	orders := NewOrderGateway(store)
+	items := NewOrderItemGateway(store)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			order := entities.NewOrder(0, "")
+			if err := orders.Create(ctx, order); err != nil {
+				t.Error(err)
+				return
+			}
+			items.Create(ctx, entities.NewOrderItem(order.ID, 1, 1, 10))
+			order.UpdateStatus(entities.OrderReceived)
+			orders.Update(ctx, order)
+			orders.GetPendingOrdersForKitchen(ctx)
+		}()
+	}
+	wg.Wait()
+
+	all, _ := orders.GetAll(ctx)
+	if len(all) != 50 {
+		t.Fatalf("Expected 50 orders, got %d", len(all))
+	}
+
+	seen := make(map[uint64]bool)
+	for _, order := range all {
+		if seen[order.ID] {
+			t.Fatalf("Duplicate ID %d", order.ID)
+		}
+		seen[order.ID] = true
+	}
+}
+
+func TestStore_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	products := NewProductGateway(store)
+
+	product := entities.NewProduct("Suco", "Laranja", 8, entities.DrinkCategory, "")
+	products.Create(ctx, product)
+
+	found, _ := products.GetByID(ctx, product.ID)
+	found.Price = 1
+
+	again, _ := products.GetByID(ctx, product.ID)
+	if again.Price != 8 {
+		t.Errorf("Expected stored price to be untouched, got %v", again.Price)
+	}
+}
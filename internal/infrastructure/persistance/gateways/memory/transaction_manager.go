@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type transactionManager struct{}
+
+// NewTransactionManager returns a manager that only runs fn and releases the
+// row locks taken during it: the in-memory backend applies each write
+// immediately and cannot roll back. It is meant for development and tests,
+// where that is acceptable.
+func NewTransactionManager() output.TransactionManager {
+	return transactionManager{}
+}
+
+type txKey struct{}
+
+// transaction holds the row locks taken through the *ForUpdate gateway
+// methods until WithinTransaction returns
+type transaction struct {
+	held map[*sync.Mutex]bool
+}
+
+func (transactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// A nested call joins the outer transaction
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return fn(ctx)
+	}
+
+	tx := &transaction{held: make(map[*sync.Mutex]bool)}
+	defer func() {
+		for lock := range tx.held {
+			lock.Unlock()
+		}
+	}()
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// rowKey names a row of the store for locking
+type rowKey struct {
+	table string
+	id    uint64
+}
+
+// lockRow locks the row until the transaction in ctx ends, like SELECT ...
+// FOR UPDATE; outside a transaction there is nothing to hold it for
+func lockRow(ctx context.Context, store *Store, key rowKey) {
+	tx, ok := ctx.Value(txKey{}).(*transaction)
+	if !ok {
+		return
+	}
+
+	store.mu.Lock()
+	lock, ok := store.rowLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		store.rowLocks[key] = lock
+	}
+	store.mu.Unlock()
+
+	if tx.held[lock] {
+		return
+	}
+	lock.Lock()
+	tx.held[lock] = true
+}
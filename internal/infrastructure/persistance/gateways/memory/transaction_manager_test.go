@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+func TestTransactionManager_GetByIDForUpdateWaitsForTheHolder(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	orders := NewOrderGateway(NewStore())
+	transactions := NewTransactionManager()
+	order := entities.NewOrder(0, "")
+	orders.Create(ctx, order)
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	go transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		orders.GetByIDForUpdate(ctx, order.ID)
+		// Taking it again in the same transaction must not block
+		orders.GetByIDForUpdate(ctx, order.ID)
+		close(locked)
+		<-release
+		return nil
+	})
+	<-locked
+
+	// Act
+	acquired := make(chan struct{})
+	go transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		orders.GetByIDForUpdate(ctx, order.ID)
+		close(acquired)
+		return nil
+	})
+
+	// Assert
+	select {
+	case <-acquired:
+		t.Fatal("Expected the second transaction to wait for the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if found, _ := orders.GetByIDForUpdate(ctx, order.ID); found == nil {
+		t.Error("Expected reads outside a transaction not to wait")
+	}
+	close(release)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the lock to be released when the first transaction ended")
+	}
+}
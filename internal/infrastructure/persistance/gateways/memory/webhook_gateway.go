@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+type webhookSubscriptionGateway struct {
+	store *Store
+}
+
+func NewWebhookSubscriptionGateway(store *Store) output.WebhookSubscriptionGateway {
+	return &webhookSubscriptionGateway{
+		store: store,
+	}
+}
+
+func (g *webhookSubscriptionGateway) Create(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	g.store.nextWebhookSubscriptionID++
+	subscription.ID = g.store.nextWebhookSubscriptionID
+	stored := *subscription
+	stored.EventTypes = slices.Clone(subscription.EventTypes)
+	g.store.webhookSubscriptions[subscription.ID] = stored
+	return nil
+}
+
+func (g *webhookSubscriptionGateway) GetByID(ctx context.Context, id uint64) (*entities.WebhookSubscription, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	subscription, ok := g.store.webhookSubscriptions[id]
+	if !ok {
+		return nil, nil
+	}
+
+	subscription.EventTypes = slices.Clone(subscription.EventTypes)
+	return &subscription, nil
+}
+
+func (g *webhookSubscriptionGateway) GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var subscriptions []*entities.WebhookSubscription
+	for _, subscription := range g.store.webhookSubscriptions {
+		subscription.EventTypes = slices.Clone(subscription.EventTypes)
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+
+	return subscriptions, nil
+}
+
+func (g *webhookSubscriptionGateway) Delete(ctx context.Context, id uint64) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for deliveryID, delivery := range g.store.webhookDeliveries {
+		if delivery.SubscriptionID == id {
+			delete(g.store.webhookDeliveries, deliveryID)
+		}
+	}
+	delete(g.store.webhookSubscriptions, id)
+	return nil
+}
+
+type webhookDeliveryGateway struct {
+	store *Store
+}
+
+func NewWebhookDeliveryGateway(store *Store) output.WebhookDeliveryGateway {
+	return &webhookDeliveryGateway{
+		store: store,
+	}
+}
+
+func (g *webhookDeliveryGateway) Create(ctx context.Context, delivery *entities.WebhookDelivery) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	for _, existing := range g.store.webhookDeliveries {
+		if existing.SubscriptionID == delivery.SubscriptionID && existing.EventID == delivery.EventID {
+			return false, nil
+		}
+	}
+
+	g.store.nextWebhookDeliveryID++
+	delivery.ID = g.store.nextWebhookDeliveryID
+	g.store.webhookDeliveries[delivery.ID] = *delivery
+	return true, nil
+}
+
+func (g *webhookDeliveryGateway) GetByID(ctx context.Context, id uint64) (*entities.WebhookDelivery, error) {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	delivery, ok := g.store.webhookDeliveries[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &delivery, nil
+}
+
+func (g *webhookDeliveryGateway) GetBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entities.WebhookDelivery, error) {
+	deliveries := g.filter(func(delivery *entities.WebhookDelivery) bool {
+		return delivery.SubscriptionID == subscriptionID
+	})
+
+	slices.Reverse(deliveries)
+	return deliveries, nil
+}
+
+func (g *webhookDeliveryGateway) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	deliveries := g.filter(func(delivery *entities.WebhookDelivery) bool {
+		return delivery.Status == entities.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now)
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (g *webhookDeliveryGateway) Claim(ctx context.Context, delivery *entities.WebhookDelivery, now, until time.Time) (bool, error) {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.webhookDeliveries[delivery.ID]
+	if !ok || existing.Status != entities.WebhookDeliveryPending || existing.NextAttemptAt.After(now) {
+		return false, nil
+	}
+
+	existing.NextAttemptAt = until
+	g.store.webhookDeliveries[delivery.ID] = existing
+	delivery.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *webhookDeliveryGateway) Update(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	g.store.mu.Lock()
+	defer g.store.mu.Unlock()
+
+	existing, ok := g.store.webhookDeliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+
+	existing.Status = delivery.Status
+	existing.Attempts = delivery.Attempts
+	existing.LastStatusCode = delivery.LastStatusCode
+	existing.LastError = delivery.LastError
+	existing.NextAttemptAt = delivery.NextAttemptAt
+	existing.DeliveredAt = delivery.DeliveredAt
+	existing.UpdatedAt = delivery.UpdatedAt
+	g.store.webhookDeliveries[delivery.ID] = existing
+	return nil
+}
+
+// filter returns the matching deliveries, oldest first
+func (g *webhookDeliveryGateway) filter(match func(delivery *entities.WebhookDelivery) bool) []*entities.WebhookDelivery {
+	g.store.mu.RLock()
+	defer g.store.mu.RUnlock()
+
+	var deliveries []*entities.WebhookDelivery
+	for _, delivery := range g.store.webhookDeliveries {
+		if match(&delivery) {
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+
+	return deliveries
+}
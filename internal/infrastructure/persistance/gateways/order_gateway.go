@@ -1,33 +1,41 @@
 package gateways
 
 import (
+	"context"
 	"database/sql"
-	"time"
+	"encoding/json"
+	"log/slog"
 
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
 )
 
 type orderGateway struct {
-	db *sql.DB
+	db     *sql.DB
+	logger *slog.Logger
 }
 
-func NewOrderGateway(db *sql.DB) output.OrderGateway {
+func NewOrderGateway(db *sql.DB, logger *slog.Logger) output.OrderGateway {
 	return &orderGateway{
-		db: db,
+		db:     db,
+		logger: logger,
 	}
 }
 
-func (g *orderGateway) Create(order *entities.Order) error {
+func (g *orderGateway) Create(ctx context.Context, order *entities.Order) error {
 	query := `
-		INSERT INTO orders (customer_id, cpf, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO orders (customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := g.db.Exec(query,
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		order.CustomerId,
 		order.CPF,
 		string(order.Status),
+		order.Discount,
+		order.ServiceFee,
+		order.Rounding,
 		order.CreatedAt,
 		order.UpdatedAt,
 	)
@@ -45,26 +53,36 @@ func (g *orderGateway) Create(order *entities.Order) error {
 	return nil
 }
 
-func (g *orderGateway) GetByID(id uint64) (*entities.Order, error) {
+func (g *orderGateway) GetByID(ctx context.Context, id uint64) (*entities.Order, error) {
+	return g.getByID(ctx, id, "")
+}
+
+func (g *orderGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Order, error) {
+	return g.getByID(ctx, id, "FOR UPDATE")
+}
+
+func (g *orderGateway) getByID(ctx context.Context, id uint64, lock string) (*entities.Order, error) {
 	query := `
-		SELECT id, customer_id, cpf, status, created_at, updated_at
+		SELECT id, customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at
 		FROM orders
 		WHERE id = ?
-	`
+	` + lock
 
-	row := g.db.QueryRow(query, id)
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id)
 
 	var order entities.Order
 	var status string
-	var createdAt, updatedAt string
 
 	err := row.Scan(
 		&order.ID,
 		&order.CustomerId,
 		&order.CPF,
 		&status,
-		&createdAt,
-		&updatedAt,
+		&order.Discount,
+		&order.ServiceFee,
+		&order.Rounding,
+		&order.CreatedAt,
+		&order.UpdatedAt,
 	)
 
 	if err != nil {
@@ -76,21 +94,18 @@ func (g *orderGateway) GetByID(id uint64) (*entities.Order, error) {
 
 	order.Status = entities.OrderStatus(status)
 
-	order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	order.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 	return &order, nil
 }
 
-func (g *orderGateway) GetByCPF(cpf string) ([]*entities.Order, error) {
+func (g *orderGateway) GetByCPF(ctx context.Context, cpf string) ([]*entities.Order, error) {
 	query := `
-		SELECT id, customer_id, cpf, status, created_at, updated_at
+		SELECT id, customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at
 		FROM orders
 		WHERE cpf = ?
 		ORDER BY created_at DESC
 	`
 
-	rows, err := g.db.Query(query, cpf)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, cpf)
 	if err != nil {
 		return nil, err
 	}
@@ -101,42 +116,41 @@ func (g *orderGateway) GetByCPF(cpf string) ([]*entities.Order, error) {
 	for rows.Next() {
 		var order entities.Order
 		var status string
-		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&order.ID,
 			&order.CustomerId,
 			&order.CPF,
 			&status,
-			&createdAt,
-			&updatedAt,
+			&order.Discount,
+			&order.ServiceFee,
+			&order.Rounding,
+			&order.CreatedAt,
+			&order.UpdatedAt,
 		)
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping order row that failed to scan", "method", "GetByCPF", "error", err)
 			continue
 		}
 
 		order.Status = entities.OrderStatus(status)
 
-		// Parse timestamps
-		order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		order.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		orders = append(orders, &order)
 	}
 
 	return orders, nil
 }
 
-func (g *orderGateway) GetByCustomerID(customerID uint64) ([]*entities.Order, error) {
+func (g *orderGateway) GetByCustomerID(ctx context.Context, customerID uint64) ([]*entities.Order, error) {
 	query := `
-		SELECT id, customer_id, cpf, status, created_at, updated_at
+		SELECT id, customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at
 		FROM orders
 		WHERE customer_id = ?
 		ORDER BY created_at DESC
 	`
 
-	rows, err := g.db.Query(query, customerID)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, customerID)
 	if err != nil {
 		return nil, err
 	}
@@ -147,41 +161,40 @@ func (g *orderGateway) GetByCustomerID(customerID uint64) ([]*entities.Order, er
 	for rows.Next() {
 		var order entities.Order
 		var status string
-		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&order.ID,
 			&order.CustomerId,
 			&order.CPF,
 			&status,
-			&createdAt,
-			&updatedAt,
+			&order.Discount,
+			&order.ServiceFee,
+			&order.Rounding,
+			&order.CreatedAt,
+			&order.UpdatedAt,
 		)
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping order row that failed to scan", "method", "GetByCustomerID", "error", err)
 			continue
 		}
 
 		order.Status = entities.OrderStatus(status)
 
-		// Parse timestamps
-		order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		order.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		orders = append(orders, &order)
 	}
 
 	return orders, nil
 }
 
-func (g *orderGateway) GetAll() ([]*entities.Order, error) {
+func (g *orderGateway) GetAll(ctx context.Context) ([]*entities.Order, error) {
 	query := `
-		SELECT id, customer_id, cpf, status, created_at, updated_at
+		SELECT id, customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at
 		FROM orders
 		ORDER BY created_at DESC
 	`
 
-	rows, err := g.db.Query(query)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -192,36 +205,35 @@ func (g *orderGateway) GetAll() ([]*entities.Order, error) {
 	for rows.Next() {
 		var order entities.Order
 		var status string
-		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&order.ID,
 			&order.CustomerId,
 			&order.CPF,
 			&status,
-			&createdAt,
-			&updatedAt,
+			&order.Discount,
+			&order.ServiceFee,
+			&order.Rounding,
+			&order.CreatedAt,
+			&order.UpdatedAt,
 		)
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping order row that failed to scan", "method", "GetAll", "error", err)
 			continue
 		}
 
 		order.Status = entities.OrderStatus(status)
 
-		// Parse timestamps
-		order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		order.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		orders = append(orders, &order)
 	}
 
 	return orders, nil
 }
 
-func (g *orderGateway) GetPendingOrdersForKitchen() ([]*entities.Order, error) {
+func (g *orderGateway) GetPendingOrdersForKitchen(ctx context.Context) ([]*entities.Order, error) {
 	query := `
-		SELECT id, customer_id, cpf, status, created_at, updated_at
+		SELECT id, customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at
 		FROM orders
 		WHERE status IN ('received', 'in_progress', 'ready')
 		ORDER BY 
@@ -233,7 +245,7 @@ func (g *orderGateway) GetPendingOrdersForKitchen() ([]*entities.Order, error) {
 			created_at ASC
 	`
 
-	rows, err := g.db.Query(query)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -244,40 +256,40 @@ func (g *orderGateway) GetPendingOrdersForKitchen() ([]*entities.Order, error) {
 	for rows.Next() {
 		var order entities.Order
 		var status string
-		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&order.ID,
 			&order.CustomerId,
 			&order.CPF,
 			&status,
-			&createdAt,
-			&updatedAt,
+			&order.Discount,
+			&order.ServiceFee,
+			&order.Rounding,
+			&order.CreatedAt,
+			&order.UpdatedAt,
 		)
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping order row that failed to scan", "method", "GetPendingOrdersForKitchen", "error", err)
 			continue
 		}
 
 		order.Status = entities.OrderStatus(status)
 
-		order.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		order.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		orders = append(orders, &order)
 	}
 
 	return orders, nil
 }
 
-func (g *orderGateway) Update(order *entities.Order) error {
+func (g *orderGateway) Update(ctx context.Context, order *entities.Order) error {
 	query := `
 		UPDATE orders
 		SET status = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	_, err := g.db.Exec(query,
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		string(order.Status),
 		order.UpdatedAt,
 		order.ID,
@@ -286,38 +298,76 @@ func (g *orderGateway) Update(order *entities.Order) error {
 	return err
 }
 
-func (g *orderGateway) Delete(id uint64) error {
-	_, err := g.db.Exec("DELETE FROM order_items WHERE order_id = ?", id)
+func (g *orderGateway) Delete(ctx context.Context, id uint64) error {
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM order_items WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM order_discounts WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM order_taxes WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM payments WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM loyalty_entries WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM receipts WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM kitchen_tickets WHERE order_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM station_tickets WHERE order_id = ?", id)
 	if err != nil {
 		return err
 	}
 
 	query := `DELETE FROM orders WHERE id = ?`
-	_, err = g.db.Exec(query, id)
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
 	return err
 }
 
 type orderItemGateway struct {
-	db *sql.DB
+	db     *sql.DB
+	logger *slog.Logger
 }
 
-func NewOrderItemGateway(db *sql.DB) output.OrderItemGateway {
+func NewOrderItemGateway(db *sql.DB, logger *slog.Logger) output.OrderItemGateway {
 	return &orderItemGateway{
-		db: db,
+		db:     db,
+		logger: logger,
 	}
 }
 
-func (g *orderItemGateway) Create(orderItem *entities.OrderItem) error {
+func (g *orderItemGateway) Create(ctx context.Context, orderItem *entities.OrderItem) error {
 	query := `
-		INSERT INTO order_items (order_id, product_id, quantity, price, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO order_items (order_id, product_id, quantity, price, modifiers, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := g.db.Exec(query,
+	modifiers, err := encodeModifiers(orderItem.Modifiers)
+	if err != nil {
+		return err
+	}
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		orderItem.OrderID,
 		orderItem.ProductID,
 		orderItem.Quantity,
 		orderItem.Price,
+		modifiers,
+		orderItem.Notes,
 		orderItem.CreatedAt,
 		orderItem.UpdatedAt,
 	)
@@ -335,15 +385,15 @@ func (g *orderItemGateway) Create(orderItem *entities.OrderItem) error {
 	return nil
 }
 
-func (g *orderItemGateway) GetByOrderID(orderID uint64) ([]*entities.OrderItem, error) {
+func (g *orderItemGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderItem, error) {
 	query := `
-		SELECT id, order_id, product_id, quantity, price, created_at, updated_at
+		SELECT id, order_id, product_id, quantity, price, modifiers, notes, created_at, updated_at
 		FROM order_items
 		WHERE order_id = ?
 		ORDER BY id
 	`
 
-	rows, err := g.db.Query(query, orderID)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +403,7 @@ func (g *orderItemGateway) GetByOrderID(orderID uint64) ([]*entities.OrderItem,
 
 	for rows.Next() {
 		var item entities.OrderItem
-		var createdAt, updatedAt string
+		var modifiers sql.NullString
 
 		err := rows.Scan(
 			&item.ID,
@@ -361,31 +411,34 @@ func (g *orderItemGateway) GetByOrderID(orderID uint64) ([]*entities.OrderItem,
 			&item.ProductID,
 			&item.Quantity,
 			&item.Price,
-			&createdAt,
-			&updatedAt,
+			&modifiers,
+			&item.Notes,
+			&item.CreatedAt,
+			&item.UpdatedAt,
 		)
+		if err == nil {
+			item.Modifiers, err = decodeModifiers(modifiers)
+		}
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping order item row that failed to scan", "method", "GetByOrderID", "error", err)
 			continue
 		}
 
-		item.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		item.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		items = append(items, &item)
 	}
 
 	return items, nil
 }
 
-func (g *orderItemGateway) Update(orderItem *entities.OrderItem) error {
+func (g *orderItemGateway) Update(ctx context.Context, orderItem *entities.OrderItem) error {
 	query := `
 		UPDATE order_items
 		SET quantity = ?, price = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	_, err := g.db.Exec(query,
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		orderItem.Quantity,
 		orderItem.Price,
 		orderItem.UpdatedAt,
@@ -395,8 +448,32 @@ func (g *orderItemGateway) Update(orderItem *entities.OrderItem) error {
 	return err
 }
 
-func (g *orderItemGateway) Delete(id uint64) error {
+func (g *orderItemGateway) Delete(ctx context.Context, id uint64) error {
 	query := `DELETE FROM order_items WHERE id = ?`
-	_, err := g.db.Exec(query, id)
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
 	return err
 }
+
+// encodeModifiers stores an item's modifiers as a JSON array, or NULL when
+// it has none
+func encodeModifiers(modifiers []string) (sql.NullString, error) {
+	if len(modifiers) == 0 {
+		return sql.NullString{}, nil
+	}
+	raw, err := json.Marshal(modifiers)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(raw), Valid: true}, nil
+}
+
+func decodeModifiers(raw sql.NullString) ([]string, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+	var modifiers []string
+	if err := json.Unmarshal([]byte(raw.String), &modifiers); err != nil {
+		return nil, err
+	}
+	return modifiers, nil
+}
@@ -0,0 +1,133 @@
+package gateways
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type outboxGateway struct {
+	db *sql.DB
+}
+
+func NewOutboxGateway(db *sql.DB) output.OutboxGateway {
+	return &outboxGateway{
+		db: db,
+	}
+}
+
+func (g *outboxGateway) Append(ctx context.Context, event *entities.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		event.EventType,
+		event.AggregateType,
+		event.AggregateID,
+		event.Payload,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.CreatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	event.ID = uint64(id)
+	return nil
+}
+
+func (g *outboxGateway) ListPending(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, published_at, created_at
+		FROM outbox_events
+		WHERE published_at IS NULL AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entities.OutboxEvent
+	for rows.Next() {
+		var event entities.OutboxEvent
+		var publishedAt sql.NullTime
+
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.Payload,
+			&event.Attempts,
+			&event.LastError,
+			&event.NextAttemptAt,
+			&publishedAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if publishedAt.Valid {
+			event.PublishedAt = &publishedAt.Time
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+func (g *outboxGateway) Claim(ctx context.Context, event *entities.OutboxEvent, now, until time.Time) (bool, error) {
+	// The conditions are checked again on the locked row, so only one of
+	// two concurrent claims updates it
+	query := `UPDATE outbox_events SET next_attempt_at = ? WHERE id = ? AND published_at IS NULL AND next_attempt_at <= ?`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, until, event.ID, now)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	event.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *outboxGateway) Update(ctx context.Context, event *entities.OutboxEvent) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = ?, last_error = ?, next_attempt_at = ?, published_at = ?
+		WHERE id = ?
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.PublishedAt,
+		event.ID,
+	)
+
+	return err
+}
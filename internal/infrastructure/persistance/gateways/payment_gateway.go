@@ -1,12 +1,14 @@
 package gateways
 
 import (
+	"context"
 	"database/sql"
 	"strconv"
 	"time"
 
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
 )
 
 type paymentGateway struct {
@@ -19,18 +21,20 @@ func NewPaymentGateway(db *sql.DB) output.PaymentGateway {
 	}
 }
 
-func (g *paymentGateway) Create(payment *entities.Payment) error {
+func (g *paymentGateway) Create(ctx context.Context, payment *entities.Payment) error {
 	query := `
-		INSERT INTO payments (order_id, amount, status, payment_method, transaction_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO payments (order_id, amount, status, payment_method, payer, transaction_id, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := g.db.Exec(query,
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		payment.OrderID,
 		payment.Amount,
 		string(payment.Status),
 		payment.PaymentMethod,
+		payment.Payer,
 		payment.TransactionID,
+		payment.ExpiresAt,
 		payment.CreatedAt,
 		payment.UpdatedAt,
 	)
@@ -48,113 +52,144 @@ func (g *paymentGateway) Create(payment *entities.Payment) error {
 	return nil
 }
 
-func (g *paymentGateway) GetByID(id uint64) (*entities.Payment, error) {
+func (g *paymentGateway) GetByID(ctx context.Context, id uint64) (*entities.Payment, error) {
 	query := `
-		SELECT id, order_id, amount, status, payment_method, transaction_id, created_at, updated_at
+		SELECT ` + paymentColumns + `
 		FROM payments
 		WHERE id = ?
 	`
 
-	row := g.db.QueryRow(query, id)
+	return g.getOne(ctx, query, id)
+}
 
-	var payment entities.Payment
-	var amount string
-	var status string
-	var createdAt, updatedAt string
+func (g *paymentGateway) GetLatestByOrderID(ctx context.Context, orderID uint64) (*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE order_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`
 
-	err := row.Scan(
-		&payment.ID,
-		&payment.OrderID,
-		&amount,
-		&status,
-		&payment.PaymentMethod,
-		&payment.TransactionID,
-		&createdAt,
-		&updatedAt,
-	)
+	return g.getOne(ctx, query, orderID)
+}
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
+func (g *paymentGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE order_id = ?
+		ORDER BY id
+	`
 
-	// Parse amount
-	if amountFloat, err := strconv.ParseFloat(amount, 32); err == nil {
-		payment.Amount = float32(amountFloat)
-	}
+	return g.list(ctx, query, orderID)
+}
 
-	// Parse status
-	payment.Status = entities.PaymentStatus(status)
+func (g *paymentGateway) GetByTransactionID(ctx context.Context, transactionID string) (*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE transaction_id = ?
+	`
 
-	// Parse timestamps
-	payment.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	payment.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	return g.getOne(ctx, query, transactionID)
+}
 
-	return &payment, nil
+func (g *paymentGateway) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = ? AND expires_at <= ?
+		ORDER BY expires_at, id
+		LIMIT ?
+	`
+
+	return g.list(ctx, query, string(entities.PaymentStatusPending), now, limit)
 }
 
-func (g *paymentGateway) GetByOrderID(orderID uint64) (*entities.Payment, error) {
+func (g *paymentGateway) ListPendingCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Payment, error) {
 	query := `
-		SELECT id, order_id, amount, status, payment_method, transaction_id, created_at, updated_at
+		SELECT ` + paymentColumns + `
 		FROM payments
-		WHERE order_id = ?
+		WHERE status = ? AND created_at < ?
+		ORDER BY created_at, id
+		LIMIT ?
 	`
 
-	row := g.db.QueryRow(query, orderID)
+	return g.list(ctx, query, string(entities.PaymentStatusPending), before, limit)
+}
 
-	var payment entities.Payment
-	var amount string
-	var status string
-	var createdAt, updatedAt string
+func (g *paymentGateway) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE created_at >= ? AND created_at < ?
+		ORDER BY id
+	`
 
-	err := row.Scan(
-		&payment.ID,
-		&payment.OrderID,
-		&amount,
-		&status,
-		&payment.PaymentMethod,
-		&payment.TransactionID,
-		&createdAt,
-		&updatedAt,
+	return g.list(ctx, query, from, to)
+}
+
+func (g *paymentGateway) Update(ctx context.Context, payment *entities.Payment) error {
+	query := `
+		UPDATE payments
+		SET amount = ?, status = ?, payment_method = ?, transaction_id = ?, expires_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		payment.Amount,
+		string(payment.Status),
+		payment.PaymentMethod,
+		payment.TransactionID,
+		payment.ExpiresAt,
+		payment.UpdatedAt,
+		payment.ID,
 	)
 
+	return err
+}
+
+func (g *paymentGateway) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM payments WHERE id = ?`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (g *paymentGateway) getOne(ctx context.Context, query string, arg any) (*entities.Payment, error) {
+	payment, err := scanPayment(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return payment, err
+}
+
+func (g *paymentGateway) list(ctx context.Context, query string, args ...any) ([]*entities.Payment, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Parse amount
-	if amountFloat, err := strconv.ParseFloat(amount, 32); err == nil {
-		payment.Amount = float32(amountFloat)
+	var payments []*entities.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
 	}
 
-	// Parse status
-	payment.Status = entities.PaymentStatus(status)
-
-	// Parse timestamps
-	payment.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	payment.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
-	return &payment, nil
+	return payments, rows.Err()
 }
 
-func (g *paymentGateway) GetByTransactionID(transactionID string) (*entities.Payment, error) {
-	query := `
-		SELECT id, order_id, amount, status, payment_method, transaction_id, created_at, updated_at
-		FROM payments
-		WHERE transaction_id = ?
-	`
-
-	row := g.db.QueryRow(query, transactionID)
+const paymentColumns = `id, order_id, amount, status, payment_method, payer, transaction_id, expires_at, created_at, updated_at`
 
+func scanPayment(row interface{ Scan(...any) error }) (*entities.Payment, error) {
 	var payment entities.Payment
 	var amount string
 	var status string
-	var createdAt, updatedAt string
+	var expiresAt sql.NullTime
 
 	err := row.Scan(
 		&payment.ID,
@@ -162,15 +197,13 @@ func (g *paymentGateway) GetByTransactionID(transactionID string) (*entities.Pay
 		&amount,
 		&status,
 		&payment.PaymentMethod,
+		&payment.Payer,
 		&payment.TransactionID,
-		&createdAt,
-		&updatedAt,
+		&expiresAt,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
 
@@ -182,34 +215,8 @@ func (g *paymentGateway) GetByTransactionID(transactionID string) (*entities.Pay
 	// Parse status
 	payment.Status = entities.PaymentStatus(status)
 
-	// Parse timestamps
-	payment.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	payment.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
+	if expiresAt.Valid {
+		payment.ExpiresAt = &expiresAt.Time
+	}
 	return &payment, nil
 }
-
-func (g *paymentGateway) Update(payment *entities.Payment) error {
-	query := `
-		UPDATE payments
-		SET amount = ?, status = ?, payment_method = ?, transaction_id = ?, updated_at = ?
-		WHERE id = ?
-	`
-
-	_, err := g.db.Exec(query,
-		payment.Amount,
-		string(payment.Status),
-		payment.PaymentMethod,
-		payment.TransactionID,
-		payment.UpdatedAt,
-		payment.ID,
-	)
-
-	return err
-}
-
-func (g *paymentGateway) Delete(id uint64) error {
-	query := `DELETE FROM payments WHERE id = ?`
-	_, err := g.db.Exec(query, id)
-	return err
-}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type customerGateway struct {
+	db *sql.DB
+}
+
+func NewCustomerGateway(db *sql.DB) output.CustomerGateway {
+	return &customerGateway{
+		db: db,
+	}
+}
+
+func (g *customerGateway) Create(ctx context.Context, customer *entities.Customer) error {
+	query := `
+		INSERT INTO customers (first_name, last_name, cpf, email, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		customer.FirstName,
+		customer.LastName,
+		customer.CPF,
+		customer.Email,
+		customer.CreatedAt,
+		customer.UpdatedAt,
+	).Scan(&customer.ID)
+}
+
+func (g *customerGateway) GetByCPF(ctx context.Context, cpf string) (*entities.Customer, error) {
+	query := `
+		SELECT id, first_name, last_name, cpf, email, created_at, updated_at
+		FROM customers
+		WHERE cpf = $1
+	`
+
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, cpf)
+
+	var customer entities.Customer
+
+	err := row.Scan(
+		&customer.ID,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.CPF,
+		&customer.Email,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+func (g *customerGateway) GetByID(ctx context.Context, id uint64) (*entities.Customer, error) {
+	return g.getByID(ctx, id, "")
+}
+
+func (g *customerGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Customer, error) {
+	return g.getByID(ctx, id, "FOR UPDATE")
+}
+
+func (g *customerGateway) getByID(ctx context.Context, id uint64, lock string) (*entities.Customer, error) {
+	query := `
+		SELECT id, first_name, last_name, cpf, email, created_at, updated_at
+		FROM customers
+		WHERE id = $1
+	` + lock
+
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id)
+
+	var customer entities.Customer
+
+	err := row.Scan(
+		&customer.ID,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.CPF,
+		&customer.Email,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+func (g *customerGateway) Update(ctx context.Context, customer *entities.Customer) error {
+	query := `
+		UPDATE customers
+		SET first_name = $1, last_name = $2, email = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		customer.FirstName,
+		customer.LastName,
+		customer.Email,
+		customer.UpdatedAt,
+		customer.ID,
+	)
+
+	return err
+}
+
+func (g *customerGateway) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM customers WHERE id = $1`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
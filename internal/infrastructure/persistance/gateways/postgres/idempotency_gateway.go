@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type idempotencyGateway struct {
+	db *sql.DB
+}
+
+func NewIdempotencyGateway(db *sql.DB) output.IdempotencyGateway {
+	return &idempotencyGateway{
+		db: db,
+	}
+}
+
+func (g *idempotencyGateway) Reserve(ctx context.Context, record *entities.IdempotencyRecord) (*entities.IdempotencyRecord, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, status_code, content_type, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (scope, idempotency_key) DO NOTHING
+	`
+
+	// The existing row may be released between the skipped insert and the
+	// lookup, so try once more before giving up
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+			record.Scope,
+			record.Key,
+			record.RequestHash,
+			record.StatusCode,
+			record.ContentType,
+			record.Body,
+			record.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if inserted == 1 {
+			return nil, nil
+		}
+
+		existing, err := g.get(ctx, record.Scope, record.Key)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	return nil, fmt.Errorf("idempotency key %q was released while being reserved", record.Key)
+}
+
+func (g *idempotencyGateway) get(ctx context.Context, scope, key string) (*entities.IdempotencyRecord, error) {
+	query := `
+		SELECT scope, idempotency_key, request_hash, status_code, content_type, body, created_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`
+
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, scope, key)
+
+	var record entities.IdempotencyRecord
+	err := row.Scan(
+		&record.Scope,
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.Body,
+		&record.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (g *idempotencyGateway) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, body = $3
+		WHERE scope = $4 AND idempotency_key = $5
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		record.StatusCode,
+		record.ContentType,
+		record.Body,
+		record.Scope,
+		record.Key,
+	)
+
+	return err
+}
+
+func (g *idempotencyGateway) Release(ctx context.Context, scope, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, scope, key)
+	return err
+}
+
+func (g *idempotencyGateway) DeleteCreatedBefore(ctx context.Context, t time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, t)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
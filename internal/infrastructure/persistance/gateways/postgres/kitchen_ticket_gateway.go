@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+const kitchenTicketColumns = `id, order_id, copy_number, status, attempts, last_error, next_attempt_at, printed_at`
+
+type kitchenTicketGateway struct {
+	db *sql.DB
+}
+
+func NewKitchenTicketGateway(db *sql.DB) output.KitchenTicketGateway {
+	return &kitchenTicketGateway{
+		db: db,
+	}
+}
+
+func (g *kitchenTicketGateway) Create(ctx context.Context, ticket *entities.KitchenTicket) (bool, error) {
+	query := `
+		INSERT INTO kitchen_tickets (order_id, copy_number, status, attempts, last_error, next_attempt_at, printed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (order_id, copy_number) DO NOTHING
+		RETURNING id
+	`
+
+	err := sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		ticket.OrderID,
+		ticket.Copy,
+		ticket.Status,
+		ticket.Attempts,
+		ticket.LastError,
+		ticket.NextAttemptAt,
+		ticket.PrintedAt,
+	).Scan(&ticket.ID)
+
+	// No row comes back when the copy was already recorded
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (g *kitchenTicketGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.KitchenTicket, error) {
+	query := `SELECT ` + kitchenTicketColumns + ` FROM kitchen_tickets WHERE order_id = $1 ORDER BY copy_number`
+
+	return g.query(ctx, query, orderID)
+}
+
+func (g *kitchenTicketGateway) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KitchenTicket, error) {
+	query := `SELECT ` + kitchenTicketColumns + ` FROM kitchen_tickets WHERE status = $1 AND next_attempt_at <= $2 ORDER BY id LIMIT $3`
+
+	return g.query(ctx, query, entities.KitchenTicketPending, now, limit)
+}
+
+func (g *kitchenTicketGateway) Claim(ctx context.Context, ticket *entities.KitchenTicket, now, until time.Time) (bool, error) {
+	// The conditions are checked again on the locked row, so only one of
+	// two concurrent claims updates it
+	query := `UPDATE kitchen_tickets SET next_attempt_at = $1 WHERE id = $2 AND status = $3 AND next_attempt_at <= $4`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, until, ticket.ID, entities.KitchenTicketPending, now)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	ticket.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *kitchenTicketGateway) Update(ctx context.Context, ticket *entities.KitchenTicket) error {
+	query := `
+		UPDATE kitchen_tickets
+		SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4, printed_at = $5
+		WHERE id = $6
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		ticket.Status,
+		ticket.Attempts,
+		ticket.LastError,
+		ticket.NextAttemptAt,
+		ticket.PrintedAt,
+		ticket.ID,
+	)
+
+	return err
+}
+
+func (g *kitchenTicketGateway) query(ctx context.Context, query string, args ...any) ([]*entities.KitchenTicket, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []*entities.KitchenTicket
+	for rows.Next() {
+		var ticket entities.KitchenTicket
+		var printedAt sql.NullTime
+		err := rows.Scan(
+			&ticket.ID,
+			&ticket.OrderID,
+			&ticket.Copy,
+			&ticket.Status,
+			&ticket.Attempts,
+			&ticket.LastError,
+			&ticket.NextAttemptAt,
+			&printedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if printedAt.Valid {
+			ticket.PrintedAt = &printedAt.Time
+		}
+		tickets = append(tickets, &ticket)
+	}
+
+	return tickets, rows.Err()
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type loyaltyGateway struct {
+	db *sql.DB
+}
+
+func NewLoyaltyGateway(db *sql.DB) output.LoyaltyGateway {
+	return &loyaltyGateway{
+		db: db,
+	}
+}
+
+func (g *loyaltyGateway) Create(ctx context.Context, entry *entities.LoyaltyEntry) error {
+	query := `
+		INSERT INTO loyalty_entries (customer_id, order_id, payment_id, type, points, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		entry.CustomerID,
+		entry.OrderID,
+		entry.PaymentID,
+		string(entry.Type),
+		entry.Points,
+		entry.ExpiresAt,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+}
+
+func (g *loyaltyGateway) ListByCustomerID(ctx context.Context, customerID uint64) ([]*entities.LoyaltyEntry, error) {
+	query := `
+		SELECT ` + loyaltyEntryColumns + `
+		FROM loyalty_entries
+		WHERE customer_id = $1
+		ORDER BY id
+	`
+
+	return g.list(ctx, query, customerID)
+}
+
+func (g *loyaltyGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.LoyaltyEntry, error) {
+	query := `
+		SELECT ` + loyaltyEntryColumns + `
+		FROM loyalty_entries
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	return g.list(ctx, query, orderID)
+}
+
+func (g *loyaltyGateway) list(ctx context.Context, query string, args ...any) ([]*entities.LoyaltyEntry, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entities.LoyaltyEntry
+	for rows.Next() {
+		entry, err := scanLoyaltyEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+const loyaltyEntryColumns = `id, customer_id, order_id, payment_id, type, points, expires_at, created_at`
+
+func scanLoyaltyEntry(row rowScanner) (*entities.LoyaltyEntry, error) {
+	var entry entities.LoyaltyEntry
+	var entryType string
+	var expiresAt sql.NullTime
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.CustomerID,
+		&entry.OrderID,
+		&entry.PaymentID,
+		&entryType,
+		&entry.Points,
+		&expiresAt,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Type = entities.LoyaltyEntryType(entryType)
+	if expiresAt.Valid {
+		entry.ExpiresAt = &expiresAt.Time
+	}
+
+	return &entry, nil
+}
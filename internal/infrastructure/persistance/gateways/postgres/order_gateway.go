@@ -0,0 +1,369 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type orderGateway struct {
+	db *sql.DB
+}
+
+func NewOrderGateway(db *sql.DB) output.OrderGateway {
+	return &orderGateway{
+		db: db,
+	}
+}
+
+func (g *orderGateway) Create(ctx context.Context, order *entities.Order) error {
+	// customer_id is a real foreign key in PostgreSQL, so anonymous orders
+	// (customer 0) are stored as NULL.
+	query := `
+		INSERT INTO orders (customer_id, cpf, status, discount, service_fee, rounding, created_at, updated_at)
+		VALUES (NULLIF($1::BIGINT, 0), $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		int64(order.CustomerId),
+		order.CPF,
+		string(order.Status),
+		order.Discount,
+		order.ServiceFee,
+		order.Rounding,
+		order.CreatedAt,
+		order.UpdatedAt,
+	).Scan(&order.ID)
+}
+
+func (g *orderGateway) GetByID(ctx context.Context, id uint64) (*entities.Order, error) {
+	return g.getByID(ctx, id, "")
+}
+
+func (g *orderGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Order, error) {
+	return g.getByID(ctx, id, "FOR UPDATE")
+}
+
+func (g *orderGateway) getByID(ctx context.Context, id uint64, lock string) (*entities.Order, error) {
+	query := `
+		SELECT id, COALESCE(customer_id, 0), COALESCE(cpf, ''), status, discount, service_fee, rounding, created_at, updated_at
+		FROM orders
+		WHERE id = $1
+	` + lock
+
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id)
+
+	order, err := scanOrder(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (g *orderGateway) GetByCPF(ctx context.Context, cpf string) ([]*entities.Order, error) {
+	query := `
+		SELECT id, COALESCE(customer_id, 0), COALESCE(cpf, ''), status, discount, service_fee, rounding, created_at, updated_at
+		FROM orders
+		WHERE cpf = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, cpf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+func (g *orderGateway) GetByCustomerID(ctx context.Context, customerID uint64) ([]*entities.Order, error) {
+	query := `
+		SELECT id, COALESCE(customer_id, 0), COALESCE(cpf, ''), status, discount, service_fee, rounding, created_at, updated_at
+		FROM orders
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+func (g *orderGateway) GetAll(ctx context.Context) ([]*entities.Order, error) {
+	query := `
+		SELECT id, COALESCE(customer_id, 0), COALESCE(cpf, ''), status, discount, service_fee, rounding, created_at, updated_at
+		FROM orders
+		ORDER BY created_at DESC
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+func (g *orderGateway) GetPendingOrdersForKitchen(ctx context.Context) ([]*entities.Order, error) {
+	query := `
+		SELECT id, COALESCE(customer_id, 0), COALESCE(cpf, ''), status, discount, service_fee, rounding, created_at, updated_at
+		FROM orders
+		WHERE status IN ('received', 'in_progress', 'ready')
+		ORDER BY
+			CASE status
+				WHEN 'ready' THEN 1
+				WHEN 'in_progress' THEN 2
+				WHEN 'received' THEN 3
+			END,
+			created_at ASC
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+func (g *orderGateway) Update(ctx context.Context, order *entities.Order) error {
+	query := `
+		UPDATE orders
+		SET status = $1, updated_at = $2
+		WHERE id = $3
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		string(order.Status),
+		order.UpdatedAt,
+		order.ID,
+	)
+
+	return err
+}
+
+func (g *orderGateway) Delete(ctx context.Context, id uint64) error {
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM order_items WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM order_discounts WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM order_taxes WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM payments WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM loyalty_entries WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM receipts WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM kitchen_tickets WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM station_tickets WHERE order_id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM orders WHERE id = $1`
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOrder(row rowScanner) (*entities.Order, error) {
+	var order entities.Order
+	var status string
+
+	err := row.Scan(
+		&order.ID,
+		&order.CustomerId,
+		&order.CPF,
+		&status,
+		&order.Discount,
+		&order.ServiceFee,
+		&order.Rounding,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status = entities.OrderStatus(status)
+
+	return &order, nil
+}
+
+func scanOrders(rows *sql.Rows) ([]*entities.Order, error) {
+	var orders []*entities.Order
+
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+type orderItemGateway struct {
+	db *sql.DB
+}
+
+func NewOrderItemGateway(db *sql.DB) output.OrderItemGateway {
+	return &orderItemGateway{
+		db: db,
+	}
+}
+
+func (g *orderItemGateway) Create(ctx context.Context, orderItem *entities.OrderItem) error {
+	query := `
+		INSERT INTO order_items (order_id, product_id, quantity, price, modifiers, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	modifiers, err := encodeModifiers(orderItem.Modifiers)
+	if err != nil {
+		return err
+	}
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		orderItem.OrderID,
+		orderItem.ProductID,
+		orderItem.Quantity,
+		orderItem.Price,
+		modifiers,
+		orderItem.Notes,
+		orderItem.CreatedAt,
+		orderItem.UpdatedAt,
+	).Scan(&orderItem.ID)
+}
+
+func (g *orderItemGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderItem, error) {
+	query := `
+		SELECT id, order_id, product_id, quantity, price, modifiers, notes, created_at, updated_at
+		FROM order_items
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*entities.OrderItem
+
+	for rows.Next() {
+		var item entities.OrderItem
+		var modifiers sql.NullString
+
+		err := rows.Scan(
+			&item.ID,
+			&item.OrderID,
+			&item.ProductID,
+			&item.Quantity,
+			&item.Price,
+			&modifiers,
+			&item.Notes,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		if item.Modifiers, err = decodeModifiers(modifiers); err != nil {
+			return nil, err
+		}
+
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+func (g *orderItemGateway) Update(ctx context.Context, orderItem *entities.OrderItem) error {
+	query := `
+		UPDATE order_items
+		SET quantity = $1, price = $2, updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		orderItem.Quantity,
+		orderItem.Price,
+		orderItem.UpdatedAt,
+		orderItem.ID,
+	)
+
+	return err
+}
+
+func (g *orderItemGateway) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM order_items WHERE id = $1`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+// encodeModifiers stores an item's modifiers as a JSON array, or NULL when
+// it has none
+func encodeModifiers(modifiers []string) (sql.NullString, error) {
+	if len(modifiers) == 0 {
+		return sql.NullString{}, nil
+	}
+	raw, err := json.Marshal(modifiers)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(raw), Valid: true}, nil
+}
+
+func decodeModifiers(raw sql.NullString) ([]string, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+	var modifiers []string
+	if err := json.Unmarshal([]byte(raw.String), &modifiers); err != nil {
+		return nil, err
+	}
+	return modifiers, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type orderTaxGateway struct {
+	db *sql.DB
+}
+
+func NewOrderTaxGateway(db *sql.DB) output.OrderTaxGateway {
+	return &orderTaxGateway{
+		db: db,
+	}
+}
+
+func (g *orderTaxGateway) Create(ctx context.Context, tax *entities.OrderTax) error {
+	query := `
+		INSERT INTO order_taxes (order_id, category, rate, base, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		tax.OrderID,
+		tax.Category,
+		tax.Rate,
+		tax.Base,
+		tax.Amount,
+	).Scan(&tax.ID)
+}
+
+func (g *orderTaxGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderTax, error) {
+	query := `
+		SELECT id, order_id, category, rate, base, amount
+		FROM order_taxes
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxes []*entities.OrderTax
+	for rows.Next() {
+		var tax entities.OrderTax
+		err := rows.Scan(
+			&tax.ID,
+			&tax.OrderID,
+			&tax.Category,
+			&tax.Rate,
+			&tax.Base,
+			&tax.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, &tax)
+	}
+
+	return taxes, rows.Err()
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type outboxGateway struct {
+	db *sql.DB
+}
+
+func NewOutboxGateway(db *sql.DB) output.OutboxGateway {
+	return &outboxGateway{
+		db: db,
+	}
+}
+
+func (g *outboxGateway) Append(ctx context.Context, event *entities.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		event.EventType,
+		event.AggregateType,
+		event.AggregateID,
+		event.Payload,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.CreatedAt,
+	).Scan(&event.ID)
+}
+
+func (g *outboxGateway) ListPending(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, published_at, created_at
+		FROM outbox_events
+		WHERE published_at IS NULL AND next_attempt_at <= $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entities.OutboxEvent
+	for rows.Next() {
+		var event entities.OutboxEvent
+		var publishedAt sql.NullTime
+
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.Payload,
+			&event.Attempts,
+			&event.LastError,
+			&event.NextAttemptAt,
+			&publishedAt,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if publishedAt.Valid {
+			event.PublishedAt = &publishedAt.Time
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+func (g *outboxGateway) Claim(ctx context.Context, event *entities.OutboxEvent, now, until time.Time) (bool, error) {
+	// The conditions are checked again on the locked row, so only one of
+	// two concurrent claims updates it
+	query := `UPDATE outbox_events SET next_attempt_at = $1 WHERE id = $2 AND published_at IS NULL AND next_attempt_at <= $3`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, until, event.ID, now)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	event.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *outboxGateway) Update(ctx context.Context, event *entities.OutboxEvent) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = $1, last_error = $2, next_attempt_at = $3, published_at = $4
+		WHERE id = $5
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.PublishedAt,
+		event.ID,
+	)
+
+	return err
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type paymentGateway struct {
+	db *sql.DB
+}
+
+func NewPaymentGateway(db *sql.DB) output.PaymentGateway {
+	return &paymentGateway{
+		db: db,
+	}
+}
+
+func (g *paymentGateway) Create(ctx context.Context, payment *entities.Payment) error {
+	query := `
+		INSERT INTO payments (order_id, amount, status, payment_method, payer, transaction_id, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		payment.OrderID,
+		payment.Amount,
+		string(payment.Status),
+		payment.PaymentMethod,
+		payment.Payer,
+		payment.TransactionID,
+		payment.ExpiresAt,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+	).Scan(&payment.ID)
+}
+
+func (g *paymentGateway) GetByID(ctx context.Context, id uint64) (*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE id = $1
+	`
+
+	return g.getOne(ctx, query, id)
+}
+
+func (g *paymentGateway) GetLatestByOrderID(ctx context.Context, orderID uint64) (*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE order_id = $1
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	return g.getOne(ctx, query, orderID)
+}
+
+func (g *paymentGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	return g.list(ctx, query, orderID)
+}
+
+func (g *paymentGateway) GetByTransactionID(ctx context.Context, transactionID string) (*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE transaction_id = $1
+	`
+
+	return g.getOne(ctx, query, transactionID)
+}
+
+func (g *paymentGateway) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = $1 AND expires_at <= $2
+		ORDER BY expires_at, id
+		LIMIT $3
+	`
+
+	return g.list(ctx, query, string(entities.PaymentStatusPending), now, limit)
+}
+
+func (g *paymentGateway) ListPendingCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = $1 AND created_at < $2
+		ORDER BY created_at, id
+		LIMIT $3
+	`
+
+	return g.list(ctx, query, string(entities.PaymentStatusPending), before, limit)
+}
+
+func (g *paymentGateway) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*entities.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY id
+	`
+
+	return g.list(ctx, query, from, to)
+}
+
+func (g *paymentGateway) Update(ctx context.Context, payment *entities.Payment) error {
+	query := `
+		UPDATE payments
+		SET amount = $1, status = $2, payment_method = $3, transaction_id = $4, expires_at = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		payment.Amount,
+		string(payment.Status),
+		payment.PaymentMethod,
+		payment.TransactionID,
+		payment.ExpiresAt,
+		payment.UpdatedAt,
+		payment.ID,
+	)
+
+	return err
+}
+
+func (g *paymentGateway) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM payments WHERE id = $1`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (g *paymentGateway) getOne(ctx context.Context, query string, arg any) (*entities.Payment, error) {
+	payment, err := scanPayment(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return payment, err
+}
+
+func (g *paymentGateway) list(ctx context.Context, query string, args ...any) ([]*entities.Payment, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*entities.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+const paymentColumns = `id, order_id, amount, status, payment_method, payer, transaction_id, expires_at, created_at, updated_at`
+
+func scanPayment(row rowScanner) (*entities.Payment, error) {
+	var payment entities.Payment
+	var status string
+	var expiresAt sql.NullTime
+
+	err := row.Scan(
+		&payment.ID,
+		&payment.OrderID,
+		&payment.Amount,
+		&status,
+		&payment.PaymentMethod,
+		&payment.Payer,
+		&payment.TransactionID,
+		&expiresAt,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.Status = entities.PaymentStatus(status)
+	if expiresAt.Valid {
+		payment.ExpiresAt = &expiresAt.Time
+	}
+	return &payment, nil
+}
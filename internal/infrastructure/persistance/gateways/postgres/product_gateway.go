@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type productGateway struct {
+	db *sql.DB
+}
+
+func NewProductGateway(db *sql.DB) output.ProductGateway {
+	return &productGateway{
+		db: db,
+	}
+}
+
+func (g *productGateway) Create(ctx context.Context, product *entities.Product) error {
+	query := `
+		INSERT INTO products (name, description, price, category, image_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.Category,
+		product.ImageUrl,
+		product.CreatedAt,
+		product.UpdatedAt,
+	).Scan(&product.ID)
+}
+
+func (g *productGateway) GetByID(ctx context.Context, id uint64) (*entities.Product, error) {
+	query := `
+		SELECT id, name, description, price, category, image_url, created_at, updated_at
+		FROM products
+		WHERE id = $1
+	`
+
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id)
+
+	var product entities.Product
+
+	err := row.Scan(
+		&product.ID,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.Category,
+		&product.ImageUrl,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+func (g *productGateway) GetByCategory(ctx context.Context, category string) ([]*entities.Product, error) {
+	query := `
+		SELECT id, name, description, price, category, image_url, created_at, updated_at
+		FROM products
+		WHERE category = $1
+		ORDER BY name
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanProducts(rows)
+}
+
+// GetAll follows the menu order that the MySQL ENUM column gives for free
+func (g *productGateway) GetAll(ctx context.Context) ([]*entities.Product, error) {
+	query := `
+		SELECT id, name, description, price, category, image_url, created_at, updated_at
+		FROM products
+		ORDER BY
+			CASE category
+				WHEN 'snack' THEN 1
+				WHEN 'side' THEN 2
+				WHEN 'drink' THEN 3
+				WHEN 'dessert' THEN 4
+			END,
+			name
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanProducts(rows)
+}
+
+func (g *productGateway) Update(ctx context.Context, product *entities.Product) error {
+	query := `
+		UPDATE products
+		SET name = $1, description = $2, price = $3, category = $4, image_url = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.Category,
+		product.ImageUrl,
+		product.UpdatedAt,
+		product.ID,
+	)
+
+	return err
+}
+
+func (g *productGateway) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM products WHERE id = $1`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func scanProducts(rows *sql.Rows) ([]*entities.Product, error) {
+	var products []*entities.Product
+
+	for rows.Next() {
+		var product entities.Product
+
+		err := rows.Scan(
+			&product.ID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.Category,
+			&product.ImageUrl,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, &product)
+	}
+
+	return products, rows.Err()
+}
@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type promotionGateway struct {
+	db *sql.DB
+}
+
+func NewPromotionGateway(db *sql.DB) output.PromotionGateway {
+	return &promotionGateway{
+		db: db,
+	}
+}
+
+func (g *promotionGateway) Create(ctx context.Context, promotion *entities.Promotion) error {
+	query := `
+		INSERT INTO promotions (name, code, type, value, buy_quantity, free_quantity, category, starts_at, ends_at,
+			weekdays, daily_start, daily_end, max_uses_per_customer, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		promotion.Name,
+		promotion.Code,
+		string(promotion.Type),
+		promotion.Value,
+		promotion.BuyQuantity,
+		promotion.FreeQuantity,
+		promotion.Category,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		joinWeekdays(promotion.Weekdays),
+		promotion.DailyStart,
+		promotion.DailyEnd,
+		promotion.MaxUsesPerCustomer,
+		promotion.Active,
+		promotion.CreatedAt,
+		promotion.UpdatedAt,
+	).Scan(&promotion.ID)
+}
+
+func (g *promotionGateway) GetByID(ctx context.Context, id uint64) (*entities.Promotion, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE id = $1
+	`
+
+	return g.getOne(ctx, query, id)
+}
+
+func (g *promotionGateway) GetByIDForUpdate(ctx context.Context, id uint64) (*entities.Promotion, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	return g.getOne(ctx, query, id)
+}
+
+func (g *promotionGateway) GetByCode(ctx context.Context, code string) (*entities.Promotion, error) {
+	if code == "" {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE code = $1
+		ORDER BY id
+		LIMIT 1
+	`
+
+	return g.getOne(ctx, query, code)
+}
+
+func (g *promotionGateway) GetAll(ctx context.Context) ([]*entities.Promotion, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		ORDER BY id
+	`
+
+	return g.list(ctx, query)
+}
+
+func (g *promotionGateway) ListAutomatic(ctx context.Context) ([]*entities.Promotion, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE active = TRUE AND code = ''
+		ORDER BY id
+	`
+
+	return g.list(ctx, query)
+}
+
+func (g *promotionGateway) Update(ctx context.Context, promotion *entities.Promotion) error {
+	query := `
+		UPDATE promotions
+		SET name = $1, code = $2, type = $3, value = $4, buy_quantity = $5, free_quantity = $6, category = $7,
+			starts_at = $8, ends_at = $9, weekdays = $10, daily_start = $11, daily_end = $12,
+			max_uses_per_customer = $13, active = $14, updated_at = $15
+		WHERE id = $16
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		promotion.Name,
+		promotion.Code,
+		string(promotion.Type),
+		promotion.Value,
+		promotion.BuyQuantity,
+		promotion.FreeQuantity,
+		promotion.Category,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		joinWeekdays(promotion.Weekdays),
+		promotion.DailyStart,
+		promotion.DailyEnd,
+		promotion.MaxUsesPerCustomer,
+		promotion.Active,
+		promotion.UpdatedAt,
+		promotion.ID,
+	)
+
+	return err
+}
+
+func (g *promotionGateway) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM promotions WHERE id = $1`
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (g *promotionGateway) getOne(ctx context.Context, query string, args ...any) (*entities.Promotion, error) {
+	promotion, err := scanPromotion(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return promotion, err
+}
+
+func (g *promotionGateway) list(ctx context.Context, query string, args ...any) ([]*entities.Promotion, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []*entities.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+const promotionColumns = `id, name, code, type, value, buy_quantity, free_quantity, category, starts_at, ends_at,
+			weekdays, daily_start, daily_end, max_uses_per_customer, active, created_at, updated_at`
+
+func scanPromotion(row rowScanner) (*entities.Promotion, error) {
+	var promotion entities.Promotion
+	var promotionType string
+	var startsAt, endsAt sql.NullTime
+	var weekdays string
+
+	err := row.Scan(
+		&promotion.ID,
+		&promotion.Name,
+		&promotion.Code,
+		&promotionType,
+		&promotion.Value,
+		&promotion.BuyQuantity,
+		&promotion.FreeQuantity,
+		&promotion.Category,
+		&startsAt,
+		&endsAt,
+		&weekdays,
+		&promotion.DailyStart,
+		&promotion.DailyEnd,
+		&promotion.MaxUsesPerCustomer,
+		&promotion.Active,
+		&promotion.CreatedAt,
+		&promotion.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.Type = entities.PromotionType(promotionType)
+	if startsAt.Valid {
+		promotion.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		promotion.EndsAt = &endsAt.Time
+	}
+	promotion.Weekdays = splitWeekdays(weekdays)
+
+	return &promotion, nil
+}
+
+// joinWeekdays stores weekdays as their numbers (0 is Sunday), comma
+// separated
+func joinWeekdays(weekdays []time.Weekday) string {
+	numbers := make([]string, len(weekdays))
+	for i, weekday := range weekdays {
+		numbers[i] = strconv.Itoa(int(weekday))
+	}
+	return strings.Join(numbers, ",")
+}
+
+func splitWeekdays(raw string) []time.Weekday {
+	var weekdays []time.Weekday
+	for _, number := range strings.Split(raw, ",") {
+		if n, err := strconv.Atoi(number); err == nil {
+			weekdays = append(weekdays, time.Weekday(n))
+		}
+	}
+	return weekdays
+}
+
+type orderDiscountGateway struct {
+	db *sql.DB
+}
+
+func NewOrderDiscountGateway(db *sql.DB) output.OrderDiscountGateway {
+	return &orderDiscountGateway{
+		db: db,
+	}
+}
+
+func (g *orderDiscountGateway) Create(ctx context.Context, discount *entities.OrderDiscount) error {
+	query := `
+		INSERT INTO order_discounts (order_id, promotion_id, code, description, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		discount.OrderID,
+		discount.PromotionID,
+		discount.Code,
+		discount.Description,
+		discount.Amount,
+	).Scan(&discount.ID)
+}
+
+func (g *orderDiscountGateway) GetByOrderID(ctx context.Context, orderID uint64) ([]*entities.OrderDiscount, error) {
+	query := `
+		SELECT id, order_id, promotion_id, code, description, amount
+		FROM order_discounts
+		WHERE order_id = $1
+		ORDER BY id
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []*entities.OrderDiscount
+	for rows.Next() {
+		var discount entities.OrderDiscount
+		err := rows.Scan(
+			&discount.ID,
+			&discount.OrderID,
+			&discount.PromotionID,
+			&discount.Code,
+			&discount.Description,
+			&discount.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, &discount)
+	}
+
+	return discounts, rows.Err()
+}
+
+func (g *orderDiscountGateway) CountUses(ctx context.Context, promotionID, customerID uint64, cpf string) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT d.order_id)
+		FROM order_discounts d
+		JOIN orders o ON o.id = d.order_id
+		WHERE d.promotion_id = $1 AND o.status <> 'cancelled'
+			AND (($2::BIGINT <> 0 AND o.customer_id = $2) OR ($3::TEXT <> '' AND o.cpf = $3))
+	`
+
+	var uses int
+	err := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, int64(promotionID), int64(customerID), cpf).Scan(&uses)
+	return uses, err
+}
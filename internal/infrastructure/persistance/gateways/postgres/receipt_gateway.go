@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type receiptGateway struct {
+	db *sql.DB
+}
+
+func NewReceiptGateway(db *sql.DB) output.ReceiptGateway {
+	return &receiptGateway{
+		db: db,
+	}
+}
+
+func (g *receiptGateway) Create(ctx context.Context, receipt *entities.Receipt) (bool, error) {
+	// The document keeps the whole receipt as JSON; the renditions are
+	// stored next to it as they were served
+	document, err := json.Marshal(receipt)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO receipts (order_id, document, xml, plain_text, issued_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id) DO NOTHING
+		RETURNING id
+	`
+
+	err = sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		receipt.OrderID,
+		document,
+		receipt.XML,
+		receipt.Text,
+		receipt.IssuedAt,
+	).Scan(&receipt.ID)
+
+	// No row comes back when the order already has a receipt
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (g *receiptGateway) GetByOrderID(ctx context.Context, orderID uint64) (*entities.Receipt, error) {
+	query := `
+		SELECT id, order_id, document, xml, plain_text, issued_at
+		FROM receipts
+		WHERE order_id = $1
+	`
+
+	receipt, err := scanReceipt(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, orderID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return receipt, err
+}
+
+func scanReceipt(row rowScanner) (*entities.Receipt, error) {
+	var (
+		receipt   entities.Receipt
+		document  []byte
+		id        uint64
+		orderID   uint64
+		xml, text []byte
+		issuedAt  time.Time
+	)
+
+	if err := row.Scan(&id, &orderID, &document, &xml, &text, &issuedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(document, &receipt); err != nil {
+		return nil, err
+	}
+
+	receipt.ID = id
+	receipt.OrderID = orderID
+	receipt.XML = xml
+	receipt.Text = text
+	receipt.IssuedAt = issuedAt
+	return &receipt, nil
+}
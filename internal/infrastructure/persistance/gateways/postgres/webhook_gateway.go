@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+// eventTypesSeparator joins a subscription's event types in one column
+const eventTypesSeparator = ","
+
+type webhookSubscriptionGateway struct {
+	db *sql.DB
+}
+
+func NewWebhookSubscriptionGateway(db *sql.DB) output.WebhookSubscriptionGateway {
+	return &webhookSubscriptionGateway{
+		db: db,
+	}
+}
+
+func (g *webhookSubscriptionGateway) Create(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	return sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		subscription.URL,
+		subscription.Secret,
+		strings.Join(subscription.EventTypes, eventTypesSeparator),
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	).Scan(&subscription.ID)
+}
+
+func (g *webhookSubscriptionGateway) GetByID(ctx context.Context, id uint64) (*entities.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription, err := scanWebhookSubscription(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return subscription, err
+}
+
+func (g *webhookSubscriptionGateway) GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*entities.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (g *webhookSubscriptionGateway) Delete(ctx context.Context, id uint64) error {
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE subscription_id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+	_, err = sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func scanWebhookSubscription(row interface{ Scan(...any) error }) (*entities.WebhookSubscription, error) {
+	var subscription entities.WebhookSubscription
+	var eventTypes string
+
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		&eventTypes,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.EventTypes = strings.Split(eventTypes, eventTypesSeparator)
+	return &subscription, nil
+}
+
+type webhookDeliveryGateway struct {
+	db *sql.DB
+}
+
+func NewWebhookDeliveryGateway(db *sql.DB) output.WebhookDeliveryGateway {
+	return &webhookDeliveryGateway{
+		db: db,
+	}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+func (g *webhookDeliveryGateway) Create(ctx context.Context, delivery *entities.WebhookDelivery) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+		RETURNING id
+	`
+
+	err := sqltx.From(ctx, g.db).QueryRowContext(ctx, query,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+		delivery.UpdatedAt,
+	).Scan(&delivery.ID)
+
+	// No row comes back when the delivery already exists
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (g *webhookDeliveryGateway) GetByID(ctx context.Context, id uint64) (*entities.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanWebhookDelivery(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return delivery, err
+}
+
+func (g *webhookDeliveryGateway) GetBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entities.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1 ORDER BY id DESC`
+
+	return g.query(ctx, query, subscriptionID)
+}
+
+func (g *webhookDeliveryGateway) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE status = $1 AND next_attempt_at <= $2 ORDER BY id LIMIT $3`
+
+	return g.query(ctx, query, entities.WebhookDeliveryPending, now, limit)
+}
+
+func (g *webhookDeliveryGateway) Claim(ctx context.Context, delivery *entities.WebhookDelivery, now, until time.Time) (bool, error) {
+	// The conditions are checked again on the locked row, so only one of
+	// two concurrent claims updates it
+	query := `UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2 AND status = $3 AND next_attempt_at <= $4`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, until, delivery.ID, entities.WebhookDeliveryPending, now)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	delivery.NextAttemptAt = until
+	return true, nil
+}
+
+func (g *webhookDeliveryGateway) Update(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5, delivered_at = $6, updated_at = $7
+		WHERE id = $8
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+		delivery.UpdatedAt,
+		delivery.ID,
+	)
+
+	return err
+}
+
+func (g *webhookDeliveryGateway) query(ctx context.Context, query string, args ...any) ([]*entities.WebhookDelivery, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entities.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row interface{ Scan(...any) error }) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	var deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&deliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return &delivery, nil
+}
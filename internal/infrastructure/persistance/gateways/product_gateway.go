@@ -1,31 +1,35 @@
 package gateways
 
 import (
+	"context"
 	"database/sql"
+	"log/slog"
 	"strconv"
-	"time"
 
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
 )
 
 type productGateway struct {
-	db *sql.DB
+	db     *sql.DB
+	logger *slog.Logger
 }
 
-func NewProductGateway(db *sql.DB) output.ProductGateway {
+func NewProductGateway(db *sql.DB, logger *slog.Logger) output.ProductGateway {
 	return &productGateway{
-		db: db,
+		db:     db,
+		logger: logger,
 	}
 }
 
-func (g *productGateway) Create(product *entities.Product) error {
+func (g *productGateway) Create(ctx context.Context, product *entities.Product) error {
 	query := `
 		INSERT INTO products (name, description, price, category, image_url, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := g.db.Exec(query,
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		product.Name,
 		product.Description,
 		product.Price,
@@ -48,18 +52,17 @@ func (g *productGateway) Create(product *entities.Product) error {
 	return nil
 }
 
-func (g *productGateway) GetByID(id uint64) (*entities.Product, error) {
+func (g *productGateway) GetByID(ctx context.Context, id uint64) (*entities.Product, error) {
 	query := `
 		SELECT id, name, description, price, category, image_url, created_at, updated_at
 		FROM products
 		WHERE id = ?
 	`
 
-	row := g.db.QueryRow(query, id)
+	row := sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id)
 
 	var product entities.Product
 	var price string
-	var createdAt, updatedAt string
 
 	err := row.Scan(
 		&product.ID,
@@ -68,8 +71,8 @@ func (g *productGateway) GetByID(id uint64) (*entities.Product, error) {
 		&price,
 		&product.Category,
 		&product.ImageUrl,
-		&createdAt,
-		&updatedAt,
+		&product.CreatedAt,
+		&product.UpdatedAt,
 	)
 
 	if err != nil {
@@ -84,14 +87,10 @@ func (g *productGateway) GetByID(id uint64) (*entities.Product, error) {
 		product.Price = float32(priceFloat)
 	}
 
-	// Parse timestamps
-	product.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-	product.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 	return &product, nil
 }
 
-func (g *productGateway) GetByCategory(category string) ([]*entities.Product, error) {
+func (g *productGateway) GetByCategory(ctx context.Context, category string) ([]*entities.Product, error) {
 	query := `
 		SELECT id, name, description, price, category, image_url, created_at, updated_at
 		FROM products
@@ -99,7 +98,7 @@ func (g *productGateway) GetByCategory(category string) ([]*entities.Product, er
 		ORDER BY name
 	`
 
-	rows, err := g.db.Query(query, category)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, category)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +109,6 @@ func (g *productGateway) GetByCategory(category string) ([]*entities.Product, er
 	for rows.Next() {
 		var product entities.Product
 		var price string
-		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&product.ID,
@@ -119,11 +117,12 @@ func (g *productGateway) GetByCategory(category string) ([]*entities.Product, er
 			&price,
 			&product.Category,
 			&product.ImageUrl,
-			&createdAt,
-			&updatedAt,
+			&product.CreatedAt,
+			&product.UpdatedAt,
 		)
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping product row that failed to scan", "method", "GetByCategory", "error", err)
 			continue
 		}
 
@@ -132,24 +131,20 @@ func (g *productGateway) GetByCategory(category string) ([]*entities.Product, er
 			product.Price = float32(priceFloat)
 		}
 
-		// Parse timestamps
-		product.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		product.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		products = append(products, &product)
 	}
 
 	return products, nil
 }
 
-func (g *productGateway) GetAll() ([]*entities.Product, error) {
+func (g *productGateway) GetAll(ctx context.Context) ([]*entities.Product, error) {
 	query := `
 		SELECT id, name, description, price, category, image_url, created_at, updated_at
 		FROM products
 		ORDER BY category, name
 	`
 
-	rows, err := g.db.Query(query)
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +155,6 @@ func (g *productGateway) GetAll() ([]*entities.Product, error) {
 	for rows.Next() {
 		var product entities.Product
 		var price string
-		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&product.ID,
@@ -169,11 +163,12 @@ func (g *productGateway) GetAll() ([]*entities.Product, error) {
 			&price,
 			&product.Category,
 			&product.ImageUrl,
-			&createdAt,
-			&updatedAt,
+			&product.CreatedAt,
+			&product.UpdatedAt,
 		)
 
 		if err != nil {
+			g.logger.WarnContext(ctx, "skipping product row that failed to scan", "method", "GetAll", "error", err)
 			continue
 		}
 
@@ -182,24 +177,20 @@ func (g *productGateway) GetAll() ([]*entities.Product, error) {
 			product.Price = float32(priceFloat)
 		}
 
-		// Parse timestamps
-		product.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
-		product.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
-
 		products = append(products, &product)
 	}
 
 	return products, nil
 }
 
-func (g *productGateway) Update(product *entities.Product) error {
+func (g *productGateway) Update(ctx context.Context, product *entities.Product) error {
 	query := `
 		UPDATE products
 		SET name = ?, description = ?, price = ?, category = ?, image_url = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	_, err := g.db.Exec(query,
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
 		product.Name,
 		product.Description,
 		product.Price,
@@ -212,8 +203,8 @@ func (g *productGateway) Update(product *entities.Product) error {
 	return err
 }
 
-func (g *productGateway) Delete(id uint64) error {
+func (g *productGateway) Delete(ctx context.Context, id uint64) error {
 	query := `DELETE FROM products WHERE id = ?`
-	_, err := g.db.Exec(query, id)
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query, id)
 	return err
 }
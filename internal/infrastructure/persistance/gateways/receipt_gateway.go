@@ -0,0 +1,100 @@
+package gateways
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type receiptGateway struct {
+	db *sql.DB
+}
+
+func NewReceiptGateway(db *sql.DB) output.ReceiptGateway {
+	return &receiptGateway{
+		db: db,
+	}
+}
+
+func (g *receiptGateway) Create(ctx context.Context, receipt *entities.Receipt) (bool, error) {
+	// The document keeps the whole receipt as JSON; the renditions are
+	// stored next to it as they were served
+	document, err := json.Marshal(receipt)
+	if err != nil {
+		return false, err
+	}
+
+	// INSERT IGNORE skips the row when the order already has a receipt
+	query := `
+		INSERT IGNORE INTO receipts (order_id, document, xml, plain_text, issued_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		receipt.OrderID,
+		document,
+		receipt.XML,
+		receipt.Text,
+		receipt.IssuedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	receipt.ID = uint64(id)
+	return true, nil
+}
+
+func (g *receiptGateway) GetByOrderID(ctx context.Context, orderID uint64) (*entities.Receipt, error) {
+	query := `
+		SELECT id, order_id, document, xml, plain_text, issued_at
+		FROM receipts
+		WHERE order_id = ?
+	`
+
+	receipt, err := scanReceipt(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, orderID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return receipt, err
+}
+
+func scanReceipt(row interface{ Scan(...any) error }) (*entities.Receipt, error) {
+	var (
+		receipt   entities.Receipt
+		document  []byte
+		id        uint64
+		orderID   uint64
+		xml, text []byte
+		issuedAt  time.Time
+	)
+
+	if err := row.Scan(&id, &orderID, &document, &xml, &text, &issuedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(document, &receipt); err != nil {
+		return nil, err
+	}
+
+	receipt.ID = id
+	receipt.OrderID = orderID
+	receipt.XML = xml
+	receipt.Text = text
+	receipt.IssuedAt = issuedAt
+	return &receipt, nil
+}
@@ -0,0 +1,118 @@
+package gateways
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type reconciliationGateway struct {
+	db *sql.DB
+}
+
+func NewReconciliationGateway(db *sql.DB) output.ReconciliationGateway {
+	return &reconciliationGateway{
+		db: db,
+	}
+}
+
+// SaveReport deletes the mismatches explicitly: MySQL ignores the inline
+// REFERENCES clause, so there is no cascade
+func (g *reconciliationGateway) SaveReport(ctx context.Context, report *entities.ReconciliationReport) error {
+	conn := sqltx.From(ctx, g.db)
+	day := report.Date.Format(time.DateOnly)
+
+	if _, err := conn.ExecContext(ctx, `DELETE FROM reconciliation_mismatches WHERE report_date = ?`, day); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, `DELETE FROM reconciliation_reports WHERE report_date = ?`, day); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO reconciliation_reports (report_date, payments_checked, provider_payments, generated_at)
+		VALUES (?, ?, ?, ?)
+	`
+	if _, err := conn.ExecContext(ctx, query, day, report.PaymentsChecked, report.ProviderPayments, report.GeneratedAt); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO reconciliation_mismatches (report_date, kind, payment_id, order_id, transaction_id, local_status, provider_status, local_amount, provider_amount)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	for _, mismatch := range report.Mismatches {
+		_, err := conn.ExecContext(ctx, query,
+			day,
+			string(mismatch.Kind),
+			mismatch.PaymentID,
+			mismatch.OrderID,
+			mismatch.TransactionID,
+			string(mismatch.LocalStatus),
+			string(mismatch.ProviderStatus),
+			mismatch.LocalAmount,
+			mismatch.ProviderAmount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *reconciliationGateway) GetReport(ctx context.Context, date time.Time) (*entities.ReconciliationReport, error) {
+	conn := sqltx.From(ctx, g.db)
+	report := entities.ReconciliationReport{Date: entities.ReportDay(date)}
+	day := report.Date.Format(time.DateOnly)
+
+	query := `
+		SELECT payments_checked, provider_payments, generated_at
+		FROM reconciliation_reports
+		WHERE report_date = ?
+	`
+	err := conn.QueryRowContext(ctx, query, day).Scan(&report.PaymentsChecked, &report.ProviderPayments, &report.GeneratedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	query = `
+		SELECT kind, payment_id, order_id, transaction_id, local_status, provider_status, local_amount, provider_amount
+		FROM reconciliation_mismatches
+		WHERE report_date = ?
+		ORDER BY id
+	`
+	rows, err := conn.QueryContext(ctx, query, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report.Mismatches = []entities.ReconciliationMismatch{}
+	for rows.Next() {
+		var mismatch entities.ReconciliationMismatch
+		err := rows.Scan(
+			&mismatch.Kind,
+			&mismatch.PaymentID,
+			&mismatch.OrderID,
+			&mismatch.TransactionID,
+			&mismatch.LocalStatus,
+			&mismatch.ProviderStatus,
+			&mismatch.LocalAmount,
+			&mismatch.ProviderAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		report.Mismatches = append(report.Mismatches, mismatch)
+	}
+
+	return &report, rows.Err()
+}
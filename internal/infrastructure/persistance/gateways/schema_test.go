@@ -0,0 +1,100 @@
+package gateways
+
+import "testing"
+
+// legacyTables are the tables as the first version of init.sql that had them
+// created them, before any column was added to them
+var legacyTables = []string{
+	`CREATE TABLE orders (
+		id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		customer_id BIGINT UNSIGNED NULL,
+		cpf         VARCHAR(14) NULL,
+		status      ENUM('awaiting_payment', 'received', 'in_progress', 'ready', 'completed', 'cancelled'),
+		created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE order_items (
+		id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		order_id   BIGINT UNSIGNED NOT NULL,
+		product_id BIGINT UNSIGNED NOT NULL,
+		quantity   INTEGER NOT NULL DEFAULT 1,
+		price      FLOAT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE payments (
+		id             BIGINT AUTO_INCREMENT PRIMARY KEY,
+		order_id       BIGINT UNSIGNED NOT NULL,
+		amount         DECIMAL(10,2) NOT NULL,
+		status         VARCHAR(20) NOT NULL DEFAULT 'pending',
+		payment_method VARCHAR(50) NOT NULL,
+		transaction_id VARCHAR(255) DEFAULT '',
+		created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE kitchen_tickets (
+		id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		order_id    BIGINT UNSIGNED NOT NULL,
+		copy_number INT NOT NULL,
+		printed_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE KEY uq_kitchen_tickets_order_copy (order_id, copy_number)
+	)`,
+}
+
+func TestSchemaUpgrade(t *testing.T) {
+	// Arrange
+	addr := startEmbeddedMySQL(t)
+	db := openDatabase(t, addr, "legacy", legacyTables)
+	script := loadScript(t)
+
+	// Act: the script runs again on every deploy, so it must be repeatable
+	for run := 1; run <= 2; run++ {
+		for _, stmt := range script {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatalf("run %d: failed to apply %q: %v", run, stmt, err)
+			}
+		}
+	}
+
+	// Assert
+	for table, columns := range map[string][]string{
+		"orders":          {"discount", "service_fee", "rounding"},
+		"order_items":     {"modifiers", "notes"},
+		"payments":        {"payer", "expires_at"},
+		"kitchen_tickets": {"status", "attempts", "last_error", "next_attempt_at"},
+	} {
+		for _, column := range columns {
+			var count int
+			err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+				WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`, table, column).Scan(&count)
+			if err != nil || count != 1 {
+				t.Errorf("Expected %s.%s to be added once, got %d, %v", table, column, count, err)
+			}
+		}
+	}
+	var indexes int
+	db.QueryRow(`SELECT COUNT(DISTINCT index_name) FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = 'payments' AND index_name = 'idx_payments_expiry'`).Scan(&indexes)
+	if indexes != 1 {
+		t.Errorf("Expected the payment expiry index to be added, got %d", indexes)
+	}
+	db.QueryRow(`SELECT COUNT(DISTINCT index_name) FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = 'kitchen_tickets' AND index_name = 'idx_kitchen_tickets_due'`).Scan(&indexes)
+	if indexes != 1 {
+		t.Errorf("Expected the kitchen ticket due index to be added, got %d", indexes)
+	}
+	var version int
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil || version == 0 {
+		t.Errorf("Expected the schema version to be recorded, got %d, %v", version, err)
+	}
+	if _, err := db.Exec("INSERT INTO payments (order_id, amount, payment_method, payer, expires_at) VALUES (1, 10, 'pix', 'Ana', NOW())"); err != nil {
+		t.Errorf("Expected the upgraded payments table to take the new columns, got %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO kitchen_tickets (order_id, copy_number, status, next_attempt_at, printed_at) VALUES (1, 1, 'pending', NOW(), NULL)"); err != nil {
+		t.Errorf("Expected the upgraded kitchen_tickets table to take the new columns, got %v", err)
+	}
+	var printedAt *string
+	if err := db.QueryRow("SELECT printed_at FROM kitchen_tickets WHERE order_id = 1").Scan(&printedAt); err != nil || printedAt != nil {
+		t.Errorf("Expected a pending kitchen ticket to have no print time, got %v, %v", printedAt, err)
+	}
+}
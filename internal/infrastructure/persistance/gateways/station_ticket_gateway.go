@@ -0,0 +1,150 @@
+package gateways
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+type stationTicketGateway struct {
+	db *sql.DB
+}
+
+func NewStationTicketGateway(db *sql.DB) output.StationTicketGateway {
+	return &stationTicketGateway{
+		db: db,
+	}
+}
+
+func (g *stationTicketGateway) Create(ctx context.Context, ticket *entities.StationTicket) (bool, error) {
+	items, err := json.Marshal(ticket.Items)
+	if err != nil {
+		return false, err
+	}
+
+	// INSERT IGNORE skips the row when the order already has a ticket for
+	// the station
+	query := `
+		INSERT IGNORE INTO station_tickets (order_id, station, status, items, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		ticket.OrderID,
+		ticket.Station,
+		string(ticket.Status),
+		items,
+		ticket.CreatedAt,
+		ticket.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	ticket.ID = uint64(id)
+	return true, nil
+}
+
+func (g *stationTicketGateway) GetByID(ctx context.Context, id uint64) (*entities.StationTicket, error) {
+	query := `
+		SELECT id, order_id, station, status, items, created_at, updated_at
+		FROM station_tickets
+		WHERE id = ?
+	`
+
+	ticket, err := scanStationTicket(sqltx.From(ctx, g.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return ticket, err
+}
+
+func (g *stationTicketGateway) ListByOrderID(ctx context.Context, orderID uint64) ([]*entities.StationTicket, error) {
+	query := `
+		SELECT id, order_id, station, status, items, created_at, updated_at
+		FROM station_tickets
+		WHERE order_id = ?
+		ORDER BY id
+	`
+
+	return g.list(ctx, query, orderID)
+}
+
+func (g *stationTicketGateway) ListOpenByStation(ctx context.Context, station string) ([]*entities.StationTicket, error) {
+	query := `
+		SELECT t.id, t.order_id, t.station, t.status, t.items, t.created_at, t.updated_at
+		FROM station_tickets t
+		JOIN orders o ON o.id = t.order_id
+		WHERE t.station = ? AND t.status IN ('pending', 'preparing') AND o.status IN ('received', 'in_progress')
+		ORDER BY t.id
+	`
+
+	return g.list(ctx, query, station)
+}
+
+func (g *stationTicketGateway) Update(ctx context.Context, ticket *entities.StationTicket) error {
+	query := `
+		UPDATE station_tickets
+		SET status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := sqltx.From(ctx, g.db).ExecContext(ctx, query,
+		string(ticket.Status),
+		ticket.UpdatedAt,
+		ticket.ID,
+	)
+
+	return err
+}
+
+func (g *stationTicketGateway) list(ctx context.Context, query string, args ...any) ([]*entities.StationTicket, error) {
+	rows, err := sqltx.From(ctx, g.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []*entities.StationTicket
+	for rows.Next() {
+		ticket, err := scanStationTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, rows.Err()
+}
+
+func scanStationTicket(row interface{ Scan(...any) error }) (*entities.StationTicket, error) {
+	var (
+		ticket entities.StationTicket
+		status string
+		items  []byte
+	)
+
+	if err := row.Scan(&ticket.ID, &ticket.OrderID, &ticket.Station, &status, &items, &ticket.CreatedAt, &ticket.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(items, &ticket.Items); err != nil {
+		return nil, err
+	}
+
+	ticket.Status = entities.StationTicketStatus(status)
+	return &ticket, nil
+}
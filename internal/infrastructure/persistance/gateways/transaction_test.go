@@ -0,0 +1,61 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+func TestTransactionManager(t *testing.T) {
+	addr := startEmbeddedMySQL(t)
+	schema := loadSchema(t)
+	ctx := context.Background()
+
+	t.Run("CommitsOnSuccess", func(t *testing.T) {
+		// Arrange
+		db := openDatabase(t, addr, "tx_commit", schema)
+		orders := NewOrderGateway(db, logging.Discard())
+		order := entities.NewOrder(0, "")
+
+		// Act
+		err := sqltx.NewManager(db).WithinTransaction(ctx, func(ctx context.Context) error {
+			return orders.Create(ctx, order)
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if found, _ := orders.GetByID(ctx, order.ID); found == nil {
+			t.Error("Expected the order to be committed")
+		}
+	})
+
+	t.Run("RollsBackOnError", func(t *testing.T) {
+		// Arrange
+		db := openDatabase(t, addr, "tx_rollback", schema)
+		orders := NewOrderGateway(db, logging.Discard())
+		order := entities.NewOrder(0, "")
+		failure := errors.New("outbox append failed")
+
+		// Act
+		err := sqltx.NewManager(db).WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := orders.Create(ctx, order); err != nil {
+				return err
+			}
+			return failure
+		})
+
+		// Assert
+		if !errors.Is(err, failure) {
+			t.Fatalf("Expected the callback error, got %v", err)
+		}
+		if found, _ := orders.GetByID(ctx, order.ID); found != nil {
+			t.Error("Expected the order to be rolled back")
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package persistance
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/postgres"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/sqltx"
+)
+
+// Supported values for the DB_DRIVER environment variable
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
+// Gateways groups every output port implementation for a single backend
+type Gateways struct {
+	Customer  output.CustomerGateway
+	Product   output.ProductGateway
+	Order     output.OrderGateway
+	OrderItem output.OrderItemGateway
+	Payment   output.PaymentGateway
+
+	Idempotency output.IdempotencyGateway
+	Outbox      output.OutboxGateway
+	// Transactions makes the gateway calls made with its ctx atomic
+	Transactions output.TransactionManager
+
+	WebhookSubscription output.WebhookSubscriptionGateway
+	WebhookDelivery     output.WebhookDeliveryGateway
+
+	Reconciliation output.ReconciliationGateway
+
+	Promotion     output.PromotionGateway
+	OrderDiscount output.OrderDiscountGateway
+	Loyalty       output.LoyaltyGateway
+	OrderTax      output.OrderTaxGateway
+	Receipt       output.ReceiptGateway
+	KitchenTicket output.KitchenTicketGateway
+	StationTicket output.StationTicketGateway
+}
+
+// NewGateways returns the gateway set matching the given database driver.
+// The memory driver ignores db and starts from an empty store.
+func NewGateways(driver string, db *sql.DB, logger *slog.Logger) (*Gateways, error) {
+	switch driver {
+	case DriverMySQL, "":
+		return &Gateways{
+			Customer:  gateways.NewCustomerGateway(db),
+			Product:   gateways.NewProductGateway(db, logger),
+			Order:     gateways.NewOrderGateway(db, logger),
+			OrderItem: gateways.NewOrderItemGateway(db, logger),
+			Payment:   gateways.NewPaymentGateway(db),
+
+			Idempotency:  gateways.NewIdempotencyGateway(db),
+			Outbox:       gateways.NewOutboxGateway(db),
+			Transactions: sqltx.NewManager(db),
+
+			WebhookSubscription: gateways.NewWebhookSubscriptionGateway(db),
+			WebhookDelivery:     gateways.NewWebhookDeliveryGateway(db),
+
+			Reconciliation: gateways.NewReconciliationGateway(db),
+
+			Promotion:     gateways.NewPromotionGateway(db),
+			OrderDiscount: gateways.NewOrderDiscountGateway(db),
+			Loyalty:       gateways.NewLoyaltyGateway(db),
+			OrderTax:      gateways.NewOrderTaxGateway(db),
+			Receipt:       gateways.NewReceiptGateway(db),
+			KitchenTicket: gateways.NewKitchenTicketGateway(db),
+			StationTicket: gateways.NewStationTicketGateway(db),
+		}, nil
+	case DriverPostgres:
+		return &Gateways{
+			Customer:  postgres.NewCustomerGateway(db),
+			Product:   postgres.NewProductGateway(db),
+			Order:     postgres.NewOrderGateway(db),
+			OrderItem: postgres.NewOrderItemGateway(db),
+			Payment:   postgres.NewPaymentGateway(db),
+
+			Idempotency:  postgres.NewIdempotencyGateway(db),
+			Outbox:       postgres.NewOutboxGateway(db),
+			Transactions: sqltx.NewManager(db),
+
+			WebhookSubscription: postgres.NewWebhookSubscriptionGateway(db),
+			WebhookDelivery:     postgres.NewWebhookDeliveryGateway(db),
+
+			Reconciliation: postgres.NewReconciliationGateway(db),
+
+			Promotion:     postgres.NewPromotionGateway(db),
+			OrderDiscount: postgres.NewOrderDiscountGateway(db),
+			Loyalty:       postgres.NewLoyaltyGateway(db),
+			OrderTax:      postgres.NewOrderTaxGateway(db),
+			Receipt:       postgres.NewReceiptGateway(db),
+			KitchenTicket: postgres.NewKitchenTicketGateway(db),
+			StationTicket: postgres.NewStationTicketGateway(db),
+		}, nil
+	case DriverMemory:
+		store := memory.NewStore()
+		return &Gateways{
+			Customer:  memory.NewCustomerGateway(store),
+			Product:   memory.NewProductGateway(store),
+			Order:     memory.NewOrderGateway(store),
+			OrderItem: memory.NewOrderItemGateway(store),
+			Payment:   memory.NewPaymentGateway(store),
+
+			Idempotency:  memory.NewIdempotencyGateway(store),
+			Outbox:       memory.NewOutboxGateway(store),
+			Transactions: memory.NewTransactionManager(),
+
+			WebhookSubscription: memory.NewWebhookSubscriptionGateway(store),
+			WebhookDelivery:     memory.NewWebhookDeliveryGateway(store),
+
+			Reconciliation: memory.NewReconciliationGateway(store),
+
+			Promotion:     memory.NewPromotionGateway(store),
+			OrderDiscount: memory.NewOrderDiscountGateway(store),
+			Loyalty:       memory.NewLoyaltyGateway(store),
+			OrderTax:      memory.NewOrderTaxGateway(store),
+			Receipt:       memory.NewReceiptGateway(store),
+			KitchenTicket: memory.NewKitchenTicketGateway(store),
+			StationTicket: memory.NewStationTicketGateway(store),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// UsesSQL reports whether the driver needs a database/sql connection
+func UsesSQL(driver string) bool {
+	return driver != DriverMemory
+}
+
+// SQLDriverName maps a DB_DRIVER value to the database/sql driver name
+func SQLDriverName(driver string) (string, error) {
+	switch driver {
+	case DriverMySQL, "":
+		return "mysql", nil
+	case DriverPostgres:
+		return "pgx", nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
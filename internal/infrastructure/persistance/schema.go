@@ -0,0 +1,19 @@
+package persistance
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ExpectedSchemaVersion is the schema_migrations version this build needs.
+// Bump it together with init.sql, init.postgres.sql and the Helm init script
+// whenever the schema changes.
+const ExpectedSchemaVersion = 14
+
+// SchemaVersion returns the highest version recorded in schema_migrations,
+// or 0 when the table is empty. The query is the same for every SQL driver.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
@@ -0,0 +1,68 @@
+// Package sqltx carries a database transaction through the context so SQL
+// gateways join it without changing their method signatures.
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// Executor is the part of *sql.DB and *sql.Tx the gateways use
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// From returns the transaction started by Manager for ctx, or db when the
+// call is not part of one
+func From(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+type manager struct {
+	db *sql.DB
+}
+
+func NewManager(db *sql.DB) output.TransactionManager {
+	return &manager{
+		db: db,
+	}
+}
+
+// WithinTransaction commits when fn returns nil and rolls back otherwise,
+// including when fn panics. A nested call joins the outer transaction.
+func (m *manager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
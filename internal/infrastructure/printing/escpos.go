@@ -0,0 +1,111 @@
+package printing
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// ESC/POS commands the tickets use
+var (
+	escInit        = []byte{0x1b, '@'}
+	escAlignLeft   = []byte{0x1b, 'a', 0}
+	escAlignCenter = []byte{0x1b, 'a', 1}
+	escBoldOn      = []byte{0x1b, 'E', 1}
+	escBoldOff     = []byte{0x1b, 'E', 0}
+	escNormalSize  = []byte{0x1d, '!', 0x00}
+	escDoubleSize  = []byte{0x1d, '!', 0x11}
+	escTallSize    = []byte{0x1d, '!', 0x01}
+	// escCut feeds the paper past the cutter and cuts it partially
+	escCut = []byte{0x1d, 'V', 66, 3}
+)
+
+// ESCPOSRenderer renders kitchen tickets as ESC/POS jobs: the order number
+// in double size, then each item in bold with its modifiers and notes below
+type ESCPOSRenderer struct {
+	width    int
+	location *time.Location
+}
+
+// NewESCPOSRenderer creates a renderer for paper width characters wide in
+// the printer's normal font that prints times in location
+func NewESCPOSRenderer(width int, location *time.Location) *ESCPOSRenderer {
+	return &ESCPOSRenderer{
+		width:    width,
+		location: location,
+	}
+}
+
+func (r *ESCPOSRenderer) Render(ticket *entities.KitchenTicket) ([]byte, error) {
+	var b bytes.Buffer
+	b.Write(escInit)
+
+	b.Write(escAlignCenter)
+	b.Write(escDoubleSize)
+	r.lines(&b, fmt.Sprintf("PEDIDO %d", ticket.OrderID), r.width/2)
+	b.Write(escNormalSize)
+	if ticket.IsReprint() {
+		b.Write(escBoldOn)
+		r.lines(&b, fmt.Sprintf("*** REIMPRESSAO - VIA %d ***", ticket.Copy), r.width)
+		b.Write(escBoldOff)
+	}
+	r.lines(&b, "Pedido "+ticket.OrderedAt.In(r.location).Format("02/01/2006 15:04"), r.width)
+	if ticket.PrintedAt != nil {
+		r.lines(&b, "Impresso "+ticket.PrintedAt.In(r.location).Format("02/01/2006 15:04"), r.width)
+	}
+
+	b.Write(escAlignLeft)
+	r.rule(&b, "=")
+	var count uint32
+	for i, item := range ticket.Items {
+		if i > 0 {
+			r.rule(&b, "-")
+		}
+		count += item.Quantity
+
+		b.Write(escBoldOn)
+		b.Write(escTallSize)
+		r.lines(&b, fmt.Sprintf("%dx %s", item.Quantity, strings.ToUpper(item.Description)), r.width)
+		b.Write(escNormalSize)
+		b.Write(escBoldOff)
+		for _, modifier := range item.Modifiers {
+			r.indented(&b, "  + ", modifier)
+		}
+		if item.Notes != "" {
+			r.indented(&b, "  OBS: ", item.Notes)
+		}
+	}
+	r.rule(&b, "=")
+	r.lines(&b, fmt.Sprintf("%d ITENS", count), r.width)
+
+	b.Write(escCut)
+	return b.Bytes(), nil
+}
+
+// lines writes text wrapped to width
+func (r *ESCPOSRenderer) lines(b *bytes.Buffer, text string, width int) {
+	for _, line := range wrap(ascii(text), width) {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
+
+// indented writes text after prefix, lining up its wrapped lines with the
+// first
+func (r *ESCPOSRenderer) indented(b *bytes.Buffer, prefix, text string) {
+	for i, line := range wrap(ascii(text), r.width-len(prefix)) {
+		if i > 0 {
+			prefix = strings.Repeat(" ", len(prefix))
+		}
+		b.WriteString(prefix + line)
+		b.WriteByte('\n')
+	}
+}
+
+func (r *ESCPOSRenderer) rule(b *bytes.Buffer, char string) {
+	b.WriteString(strings.Repeat(char, r.width))
+	b.WriteByte('\n')
+}
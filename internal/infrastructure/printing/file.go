@@ -0,0 +1,37 @@
+package printing
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// FilePrinter appends each job to a file instead of printing it, so tests
+// and development setups can see what the kitchen would get
+type FilePrinter struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFilePrinter creates a printer that writes to path, creating the file
+// when it does not exist
+func NewFilePrinter(path string) *FilePrinter {
+	return &FilePrinter{
+		path: path,
+	}
+}
+
+func (p *FilePrinter) Print(ctx context.Context, document []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	file, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(document); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,80 @@
+// Package printing prints kitchen tickets on ESC/POS thermal printers. Jobs
+// go over a raw TCP socket, as network printers take them on port 9100, or
+// are appended to a file for testing.
+package printing
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Printers selectable with KITCHEN_PRINTER
+const (
+	PrinterNone = "none"
+	PrinterTCP  = "tcp"
+	PrinterFile = "file"
+)
+
+// asciiFolds drops the accents of Portuguese text. Printers differ in their
+// code pages, so tickets are printed in plain ASCII.
+var asciiFolds = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// ascii folds text to ASCII, printing ? for what has no plain letter
+func ascii(text string) string {
+	text = asciiFolds.Replace(text)
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		case r < utf8.RuneSelf && r >= ' ':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// wrap breaks ASCII text into lines of at most width characters at spaces,
+// cutting words that do not fit in a line of their own
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for len(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
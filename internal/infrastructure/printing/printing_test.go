@@ -0,0 +1,163 @@
+package printing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+func testTicket(copyNumber int) *entities.KitchenTicket {
+	printedAt := time.Date(2026, 10, 19, 15, 31, 0, 0, time.UTC)
+	return &entities.KitchenTicket{
+		OrderID: 42,
+		Copy:    copyNumber,
+		Items: []entities.KitchenTicketItem{
+			{Quantity: 2, Description: "X-Salada", Modifiers: []string{"sem cebola", "pão australiano"}, Notes: "cortar ao meio e embalar separado para viagem"},
+			{Quantity: 1, Description: "Refrigerante"},
+		},
+		OrderedAt: time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC),
+		PrintedAt: &printedAt,
+	}
+}
+
+func TestESCPOSRenderer_Render(t *testing.T) {
+	// Arrange
+	renderer := NewESCPOSRenderer(32, time.FixedZone("BRT", -3*60*60))
+
+	// Act
+	job, err := renderer.Render(testTicket(1))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.HasPrefix(job, escInit) || !bytes.HasSuffix(job, escCut) {
+		t.Errorf("Expected the job to reset the printer and cut the paper, got %q", job)
+	}
+	if !bytes.Contains(job, append(escDoubleSize, "PEDIDO 42\n"...)) {
+		t.Errorf("Expected the order number in double size, got %q", job)
+	}
+	for _, expected := range []string{
+		"Pedido 19/10/2026 12:30\n",
+		"2x X-SALADA\n",
+		"  + sem cebola\n",
+		"  + pao australiano\n",
+		"  OBS: cortar ao meio e embalar\n       separado para viagem\n",
+		"1x REFRIGERANTE\n",
+		"3 ITENS\n",
+	} {
+		if !bytes.Contains(job, []byte(expected)) {
+			t.Errorf("Expected the ticket to contain %q, got\n%s", expected, job)
+		}
+	}
+	if bytes.Contains(job, []byte("REIMPRESSAO")) {
+		t.Errorf("Expected the first copy not to be marked as a reprint, got\n%s", job)
+	}
+}
+
+func TestESCPOSRenderer_MarksReprints(t *testing.T) {
+	// Arrange
+	renderer := NewESCPOSRenderer(48, time.UTC)
+
+	// Act
+	job, _ := renderer.Render(testTicket(3))
+
+	// Assert
+	if !bytes.Contains(job, []byte("*** REIMPRESSAO - VIA 3 ***\n")) {
+		t.Errorf("Expected the reprint banner, got\n%s", job)
+	}
+}
+
+func TestASCIIAndWrap(t *testing.T) {
+	// Act
+	folded := ascii("Açaí com granola ☕\tgelado")
+	lines := wrap("um texto com umapalavramuitocomprida", 10)
+
+	// Assert
+	if folded != "Acai com granola ? gelado" {
+		t.Errorf("Expected accents folded and other runes replaced, got %q", folded)
+	}
+	expected := []string{"um texto", "com", "umapalavra", "muitocompr", "ida"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+}
+
+func TestTCPPrinter_Print(t *testing.T) {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		body, _ := io.ReadAll(conn)
+		received <- body
+	}()
+	printer := NewTCPPrinter(listener.Addr().String(), time.Second)
+
+	// Act
+	err = printer.Print(context.Background(), []byte("job"))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case body := <-received:
+		if string(body) != "job" {
+			t.Errorf("Expected the printer to get %q, got %q", "job", body)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the printer to get the job")
+	}
+}
+
+func TestTCPPrinter_PrinterOffline(t *testing.T) {
+	// Arrange
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	listener.Close()
+	printer := NewTCPPrinter(address, time.Second)
+
+	// Act
+	err := printer.Print(context.Background(), []byte("job"))
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error when the printer is unreachable")
+	}
+}
+
+func TestFilePrinter_AppendsJobs(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "kitchen.bin")
+	printer := NewFilePrinter(path)
+
+	// Act
+	first := printer.Print(context.Background(), []byte("first\n"))
+	second := printer.Print(context.Background(), []byte("second\n"))
+
+	// Assert
+	if first != nil || second != nil {
+		t.Fatalf("Expected no errors, got %v and %v", first, second)
+	}
+	body, _ := os.ReadFile(path)
+	if string(body) != "first\nsecond\n" {
+		t.Errorf("Expected both jobs in order, got %q", body)
+	}
+}
@@ -0,0 +1,48 @@
+package printing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// TCPPrinter sends each job over a new raw TCP connection, the way network
+// thermal printers take jobs on port 9100
+type TCPPrinter struct {
+	address string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+// NewTCPPrinter creates a printer at address (host:port) that gives up on a
+// job after timeout
+func NewTCPPrinter(address string, timeout time.Duration) *TCPPrinter {
+	return &TCPPrinter{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+func (p *TCPPrinter) Print(ctx context.Context, document []byte) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	conn, err := p.dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return fmt.Errorf("failed to reach printer %s: %w", p.address, err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to finish job on printer %s: %w", p.address, closeErr)
+		}
+	}()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(document); err != nil {
+		return fmt.Errorf("failed to send job to printer %s: %w", p.address, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultSweepInterval is how often Run drops buckets that refilled
+const DefaultSweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket is back to Burst tokens and can be forgotten
+	full time.Time
+}
+
+// MemoryStore keeps buckets in a map. Limits are per replica, so with N
+// replicas a client gets up to N times the policy.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, policy Policy) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	interval := policy.interval()
+	burst := float64(policy.Burst)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens += float64(now.Sub(b.last)) / float64(interval)
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+
+	decision := Decision{Allowed: b.tokens >= 1}
+	if decision.Allowed {
+		b.tokens--
+	} else {
+		decision.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+	decision.Remaining = int(b.tokens)
+	b.full = now.Add(time.Duration((burst - b.tokens) * float64(interval)))
+
+	return decision, nil
+}
+
+// Sweep forgets buckets that have refilled, since a new bucket starts full
+// anyway. It returns how many remain.
+func (s *MemoryStore) Sweep() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	return len(s.buckets)
+}
+
+// Run sweeps every interval until ctx is cancelled, keeping memory bounded
+// by the number of recently active clients
+func (s *MemoryStore) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
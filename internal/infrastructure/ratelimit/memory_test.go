@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestStore returns a store whose clock only moves when advance is called
+func newTestStore() (*MemoryStore, func(time.Duration)) {
+	store := NewMemoryStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryStore_AllowsBurstThenRejects(t *testing.T) {
+	// Arrange
+	store, _ := newTestStore()
+	policy := Policy{Name: "orders", RequestsPerMinute: 60, Burst: 3}
+
+	// Act
+	var decisions []Decision
+	for i := 0; i < 4; i++ {
+		decision, _ := store.Take(context.Background(), "ip:10.0.0.1", policy)
+		decisions = append(decisions, decision)
+	}
+
+	// Assert
+	for i, decision := range decisions[:3] {
+		if !decision.Allowed {
+			t.Errorf("Request %d: expected to be allowed", i+1)
+		}
+	}
+	if decisions[2].Remaining != 0 {
+		t.Errorf("Expected 0 tokens remaining, got %d", decisions[2].Remaining)
+	}
+
+	last := decisions[3]
+	if last.Allowed {
+		t.Fatal("Expected the request after the burst to be rejected")
+	}
+	if last.RetryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %s", last.RetryAfter)
+	}
+}
+
+func TestMemoryStore_Refills(t *testing.T) {
+	// Arrange
+	store, advance := newTestStore()
+	policy := Policy{Name: "orders", RequestsPerMinute: 60, Burst: 1}
+	store.Take(context.Background(), "ip:10.0.0.1", policy)
+
+	// Act
+	advance(500 * time.Millisecond)
+	early, _ := store.Take(context.Background(), "ip:10.0.0.1", policy)
+	advance(500 * time.Millisecond)
+	later, _ := store.Take(context.Background(), "ip:10.0.0.1", policy)
+
+	// Assert
+	if early.Allowed {
+		t.Error("Expected a rejection before a full token refilled")
+	}
+	if early.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Expected retry after 500ms, got %s", early.RetryAfter)
+	}
+	if !later.Allowed {
+		t.Error("Expected the request to be allowed once the token refilled")
+	}
+}
+
+func TestMemoryStore_KeysAreIndependent(t *testing.T) {
+	// Arrange
+	store, _ := newTestStore()
+	policy := Policy{Name: "customers", RequestsPerMinute: 1, Burst: 1}
+	store.Take(context.Background(), "ip:10.0.0.1", policy)
+
+	// Act
+	decision, _ := store.Take(context.Background(), "ip:10.0.0.2", policy)
+
+	// Assert
+	if !decision.Allowed {
+		t.Error("Expected another client to have its own bucket")
+	}
+}
+
+func TestMemoryStore_SweepDropsRefilledBuckets(t *testing.T) {
+	// Arrange
+	store, advance := newTestStore()
+	policy := Policy{Name: "customers", RequestsPerMinute: 60, Burst: 2}
+	store.Take(context.Background(), "ip:10.0.0.1", policy)
+	store.Take(context.Background(), "ip:10.0.0.1", policy)
+	store.Take(context.Background(), "ip:10.0.0.2", policy)
+
+	// Act
+	advance(time.Second)
+	remaining := store.Sweep()
+
+	// Assert
+	if remaining != 1 {
+		t.Errorf("Expected 1 bucket left, got %d", remaining)
+	}
+	if _, ok := store.buckets["ip:10.0.0.2"]; ok {
+		t.Error("Expected the refilled bucket to be dropped")
+	}
+}
@@ -0,0 +1,49 @@
+// Package ratelimit implements token bucket rate limiting. Buckets live in a
+// Store so replicas can share them; MemoryStore keeps them in the process.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Route groups with a policy
+const (
+	GroupOrders    = "orders"
+	GroupCustomers = "customers"
+)
+
+// Policy configures the buckets of one route group. Each key gets Burst
+// tokens, refilled at RequestsPerMinute; a request takes one token.
+type Policy struct {
+	Name              string
+	RequestsPerMinute int
+	Burst             int
+}
+
+// Enabled reports whether the policy limits anything. A zero rate or burst
+// turns it off.
+func (p Policy) Enabled() bool {
+	return p.RequestsPerMinute > 0 && p.Burst > 0
+}
+
+// interval is the time it takes to refill one token
+func (p Policy) interval() time.Duration {
+	return time.Minute / time.Duration(p.RequestsPerMinute)
+}
+
+// Decision is the outcome of taking a token
+type Decision struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket
+	Remaining int
+	// RetryAfter is how long until a token is available; zero when allowed
+	RetryAfter time.Duration
+}
+
+// Store holds the buckets. Implementations must be safe for concurrent use
+// and apply Take atomically per key, so a distributed store (e.g. Redis with
+// a script) can be plugged in without changing the middleware.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Decision, error)
+}
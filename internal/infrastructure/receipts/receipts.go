@@ -0,0 +1,100 @@
+// Package receipts renders order receipts: an XML document in a layout
+// modelled on the NFC-e (nota fiscal de consumidor eletrônica) and plain
+// text sized for thermal printers. Renditions are made once, when a receipt
+// is issued, and stored with it.
+package receipts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// paymentCodes maps payment methods to NFC-e payment type codes (tPag);
+// other methods are "99", others
+var paymentCodes = map[string]string{
+	"cash":        "01",
+	"credit_card": "03",
+	"debit_card":  "04",
+	"pix":         "17",
+	"qr_code":     "17",
+}
+
+var paymentNames = map[string]string{
+	"cash":        "Dinheiro",
+	"credit_card": "Cartão de crédito",
+	"debit_card":  "Cartão de débito",
+	"pix":         "PIX",
+	"qr_code":     "QR Code",
+}
+
+var categoryNames = map[string]string{
+	"snack":   "Lanches",
+	"drink":   "Bebidas",
+	"dessert": "Sobremesas",
+	"side":    "Acompanhamentos",
+}
+
+func paymentName(method string) string {
+	if name, ok := paymentNames[method]; ok {
+		return name
+	}
+	return method
+}
+
+func categoryName(category string) string {
+	if name, ok := categoryNames[category]; ok {
+		return name
+	}
+	return category
+}
+
+// digits strips the punctuation of a CPF or CNPJ
+func digits(document string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, document)
+}
+
+// formatCNPJ punctuates a 14 digit CNPJ as 12.345.678/0001-90, leaving
+// anything else as it is
+func formatCNPJ(cnpj string) string {
+	d := digits(cnpj)
+	if len(d) != 14 {
+		return cnpj
+	}
+	return fmt.Sprintf("%s.%s.%s/%s-%s", d[:2], d[2:5], d[5:8], d[8:12], d[12:])
+}
+
+// decimal formats an amount with a point, as XML documents expect
+func decimal(amount float32) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+// reais formats an amount the Brazilian way, with a decimal comma and a
+// thousands point: 1.234,56
+func reais(amount float32) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	whole, cents, _ := strings.Cut(fmt.Sprintf("%.2f", amount), ".")
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "." + whole[i:]
+	}
+	return sign + whole + "," + cents
+}
+
+// percent formats a tax rate the Brazilian way, without trailing zeros
+func percent(rate float32) string {
+	formatted := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", rate), "0"), ".")
+	return strings.Replace(formatted, ".", ",", 1) + "%"
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,144 @@
+package receipts
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// testReceipt is an order of two burgers and a soda paid by pix, with a
+// discount, a service fee and rounding that takes a cent off
+func testReceipt() *entities.Receipt {
+	return &entities.Receipt{
+		ID:      1,
+		OrderID: 42,
+		Issuer:  entities.ReceiptIssuer{Name: "Fast Food", CNPJ: "12.345.678/0001-90", Address: "Rua das Flores, 100"},
+		CPF:     "123.456.789-00",
+		Items: []entities.ReceiptItem{
+			{Number: 1, ProductID: 7, Description: "X-Burger", Category: "snack", Quantity: 2, UnitPrice: 20, Total: 40},
+			{Number: 2, ProductID: 9, Description: "Refrigerante", Category: "drink", Quantity: 1, UnitPrice: 6, Total: 6},
+		},
+		Subtotal:   46,
+		Discount:   4,
+		ServiceFee: 4.2,
+		Rounding:   -0.01,
+		Total:      46.19,
+		Taxes: []entities.ReceiptTax{
+			{Category: "drink", Rate: 20, Amount: 1.09},
+			{Category: "snack", Rate: 13.45, Amount: 4.91},
+		},
+		TaxTotal: 6,
+		Payments: []entities.ReceiptPayment{{Method: "pix", Amount: 46.19, TransactionID: "tx-1"}},
+		IssuedAt: time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestXMLRenderer_Render(t *testing.T) {
+	// Arrange
+	renderer := NewXMLRenderer(time.FixedZone("BRT", -3*60*60))
+
+	// Act
+	body, err := renderer.Render(testReceipt())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var document nfe
+	if err := xml.Unmarshal(body, &document); err != nil {
+		t.Fatalf("Expected well-formed XML, got %v\n%s", err, body)
+	}
+	info := document.InfNFe
+	if info.Ide.Mod != "65" || info.Ide.NNF != 42 || info.Ide.DhEmi != "2026-10-19T12:30:00-03:00" {
+		t.Errorf("Expected model 65 number 42 issued in the store's time zone, got %+v", info.Ide)
+	}
+	if info.Emit.CNPJ != "12345678000190" || info.Dest == nil || info.Dest.CPF != "12345678900" {
+		t.Errorf("Expected the CNPJ and CPF as digits, got %+v and %+v", info.Emit, info.Dest)
+	}
+	if len(info.Det) != 2 || info.Det[0].Prod.QCom != "2.0000" || info.Det[0].Prod.VProd != "40.00" || info.Det[0].Imposto.VTotTrib != "4.91" || info.Det[1].Imposto.VTotTrib != "1.09" {
+		t.Errorf("Expected each item with its category's tax, got %+v", info.Det)
+	}
+	totals := info.Total.ICMSTot
+	if totals.VProd != "46.00" || totals.VDesc != "4.01" || totals.VOutro != "4.20" || totals.VNF != "46.19" || totals.VTotTrib != "6.00" {
+		t.Errorf("Expected 46.00 - 4.01 + 4.20 = 46.19 with 6.00 of taxes, got %+v", totals)
+	}
+	if len(info.Pag.DetPag) != 1 || info.Pag.DetPag[0].TPag != "17" || info.Pag.DetPag[0].VPag != "46.19" {
+		t.Errorf("Expected a pix payment, got %+v", info.Pag.DetPag)
+	}
+	if info.InfAdic == nil || !strings.Contains(info.InfAdic.InfCpl, "R$ 6,00") {
+		t.Errorf("Expected the approximate taxes note, got %+v", info.InfAdic)
+	}
+}
+
+func TestXMLRenderer_SharesCategoryTaxAmongItems(t *testing.T) {
+	// Arrange
+	receipt := testReceipt()
+	receipt.Items = append(receipt.Items, entities.ReceiptItem{Number: 3, ProductID: 8, Description: "X-Salada", Category: "snack", Quantity: 1, UnitPrice: 10, Total: 10})
+	receipt.Taxes[1].Amount = 1.01
+
+	// Act
+	shares := NewXMLRenderer(time.UTC).itemTaxes(receipt)
+
+	// Assert
+	if len(shares) != 3 || shares[0] != 0.81 || shares[1] != 1.09 || shares[2] != 0.2 {
+		t.Errorf("Expected 1.01 shared 0.81/0.20 among the snacks, got %v", shares)
+	}
+}
+
+func TestTextRenderer_Render(t *testing.T) {
+	// Arrange
+	renderer := NewTextRenderer(32, time.FixedZone("BRT", -3*60*60))
+
+	// Act
+	body, err := renderer.Render(testReceipt())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	text := string(body)
+	for _, expected := range []string{
+		"CNPJ 12.345.678/0001-90",
+		"CUPOM DO PEDIDO 000042",
+		"Emissão 19/10/2026 12:30:00",
+		"001 X-Burger",
+		"Descontos",
+		"Arredondamento",
+		"TOTAL R$",
+		"PIX",
+		"Total de tributos",
+		"CONSUMIDOR CPF 123.456.789-00",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected the receipt to contain %q, got\n%s", expected, text)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if utf8.RuneCountInString(line) > 32 {
+			t.Errorf("Expected lines of at most 32 columns, got %d in %q", utf8.RuneCountInString(line), line)
+		}
+	}
+}
+
+func TestTextRenderer_AnonymousReceipt(t *testing.T) {
+	// Arrange
+	receipt := testReceipt()
+	receipt.CPF = ""
+	receipt.Taxes = nil
+
+	// Act
+	body, _ := NewTextRenderer(48, time.UTC).Render(receipt)
+
+	// Assert
+	text := string(body)
+	if !strings.HasSuffix(text, "CONSUMIDOR NÃO IDENTIFICADO\n") {
+		t.Errorf("Expected an anonymous receipt, got\n%s", text)
+	}
+	if strings.Contains(text, "Trib.") {
+		t.Errorf("Expected no taxes block without tax lines, got\n%s", text)
+	}
+}
@@ -0,0 +1,125 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// TextRenderer renders receipts as plain text lines of a fixed width, for
+// thermal printers: 48 columns fit 80 mm paper and 32 fit 58 mm paper
+type TextRenderer struct {
+	width    int
+	location *time.Location
+}
+
+// NewTextRenderer creates a renderer of width columns that prints the issue
+// time in location
+func NewTextRenderer(width int, location *time.Location) *TextRenderer {
+	return &TextRenderer{
+		width:    width,
+		location: location,
+	}
+}
+
+// Render writes the receipt in UTF-8, one line per row
+func (r *TextRenderer) Render(receipt *entities.Receipt) ([]byte, error) {
+	var b bytes.Buffer
+
+	r.center(&b, strings.ToUpper(receipt.Issuer.Name))
+	if receipt.Issuer.CNPJ != "" {
+		r.center(&b, "CNPJ "+formatCNPJ(receipt.Issuer.CNPJ))
+	}
+	if receipt.Issuer.Address != "" {
+		r.center(&b, receipt.Issuer.Address)
+	}
+	r.rule(&b)
+	r.center(&b, fmt.Sprintf("CUPOM DO PEDIDO %06d", receipt.OrderID))
+	r.center(&b, "Emissão "+receipt.IssuedAt.In(r.location).Format("02/01/2006 15:04:05"))
+	r.rule(&b)
+
+	r.pair(&b, "ITEM DESCRIÇÃO", "VALOR")
+	for _, item := range receipt.Items {
+		r.line(&b, fmt.Sprintf("%03d %s", item.Number, item.Description))
+		r.pair(&b, fmt.Sprintf("    %d x %s", item.Quantity, reais(item.UnitPrice)), reais(item.Total))
+	}
+	r.rule(&b)
+
+	r.pair(&b, "Subtotal", reais(receipt.Subtotal))
+	if receipt.Discount > 0 {
+		r.pair(&b, "Descontos", reais(-receipt.Discount))
+	}
+	if receipt.ServiceFee > 0 {
+		r.pair(&b, "Taxa de serviço", reais(receipt.ServiceFee))
+	}
+	if receipt.Rounding != 0 {
+		r.pair(&b, "Arredondamento", reais(receipt.Rounding))
+	}
+	r.pair(&b, "TOTAL R$", reais(receipt.Total))
+	r.rule(&b)
+
+	r.pair(&b, "FORMA DE PAGAMENTO", "VALOR PAGO")
+	for _, payment := range receipt.Payments {
+		name := paymentName(payment.Method)
+		if payment.Payer != "" {
+			name += " (" + payment.Payer + ")"
+		}
+		r.pair(&b, name, reais(payment.Amount))
+	}
+
+	if len(receipt.Taxes) > 0 {
+		r.rule(&b)
+		r.line(&b, "Trib. aprox. Lei 12.741/2012")
+		for _, tax := range receipt.Taxes {
+			r.pair(&b, categoryName(tax.Category)+" "+percent(tax.Rate), reais(tax.Amount))
+		}
+		r.pair(&b, "Total de tributos", "R$ "+reais(receipt.TaxTotal))
+	}
+	r.rule(&b)
+
+	if receipt.CPF != "" {
+		r.line(&b, "CONSUMIDOR CPF "+receipt.CPF)
+	} else {
+		r.line(&b, "CONSUMIDOR NÃO IDENTIFICADO")
+	}
+
+	return b.Bytes(), nil
+}
+
+// line writes text cut to the width
+func (r *TextRenderer) line(b *bytes.Buffer, text string) {
+	b.WriteString(truncate(text, r.width))
+	b.WriteByte('\n')
+}
+
+// center writes text centred in the width
+func (r *TextRenderer) center(b *bytes.Buffer, text string) {
+	text = truncate(text, r.width)
+	r.line(b, strings.Repeat(" ", (r.width-utf8.RuneCountInString(text))/2)+text)
+}
+
+// pair writes left and right aligned to the edges, cutting left when they
+// do not fit together
+func (r *TextRenderer) pair(b *bytes.Buffer, left, right string) {
+	room := r.width - utf8.RuneCountInString(right) - 1
+	left = truncate(left, room)
+	r.line(b, left+strings.Repeat(" ", room-utf8.RuneCountInString(left)+1)+right)
+}
+
+func (r *TextRenderer) rule(b *bytes.Buffer) {
+	r.line(b, strings.Repeat("-", r.width))
+}
+
+func truncate(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width])
+}
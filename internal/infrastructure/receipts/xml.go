@@ -0,0 +1,220 @@
+package receipts
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+)
+
+// XMLRenderer renders receipts in an NFC-e–like XML layout. The document is
+// not signed nor authorized, so it is not a valid NFC-e; the tags follow the
+// NFC-e names so fiscal integrations can map them.
+type XMLRenderer struct {
+	location *time.Location
+}
+
+// NewXMLRenderer creates a renderer that writes the issue time in location
+func NewXMLRenderer(location *time.Location) *XMLRenderer {
+	return &XMLRenderer{
+		location: location,
+	}
+}
+
+type nfe struct {
+	XMLName xml.Name `xml:"NFe"`
+	InfNFe  infNFe   `xml:"infNFe"`
+}
+
+type infNFe struct {
+	Versao  string   `xml:"versao,attr"`
+	Ide     ide      `xml:"ide"`
+	Emit    emit     `xml:"emit"`
+	Dest    *dest    `xml:"dest,omitempty"`
+	Det     []det    `xml:"det"`
+	Total   total    `xml:"total"`
+	Pag     pag      `xml:"pag"`
+	InfAdic *infAdic `xml:"infAdic,omitempty"`
+}
+
+type ide struct {
+	Mod   string `xml:"mod"`
+	Serie string `xml:"serie"`
+	NNF   uint64 `xml:"nNF"`
+	DhEmi string `xml:"dhEmi"`
+}
+
+type emit struct {
+	CNPJ      string     `xml:"CNPJ,omitempty"`
+	XNome     string     `xml:"xNome"`
+	EnderEmit *enderEmit `xml:"enderEmit,omitempty"`
+}
+
+type enderEmit struct {
+	XLgr string `xml:"xLgr"`
+}
+
+type dest struct {
+	CPF string `xml:"CPF"`
+}
+
+type det struct {
+	NItem   int     `xml:"nItem,attr"`
+	Prod    prod    `xml:"prod"`
+	Imposto imposto `xml:"imposto"`
+}
+
+type prod struct {
+	CProd  uint64 `xml:"cProd"`
+	XProd  string `xml:"xProd"`
+	UCom   string `xml:"uCom"`
+	QCom   string `xml:"qCom"`
+	VUnCom string `xml:"vUnCom"`
+	VProd  string `xml:"vProd"`
+}
+
+type imposto struct {
+	VTotTrib string `xml:"vTotTrib"`
+}
+
+type total struct {
+	ICMSTot icmsTot `xml:"ICMSTot"`
+}
+
+type icmsTot struct {
+	VProd    string `xml:"vProd"`
+	VDesc    string `xml:"vDesc"`
+	VOutro   string `xml:"vOutro"`
+	VNF      string `xml:"vNF"`
+	VTotTrib string `xml:"vTotTrib"`
+}
+
+type pag struct {
+	DetPag []detPag `xml:"detPag"`
+}
+
+type detPag struct {
+	TPag string `xml:"tPag"`
+	XPag string `xml:"xPag,omitempty"`
+	VPag string `xml:"vPag"`
+}
+
+type infAdic struct {
+	InfCpl string `xml:"infCpl"`
+}
+
+// Render writes the receipt as an indented XML document. Items carry the
+// tax of their category in proportion to their value; discounts, and
+// rounding that takes off, go in vDesc, while the service fee, and rounding
+// that adds, go in vOutro.
+func (r *XMLRenderer) Render(receipt *entities.Receipt) ([]byte, error) {
+	document := nfe{InfNFe: infNFe{
+		Versao: "4.00",
+		Ide: ide{
+			Mod:   "65",
+			Serie: "1",
+			NNF:   receipt.OrderID,
+			DhEmi: receipt.IssuedAt.In(r.location).Format(time.RFC3339),
+		},
+		Emit: emit{
+			CNPJ:  digits(receipt.Issuer.CNPJ),
+			XNome: receipt.Issuer.Name,
+		},
+	}}
+	if receipt.Issuer.Address != "" {
+		document.InfNFe.Emit.EnderEmit = &enderEmit{XLgr: receipt.Issuer.Address}
+	}
+	if receipt.CPF != "" {
+		document.InfNFe.Dest = &dest{CPF: digits(receipt.CPF)}
+	}
+
+	itemTaxes := r.itemTaxes(receipt)
+	for i, item := range receipt.Items {
+		document.InfNFe.Det = append(document.InfNFe.Det, det{
+			NItem: item.Number,
+			Prod: prod{
+				CProd:  item.ProductID,
+				XProd:  item.Description,
+				UCom:   "UN",
+				QCom:   fmt.Sprintf("%.4f", float64(item.Quantity)),
+				VUnCom: decimal(item.UnitPrice),
+				VProd:  decimal(item.Total),
+			},
+			Imposto: imposto{VTotTrib: decimal(itemTaxes[i])},
+		})
+	}
+
+	discount, other := receipt.Discount, receipt.ServiceFee
+	if receipt.Rounding < 0 {
+		discount -= receipt.Rounding
+	} else {
+		other += receipt.Rounding
+	}
+	document.InfNFe.Total.ICMSTot = icmsTot{
+		VProd:    decimal(receipt.Subtotal),
+		VDesc:    decimal(discount),
+		VOutro:   decimal(other),
+		VNF:      decimal(receipt.Total),
+		VTotTrib: decimal(receipt.TaxTotal),
+	}
+
+	for _, payment := range receipt.Payments {
+		line := detPag{TPag: "99", VPag: decimal(payment.Amount)}
+		if code, ok := paymentCodes[payment.Method]; ok {
+			line.TPag = code
+		} else {
+			line.XPag = payment.Method
+		}
+		document.InfNFe.Pag.DetPag = append(document.InfNFe.Pag.DetPag, line)
+	}
+
+	if len(receipt.Taxes) > 0 {
+		var taxes []string
+		for _, tax := range receipt.Taxes {
+			taxes = append(taxes, fmt.Sprintf("%s %s R$ %s", categoryName(tax.Category), percent(tax.Rate), reais(tax.Amount)))
+		}
+		document.InfNFe.InfAdic = &infAdic{InfCpl: fmt.Sprintf("Valor aproximado dos tributos R$ %s (%s). Fonte: Lei 12.741/2012",
+			reais(receipt.TaxTotal), strings.Join(taxes, "; "))}
+	}
+
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
+
+// itemTaxes shares each category's tax among its items by value. The last
+// item of a category takes what rounding leaves, so the shares add up.
+func (r *XMLRenderer) itemTaxes(receipt *entities.Receipt) []float32 {
+	categoryTotals := make(map[string]float64)
+	for _, item := range receipt.Items {
+		categoryTotals[item.Category] += float64(item.Total)
+	}
+	left := make(map[string]float64)
+	lastItem := make(map[string]int)
+	for _, tax := range receipt.Taxes {
+		left[tax.Category] = float64(tax.Amount)
+	}
+	for i, item := range receipt.Items {
+		lastItem[item.Category] = i
+	}
+
+	shares := make([]float32, len(receipt.Items))
+	for i, item := range receipt.Items {
+		tax, taxed := left[item.Category]
+		if !taxed || categoryTotals[item.Category] == 0 {
+			continue
+		}
+		share := tax
+		if lastItem[item.Category] != i {
+			share = roundCents(tax * float64(item.Total) / categoryTotals[item.Category])
+			categoryTotals[item.Category] -= float64(item.Total)
+		}
+		left[item.Category] = roundCents(tax - share)
+		shares[i] = float32(share)
+	}
+	return shares
+}
@@ -0,0 +1,176 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+const namespace = "fastfood"
+
+// kitchenScrapeTimeout bounds the query made for every /metrics scrape
+const kitchenScrapeTimeout = 2 * time.Second
+
+// Metrics owns the Prometheus registry served on /metrics. Besides the HTTP
+// and runtime collectors it implements output.Metrics for the use cases.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	ordersCreated      *prometheus.CounterVec
+	orderStatusChanges *prometheus.CounterVec
+	paymentsSettled    *prometheus.CounterVec
+	webhookDuration    *prometheus.HistogramVec
+}
+
+// NewMetrics registers every collector on a fresh registry. The kitchen
+// queue depth is read from orderGateway at scrape time.
+func NewMetrics(orderGateway output.OrderGateway) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		ordersCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_created_total",
+			Help:      "Orders created, by their status at creation.",
+		}, []string{"status"}),
+		orderStatusChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "order_status_changes_total",
+			Help:      "Order status changes, by the new status.",
+		}, []string{"status"}),
+		paymentsSettled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_settled_total",
+			Help:      "Payments moved to a final status by the webhook.",
+		}, []string{"status"}),
+		webhookDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "payment_webhook_duration_seconds",
+			Help:      "Time spent processing payment webhooks, by result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.ordersCreated,
+		m.orderStatusChanges,
+		m.paymentsSettled,
+		m.webhookDuration,
+		newKitchenQueueCollector(orderGateway),
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTP records one finished request. route is the route template, not
+// the raw path, to keep label cardinality bounded.
+func (m *Metrics) ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) OrderCreated(status entities.OrderStatus) {
+	m.ordersCreated.WithLabelValues(string(status)).Inc()
+}
+
+func (m *Metrics) OrderStatusChanged(status entities.OrderStatus) {
+	m.orderStatusChanges.WithLabelValues(string(status)).Inc()
+}
+
+func (m *Metrics) PaymentSettled(status entities.PaymentStatus) {
+	m.paymentsSettled.WithLabelValues(string(status)).Inc()
+}
+
+func (m *Metrics) WebhookProcessed(elapsed time.Duration, failed bool) {
+	result := "ok"
+	if failed {
+		result = "error"
+	}
+	m.webhookDuration.WithLabelValues(result).Observe(elapsed.Seconds())
+}
+
+// kitchenQueueCollector reports how many orders are waiting in each kitchen
+// status. It queries the gateway on scrape so the value is never stale.
+type kitchenQueueCollector struct {
+	orders output.OrderGateway
+	desc   *prometheus.Desc
+}
+
+func newKitchenQueueCollector(orders output.OrderGateway) *kitchenQueueCollector {
+	return &kitchenQueueCollector{
+		orders: orders,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "kitchen", "queue_depth"),
+			"Orders in the kitchen queue, by status.",
+			[]string{"status"}, nil,
+		),
+	}
+}
+
+func (k *kitchenQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- k.desc
+}
+
+func (k *kitchenQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), kitchenScrapeTimeout)
+	defer cancel()
+
+	orders, err := k.orders.GetPendingOrdersForKitchen(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(k.desc, err)
+		return
+	}
+
+	depth := map[entities.OrderStatus]int{
+		entities.OrderReceived:   0,
+		entities.OrderInProgress: 0,
+		entities.OrderReady:      0,
+	}
+	for _, order := range orders {
+		depth[order.Status]++
+	}
+	for status, count := range depth {
+		ch <- prometheus.MustNewConstMetric(k.desc, prometheus.GaugeValue, float64(count), string(status))
+	}
+}
+
+// Discard returns an output.Metrics that records nothing, for tests and
+// tools that do not serve /metrics
+func Discard() output.Metrics {
+	return discard{}
+}
+
+type discard struct{}
+
+func (discard) OrderCreated(entities.OrderStatus)       {}
+func (discard) OrderStatusChanged(entities.OrderStatus) {}
+func (discard) PaymentSettled(entities.PaymentStatus)   {}
+func (discard) WebhookProcessed(time.Duration, bool)    {}
@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	return recorder.Body.String()
+}
+
+func TestMetrics_KitchenQueueDepth(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	orders := memory.NewOrderGateway(memory.NewStore())
+	for _, status := range []entities.OrderStatus{entities.OrderReceived, entities.OrderReceived, entities.OrderReady, entities.OrderCompleted} {
+		order := entities.NewOrder(0, "")
+		order.Status = status
+		orders.Create(ctx, order)
+	}
+	m := NewMetrics(orders)
+
+	// Act
+	body := scrape(t, m)
+
+	// Assert
+	for _, line := range []string{
+		`fastfood_kitchen_queue_depth{status="received"} 2`,
+		`fastfood_kitchen_queue_depth{status="in_progress"} 0`,
+		`fastfood_kitchen_queue_depth{status="ready"} 1`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected %q in scrape output", line)
+		}
+	}
+}
+
+func TestMetrics_BusinessEvents(t *testing.T) {
+	// Arrange
+	m := NewMetrics(memory.NewOrderGateway(memory.NewStore()))
+
+	// Act
+	m.OrderCreated(entities.OrderAwaitingPayment)
+	m.PaymentSettled(entities.PaymentStatusApproved)
+	m.PaymentSettled(entities.PaymentStatusRejected)
+	m.WebhookProcessed(20*time.Millisecond, false)
+	m.ObserveHTTP(http.MethodPost, "/api/v1/orders", http.StatusCreated, time.Millisecond)
+	body := scrape(t, m)
+
+	// Assert
+	for _, line := range []string{
+		`fastfood_orders_created_total{status="awaiting_payment"} 1`,
+		`fastfood_payments_settled_total{status="approved"} 1`,
+		`fastfood_payments_settled_total{status="rejected"} 1`,
+		`fastfood_payment_webhook_duration_seconds_count{result="ok"} 1`,
+		`fastfood_http_requests_total{method="POST",route="/api/v1/orders",status="201"} 1`,
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected %q in scrape output", line)
+		}
+	}
+}
+
+func TestSetupTracing_RejectsUnknownExporter(t *testing.T) {
+	// Act
+	_, err := SetupTracing(context.Background(), "zipkin", DefaultServiceName, io.Discard)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error for an unsupported exporter")
+	}
+}
@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Supported values for the OTEL_TRACES_EXPORTER environment variable
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultServiceName is reported when no service name is configured
+const DefaultServiceName = "fast-food-api"
+
+// SetupTracing installs the global tracer provider and the W3C trace context
+// propagator. The none exporter keeps the no-op provider, so spans cost
+// nothing and no network is needed; stdout writes finished spans to w and
+// otlp sends them over HTTP to OTEL_EXPORTER_OTLP_ENDPOINT. The returned
+// function flushes pending spans and must be called on shutdown.
+func SetupTracing(ctx context.Context, exporter, serviceName string, w io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported traces exporter: %s", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	// OTEL_RESOURCE_ATTRIBUTES can add attributes to the resource
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
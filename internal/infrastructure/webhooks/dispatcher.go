@@ -0,0 +1,245 @@
+// Package webhooks sends queued webhook deliveries to partner endpoints.
+// Each request is signed with the subscription secret; failures are retried
+// with exponential backoff until MaxAttempts, after which the delivery is
+// failed and can only be sent again through a manual redelivery.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+// Headers sent with every delivery
+const (
+	DeliveryIDHeader = "X-Webhook-Id"
+	EventIDHeader    = "X-Webhook-Event-Id"
+	EventTypeHeader  = "X-Webhook-Event"
+	TimestampHeader  = "X-Webhook-Timestamp"
+	SignatureHeader  = "X-Webhook-Signature"
+)
+
+// signaturePrefix names the algorithm in SignatureHeader
+const signaturePrefix = "sha256="
+
+// Defaults used when a Config field is zero
+const (
+	DefaultPollInterval   = time.Second
+	DefaultBatchSize      = 50
+	DefaultMaxAttempts    = 10
+	DefaultInitialBackoff = 30 * time.Second
+	DefaultMaxBackoff     = time.Hour
+)
+
+// ClaimLease is how long a claimed delivery is hidden from the other
+// replicas polling for due deliveries; requests must time out well before
+const ClaimLease = time.Minute
+
+// Config tunes the dispatcher. The first retry waits InitialBackoff and each
+// following one twice as long, up to MaxBackoff.
+type Config struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+type Dispatcher struct {
+	subscriptions output.WebhookSubscriptionGateway
+	deliveries    output.WebhookDeliveryGateway
+	client        *http.Client
+	logger        *slog.Logger
+	config        Config
+	now           func() time.Time
+}
+
+func NewDispatcher(
+	subscriptions output.WebhookSubscriptionGateway,
+	deliveries output.WebhookDeliveryGateway,
+	client *http.Client,
+	logger *slog.Logger,
+	config Config,
+) *Dispatcher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+
+	return &Dispatcher{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		client:        client,
+		logger:        logger,
+		config:        config,
+		now:           time.Now,
+	}
+}
+
+// Run sends due deliveries every PollInterval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.dispatchAll(ctx)
+		}
+	}
+}
+
+// dispatchAll sends batches of due deliveries until one comes back short
+func (d *Dispatcher) dispatchAll(ctx context.Context) {
+	for {
+		dispatched, err := d.DispatchDue(ctx)
+		if err != nil {
+			d.logger.WarnContext(ctx, "Failed to read webhook deliveries", "error", err)
+		}
+		// A full batch means more deliveries may be waiting
+		if err != nil || dispatched < d.config.BatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// DispatchDue sends one batch of due deliveries and saves the outcome of
+// each. It returns how many deliveries were due, claimed by this replica or
+// not.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	now := d.now()
+	deliveries, err := d.deliveries.ListDue(ctx, now, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := map[uint64]*entities.WebhookSubscription{}
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.subscriptions.GetByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				return 0, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		// Deleted while the batch was read; its deliveries are gone too
+		if subscription == nil {
+			continue
+		}
+
+		// Every replica polls, so each delivery is claimed before it is sent
+		claimed, err := d.deliveries.Claim(ctx, delivery, now, d.now().Add(ClaimLease))
+		if err != nil {
+			return 0, err
+		}
+		if claimed {
+			d.dispatch(ctx, subscription, delivery)
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, subscription *entities.WebhookSubscription, delivery *entities.WebhookDelivery) {
+	statusCode, err := d.send(ctx, subscription, delivery)
+	if err == nil {
+		delivery.MarkSucceeded(statusCode, d.now())
+	} else {
+		var retryAt *time.Time
+		if delivery.Attempts+1 < d.config.MaxAttempts {
+			next := d.now().Add(d.backoff(delivery.Attempts))
+			retryAt = &next
+		}
+		delivery.MarkFailed(statusCode, err, retryAt)
+		d.logger.WarnContext(ctx, "Failed to deliver webhook",
+			"delivery_id", delivery.ID,
+			"subscription_id", subscription.ID,
+			"event_type", delivery.EventType,
+			"attempts", delivery.Attempts,
+			"status", delivery.Status,
+			"error", err,
+		)
+	}
+
+	// If this fails the delivery stays due and is sent again; partners
+	// deduplicate by event ID
+	if err := d.deliveries.Update(context.WithoutCancel(ctx), delivery); err != nil {
+		d.logger.ErrorContext(ctx, "Failed to save webhook delivery state", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send POSTs the delivery payload and returns the response status, zero when
+// no response arrived. Any status other than 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, subscription *entities.WebhookSubscription, delivery *entities.WebhookDelivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(DeliveryIDHeader, strconv.FormatUint(delivery.ID, 10))
+	request.Header.Set(EventIDHeader, strconv.FormatUint(delivery.EventID, 10))
+	request.Header.Set(EventTypeHeader, delivery.EventType)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("endpoint answered %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// backoff returns how long to wait after the given number of failed attempts
+func (d *Dispatcher) backoff(failedAttempts int) time.Duration {
+	delay := d.config.InitialBackoff
+	for i := 0; i < failedAttempts && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.config.MaxBackoff)
+}
+
+// Sign returns the SignatureHeader value for a delivery: the hex HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the subscription secret. Partners
+// recompute it and compare in constant time; including the timestamp lets
+// them reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
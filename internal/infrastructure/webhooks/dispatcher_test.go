@@ -0,0 +1,207 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+var ctx = context.Background()
+
+type fixture struct {
+	subscriptions output.WebhookSubscriptionGateway
+	deliveries    output.WebhookDeliveryGateway
+	subscription  *entities.WebhookSubscription
+	delivery      *entities.WebhookDelivery
+}
+
+func newFixture(t *testing.T, url string) *fixture {
+	t.Helper()
+	store := memory.NewStore()
+	f := &fixture{
+		subscriptions: memory.NewWebhookSubscriptionGateway(store),
+		deliveries:    memory.NewWebhookDeliveryGateway(store),
+	}
+
+	f.subscription = entities.NewWebhookSubscription(url, "whsec_test", []string{"order.ready"})
+	f.subscriptions.Create(ctx, f.subscription)
+	f.delivery = entities.NewWebhookDelivery(f.subscription.ID, 42, "order.ready", []byte(`{"id":42}`))
+	f.deliveries.Create(ctx, f.delivery)
+	return f
+}
+
+func (f *fixture) newDispatcher(client *http.Client, config Config) *Dispatcher {
+	return NewDispatcher(f.subscriptions, f.deliveries, client, logging.Discard(), config)
+}
+
+func TestDispatcher_SendsSignedDelivery(t *testing.T) {
+	// Arrange
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	f := newFixture(t, server.URL)
+
+	// Act
+	dispatched, err := f.newDispatcher(server.Client(), Config{}).DispatchDue(ctx)
+
+	// Assert
+	if err != nil || dispatched != 1 {
+		t.Fatalf("Expected 1 delivery dispatched, got %d, %v", dispatched, err)
+	}
+	if string(body) != `{"id":42}` {
+		t.Errorf("Expected the stored payload, got %s", body)
+	}
+
+	timestamp, _ := strconv.ParseInt(received.Header.Get(TimestampHeader), 10, 64)
+	if signature := received.Header.Get(SignatureHeader); signature != Sign("whsec_test", timestamp, body) {
+		t.Errorf("Expected a valid signature, got %q", signature)
+	}
+	if received.Header.Get(EventTypeHeader) != "order.ready" || received.Header.Get(EventIDHeader) != "42" {
+		t.Errorf("Expected event headers, got %v", received.Header)
+	}
+
+	delivery, _ := f.deliveries.GetByID(ctx, f.delivery.ID)
+	if delivery.Status != entities.WebhookDeliverySucceeded || delivery.LastStatusCode != http.StatusNoContent || delivery.Attempts != 1 {
+		t.Errorf("Expected a succeeded delivery, got %+v", delivery)
+	}
+}
+
+func TestDispatcher_RetriesThenGivesUp(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	f := newFixture(t, server.URL)
+	dispatcher := f.newDispatcher(server.Client(), Config{MaxAttempts: 2, InitialBackoff: time.Minute})
+	now := time.Now()
+	dispatcher.now = func() time.Time { return now }
+
+	// Act
+	dispatcher.DispatchDue(ctx)
+	retrying, _ := f.deliveries.GetByID(ctx, f.delivery.ID)
+	now = now.Add(time.Minute)
+	dispatcher.DispatchDue(ctx)
+	failed, _ := f.deliveries.GetByID(ctx, f.delivery.ID)
+
+	// Assert
+	if retrying.Status != entities.WebhookDeliveryPending || !retrying.NextAttemptAt.Equal(now) {
+		t.Errorf("Expected a retry one minute later, got %+v", retrying)
+	}
+	if failed.Status != entities.WebhookDeliveryFailed || failed.Attempts != 2 || failed.LastStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the delivery to give up after 2 attempts, got %+v", failed)
+	}
+	if due, _ := f.deliveries.ListDue(ctx, now.Add(24*time.Hour), 10); len(due) != 0 {
+		t.Errorf("Expected no due deliveries, got %d", len(due))
+	}
+}
+
+func TestDispatcher_SendsEveryDueBatch(t *testing.T) {
+	// Arrange
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	f := newFixture(t, server.URL)
+	for eventID := uint64(43); eventID <= 44; eventID++ {
+		f.deliveries.Create(ctx, entities.NewWebhookDelivery(f.subscription.ID, eventID, "order.ready", []byte(`{}`)))
+	}
+	dispatcher := f.newDispatcher(server.Client(), Config{BatchSize: 2})
+
+	// Act
+	dispatcher.dispatchAll(ctx)
+
+	// Assert
+	if sent := requests.Load(); sent != 3 {
+		t.Errorf("Expected the 3 due deliveries sent in one round of batches, got %d", sent)
+	}
+}
+
+// rendezvousDue holds each of the first two due lists until the other one
+// was read or a short wait passes, so two replicas both see the same
+// deliveries due
+type rendezvousDue struct {
+	output.WebhookDeliveryGateway
+	lists atomic.Int32
+	both  chan struct{}
+}
+
+func (g *rendezvousDue) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	due, err := g.WebhookDeliveryGateway.ListDue(ctx, now, limit)
+	if n := g.lists.Add(1); n == 2 {
+		close(g.both)
+	} else if n == 1 {
+		select {
+		case <-g.both:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return due, err
+}
+
+func TestDispatcher_EachDeliveryIsSentByOneReplica(t *testing.T) {
+	// Arrange
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	f := newFixture(t, server.URL)
+	deliveries := &rendezvousDue{WebhookDeliveryGateway: f.deliveries, both: make(chan struct{})}
+
+	// Act
+	var wg sync.WaitGroup
+	for range 2 {
+		replica := NewDispatcher(f.subscriptions, deliveries, server.Client(), logging.Discard(), Config{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replica.DispatchDue(ctx)
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if sent := requests.Load(); sent != 1 {
+		t.Errorf("Expected the delivery sent once across replicas, got %d", sent)
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	dispatcher := NewDispatcher(nil, nil, nil, logging.Discard(), Config{InitialBackoff: 30 * time.Second, MaxBackoff: time.Hour})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{4, 8 * time.Minute},
+		{7, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := dispatcher.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
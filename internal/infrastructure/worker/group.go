@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Group runs long-lived background jobs (relays, schedulers) that must stop
+// when the server shuts down. Every job receives the same context; cancel it
+// and call Wait to drain them.
+type Group struct {
+	logger *slog.Logger
+	wg     sync.WaitGroup
+}
+
+func NewGroup(logger *slog.Logger) *Group {
+	return &Group{logger: logger}
+}
+
+// Go starts run in its own goroutine. A job that returns an error other than
+// the context's is logged; it is not restarted.
+func (g *Group) Go(ctx context.Context, name string, run func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		g.logger.InfoContext(ctx, "worker started", "worker", name)
+		err := run(ctx)
+		if err != nil && ctx.Err() == nil {
+			g.logger.ErrorContext(ctx, "worker stopped unexpectedly", "worker", name, "error", err)
+			return
+		}
+		g.logger.Info("worker stopped", "worker", name)
+	}()
+}
+
+// Wait blocks until every job has returned or ctx expires, in which case it
+// returns ctx.Err()
+func (g *Group) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
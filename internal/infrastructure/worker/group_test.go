@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+)
+
+func TestGroup_WaitReturnsAfterJobsStop(t *testing.T) {
+	// Arrange
+	group := NewGroup(logging.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stopped := make(chan struct{})
+	group.Go(ctx, "relay", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	})
+
+	// Act
+	cancel()
+	err := group.Wait(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("Expected the job to have stopped before Wait returned")
+	}
+}
+
+func TestGroup_WaitGivesUpAtDeadline(t *testing.T) {
+	// Arrange
+	group := NewGroup(logging.Discard())
+	release := make(chan struct{})
+	defer close(release)
+
+	group.Go(context.Background(), "stuck", func(context.Context) error {
+		<-release
+		return errors.New("released")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := group.Wait(ctx)
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
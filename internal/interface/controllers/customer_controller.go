@@ -30,84 +30,79 @@ func NewCustomerController(
 // @Description Create new customer
 // @Tags customers
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param customer body dto.CreateCustomerRequest true "customer"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.CustomerResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /customers [post]
 func (ctrl *CustomerController) CreateCustomer(c *gin.Context) {
 	var request dto.CreateCustomerRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidBody(err))
 		return
 	}
 
-	customer, err := ctrl.customerUseCase.CreateCustomer(&request)
+	customer, err := ctrl.customerUseCase.CreateCustomer(c.Request.Context(), &request)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentCustomer(customer)
-	c.JSON(http.StatusCreated, response)
+	respond(c, http.StatusCreated, ctrl.presenter.PresentCustomer(customer))
 }
 
 // GetCustomerByCPF godoc
 // @Summary Get customer by CPF
 // @Description Get customer by CPF
 // @Tags customers
-// @Produce json
+// @Produce json,xml
 // @Param cpf path string true "Customer CPF"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Param X-Client-Id header string false "Client (totem) identifier for rate limiting"
+// @Success 200 {object} presenters.Response[dto.CustomerResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 429 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /customers/{cpf} [get]
 func (ctrl *CustomerController) GetCustomerByCPF(c *gin.Context) {
 	cpf := c.Param("cpf")
 
-	customer, err := ctrl.customerUseCase.GetCustomerByCPF(cpf)
+	customer, err := ctrl.customerUseCase.GetCustomerByCPF(c.Request.Context(), cpf)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusNotFound, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentCustomer(customer)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentCustomer(customer))
 }
 
 // GetCustomerByID godoc
 // @Summary Get customer by ID
 // @Description Get customer by ID
 // @Tags customers
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Customer ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.CustomerResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /customers/id/{id} [get]
 func (ctrl *CustomerController) GetCustomerByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	customer, err := ctrl.customerUseCase.GetCustomerByID(id)
+	customer, err := ctrl.customerUseCase.GetCustomerByID(c.Request.Context(), id)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusNotFound, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentCustomer(customer)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentCustomer(customer))
 }
 
 // UpdateCustomer godoc
@@ -115,67 +110,60 @@ func (ctrl *CustomerController) GetCustomerByID(c *gin.Context) {
 // @Description Update customer information
 // @Tags customers
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Customer ID"
 // @Param customer body dto.UpdateCustomerRequest true "customer"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.CustomerResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /customers/{id} [put]
 func (ctrl *CustomerController) UpdateCustomer(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
 	var request dto.UpdateCustomerRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidBody(err))
 		return
 	}
 
-	customer, err := ctrl.customerUseCase.UpdateCustomer(id, &request)
+	customer, err := ctrl.customerUseCase.UpdateCustomer(c.Request.Context(), id, &request)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentCustomer(customer)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentCustomer(customer))
 }
 
 // DeleteCustomer godoc
 // @Summary Delete customer
 // @Description Delete customer by ID
 // @Tags customers
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Customer ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[any]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /customers/{id} [delete]
 func (ctrl *CustomerController) DeleteCustomer(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	err = ctrl.customerUseCase.DeleteCustomer(id)
+	err = ctrl.customerUseCase.DeleteCustomer(c.Request.Context(), id)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentSuccess("Customer deleted successfully")
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentSuccess("Customer deleted successfully"))
 }
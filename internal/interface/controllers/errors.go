@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+)
+
+// Handlers report failures with c.Error; middleware.ErrorHandler turns them
+// into problem responses.
+
+func invalidBody(err error) error {
+	return errs.Validation(errs.CodeInvalidRequest, err.Error())
+}
+
+func invalidParam(name, value string) error {
+	return errs.Validation(errs.CodeInvalidRequest, fmt.Sprintf("invalid %s: %q", name, value))
+}
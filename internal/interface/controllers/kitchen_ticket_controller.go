@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type KitchenTicketController struct {
+	kitchenTicketUseCase input.KitchenTicketUseCase
+	presenter            presenters.KitchenTicketPresenter
+}
+
+func NewKitchenTicketController(
+	kitchenTicketUseCase input.KitchenTicketUseCase,
+	presenter presenters.KitchenTicketPresenter,
+) *KitchenTicketController {
+	return &KitchenTicketController{
+		kitchenTicketUseCase: kitchenTicketUseCase,
+		presenter:            presenter,
+	}
+}
+
+// ReprintTicket godoc
+// @Summary Reprint an order's kitchen ticket
+// @Description Queue the kitchen ticket of a paid order again, e.g. after the paper jammed or the printer gave up on it. Each copy is numbered; copies after the first are marked as reprints. The copy comes back pending and is printed in the background, retried while the printer fails.
+// @Tags orders
+// @Produce json,xml
+// @Param id path int true "Order ID"
+// @Success 201 {object} presenters.Response[dto.KitchenTicketResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /orders/{id}/kitchen-ticket/reprint [post]
+func (ctrl *KitchenTicketController) ReprintTicket(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	ticket, err := ctrl.kitchenTicketUseCase.ReprintTicket(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusCreated, ctrl.presenter.PresentKitchenTicket(ticket))
+}
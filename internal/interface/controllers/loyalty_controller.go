@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type LoyaltyController struct {
+	loyaltyUseCase input.LoyaltyUseCase
+	presenter      presenters.LoyaltyPresenter
+}
+
+func NewLoyaltyController(
+	loyaltyUseCase input.LoyaltyUseCase,
+	presenter presenters.LoyaltyPresenter,
+) *LoyaltyController {
+	return &LoyaltyController{
+		loyaltyUseCase: loyaltyUseCase,
+		presenter:      presenter,
+	}
+}
+
+// GetCustomerLoyalty godoc
+// @Summary Customer loyalty points
+// @Description Balance, next expiration and ledger of a customer's loyalty points. Completed orders earn points, redemptions at checkout spend them, cancellations give redeemed points back, refunds take earned points away and unused points expire
+// @Tags customers
+// @Produce json,xml
+// @Param id path int true "Customer ID"
+// @Success 200 {object} presenters.Response[dto.LoyaltyResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /customers/{id}/loyalty [get]
+func (ctrl *LoyaltyController) GetCustomerLoyalty(c *gin.Context) {
+	// The router names this segment cpf, as gin allows one wildcard name
+	// per position and GET /customers/:cpf came first
+	idStr := c.Param("cpf")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	loyalty, err := ctrl.loyaltyUseCase.GetCustomerLoyalty(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentLoyalty(loyalty))
+}
@@ -30,220 +30,240 @@ func NewOrderController(
 // @Description Create new order with items
 // @Tags orders
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param order body dto.CreateOrderRequest true "order"
-// @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Param X-Client-Id header string false "Client (totem) identifier for rate limiting"
+// @Param Idempotency-Key header string false "Makes retries replay the first response"
+// @Success 201 {object} presenters.Response[dto.OrderResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 422 {object} middleware.Problem
+// @Failure 429 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders [post]
 func (ctrl *OrderController) CreateOrder(c *gin.Context) {
 	var request dto.CreateOrderRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidBody(err))
 		return
 	}
 
-	order, err := ctrl.orderUseCase.CreateOrder(&request)
+	order, err := ctrl.orderUseCase.CreateOrder(c.Request.Context(), &request)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrder(order)
-	c.JSON(http.StatusCreated, response)
+	respond(c, http.StatusCreated, ctrl.presenter.PresentOrder(order))
 }
 
 // GetOrderByID godoc
 // @Summary Get order by ID
 // @Description Get order by ID with items
 // @Tags orders
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Order ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.OrderResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders/{id} [get]
 func (ctrl *OrderController) GetOrderByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	order, err := ctrl.orderUseCase.GetOrderByID(id)
+	order, err := ctrl.orderUseCase.GetOrderByID(c.Request.Context(), id)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusNotFound, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrder(order)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentOrder(order))
 }
 
 // GetOrdersByCPF godoc
 // @Summary Get orders by CPF
 // @Description Get all orders for a specific CPF
 // @Tags orders
-// @Produce json
+// @Produce json,xml
 // @Param cpf path string true "Customer CPF"
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.OrderResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders/cpf/{cpf} [get]
 func (ctrl *OrderController) GetOrdersByCPF(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
 	cpf := c.Param("cpf")
 
-	orders, err := ctrl.orderUseCase.GetOrdersByCPF(cpf)
+	orders, err := ctrl.orderUseCase.GetOrdersByCPF(c.Request.Context(), cpf)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrders(orders)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentOrders(orders, page))
 }
 
 // GetOrdersByCustomerID godoc
 // @Summary Get orders by customer ID
 // @Description Get all orders for a specific customer ID
 // @Tags orders
-// @Produce json
+// @Produce json,xml
 // @Param customerId path int true "Customer ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.OrderResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders/customer/{customerId} [get]
 func (ctrl *OrderController) GetOrdersByCustomerID(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
 	customerIdStr := c.Param("customerId")
 	customerId, err := strconv.ParseUint(customerIdStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("customerId", customerIdStr))
 		return
 	}
 
-	orders, err := ctrl.orderUseCase.GetOrdersByCustomerID(customerId)
+	orders, err := ctrl.orderUseCase.GetOrdersByCustomerID(c.Request.Context(), customerId)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrders(orders)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentOrders(orders, page))
 }
 
 // GetAllOrders godoc
 // @Summary Get all orders
 // @Description Get all orders in the system
 // @Tags orders
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Produce json,xml
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.OrderResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders [get]
 func (ctrl *OrderController) GetAllOrders(c *gin.Context) {
-	orders, err := ctrl.orderUseCase.GetAllOrders()
+	page, err := parsePage(c)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrders(orders)
-	c.JSON(http.StatusOK, response)
+	orders, err := ctrl.orderUseCase.GetAllOrders(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentOrders(orders, page))
 }
 
 // GetOrdersForKitchen godoc
 // @Summary Get orders for kitchen
-// @Description Get orders for kitchen with priority ordering (Ready > In Progress > Received) and oldest first. Orders awaiting payment and completed orders are excluded.
+// @Description Get orders for kitchen with priority ordering (Ready > In Progress > Received) and oldest first. Orders awaiting payment and completed orders are excluded. With KITCHEN_STATIONS set, each station works from its own queue at /kitchen/stations/{station}/tickets.
 // @Tags orders
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Produce json,xml
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.OrderResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders/kitchen [get]
 func (ctrl *OrderController) GetOrdersForKitchen(c *gin.Context) {
-	orders, err := ctrl.orderUseCase.GetOrdersForKitchen()
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	orders, err := ctrl.orderUseCase.GetOrdersForKitchen(c.Request.Context())
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrders(orders)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentOrders(orders, page))
 }
 
 // UpdateOrderStatus godoc
 // @Summary Update order status
-// @Description Update the status of an existing order
+// @Description Update the status of an existing order. Allowed transitions: awaiting_payment > received > in_progress > ready > completed, and cancelled from any status before ready.
 // @Tags orders
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Order ID"
 // @Param status body dto.UpdateOrderStatusRequest true "status"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.OrderResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders/{id}/status [put]
 func (ctrl *OrderController) UpdateOrderStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
 	var request dto.UpdateOrderStatusRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidBody(err))
 		return
 	}
 
-	order, err := ctrl.orderUseCase.UpdateOrderStatus(id, &request)
+	order, err := ctrl.orderUseCase.UpdateOrderStatus(c.Request.Context(), id, &request)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentOrder(order)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentOrder(order))
 }
 
 // DeleteOrder godoc
 // @Summary Delete order
 // @Description Delete order by ID
 // @Tags orders
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Order ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[any]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /orders/{id} [delete]
 func (ctrl *OrderController) DeleteOrder(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	err = ctrl.orderUseCase.DeleteOrder(id)
+	err = ctrl.orderUseCase.DeleteOrder(c.Request.Context(), id)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentSuccess("Order deleted successfully")
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentSuccess("Order deleted successfully"))
 }
@@ -27,120 +27,177 @@ func NewPaymentController(
 
 // CreatePayment godoc
 // @Summary Create new payment
-// @Description Create a new payment for an order
+// @Description Start a payment attempt for an order awaiting payment. A bill can be split by creating one payment per share, each with its own amount and payer; shares that exceed what is not yet covered are rejected with 409 payment_exceeds_balance. Repeating a pending share (same amount and payer) returns it instead; after it was rejected or canceled a new attempt is created, e.g. with another payment method. Orders no longer awaiting payment are rejected with 409 order_not_awaiting_payment.
 // @Tags payments
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param payment body dto.CreatePaymentRequest true "payment"
-// @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Param Idempotency-Key header string false "Makes retries replay the first response"
+// @Success 201 {object} presenters.Response[dto.PaymentResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 422 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /payments [post]
 func (ctrl *PaymentController) CreatePayment(c *gin.Context) {
 	var request dto.CreatePaymentRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, ctrl.presenter.PresentError(err))
+		c.Error(invalidBody(err))
 		return
 	}
 
-	response, err := ctrl.paymentUseCase.CreatePayment(&request)
+	response, err := ctrl.paymentUseCase.CreatePayment(c.Request.Context(), &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ctrl.presenter.PresentError(err))
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, ctrl.presenter.PresentPayment(response))
+	respond(c, http.StatusCreated, ctrl.presenter.PresentPayment(response))
 }
 
 // GetPaymentStatus godoc
 // @Summary Get payment status by order ID
-// @Description Get the current payment status for an order
+// @Description Get the status of the order's latest payment attempt, with the order total and how much of it is paid and still due
 // @Tags payments
-// @Produce json
+// @Produce json,xml
 // @Param order_id path int true "Order ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.PaymentStatusResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /payments/status/{order_id} [get]
 func (ctrl *PaymentController) GetPaymentStatus(c *gin.Context) {
 	orderIDStr := c.Param("order_id")
 	orderID, err := strconv.ParseUint(orderIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ctrl.presenter.PresentError(err))
+		c.Error(invalidParam("order_id", orderIDStr))
 		return
 	}
 
-	response, err := ctrl.paymentUseCase.GetPaymentStatus(orderID)
+	response, err := ctrl.paymentUseCase.GetPaymentStatus(c.Request.Context(), orderID)
 	if err != nil {
-		if err.Error() == "payment not found for this order" {
-			c.JSON(http.StatusNotFound, ctrl.presenter.PresentError(err))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, ctrl.presenter.PresentError(err))
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ctrl.presenter.PresentPaymentStatus(response))
+	respond(c, http.StatusOK, ctrl.presenter.PresentPaymentStatus(response))
+}
+
+// GetPaymentAttempts godoc
+// @Summary List payment attempts of an order
+// @Description List every payment attempt of an order, oldest first, including each share of a split bill.
+// @Tags payments
+// @Produce json,xml
+// @Param order_id path int true "Order ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.PaymentResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /payments/attempts/{order_id} [get]
+func (ctrl *PaymentController) GetPaymentAttempts(c *gin.Context) {
+	orderIDStr := c.Param("order_id")
+	orderID, err := strconv.ParseUint(orderIDStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("order_id", orderIDStr))
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	attempts, err := ctrl.paymentUseCase.GetPaymentAttempts(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentPaymentAttempts(attempts, page))
 }
 
 // GetPaymentByTransactionID godoc
 // @Summary Get payment by transaction ID
 // @Description Get payment details by transaction ID
 // @Tags payments
-// @Produce json
+// @Produce json,xml
 // @Param transaction_id path string true "Transaction ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.PaymentResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /payments/transaction/{transaction_id} [get]
 func (ctrl *PaymentController) GetPaymentByTransactionID(c *gin.Context) {
 	transactionID := c.Param("transaction_id")
 	if transactionID == "" {
-		c.JSON(http.StatusBadRequest, ctrl.presenter.PresentError(gin.Error{Err: gin.Error{Type: gin.ErrorTypeBind}.Err, Type: gin.ErrorTypeBind}))
+		c.Error(invalidParam("transaction_id", transactionID))
 		return
 	}
 
-	response, err := ctrl.paymentUseCase.GetPaymentByTransactionID(transactionID)
+	response, err := ctrl.paymentUseCase.GetPaymentByTransactionID(c.Request.Context(), transactionID)
 	if err != nil {
-		if err.Error() == "payment not found" {
-			c.JSON(http.StatusNotFound, ctrl.presenter.PresentError(err))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, ctrl.presenter.PresentError(err))
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ctrl.presenter.PresentPayment(response))
+	respond(c, http.StatusOK, ctrl.presenter.PresentPayment(response))
 }
 
 // PaymentWebhook godoc
 // @Summary Payment webhook endpoint
-// @Description Webhook endpoint to receive payment status updates from payment provider
+// @Description Webhook endpoint to receive payment status updates from payment provider. The order is received once its approved payments cover the total. Updates for a payment that already expired are rejected with 409 payment_expired; refunds of a payment that was not approved with 409 payment_not_refundable; any other change to a payment that is no longer pending (e.g. approved to rejected) with 409 invalid_payment_transition.
 // @Tags payments
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param webhook body dto.WebhookPaymentRequest true "webhook payload"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[any]
+// @Failure 400 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /payments/webhook [post]
 func (ctrl *PaymentController) PaymentWebhook(c *gin.Context) {
 	var request dto.WebhookPaymentRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, ctrl.presenter.PresentError(err))
+		c.Error(invalidBody(err))
+		return
+	}
+
+	err := ctrl.paymentUseCase.ProcessWebhookPayment(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentSuccess("Payment webhook processed successfully"))
+}
+
+// RefundPayment godoc
+// @Summary Refund a payment
+// @Description Record the refund of an approved payment, e.g. one share of a split bill, and publish payment.refunded. The order keeps its status; the money itself is returned through the payment provider. Payments that are not approved are rejected with 409 payment_not_refundable.
+// @Tags payments
+// @Produce json,xml
+// @Param id path int true "Payment ID"
+// @Success 200 {object} presenters.Response[dto.PaymentResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /payments/{id}/refund [post]
+func (ctrl *PaymentController) RefundPayment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	err := ctrl.paymentUseCase.ProcessWebhookPayment(&request)
+	response, err := ctrl.paymentUseCase.RefundPayment(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "payment not found for this order" || err.Error() == "invalid payment status received" {
-			c.JSON(http.StatusBadRequest, ctrl.presenter.PresentError(err))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, ctrl.presenter.PresentError(err))
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ctrl.presenter.PresentSuccess("Payment webhook processed successfully"))
+	respond(c, http.StatusOK, ctrl.presenter.PresentPayment(response))
 }
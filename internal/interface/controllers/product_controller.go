@@ -30,104 +30,111 @@ func NewProductController(
 // @Description Create new product
 // @Tags products
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param product body dto.CreateProductRequest true "product"
-// @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 201 {object} presenters.Response[dto.ProductResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /products [post]
 func (ctrl *ProductController) CreateProduct(c *gin.Context) {
 	var request dto.CreateProductRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidBody(err))
 		return
 	}
 
-	product, err := ctrl.productUseCase.CreateProduct(&request)
+	product, err := ctrl.productUseCase.CreateProduct(c.Request.Context(), &request)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentProduct(product)
-	c.JSON(http.StatusCreated, response)
+	respond(c, http.StatusCreated, ctrl.presenter.PresentProduct(product))
 }
 
 // GetProductByID godoc
 // @Summary Get product by ID
 // @Description Get product by ID
 // @Tags products
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Product ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.ProductResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /products/{id} [get]
 func (ctrl *ProductController) GetProductByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	product, err := ctrl.productUseCase.GetProductByID(id)
+	product, err := ctrl.productUseCase.GetProductByID(c.Request.Context(), id)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusNotFound, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentProduct(product)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentProduct(product))
 }
 
 // GetProductsByCategory godoc
 // @Summary Get products by category
 // @Description Get products by category (snack, drink, dessert, side)
 // @Tags products
-// @Produce json
+// @Produce json,xml
 // @Param category path string true "Product Category"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.ProductResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /products/category/{category} [get]
 func (ctrl *ProductController) GetProductsByCategory(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
 	category := c.Param("category")
 
-	products, err := ctrl.productUseCase.GetProductsByCategory(category)
+	products, err := ctrl.productUseCase.GetProductsByCategory(c.Request.Context(), category)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentProducts(products)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentProducts(products, page))
 }
 
 // GetAllProducts godoc
 // @Summary Get all products
 // @Description Get all products
 // @Tags products
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Produce json,xml
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.ProductResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /products [get]
 func (ctrl *ProductController) GetAllProducts(c *gin.Context) {
-	products, err := ctrl.productUseCase.GetAllProducts()
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	products, err := ctrl.productUseCase.GetAllProducts(c.Request.Context())
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentProducts(products)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentProducts(products, page))
 }
 
 // UpdateProduct godoc
@@ -135,67 +142,60 @@ func (ctrl *ProductController) GetAllProducts(c *gin.Context) {
 // @Description Update product information
 // @Tags products
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Product ID"
 // @Param product body dto.UpdateProductRequest true "product"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[dto.ProductResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /products/{id} [put]
 func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
 	var request dto.UpdateProductRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidBody(err))
 		return
 	}
 
-	product, err := ctrl.productUseCase.UpdateProduct(id, &request)
+	product, err := ctrl.productUseCase.UpdateProduct(c.Request.Context(), id, &request)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentProduct(product)
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentProduct(product))
 }
 
 // DeleteProduct godoc
 // @Summary Delete product
 // @Description Delete product by ID
 // @Tags products
-// @Produce json
+// @Produce json,xml
 // @Param id path int true "Product ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Success 200 {object} presenters.Response[any]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /products/{id} [delete]
 func (ctrl *ProductController) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusBadRequest, response)
+		c.Error(invalidParam("id", idStr))
 		return
 	}
 
-	err = ctrl.productUseCase.DeleteProduct(id)
+	err = ctrl.productUseCase.DeleteProduct(c.Request.Context(), id)
 	if err != nil {
-		response := ctrl.presenter.PresentError(err)
-		c.JSON(http.StatusInternalServerError, response)
+		c.Error(err)
 		return
 	}
 
-	response := ctrl.presenter.PresentSuccess("Product deleted successfully")
-	c.JSON(http.StatusOK, response)
+	respond(c, http.StatusOK, ctrl.presenter.PresentSuccess("Product deleted successfully"))
 }
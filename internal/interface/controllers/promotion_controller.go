@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type PromotionController struct {
+	promotionUseCase input.PromotionUseCase
+	presenter        presenters.PromotionPresenter
+}
+
+func NewPromotionController(
+	promotionUseCase input.PromotionUseCase,
+	presenter presenters.PromotionPresenter,
+) *PromotionController {
+	return &PromotionController{
+		promotionUseCase: promotionUseCase,
+		presenter:        presenter,
+	}
+}
+
+// CreatePromotion godoc
+// @Summary Create promotion
+// @Description Create a promotion. Without a code it applies to every order placed while in effect; with one it is a coupon. Types: percentage (value is the percent), fixed (value is the amount), buy_x_get_y (buy_quantity and free_quantity). Weekdays and daily_start/daily_end are read in the store's time zone
+// @Tags promotions
+// @Accept json
+// @Produce json,xml
+// @Param promotion body dto.PromotionRequest true "promotion"
+// @Success 201 {object} presenters.Response[dto.PromotionResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /promotions [post]
+func (ctrl *PromotionController) CreatePromotion(c *gin.Context) {
+	var request dto.PromotionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	promotion, err := ctrl.promotionUseCase.CreatePromotion(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusCreated, ctrl.presenter.PresentPromotion(promotion))
+}
+
+// GetAllPromotions godoc
+// @Summary List promotions
+// @Description List every promotion, oldest first
+// @Tags promotions
+// @Produce json,xml
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.PromotionResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /promotions [get]
+func (ctrl *PromotionController) GetAllPromotions(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	promotions, err := ctrl.promotionUseCase.GetAllPromotions(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentPromotions(promotions, page))
+}
+
+// GetPromotionByID godoc
+// @Summary Get promotion by ID
+// @Description Get promotion by ID
+// @Tags promotions
+// @Produce json,xml
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} presenters.Response[dto.PromotionResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /promotions/{id} [get]
+func (ctrl *PromotionController) GetPromotionByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	promotion, err := ctrl.promotionUseCase.GetPromotionByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentPromotion(promotion))
+}
+
+// UpdatePromotion godoc
+// @Summary Update promotion
+// @Description Replace a promotion's rule and time window. Orders already placed keep their discounts
+// @Tags promotions
+// @Accept json
+// @Produce json,xml
+// @Param id path int true "Promotion ID"
+// @Param promotion body dto.PromotionRequest true "promotion"
+// @Success 200 {object} presenters.Response[dto.PromotionResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /promotions/{id} [put]
+func (ctrl *PromotionController) UpdatePromotion(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	var request dto.PromotionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	promotion, err := ctrl.promotionUseCase.UpdatePromotion(c.Request.Context(), id, &request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentPromotion(promotion))
+}
+
+// DeletePromotion godoc
+// @Summary Delete promotion
+// @Description Delete a promotion. Orders already placed keep their discount lines
+// @Tags promotions
+// @Produce json,xml
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} presenters.Response[any]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /promotions/{id} [delete]
+func (ctrl *PromotionController) DeletePromotion(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	err = ctrl.promotionUseCase.DeletePromotion(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentSuccess("Promotion deleted successfully"))
+}
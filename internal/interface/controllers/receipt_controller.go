@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type ReceiptController struct {
+	receiptUseCase input.ReceiptUseCase
+	presenter      presenters.ReceiptPresenter
+}
+
+func NewReceiptController(
+	receiptUseCase input.ReceiptUseCase,
+	presenter presenters.ReceiptPresenter,
+) *ReceiptController {
+	return &ReceiptController{
+		receiptUseCase: receiptUseCase,
+		presenter:      presenter,
+	}
+}
+
+// GetOrderReceipt godoc
+// @Summary Get the receipt of an order
+// @Description Get the fiscal-style receipt of a paid order, with its items, price breakdown, approximate taxes, approved payments and the customer's CPF when given. Receipts are issued once the order is paid and never change afterwards. Use format=nfce for the NFC-e–like XML document or format=text for the thermal printer layout; otherwise the receipt comes in the usual envelope.
+// @Tags orders
+// @Produce json,xml,plain
+// @Param id path int true "Order ID"
+// @Param format query string false "Rendition" Enums(nfce, text)
+// @Success 200 {object} presenters.Response[dto.ReceiptResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /orders/{id}/receipt [get]
+func (ctrl *ReceiptController) GetOrderReceipt(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	format := c.Query("format")
+	switch format {
+	case "", presenters.ReceiptFormatNFCe, presenters.ReceiptFormatText:
+	default:
+		c.Error(invalidParam("format", format))
+		return
+	}
+
+	receipt, err := ctrl.receiptUseCase.GetOrderReceipt(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	switch format {
+	case presenters.ReceiptFormatNFCe:
+		c.Data(http.StatusOK, "application/xml; charset=utf-8", receipt.XML)
+	case presenters.ReceiptFormatText:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", receipt.Text)
+	default:
+		respond(c, http.StatusOK, ctrl.presenter.PresentReceipt(receipt))
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type ReconciliationController struct {
+	reconciliationUseCase input.ReconciliationUseCase
+	presenter             presenters.ReconciliationPresenter
+}
+
+func NewReconciliationController(
+	reconciliationUseCase input.ReconciliationUseCase,
+	presenter presenters.ReconciliationPresenter,
+) *ReconciliationController {
+	return &ReconciliationController{
+		reconciliationUseCase: reconciliationUseCase,
+		presenter:             presenter,
+	}
+}
+
+// GetReport godoc
+// @Summary Get the payment reconciliation report of a day
+// @Description Get the mismatches found between our payments and the payment provider's records on a UTC day. Reports are generated daily for the previous day. Send Accept: text/csv to download the mismatches as CSV.
+// @Tags payments
+// @Produce json,xml,text/csv
+// @Param date path string true "Day (YYYY-MM-DD)"
+// @Success 200 {object} presenters.Response[dto.ReconciliationReportResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /payments/reconciliation/{date} [get]
+func (ctrl *ReconciliationController) GetReport(c *gin.Context) {
+	dateStr := c.Param("date")
+	date, err := time.Parse(time.DateOnly, dateStr)
+	if err != nil {
+		c.Error(invalidParam("date", dateStr))
+		return
+	}
+
+	report, err := ctrl.reconciliationUseCase.GetReport(c.Request.Context(), date)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if c.NegotiateFormat(binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2, presenters.CSVMediaType) == presenters.CSVMediaType {
+		c.Header("Content-Type", presenters.CSVMediaType+"; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "reconciliation-"+report.Date+".csv"))
+		c.Status(http.StatusOK)
+		if err := ctrl.presenter.WriteReportCSV(c.Writer, report); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentReport(report))
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/middleware"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+// maxPageSize caps page_size so a single request cannot ask for everything
+const maxPageSize = 100
+
+// respond writes body in the format picked from the Accept header. JSON is
+// used when the client does not state a preference.
+func respond(c *gin.Context, status int, body presenters.Envelope) {
+	body.SetTraceID(middleware.TraceIDFrom(c.Request.Context()))
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2) {
+	case binding.MIMEJSON:
+		c.JSON(status, body)
+	case binding.MIMEXML, binding.MIMEXML2:
+		c.XML(status, body)
+	default:
+		c.Error(middleware.ErrNotAcceptable)
+	}
+}
+
+// parsePage reads the page and page_size query parameters. Without page_size
+// the whole list is returned.
+func parsePage(c *gin.Context) (presenters.PageRequest, error) {
+	page := presenters.PageRequest{Page: 1}
+
+	if raw := c.Query("page"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			return page, invalidParam("page", raw)
+		}
+		page.Page = value
+	}
+
+	if raw := c.Query("page_size"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxPageSize {
+			return page, invalidParam("page_size", raw)
+		}
+		page.Size = value
+	}
+
+	return page, nil
+}
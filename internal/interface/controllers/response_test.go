@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/middleware"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+func serveProduct(accept string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.TraceID(), middleware.ErrorHandler(logging.Discard()))
+	router.GET("/product", func(c *gin.Context) {
+		product := &dto.ProductResponse{ID: 7, Name: "X-Burger", Category: "snack"}
+		respond(c, http.StatusOK, presenters.NewProductPresenter().PresentProduct(product))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/product", nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRespond_DefaultsToJSON(t *testing.T) {
+	for _, accept := range []string{"", "*/*", "application/json"} {
+		// Act
+		recorder := serveProduct(accept)
+
+		// Assert
+		var body presenters.Response[dto.ProductResponse]
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Accept %q: expected JSON, got %s", accept, recorder.Body.String())
+		}
+
+		if !body.Success || body.Data.ID != 7 {
+			t.Errorf("Accept %q: unexpected body %+v", accept, body)
+		}
+
+		if body.Meta.TraceID == "" || body.Meta.TraceID != recorder.Header().Get(middleware.TraceIDHeader) {
+			t.Errorf("Accept %q: expected trace ID %q in meta, got %q", accept, recorder.Header().Get(middleware.TraceIDHeader), body.Meta.TraceID)
+		}
+	}
+}
+
+func TestRespond_XML(t *testing.T) {
+	// Act
+	recorder := serveProduct("application/xml")
+
+	// Assert
+	var body presenters.Response[dto.ProductResponse]
+	if err := xml.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected XML, got %s", recorder.Body.String())
+	}
+
+	if body.Data.Name != "X-Burger" || body.Meta.TraceID == "" {
+		t.Errorf("Unexpected body %+v", body)
+	}
+}
+
+func TestRespond_NotAcceptable(t *testing.T) {
+	// Act
+	recorder := serveProduct("text/csv")
+
+	// Assert
+	if recorder.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected status 406, got %d", recorder.Code)
+	}
+}
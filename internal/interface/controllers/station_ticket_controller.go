@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type StationTicketController struct {
+	stationTicketUseCase input.StationTicketUseCase
+	presenter            presenters.StationTicketPresenter
+}
+
+func NewStationTicketController(
+	stationTicketUseCase input.StationTicketUseCase,
+	presenter presenters.StationTicketPresenter,
+) *StationTicketController {
+	return &StationTicketController{
+		stationTicketUseCase: stationTicketUseCase,
+		presenter:            presenter,
+	}
+}
+
+// GetStations godoc
+// @Summary List kitchen stations
+// @Description List the kitchen stations set in KITCHEN_STATIONS and the product categories each one prepares. Empty when the kitchen works from a single queue.
+// @Tags kitchen
+// @Produce json,xml
+// @Success 200 {object} presenters.Response[[]dto.KitchenStationResponse]
+// @Failure 500 {object} middleware.Problem
+// @Router /kitchen/stations [get]
+func (ctrl *StationTicketController) GetStations(c *gin.Context) {
+	stations, err := ctrl.stationTicketUseCase.GetStations(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentStations(stations))
+}
+
+// GetStationQueue godoc
+// @Summary Get a station's queue
+// @Description Get the pending and preparing tickets of a kitchen station, oldest first. Tickets of orders that left the kitchen (ready, completed or cancelled) are excluded.
+// @Tags kitchen
+// @Produce json,xml
+// @Param station path string true "Station name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.StationTicketResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /kitchen/stations/{station}/tickets [get]
+func (ctrl *StationTicketController) GetStationQueue(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	tickets, err := ctrl.stationTicketUseCase.GetStationQueue(c.Request.Context(), c.Param("station"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentQueue(tickets, page))
+}
+
+// GetOrderStationTickets godoc
+// @Summary Get an order's station tickets
+// @Description Get the tickets an order was split into, one per kitchen station that prepares part of it
+// @Tags orders
+// @Produce json,xml
+// @Param id path int true "Order ID"
+// @Success 200 {object} presenters.Response[[]dto.StationTicketResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /orders/{id}/station-tickets [get]
+func (ctrl *StationTicketController) GetOrderStationTickets(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	tickets, err := ctrl.stationTicketUseCase.GetOrderStationTickets(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentTickets(tickets))
+}
+
+// UpdateStationTicketStatus godoc
+// @Summary Update station ticket status
+// @Description Move a station ticket along pending > preparing > done. The order moves to in_progress when the first of its tickets is being prepared and to ready once all of them are done.
+// @Tags kitchen
+// @Accept json
+// @Produce json,xml
+// @Param id path int true "Station ticket ID"
+// @Param status body dto.UpdateStationTicketStatusRequest true "status"
+// @Success 200 {object} presenters.Response[dto.StationTicketResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 409 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /kitchen/tickets/{id}/status [put]
+func (ctrl *StationTicketController) UpdateStationTicketStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	var request dto.UpdateStationTicketStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	ticket, err := ctrl.stationTicketUseCase.UpdateStationTicketStatus(c.Request.Context(), id, &request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentTicket(ticket))
+}
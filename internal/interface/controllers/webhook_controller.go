@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
+)
+
+type WebhookController struct {
+	webhookUseCase input.WebhookUseCase
+	presenter      presenters.WebhookPresenter
+}
+
+func NewWebhookController(
+	webhookUseCase input.WebhookUseCase,
+	presenter presenters.WebhookPresenter,
+) *WebhookController {
+	return &WebhookController{
+		webhookUseCase: webhookUseCase,
+		presenter:      presenter,
+	}
+}
+
+// CreateSubscription godoc
+// @Summary Subscribe to webhooks
+// @Description Register a partner endpoint for order and payment events. Deliveries are signed with the secret (generated when omitted), which is only returned here. Event types: order.created, order.status_changed, order.received, order.in_progress, order.ready, order.completed, order.cancelled, payment.approved, payment.rejected, payment.canceled, payment.expired, payment.refunded
+// @Tags webhooks
+// @Accept json
+// @Produce json,xml
+// @Param subscription body dto.CreateWebhookSubscriptionRequest true "subscription"
+// @Success 201 {object} presenters.Response[dto.WebhookSubscriptionResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /webhooks [post]
+func (ctrl *WebhookController) CreateSubscription(c *gin.Context) {
+	var request dto.CreateWebhookSubscriptionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	subscription, err := ctrl.webhookUseCase.CreateSubscription(c.Request.Context(), &request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusCreated, ctrl.presenter.PresentSubscription(subscription))
+}
+
+// GetAllSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Description List every webhook subscription
+// @Tags webhooks
+// @Produce json,xml
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.WebhookSubscriptionResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /webhooks [get]
+func (ctrl *WebhookController) GetAllSubscriptions(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	subscriptions, err := ctrl.webhookUseCase.GetAllSubscriptions(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentSubscriptions(subscriptions, page))
+}
+
+// GetSubscriptionByID godoc
+// @Summary Get webhook subscription by ID
+// @Description Get webhook subscription by ID
+// @Tags webhooks
+// @Produce json,xml
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} presenters.Response[dto.WebhookSubscriptionResponse]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /webhooks/{id} [get]
+func (ctrl *WebhookController) GetSubscriptionByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	subscription, err := ctrl.webhookUseCase.GetSubscriptionByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentSubscription(subscription))
+}
+
+// DeleteSubscription godoc
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription and its delivery log
+// @Tags webhooks
+// @Produce json,xml
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} presenters.Response[any]
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /webhooks/{id} [delete]
+func (ctrl *WebhookController) DeleteSubscription(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	err = ctrl.webhookUseCase.DeleteSubscription(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentSuccess("Webhook subscription deleted successfully"))
+}
+
+// GetDeliveries godoc
+// @Summary Webhook delivery log
+// @Description List a subscription's deliveries, newest first, with attempts and the outcome of the last one
+// @Tags webhooks
+// @Produce json,xml
+// @Param id path int true "Subscription ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page (1-100), omit to list everything"
+// @Success 200 {object} presenters.Response[[]dto.WebhookDeliveryResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /webhooks/{id}/deliveries [get]
+func (ctrl *WebhookController) GetDeliveries(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	deliveries, err := ctrl.webhookUseCase.GetDeliveries(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, ctrl.presenter.PresentDeliveries(deliveries, page))
+}
+
+// Redeliver godoc
+// @Summary Redeliver a webhook
+// @Description Queue a delivery again with a fresh retry budget, e.g. after it failed or the partner lost it
+// @Tags webhooks
+// @Produce json,xml
+// @Param id path int true "Subscription ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} presenters.Response[dto.WebhookDeliveryResponse]
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (ctrl *WebhookController) Redeliver(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("id", idStr))
+		return
+	}
+
+	deliveryIDStr := c.Param("delivery_id")
+	deliveryID, err := strconv.ParseUint(deliveryIDStr, 10, 64)
+	if err != nil {
+		c.Error(invalidParam("delivery_id", deliveryIDStr))
+		return
+	}
+
+	delivery, err := ctrl.webhookUseCase.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusAccepted, ctrl.presenter.PresentDelivery(delivery))
+}
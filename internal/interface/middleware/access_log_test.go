@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+)
+
+func newLoggedRouter(t *testing.T, logs *bytes.Buffer) *gin.Engine {
+	t.Helper()
+	logger, err := logging.New(logs, logging.FormatJSON, "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), TraceID(), AccessLog(logger), ErrorHandler(logger), Recovery(logger))
+	router.GET("/orders/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return router
+}
+
+func TestAccessLog_CarriesRequestID(t *testing.T) {
+	// Arrange
+	var logs bytes.Buffer
+	router := newLoggedRouter(t, &logs)
+	request := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	request.Header.Set(RequestIDHeader, "client-req-42")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, request)
+
+	// Assert
+	if recorder.Header().Get(RequestIDHeader) != "client-req-42" {
+		t.Errorf("Expected request ID to be echoed, got %q", recorder.Header().Get(RequestIDHeader))
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+		t.Fatalf("Expected one JSON access log line, got %q", logs.String())
+	}
+
+	if line["request_id"] != "client-req-42" || line["route"] != "/orders/:id" || line["status"] != float64(http.StatusNoContent) {
+		t.Errorf("Unexpected access log %v", line)
+	}
+
+	if line["trace_id"] != recorder.Header().Get(TraceIDHeader) {
+		t.Errorf("Expected trace_id %q, got %v", recorder.Header().Get(TraceIDHeader), line["trace_id"])
+	}
+}
+
+func TestRequestID_ReplacesUnsafeValues(t *testing.T) {
+	// Arrange
+	var logs bytes.Buffer
+	router := newLoggedRouter(t, &logs)
+	request := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	request.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, request)
+
+	// Assert
+	requestID := recorder.Header().Get(RequestIDHeader)
+	if len(requestID) != 36 || strings.Count(requestID, "-") != 4 {
+		t.Errorf("Expected a generated UUID, got %q", requestID)
+	}
+}
+
+func TestRecovery_ReturnsProblemAndLogsPanic(t *testing.T) {
+	// Arrange
+	var logs bytes.Buffer
+	router := newLoggedRouter(t, &logs)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	// Assert
+	if recorder.Code != http.StatusInternalServerError || recorder.Header().Get("Content-Type") != problemContentType {
+		t.Errorf("Expected 500 problem, got %d %s", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+
+	if !strings.Contains(logs.String(), `"msg":"panic recovered"`) || !strings.Contains(logs.String(), `"panic":"boom"`) {
+		t.Errorf("Expected panic to be logged, got %q", logs.String())
+	}
+}
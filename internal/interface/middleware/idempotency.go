@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a snapshot
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotencyKeyPattern accepts UUIDs and any other visible ASCII token
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// ErrIdempotencyKeyReused is reported when a key comes back with a
+// different body than the one it was first used with
+var ErrIdempotencyKeyReused = &errs.Error{
+	Code:    errs.CodeIdempotencyKeyReused,
+	Message: "this Idempotency-Key was already used with a different request body",
+}
+
+// Idempotency makes a POST safe to retry. The first request with a given
+// Idempotency-Key runs and its response is stored; a retry with the same body
+// gets that response back instead of running again, a retry with another
+// body gets 422 and one arriving while the first still runs gets 409.
+// Requests without the header are not affected.
+//
+// Only responses the handler wrote with a status below 500 are stored.
+// Otherwise, including errors rendered by ErrorHandler, the key is released
+// so the retry runs again.
+func Idempotency(gateway output.IdempotencyGateway, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !idempotencyKeyPattern.MatchString(key) {
+			abort(c, errs.Validation(errs.CodeInvalidIdempotencyKey, "Idempotency-Key must be 1 to 255 visible ASCII characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, errs.Validation(errs.CodeInvalidRequest, "could not read the request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		record := entities.NewIdempotencyRecord(c.Request.Method+" "+c.FullPath(), key, hex.EncodeToString(hash[:]))
+
+		existing, err := gateway.Reserve(c.Request.Context(), record)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				abort(c, ErrIdempotencyKeyReused)
+			case !existing.IsCompleted():
+				abort(c, errs.Conflict(errs.CodeIdempotencyInProgress, "a request with this Idempotency-Key is still being processed"))
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		writer := &snapshotWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// Deferred so a panicking handler does not leave the key reserved
+		defer finishIdempotent(c, gateway, logger, record, writer)
+		c.Next()
+	}
+}
+
+// finishIdempotent stores the response of a reserved key or releases it
+func finishIdempotent(c *gin.Context, gateway output.IdempotencyGateway, logger *slog.Logger, record *entities.IdempotencyRecord, writer *snapshotWriter) {
+	// The request deadline may have passed; the key must still be saved or
+	// released
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	if writer.Written() && writer.Status() < http.StatusInternalServerError {
+		record.Complete(writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes())
+		if err := gateway.Complete(ctx, record); err != nil {
+			logger.ErrorContext(ctx, "failed to store idempotent response", "idempotency_key", record.Key, "error", err)
+		}
+		return
+	}
+
+	if err := gateway.Release(ctx, record.Scope, record.Key); err != nil {
+		logger.ErrorContext(ctx, "failed to release idempotency key", "idempotency_key", record.Key, "error", err)
+	}
+}
+
+func abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+// snapshotWriter keeps a copy of the response body
+type snapshotWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *snapshotWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *snapshotWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/entities"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/output"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways/memory"
+)
+
+// newIdempotencyRouter counts how many times the handler really runs and
+// fails with a domain error when the body is "invalid"
+func newIdempotencyRouter(gateway output.IdempotencyGateway, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(logging.Discard()))
+	router.POST("/orders", Idempotency(gateway, logging.Discard()), func(c *gin.Context) {
+		*calls++
+		var body map[string]any
+		c.ShouldBindJSON(&body)
+		if body["invalid"] == true {
+			c.Error(errs.Validation(errs.CodeInvalidOrder, "invalid order data"))
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": *calls})
+	})
+	return router
+}
+
+func postOrder(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		request.Header.Set(IdempotencyKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func problemCode(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+	var problem Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Expected a problem body, got %s", recorder.Body.String())
+	}
+	return problem.Code
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	// Arrange
+	calls := 0
+	router := newIdempotencyRouter(memory.NewIdempotencyGateway(memory.NewStore()), &calls)
+	first := postOrder(router, "key-1", `{"items":[1]}`)
+
+	// Act
+	retry := postOrder(router, "key-1", `{"items":[1]}`)
+
+	// Assert
+	if calls != 1 {
+		t.Errorf("Expected the handler to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected the first response replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected %s header on the replay", IdempotentReplayedHeader)
+	}
+	if contentType := retry.Header().Get("Content-Type"); contentType != first.Header().Get("Content-Type") {
+		t.Errorf("Expected content type %q, got %q", first.Header().Get("Content-Type"), contentType)
+	}
+}
+
+func TestIdempotency_RejectsDifferentBody(t *testing.T) {
+	// Arrange
+	calls := 0
+	router := newIdempotencyRouter(memory.NewIdempotencyGateway(memory.NewStore()), &calls)
+	postOrder(router, "key-1", `{"items":[1]}`)
+
+	// Act
+	recorder := postOrder(router, "key-1", `{"items":[2]}`)
+
+	// Assert
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", recorder.Code)
+	}
+	if code := problemCode(t, recorder); code != errs.CodeIdempotencyKeyReused {
+		t.Errorf("Expected code %s, got %q", errs.CodeIdempotencyKeyReused, code)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_ConflictWhileInProgress(t *testing.T) {
+	// Arrange
+	calls := 0
+	gateway := memory.NewIdempotencyGateway(memory.NewStore())
+	router := newIdempotencyRouter(gateway, &calls)
+	hash := sha256.Sum256([]byte(`{"items":[1]}`))
+	gateway.Reserve(context.Background(), entities.NewIdempotencyRecord("POST /orders", "key-1", hex.EncodeToString(hash[:])))
+
+	// Act
+	recorder := postOrder(router, "key-1", `{"items":[1]}`)
+
+	// Assert
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", recorder.Code)
+	}
+	if code := problemCode(t, recorder); code != errs.CodeIdempotencyInProgress {
+		t.Errorf("Expected code %s, got %q", errs.CodeIdempotencyInProgress, code)
+	}
+}
+
+func TestIdempotency_ReleasesKeyOnError(t *testing.T) {
+	// Arrange
+	calls := 0
+	router := newIdempotencyRouter(memory.NewIdempotencyGateway(memory.NewStore()), &calls)
+	failed := postOrder(router, "key-1", `{"invalid":true}`)
+
+	// Act
+	retry := postOrder(router, "key-1", `{"items":[1]}`)
+
+	// Assert
+	if failed.Code != http.StatusBadRequest {
+		t.Errorf("Expected the first request to fail with 400, got %d", failed.Code)
+	}
+	if retry.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("Expected the retry to run again, got %d after %d calls", retry.Code, calls)
+	}
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	// Arrange
+	calls := 0
+	router := newIdempotencyRouter(memory.NewIdempotencyGateway(memory.NewStore()), &calls)
+
+	// Act
+	postOrder(router, "", `{"items":[1]}`)
+	postOrder(router, "", `{"items":[1]}`)
+
+	// Assert
+	if calls != 2 {
+		t.Errorf("Expected every request without a key to run, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_RejectsInvalidKey(t *testing.T) {
+	// Arrange
+	calls := 0
+	router := newIdempotencyRouter(memory.NewIdempotencyGateway(memory.NewStore()), &calls)
+
+	// Act
+	recorder := postOrder(router, "key with spaces", `{}`)
+
+	// Assert
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", recorder.Code)
+	}
+	if code := problemCode(t, recorder); code != errs.CodeInvalidIdempotencyKey {
+		t.Errorf("Expected code %s, got %q", errs.CodeInvalidIdempotencyKey, code)
+	}
+}
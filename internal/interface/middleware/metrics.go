@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that hit no route, so scanners probing
+// random paths cannot blow up metric cardinality
+const unmatchedRoute = "unmatched"
+
+// HTTPObserver records finished requests, see telemetry.Metrics
+type HTTPObserver interface {
+	ObserveHTTP(method, route string, status int, elapsed time.Duration)
+}
+
+// Metrics reports every request to observer, labelled by route template
+func Metrics(observer HTTPObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		observer.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
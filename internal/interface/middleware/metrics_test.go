@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+type recordingObserver struct {
+	requests []observedRequest
+}
+
+func (r *recordingObserver) ObserveHTTP(method, route string, status int, _ time.Duration) {
+	r.requests = append(r.requests, observedRequest{method, route, status})
+}
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	observer := &recordingObserver{}
+	router := gin.New()
+	router.Use(Metrics(observer))
+	router.GET("/orders/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/wp-admin", nil))
+
+	// Assert
+	expected := []observedRequest{
+		{http.MethodGet, "/orders/:id", http.StatusNoContent},
+		{http.MethodGet, unmatchedRoute, http.StatusNotFound},
+	}
+	if len(observer.requests) != len(expected) {
+		t.Fatalf("Expected %d observations, got %+v", len(expected), observer.requests)
+	}
+	for i, want := range expected {
+		if observer.requests[i] != want {
+			t.Errorf("Observation %d: expected %+v, got %+v", i, want, observer.requests[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+)
+
+const problemContentType = "application/problem+json"
+
+// ErrNotAcceptable is reported when none of the formats in the Accept header
+// can be produced
+var ErrNotAcceptable = &errs.Error{
+	Code:    errs.CodeNotAcceptable,
+	Message: "none of the accepted formats are supported, use application/json or application/xml",
+}
+
+// Problem is an RFC 7807 problem details body. Code is an extension member
+// carrying the stable errs code.
+type Problem struct {
+	Type     string `json:"type" example:"/problems/order_not_found"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail" example:"order not found"`
+	Instance string `json:"instance" example:"/api/v1/orders/42"`
+	Code     string `json:"code" example:"order_not_found"`
+	TraceID  string `json:"trace_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+}
+
+// ErrorHandler renders the last error attached with c.Error as a problem
+// response. Handlers that already wrote a response are left alone. Errors
+// that become a 500 are logged since their detail is not sent to the client.
+func ErrorHandler(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := NewProblem(err, c.Request.URL.Path)
+		problem.TraceID = TraceIDFrom(c.Request.Context())
+
+		if problem.Status == http.StatusInternalServerError {
+			logger.ErrorContext(c.Request.Context(), "request failed", "error", err)
+		}
+		c.Header("Content-Type", problemContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// NotFoundHandler answers unknown routes with a problem response
+func NotFoundHandler(c *gin.Context) {
+	c.Error(errs.NotFound(errs.CodeRouteNotFound, "route not found"))
+}
+
+// NewProblem maps err to a problem. Errors that are not domain errors become
+// a 500 with a generic detail, except an expired request deadline which is a
+// 504.
+func NewProblem(err error, instance string) Problem {
+	status := statusFor(err)
+	code := errs.CodeOf(err)
+	detail := err.Error()
+
+	switch status {
+	case http.StatusInternalServerError:
+		code = errs.CodeInternal
+		detail = "an unexpected error occurred"
+	case http.StatusGatewayTimeout:
+		code = errs.CodeRequestTimeout
+		detail = "the request did not complete in time"
+	}
+
+	return Problem{
+		Type:     "/problems/" + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+	}
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrConflict), errors.Is(err, errs.ErrInvalidTransition):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotAcceptable):
+		return http.StatusNotAcceptable
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+)
+
+func newProblemRouter(err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(logging.Discard()))
+	router.NoRoute(NotFoundHandler)
+	router.GET("/fail", func(c *gin.Context) {
+		c.Error(err)
+	})
+	return router
+}
+
+func TestErrorHandler_MapsDomainErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", errs.NotFound(errs.CodeOrderNotFound, "order not found"), http.StatusNotFound, errs.CodeOrderNotFound},
+		{"conflict", errs.Conflict(errs.CodeCustomerAlreadyExists, "exists"), http.StatusConflict, errs.CodeCustomerAlreadyExists},
+		{"validation", errs.Validation(errs.CodeInvalidRequest, "bad"), http.StatusBadRequest, errs.CodeInvalidRequest},
+		{"transition", errs.InvalidTransition(errs.CodeInvalidOrderTransition, "no"), http.StatusConflict, errs.CodeInvalidOrderTransition},
+		{"wrapped", fmt.Errorf("loading: %w", errs.NotFound(errs.CodeProductNotFound, "product not found")), http.StatusNotFound, errs.CodeProductNotFound},
+		{"unknown", errors.New("connection refused"), http.StatusInternalServerError, errs.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			router := newProblemRouter(tt.err)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+			// Assert
+			if recorder.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, recorder.Code)
+			}
+
+			if contentType := recorder.Header().Get("Content-Type"); contentType != problemContentType {
+				t.Errorf("Expected %s, got %s", problemContentType, contentType)
+			}
+
+			var problem Problem
+			if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+
+			if problem.Code != tt.code || problem.Status != tt.status || problem.Instance != "/fail" {
+				t.Errorf("Unexpected problem %+v", problem)
+			}
+		})
+	}
+}
+
+func TestErrorHandler_HidesInternalDetails(t *testing.T) {
+	// Arrange
+	router := newProblemRouter(errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	// Assert
+	var problem Problem
+	json.Unmarshal(recorder.Body.Bytes(), &problem)
+
+	if problem.Detail != "an unexpected error occurred" {
+		t.Errorf("Expected generic detail, got %q", problem.Detail)
+	}
+}
+
+func TestErrorHandler_UnknownRoute(t *testing.T) {
+	// Arrange
+	router := newProblemRouter(nil)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	// Assert
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", recorder.Code)
+	}
+
+	if !json.Valid(recorder.Body.Bytes()) || recorder.Header().Get("Content-Type") != problemContentType {
+		t.Errorf("Expected problem body, got %s", recorder.Body.String())
+	}
+}
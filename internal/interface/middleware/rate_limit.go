@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/ratelimit"
+)
+
+// ClientIDHeader identifies the calling client (e.g. a totem) so it gets its
+// own bucket besides the one for its IP
+const ClientIDHeader = "X-Client-Id"
+
+var clientIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ErrRateLimited is reported when a client ran out of tokens
+var ErrRateLimited = &errs.Error{
+	Code:    errs.CodeRateLimited,
+	Message: "too many requests, retry after the time in the Retry-After header",
+}
+
+// RateLimit takes a token from the client IP bucket and, when X-Client-Id is
+// sent, from the client's bucket too. If either is empty the request is
+// rejected with 429 and Retry-After. Store failures let the request through:
+// losing the limiter must not take the totems down.
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, logger *slog.Logger) gin.HandlerFunc {
+	if !policy.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		keys := []string{policy.Name + ":ip:" + c.ClientIP()}
+		if clientID := c.GetHeader(ClientIDHeader); clientIDPattern.MatchString(clientID) {
+			keys = append(keys, policy.Name+":client:"+clientID)
+		}
+
+		var retryAfter time.Duration
+		limited := false
+		for _, key := range keys {
+			decision, err := store.Take(c.Request.Context(), key, policy)
+			if err != nil {
+				logger.WarnContext(c.Request.Context(), "rate limit store failed, allowing request", "policy", policy.Name, "error", err)
+				continue
+			}
+			if !decision.Allowed {
+				limited = true
+				retryAfter = max(retryAfter, decision.RetryAfter)
+			}
+		}
+
+		if limited {
+			logger.InfoContext(c.Request.Context(), "request rate limited", "policy", policy.Name, "client_ip", c.ClientIP(), "retry_after", retryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+			c.Error(ErrRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// retryAfterSeconds rounds up, since Retry-After only takes whole seconds
+// and retrying early would be rejected again
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/ratelimit"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Policy) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("connection refused")
+}
+
+func newRateLimitRouter(store ratelimit.Store, policy ratelimit.Policy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(logging.Discard()))
+	router.GET("/customers/:cpf", RateLimit(store, policy, logging.Discard()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func lookup(router *gin.Engine, remoteAddr, clientID string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/customers/12345678900", nil)
+	request.RemoteAddr = remoteAddr
+	if clientID != "" {
+		request.Header.Set(ClientIDHeader, clientID)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRateLimit_RejectsWithRetryAfter(t *testing.T) {
+	// Arrange
+	policy := ratelimit.Policy{Name: "customers", RequestsPerMinute: 2, Burst: 1}
+	router := newRateLimitRouter(ratelimit.NewMemoryStore(), policy)
+	lookup(router, "10.0.0.1:1234", "")
+
+	// Act
+	recorder := lookup(router, "10.0.0.1:1234", "")
+
+	// Assert
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", recorder.Code)
+	}
+
+	if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != "30" {
+		t.Errorf("Expected Retry-After 30, got %q", retryAfter)
+	}
+
+	var problem Problem
+	json.Unmarshal(recorder.Body.Bytes(), &problem)
+
+	if problem.Code != errs.CodeRateLimited {
+		t.Errorf("Expected code %s, got %q", errs.CodeRateLimited, problem.Code)
+	}
+}
+
+func TestRateLimit_SeparatesClientsBehindOneIP(t *testing.T) {
+	// Arrange
+	policy := ratelimit.Policy{Name: "orders", RequestsPerMinute: 60, Burst: 2}
+	router := newRateLimitRouter(ratelimit.NewMemoryStore(), policy)
+	lookup(router, "10.0.0.1:1234", "totem-1")
+	lookup(router, "10.0.0.2:1234", "totem-1")
+
+	// Act
+	sameClient := lookup(router, "10.0.0.3:1234", "totem-1")
+	otherClient := lookup(router, "10.0.0.4:1234", "totem-2")
+
+	// Assert
+	if sameClient.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the client bucket to be exhausted across IPs, got %d", sameClient.Code)
+	}
+	if otherClient.Code != http.StatusOK {
+		t.Errorf("Expected another client to be allowed, got %d", otherClient.Code)
+	}
+}
+
+func TestRateLimit_DisabledPolicy(t *testing.T) {
+	// Arrange
+	router := newRateLimitRouter(failingStore{}, ratelimit.Policy{Name: "customers"})
+
+	// Act
+	recorder := lookup(router, "10.0.0.1:1234", "")
+
+	// Assert
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+}
+
+func TestRateLimit_AllowsWhenStoreFails(t *testing.T) {
+	// Arrange
+	policy := ratelimit.Policy{Name: "customers", RequestsPerMinute: 1, Burst: 1}
+	router := newRateLimitRouter(failingStore{}, policy)
+
+	// Act
+	recorder := lookup(router, "10.0.0.1:1234", "")
+
+	// Assert
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+}
+
+func TestRetryAfterSeconds_RoundsUp(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want int
+	}{
+		{0, 1},
+		{200 * time.Millisecond, 1},
+		{1500 * time.Millisecond, 2},
+		{30 * time.Second, 30},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfterSeconds(tt.in); got != tt.want {
+			t.Errorf("retryAfterSeconds(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
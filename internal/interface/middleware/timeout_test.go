@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/errs"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+)
+
+func TestTimeout_DeadlineBecomesGatewayTimeout(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(logging.Discard()), Timeout(10*time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.Error(c.Request.Context().Err())
+	})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	// Assert
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d", recorder.Code)
+	}
+
+	var problem Problem
+	json.Unmarshal(recorder.Body.Bytes(), &problem)
+
+	if problem.Code != errs.CodeRequestTimeout {
+		t.Errorf("Expected code %s, got %q", errs.CodeRequestTimeout, problem.Code)
+	}
+}
+
+func TestTimeout_Disabled(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(0))
+
+	var hasDeadline bool
+	router.GET("/", func(c *gin.Context) {
+		_, hasDeadline = c.Request.Context().Deadline()
+	})
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Assert
+	if hasDeadline {
+		t.Error("Expected no deadline when the timeout is disabled")
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader carries the trace ID on responses. Clients may also send it
+// to correlate their own logs with ours.
+const TraceIDHeader = "X-Trace-Id"
+
+var traceIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// TraceID assigns every request a trace ID and stores it in the request
+// context. When an OpenTelemetry span is active its trace ID wins, so logs and
+// exported spans match; otherwise it is taken from a W3C traceparent or
+// X-Trace-Id header when present.
+func TraceID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		traceID := spanTraceID(c)
+		if traceID == "" {
+			traceID = incomingTraceID(c)
+		}
+		if traceID == "" {
+			traceID = newTraceID()
+		}
+
+		c.Request = c.Request.WithContext(logging.WithTraceID(c.Request.Context(), traceID))
+		c.Header(TraceIDHeader, traceID)
+
+		c.Next()
+	}
+}
+
+// TraceIDFrom returns the trace ID stored by TraceID, or "" outside a request
+func TraceIDFrom(ctx context.Context) string {
+	return logging.TraceIDFrom(ctx)
+}
+
+func spanTraceID(c *gin.Context) string {
+	if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
+func incomingTraceID(c *gin.Context) string {
+	// traceparent: version-traceid-parentid-flags
+	if parts := strings.Split(c.GetHeader("traceparent"), "-"); len(parts) == 4 && traceIDPattern.MatchString(parts[1]) {
+		return parts[1]
+	}
+	if traceID := strings.ToLower(c.GetHeader(TraceIDHeader)); traceIDPattern.MatchString(traceID) {
+		return traceID
+	}
+	return ""
+}
+
+func newTraceID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
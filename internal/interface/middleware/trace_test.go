@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serveTraced(header, value string) (*httptest.ResponseRecorder, string) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TraceID())
+
+	var seen string
+	router.GET("/", func(c *gin.Context) {
+		seen = TraceIDFrom(c.Request.Context())
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		request.Header.Set(header, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder, seen
+}
+
+func TestTraceID_UsesTraceparent(t *testing.T) {
+	// Act
+	recorder, seen := serveTraced("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// Assert
+	if seen != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected trace ID from traceparent, got %q", seen)
+	}
+
+	if recorder.Header().Get(TraceIDHeader) != seen {
+		t.Errorf("Expected %s header %q, got %q", TraceIDHeader, seen, recorder.Header().Get(TraceIDHeader))
+	}
+}
+
+func TestTraceID_GeneratesWhenMissingOrInvalid(t *testing.T) {
+	for _, value := range []string{"", "not-a-trace-id"} {
+		// Act
+		_, seen := serveTraced(TraceIDHeader, value)
+
+		// Assert
+		if !traceIDPattern.MatchString(seen) || seen == value {
+			t.Errorf("Expected a generated trace ID for %q, got %q", value, seen)
+		}
+	}
+}
+
+func TestTraceID_PrefersActiveSpan(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:  trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+	})
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), spanContext))
+	}, TraceID())
+
+	var seen string
+	router.GET("/", func(c *gin.Context) {
+		seen = TraceIDFrom(c.Request.Context())
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(TraceIDHeader, "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	// Assert
+	if seen != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("Expected the span trace ID, got %q", seen)
+	}
+}
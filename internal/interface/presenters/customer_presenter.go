@@ -5,10 +5,9 @@ import (
 )
 
 type CustomerPresenter interface {
-	PresentCustomer(customer *dto.CustomerResponse) interface{}
-	PresentCustomers(customers []*dto.CustomerResponse) interface{}
-	PresentError(err error) interface{}
-	PresentSuccess(message string) interface{}
+	PresentCustomer(customer *dto.CustomerResponse) *Response[*dto.CustomerResponse]
+	PresentCustomers(customers []*dto.CustomerResponse, page PageRequest) *Response[[]*dto.CustomerResponse]
+	PresentSuccess(message string) *Response[any]
 }
 
 type customerPresenter struct{}
@@ -17,43 +16,14 @@ func NewCustomerPresenter() CustomerPresenter {
 	return &customerPresenter{}
 }
 
-func (p *customerPresenter) PresentCustomer(customer *dto.CustomerResponse) interface{} {
-	if customer == nil {
-		return map[string]interface{}{
-			"success": false,
-			"message": "Customer not found",
-			"data":    nil,
-		}
-	}
-
-	return map[string]interface{}{
-		"success": true,
-		"message": "Customer retrieved successfully",
-		"data":    customer,
-	}
-}
-
-func (p *customerPresenter) PresentCustomers(customers []*dto.CustomerResponse) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": "Customers retrieved successfully",
-		"data":    customers,
-		"count":   len(customers),
-	}
+func (p *customerPresenter) PresentCustomer(customer *dto.CustomerResponse) *Response[*dto.CustomerResponse] {
+	return newResponse("Customer retrieved successfully", customer)
 }
 
-func (p *customerPresenter) PresentError(err error) interface{} {
-	return map[string]interface{}{
-		"success": false,
-		"message": err.Error(),
-		"data":    nil,
-	}
+func (p *customerPresenter) PresentCustomers(customers []*dto.CustomerResponse, page PageRequest) *Response[[]*dto.CustomerResponse] {
+	return newPage("Customers retrieved successfully", customers, page)
 }
 
-func (p *customerPresenter) PresentSuccess(message string) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": message,
-		"data":    nil,
-	}
+func (p *customerPresenter) PresentSuccess(message string) *Response[any] {
+	return newResponse[any](message, nil)
 }
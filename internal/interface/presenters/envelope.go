@@ -0,0 +1,78 @@
+package presenters
+
+import "encoding/xml"
+
+// Response is the envelope shared by every successful response
+type Response[T any] struct {
+	XMLName xml.Name `json:"-" xml:"response" swaggerignore:"true"`
+	Success bool     `json:"success" xml:"success" example:"true"`
+	Message string   `json:"message,omitempty" xml:"message,omitempty"`
+	Data    T        `json:"data" xml:"data"`
+	Meta    Meta     `json:"meta" xml:"meta"`
+}
+
+type Meta struct {
+	TraceID    string      `json:"trace_id" xml:"trace_id" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Pagination *Pagination `json:"pagination,omitempty" xml:"pagination,omitempty"`
+}
+
+type Pagination struct {
+	Page       int `json:"page" xml:"page" example:"1"`
+	PageSize   int `json:"page_size" xml:"page_size" example:"20"`
+	Total      int `json:"total" xml:"total" example:"42"`
+	TotalPages int `json:"total_pages" xml:"total_pages" example:"3"`
+}
+
+// PageRequest selects a page of a list. A zero Size returns the whole list as
+// a single page.
+type PageRequest struct {
+	Page int
+	Size int
+}
+
+// Envelope lets the HTTP layer stamp request metadata on any Response
+type Envelope interface {
+	SetTraceID(traceID string)
+}
+
+func (r *Response[T]) SetTraceID(traceID string) {
+	r.Meta.TraceID = traceID
+}
+
+func newResponse[T any](message string, data T) *Response[T] {
+	return &Response[T]{
+		Success: true,
+		Message: message,
+		Data:    data,
+	}
+}
+
+func newPage[T any](message string, items []T, page PageRequest) *Response[[]T] {
+	total := len(items)
+	if page.Size <= 0 {
+		page = PageRequest{Page: 1, Size: total}
+	}
+	if page.Page <= 0 {
+		page.Page = 1
+	}
+
+	totalPages := 0
+	if page.Size > 0 {
+		totalPages = (total + page.Size - 1) / page.Size
+	}
+
+	start := min((page.Page-1)*page.Size, total)
+	end := min(start+page.Size, total)
+
+	response := newResponse(message, items[start:end])
+	if response.Data == nil {
+		response.Data = []T{}
+	}
+	response.Meta.Pagination = &Pagination{
+		Page:       page.Page,
+		PageSize:   page.Size,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+	return response
+}
@@ -0,0 +1,54 @@
+package presenters
+
+import "testing"
+
+func TestNewPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name       string
+		page       PageRequest
+		want       []int
+		pagination Pagination
+	}{
+		{"whole list", PageRequest{Page: 1}, []int{1, 2, 3, 4, 5}, Pagination{Page: 1, PageSize: 5, Total: 5, TotalPages: 1}},
+		{"first page", PageRequest{Page: 1, Size: 2}, []int{1, 2}, Pagination{Page: 1, PageSize: 2, Total: 5, TotalPages: 3}},
+		{"last partial page", PageRequest{Page: 3, Size: 2}, []int{5}, Pagination{Page: 3, PageSize: 2, Total: 5, TotalPages: 3}},
+		{"past the end", PageRequest{Page: 9, Size: 2}, []int{}, Pagination{Page: 9, PageSize: 2, Total: 5, TotalPages: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			response := newPage("ok", items, tt.page)
+
+			// Assert
+			if len(response.Data) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, response.Data)
+			}
+			for i := range tt.want {
+				if response.Data[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, response.Data)
+				}
+			}
+
+			if *response.Meta.Pagination != tt.pagination {
+				t.Errorf("Expected %+v, got %+v", tt.pagination, *response.Meta.Pagination)
+			}
+		})
+	}
+}
+
+func TestNewPage_EmptyListIsNotNull(t *testing.T) {
+	// Act
+	response := newPage[int]("ok", nil, PageRequest{Page: 1})
+
+	// Assert
+	if response.Data == nil {
+		t.Error("Expected an empty slice so the list encodes as []")
+	}
+
+	if response.Meta.Pagination.TotalPages != 0 {
+		t.Errorf("Expected 0 pages, got %d", response.Meta.Pagination.TotalPages)
+	}
+}
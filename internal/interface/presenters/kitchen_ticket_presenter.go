@@ -0,0 +1,19 @@
+package presenters
+
+import (
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+type KitchenTicketPresenter interface {
+	PresentKitchenTicket(ticket *dto.KitchenTicketResponse) *Response[*dto.KitchenTicketResponse]
+}
+
+type kitchenTicketPresenter struct{}
+
+func NewKitchenTicketPresenter() KitchenTicketPresenter {
+	return &kitchenTicketPresenter{}
+}
+
+func (p *kitchenTicketPresenter) PresentKitchenTicket(ticket *dto.KitchenTicketResponse) *Response[*dto.KitchenTicketResponse] {
+	return newResponse("Kitchen ticket printed successfully", ticket)
+}
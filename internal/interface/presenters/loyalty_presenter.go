@@ -0,0 +1,19 @@
+package presenters
+
+import (
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+type LoyaltyPresenter interface {
+	PresentLoyalty(loyalty *dto.LoyaltyResponse) *Response[*dto.LoyaltyResponse]
+}
+
+type loyaltyPresenter struct{}
+
+func NewLoyaltyPresenter() LoyaltyPresenter {
+	return &loyaltyPresenter{}
+}
+
+func (p *loyaltyPresenter) PresentLoyalty(loyalty *dto.LoyaltyResponse) *Response[*dto.LoyaltyResponse] {
+	return newResponse("Loyalty account retrieved successfully", loyalty)
+}
@@ -5,10 +5,9 @@ import (
 )
 
 type OrderPresenter interface {
-	PresentOrder(order *dto.OrderResponse) interface{}
-	PresentOrders(orders []*dto.OrderResponse) interface{}
-	PresentError(err error) interface{}
-	PresentSuccess(message string) interface{}
+	PresentOrder(order *dto.OrderResponse) *Response[*dto.OrderResponse]
+	PresentOrders(orders []*dto.OrderResponse, page PageRequest) *Response[[]*dto.OrderResponse]
+	PresentSuccess(message string) *Response[any]
 }
 
 type orderPresenter struct{}
@@ -17,43 +16,14 @@ func NewOrderPresenter() OrderPresenter {
 	return &orderPresenter{}
 }
 
-func (p *orderPresenter) PresentOrder(order *dto.OrderResponse) interface{} {
-	if order == nil {
-		return map[string]interface{}{
-			"success": false,
-			"message": "Order not found",
-			"data":    nil,
-		}
-	}
-
-	return map[string]interface{}{
-		"success": true,
-		"message": "Order retrieved successfully",
-		"data":    order,
-	}
-}
-
-func (p *orderPresenter) PresentOrders(orders []*dto.OrderResponse) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": "Orders retrieved successfully",
-		"data":    orders,
-		"count":   len(orders),
-	}
+func (p *orderPresenter) PresentOrder(order *dto.OrderResponse) *Response[*dto.OrderResponse] {
+	return newResponse("Order retrieved successfully", order)
 }
 
-func (p *orderPresenter) PresentError(err error) interface{} {
-	return map[string]interface{}{
-		"success": false,
-		"message": err.Error(),
-		"data":    nil,
-	}
+func (p *orderPresenter) PresentOrders(orders []*dto.OrderResponse, page PageRequest) *Response[[]*dto.OrderResponse] {
+	return newPage("Orders retrieved successfully", orders, page)
 }
 
-func (p *orderPresenter) PresentSuccess(message string) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": message,
-		"data":    nil,
-	}
+func (p *orderPresenter) PresentSuccess(message string) *Response[any] {
+	return newResponse[any](message, nil)
 }
@@ -5,10 +5,10 @@ import (
 )
 
 type PaymentPresenter interface {
-	PresentPayment(payment *dto.PaymentResponse) interface{}
-	PresentPaymentStatus(status *dto.PaymentStatusResponse) interface{}
-	PresentError(err error) interface{}
-	PresentSuccess(message string) interface{}
+	PresentPayment(payment *dto.PaymentResponse) *Response[*dto.PaymentResponse]
+	PresentPaymentStatus(status *dto.PaymentStatusResponse) *Response[*dto.PaymentStatusResponse]
+	PresentPaymentAttempts(attempts []*dto.PaymentResponse, page PageRequest) *Response[[]*dto.PaymentResponse]
+	PresentSuccess(message string) *Response[any]
 }
 
 type paymentPresenter struct{}
@@ -17,30 +17,18 @@ func NewPaymentPresenter() PaymentPresenter {
 	return &paymentPresenter{}
 }
 
-func (p *paymentPresenter) PresentPayment(payment *dto.PaymentResponse) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"data":    payment,
-	}
+func (p *paymentPresenter) PresentPayment(payment *dto.PaymentResponse) *Response[*dto.PaymentResponse] {
+	return newResponse("", payment)
 }
 
-func (p *paymentPresenter) PresentPaymentStatus(status *dto.PaymentStatusResponse) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"data":    status,
-	}
+func (p *paymentPresenter) PresentPaymentStatus(status *dto.PaymentStatusResponse) *Response[*dto.PaymentStatusResponse] {
+	return newResponse("", status)
 }
 
-func (p *paymentPresenter) PresentError(err error) interface{} {
-	return map[string]interface{}{
-		"success": false,
-		"error":   err.Error(),
-	}
+func (p *paymentPresenter) PresentPaymentAttempts(attempts []*dto.PaymentResponse, page PageRequest) *Response[[]*dto.PaymentResponse] {
+	return newPage("", attempts, page)
 }
 
-func (p *paymentPresenter) PresentSuccess(message string) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": message,
-	}
+func (p *paymentPresenter) PresentSuccess(message string) *Response[any] {
+	return newResponse[any](message, nil)
 }
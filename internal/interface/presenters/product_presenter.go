@@ -5,10 +5,9 @@ import (
 )
 
 type ProductPresenter interface {
-	PresentProduct(product *dto.ProductResponse) interface{}
-	PresentProducts(products []*dto.ProductResponse) interface{}
-	PresentError(err error) interface{}
-	PresentSuccess(message string) interface{}
+	PresentProduct(product *dto.ProductResponse) *Response[*dto.ProductResponse]
+	PresentProducts(products []*dto.ProductResponse, page PageRequest) *Response[[]*dto.ProductResponse]
+	PresentSuccess(message string) *Response[any]
 }
 
 type productPresenter struct{}
@@ -17,43 +16,14 @@ func NewProductPresenter() ProductPresenter {
 	return &productPresenter{}
 }
 
-func (p *productPresenter) PresentProduct(product *dto.ProductResponse) interface{} {
-	if product == nil {
-		return map[string]interface{}{
-			"success": false,
-			"message": "Product not found",
-			"data":    nil,
-		}
-	}
-
-	return map[string]interface{}{
-		"success": true,
-		"message": "Product retrieved successfully",
-		"data":    product,
-	}
-}
-
-func (p *productPresenter) PresentProducts(products []*dto.ProductResponse) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": "Products retrieved successfully",
-		"data":    products,
-		"count":   len(products),
-	}
+func (p *productPresenter) PresentProduct(product *dto.ProductResponse) *Response[*dto.ProductResponse] {
+	return newResponse("Product retrieved successfully", product)
 }
 
-func (p *productPresenter) PresentError(err error) interface{} {
-	return map[string]interface{}{
-		"success": false,
-		"message": err.Error(),
-		"data":    nil,
-	}
+func (p *productPresenter) PresentProducts(products []*dto.ProductResponse, page PageRequest) *Response[[]*dto.ProductResponse] {
+	return newPage("Products retrieved successfully", products, page)
 }
 
-func (p *productPresenter) PresentSuccess(message string) interface{} {
-	return map[string]interface{}{
-		"success": true,
-		"message": message,
-		"data":    nil,
-	}
+func (p *productPresenter) PresentSuccess(message string) *Response[any] {
+	return newResponse[any](message, nil)
 }
@@ -0,0 +1,29 @@
+package presenters
+
+import (
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+type PromotionPresenter interface {
+	PresentPromotion(promotion *dto.PromotionResponse) *Response[*dto.PromotionResponse]
+	PresentPromotions(promotions []*dto.PromotionResponse, page PageRequest) *Response[[]*dto.PromotionResponse]
+	PresentSuccess(message string) *Response[any]
+}
+
+type promotionPresenter struct{}
+
+func NewPromotionPresenter() PromotionPresenter {
+	return &promotionPresenter{}
+}
+
+func (p *promotionPresenter) PresentPromotion(promotion *dto.PromotionResponse) *Response[*dto.PromotionResponse] {
+	return newResponse("Promotion retrieved successfully", promotion)
+}
+
+func (p *promotionPresenter) PresentPromotions(promotions []*dto.PromotionResponse, page PageRequest) *Response[[]*dto.PromotionResponse] {
+	return newPage("Promotions retrieved successfully", promotions, page)
+}
+
+func (p *promotionPresenter) PresentSuccess(message string) *Response[any] {
+	return newResponse[any](message, nil)
+}
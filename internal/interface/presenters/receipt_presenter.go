@@ -0,0 +1,26 @@
+package presenters
+
+import (
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+// Receipt formats besides the envelope, picked with the format query
+// parameter: the NFC-e–like XML document and the thermal printer text
+const (
+	ReceiptFormatNFCe = "nfce"
+	ReceiptFormatText = "text"
+)
+
+type ReceiptPresenter interface {
+	PresentReceipt(receipt *dto.ReceiptResponse) *Response[*dto.ReceiptResponse]
+}
+
+type receiptPresenter struct{}
+
+func NewReceiptPresenter() ReceiptPresenter {
+	return &receiptPresenter{}
+}
+
+func (p *receiptPresenter) PresentReceipt(receipt *dto.ReceiptResponse) *Response[*dto.ReceiptResponse] {
+	return newResponse("Receipt retrieved successfully", receipt)
+}
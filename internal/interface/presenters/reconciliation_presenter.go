@@ -0,0 +1,67 @@
+package presenters
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+// CSVMediaType is the Accept value that exports a report as CSV
+const CSVMediaType = "text/csv"
+
+// reconciliationCSVHeader names the columns of WriteReportCSV, one mismatch
+// per row
+var reconciliationCSVHeader = []string{
+	"date", "kind", "payment_id", "order_id", "transaction_id",
+	"local_status", "provider_status", "local_amount", "provider_amount",
+}
+
+type ReconciliationPresenter interface {
+	PresentReport(report *dto.ReconciliationReportResponse) *Response[*dto.ReconciliationReportResponse]
+	WriteReportCSV(w io.Writer, report *dto.ReconciliationReportResponse) error
+}
+
+type reconciliationPresenter struct{}
+
+func NewReconciliationPresenter() ReconciliationPresenter {
+	return &reconciliationPresenter{}
+}
+
+func (p *reconciliationPresenter) PresentReport(report *dto.ReconciliationReportResponse) *Response[*dto.ReconciliationReportResponse] {
+	return newResponse("", report)
+}
+
+// WriteReportCSV writes a header row and one row per mismatch; a report
+// without mismatches is just the header
+func (p *reconciliationPresenter) WriteReportCSV(w io.Writer, report *dto.ReconciliationReportResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reconciliationCSVHeader); err != nil {
+		return err
+	}
+
+	for _, mismatch := range report.Mismatches {
+		paymentID := ""
+		if mismatch.PaymentID != 0 {
+			paymentID = strconv.FormatUint(mismatch.PaymentID, 10)
+		}
+		err := writer.Write([]string{
+			report.Date,
+			mismatch.Kind,
+			paymentID,
+			strconv.FormatUint(mismatch.OrderID, 10),
+			mismatch.TransactionID,
+			mismatch.LocalStatus,
+			mismatch.ProviderStatus,
+			strconv.FormatFloat(float64(mismatch.LocalAmount), 'f', 2, 32),
+			strconv.FormatFloat(float64(mismatch.ProviderAmount), 'f', 2, 32),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
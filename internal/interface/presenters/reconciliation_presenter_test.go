@@ -0,0 +1,34 @@
+package presenters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+func TestReconciliationPresenter_WriteReportCSV(t *testing.T) {
+	// Arrange
+	report := &dto.ReconciliationReportResponse{
+		Date: "2026-10-18",
+		Mismatches: []dto.ReconciliationMismatchResponse{
+			{Kind: "amount", PaymentID: 3, OrderID: 7, TransactionID: "mp-1", LocalStatus: "approved", ProviderStatus: "approved", LocalAmount: 20, ProviderAmount: 25.5},
+			{Kind: "missing_locally", OrderID: 9, TransactionID: "mp-2, refunded", ProviderStatus: "approved", ProviderAmount: 5},
+		},
+	}
+	var out strings.Builder
+
+	// Act
+	err := NewReconciliationPresenter().WriteReportCSV(&out, report)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := "date,kind,payment_id,order_id,transaction_id,local_status,provider_status,local_amount,provider_amount\n" +
+		"2026-10-18,amount,3,7,mp-1,approved,approved,20.00,25.50\n" +
+		"2026-10-18,missing_locally,,9,\"mp-2, refunded\",,approved,0.00,5.00\n"
+	if out.String() != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, out.String())
+	}
+}
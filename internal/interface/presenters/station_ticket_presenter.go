@@ -0,0 +1,34 @@
+package presenters
+
+import (
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+type StationTicketPresenter interface {
+	PresentStations(stations []*dto.KitchenStationResponse) *Response[[]*dto.KitchenStationResponse]
+	PresentTicket(ticket *dto.StationTicketResponse) *Response[*dto.StationTicketResponse]
+	PresentTickets(tickets []*dto.StationTicketResponse) *Response[[]*dto.StationTicketResponse]
+	PresentQueue(tickets []*dto.StationTicketResponse, page PageRequest) *Response[[]*dto.StationTicketResponse]
+}
+
+type stationTicketPresenter struct{}
+
+func NewStationTicketPresenter() StationTicketPresenter {
+	return &stationTicketPresenter{}
+}
+
+func (p *stationTicketPresenter) PresentStations(stations []*dto.KitchenStationResponse) *Response[[]*dto.KitchenStationResponse] {
+	return newResponse("Kitchen stations retrieved successfully", stations)
+}
+
+func (p *stationTicketPresenter) PresentTicket(ticket *dto.StationTicketResponse) *Response[*dto.StationTicketResponse] {
+	return newResponse("Station ticket retrieved successfully", ticket)
+}
+
+func (p *stationTicketPresenter) PresentTickets(tickets []*dto.StationTicketResponse) *Response[[]*dto.StationTicketResponse] {
+	return newResponse("Station tickets retrieved successfully", tickets)
+}
+
+func (p *stationTicketPresenter) PresentQueue(tickets []*dto.StationTicketResponse, page PageRequest) *Response[[]*dto.StationTicketResponse] {
+	return newPage("Station tickets retrieved successfully", tickets, page)
+}
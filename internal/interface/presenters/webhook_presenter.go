@@ -0,0 +1,39 @@
+package presenters
+
+import (
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/dto"
+)
+
+type WebhookPresenter interface {
+	PresentSubscription(subscription *dto.WebhookSubscriptionResponse) *Response[*dto.WebhookSubscriptionResponse]
+	PresentSubscriptions(subscriptions []*dto.WebhookSubscriptionResponse, page PageRequest) *Response[[]*dto.WebhookSubscriptionResponse]
+	PresentDelivery(delivery *dto.WebhookDeliveryResponse) *Response[*dto.WebhookDeliveryResponse]
+	PresentDeliveries(deliveries []*dto.WebhookDeliveryResponse, page PageRequest) *Response[[]*dto.WebhookDeliveryResponse]
+	PresentSuccess(message string) *Response[any]
+}
+
+type webhookPresenter struct{}
+
+func NewWebhookPresenter() WebhookPresenter {
+	return &webhookPresenter{}
+}
+
+func (p *webhookPresenter) PresentSubscription(subscription *dto.WebhookSubscriptionResponse) *Response[*dto.WebhookSubscriptionResponse] {
+	return newResponse("Webhook subscription retrieved successfully", subscription)
+}
+
+func (p *webhookPresenter) PresentSubscriptions(subscriptions []*dto.WebhookSubscriptionResponse, page PageRequest) *Response[[]*dto.WebhookSubscriptionResponse] {
+	return newPage("Webhook subscriptions retrieved successfully", subscriptions, page)
+}
+
+func (p *webhookPresenter) PresentDelivery(delivery *dto.WebhookDeliveryResponse) *Response[*dto.WebhookDeliveryResponse] {
+	return newResponse("Webhook delivery queued successfully", delivery)
+}
+
+func (p *webhookPresenter) PresentDeliveries(deliveries []*dto.WebhookDeliveryResponse, page PageRequest) *Response[[]*dto.WebhookDeliveryResponse] {
+	return newPage("Webhook deliveries retrieved successfully", deliveries, page)
+}
+
+func (p *webhookPresenter) PresentSuccess(message string) *Response[any] {
+	return newResponse[any](message, nil)
+}
@@ -2,52 +2,115 @@ package routers
 
 import (
 	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/docs"
-	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/application/usecases"
-	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance/gateways"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/domain/ports/input"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/health"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/persistance"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/ratelimit"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/infrastructure/telemetry"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/controllers"
+	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/middleware"
 	"github.com/samuellalvs/soat_tech_challenge_fast_food/internal/interface/presenters"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type RouterConfig struct {
-	Engine *gin.Engine
-	DB     *sql.DB
+	Engine   *gin.Engine
+	DB       *sql.DB
+	Gateways *persistance.Gateways
+	Logger   *slog.Logger
+	Metrics  *telemetry.Metrics
+	// Readiness backs /readyz; with no checks the pod is always ready
+	Readiness *health.Checker
+	// RequestTimeout bounds each request's context; zero disables it
+	RequestTimeout time.Duration
+	// RateLimitStore holds the token buckets of the RateLimits policies,
+	// keyed by route group; a group without a policy is not limited
+	RateLimitStore ratelimit.Store
+	RateLimits     map[string]ratelimit.Policy
+	// UseCases are built once in main, which shares them with the
+	// background workers and the outbox relay
+	UseCases UseCases
 }
 
-func SetupRoutes(config RouterConfig) {
-	customerGateway := gateways.NewCustomerGateway(config.DB)
-	productGateway := gateways.NewProductGateway(config.DB)
-	orderGateway := gateways.NewOrderGateway(config.DB)
-	orderItemGateway := gateways.NewOrderItemGateway(config.DB)
-	paymentGateway := gateways.NewPaymentGateway(config.DB)
+// UseCases holds the use cases the controllers are built on
+type UseCases struct {
+	Customer       input.CustomerUseCase
+	Product        input.ProductUseCase
+	Order          input.OrderUseCase
+	Payment        input.PaymentUseCase
+	Webhook        input.WebhookUseCase
+	Promotion      input.PromotionUseCase
+	Loyalty        input.LoyaltyUseCase
+	Reconciliation input.ReconciliationUseCase
+	Receipt        input.ReceiptUseCase
+	KitchenTicket  input.KitchenTicketUseCase
+	StationTicket  input.StationTicketUseCase
+}
 
-	customerUseCase := usecases.NewCustomerUseCase(customerGateway)
-	productUseCase := usecases.NewProductUseCase(productGateway)
-	orderUseCase := usecases.NewOrderUseCase(orderGateway, orderItemGateway, productGateway, paymentGateway)
-	paymentUseCase := usecases.NewPaymentUseCase(paymentGateway, orderGateway)
+func SetupRoutes(config RouterConfig) {
+	useCases := config.UseCases
 
 	customerPresenter := presenters.NewCustomerPresenter()
 	productPresenter := presenters.NewProductPresenter()
 	orderPresenter := presenters.NewOrderPresenter()
 	paymentPresenter := presenters.NewPaymentPresenter()
+	webhookPresenter := presenters.NewWebhookPresenter()
+	reconciliationPresenter := presenters.NewReconciliationPresenter()
+	promotionPresenter := presenters.NewPromotionPresenter()
+	loyaltyPresenter := presenters.NewLoyaltyPresenter()
+	receiptPresenter := presenters.NewReceiptPresenter()
+	kitchenTicketPresenter := presenters.NewKitchenTicketPresenter()
+	stationTicketPresenter := presenters.NewStationTicketPresenter()
+
+	customerController := controllers.NewCustomerController(useCases.Customer, customerPresenter)
+	productController := controllers.NewProductController(useCases.Product, productPresenter)
+	orderController := controllers.NewOrderController(useCases.Order, orderPresenter)
+	paymentController := controllers.NewPaymentController(useCases.Payment, paymentPresenter)
+	webhookController := controllers.NewWebhookController(useCases.Webhook, webhookPresenter)
+	reconciliationController := controllers.NewReconciliationController(useCases.Reconciliation, reconciliationPresenter)
+	promotionController := controllers.NewPromotionController(useCases.Promotion, promotionPresenter)
+	loyaltyController := controllers.NewLoyaltyController(useCases.Loyalty, loyaltyPresenter)
+	receiptController := controllers.NewReceiptController(useCases.Receipt, receiptPresenter)
+	kitchenTicketController := controllers.NewKitchenTicketController(useCases.KitchenTicket, kitchenTicketPresenter)
+	stationTicketController := controllers.NewStationTicketController(useCases.StationTicket, stationTicketPresenter)
+
+	config.Engine.Use(
+		middleware.RequestID(),
+		otelgin.Middleware(telemetry.DefaultServiceName, otelgin.WithFilter(traced)),
+		middleware.TraceID(),
+		middleware.AccessLog(config.Logger),
+		middleware.Metrics(config.Metrics),
+		middleware.ErrorHandler(config.Logger),
+		middleware.Recovery(config.Logger),
+		middleware.Timeout(config.RequestTimeout),
+	)
+	config.Engine.NoRoute(middleware.NotFoundHandler)
 
-	customerController := controllers.NewCustomerController(customerUseCase, customerPresenter)
-	productController := controllers.NewProductController(productUseCase, productPresenter)
-	orderController := controllers.NewOrderController(orderUseCase, orderPresenter)
-	paymentController := controllers.NewPaymentController(paymentUseCase, paymentPresenter)
+	rateLimit := func(group string) gin.HandlerFunc {
+		return middleware.RateLimit(config.RateLimitStore, config.RateLimits[group], config.Logger)
+	}
+	// Totems retry creations on flaky networks; Idempotency-Key replays them
+	idempotent := middleware.Idempotency(config.Gateways.Idempotency, config.Logger)
 
 	api := config.Engine.Group("/api/v1")
 	{
 		customers := api.Group("/customers")
 		{
 			customers.POST("", customerController.CreateCustomer)
-			customers.GET("/:cpf", customerController.GetCustomerByCPF)
+			// Limited so the totem lookup cannot be used to enumerate CPFs
+			customers.GET("/:cpf", rateLimit(ratelimit.GroupCustomers), customerController.GetCustomerByCPF)
 			customers.GET("/id/:id", customerController.GetCustomerByID)
+			// The segment is the customer ID; see GetCustomerLoyalty
+			customers.GET("/:cpf/loyalty", loyaltyController.GetCustomerLoyalty)
 			customers.PUT("/:id", customerController.UpdateCustomer)
 			customers.DELETE("/:id", customerController.DeleteCustomer)
 		}
@@ -64,31 +127,73 @@ func SetupRoutes(config RouterConfig) {
 
 		orders := api.Group("/orders")
 		{
-			orders.POST("", orderController.CreateOrder)
+			orders.POST("", rateLimit(ratelimit.GroupOrders), idempotent, orderController.CreateOrder)
 			orders.GET("", orderController.GetAllOrders)
 			orders.GET("/kitchen", orderController.GetOrdersForKitchen)
 			orders.GET("/cpf/:cpf", orderController.GetOrdersByCPF)
 			orders.GET("/customer/:customerId", orderController.GetOrdersByCustomerID)
 			orders.GET("/:id", orderController.GetOrderByID)
+			orders.GET("/:id/receipt", receiptController.GetOrderReceipt)
+			orders.POST("/:id/kitchen-ticket/reprint", kitchenTicketController.ReprintTicket)
+			orders.GET("/:id/station-tickets", stationTicketController.GetOrderStationTickets)
 			orders.PUT("/:id/status", orderController.UpdateOrderStatus)
 			orders.DELETE("/:id", orderController.DeleteOrder)
 		}
 
+		kitchen := api.Group("/kitchen")
+		{
+			kitchen.GET("/stations", stationTicketController.GetStations)
+			kitchen.GET("/stations/:station/tickets", stationTicketController.GetStationQueue)
+			kitchen.PUT("/tickets/:id/status", stationTicketController.UpdateStationTicketStatus)
+		}
+
+		promotions := api.Group("/promotions")
+		{
+			promotions.POST("", promotionController.CreatePromotion)
+			promotions.GET("", promotionController.GetAllPromotions)
+			promotions.GET("/:id", promotionController.GetPromotionByID)
+			promotions.PUT("/:id", promotionController.UpdatePromotion)
+			promotions.DELETE("/:id", promotionController.DeletePromotion)
+		}
+
 		payments := api.Group("/payments")
 		{
-			payments.POST("", paymentController.CreatePayment)
+			payments.POST("", idempotent, paymentController.CreatePayment)
 			payments.GET("/status/:order_id", paymentController.GetPaymentStatus)
+			payments.GET("/attempts/:order_id", paymentController.GetPaymentAttempts)
+			payments.POST("/:id/refund", paymentController.RefundPayment)
 			payments.POST("/webhook", paymentController.PaymentWebhook)
+			payments.GET("/reconciliation/:date", reconciliationController.GetReport)
+		}
+
+		// Outgoing webhooks to partners; /payments/webhook is the incoming one
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("", webhookController.CreateSubscription)
+			webhooks.GET("", webhookController.GetAllSubscriptions)
+			webhooks.GET("/:id", webhookController.GetSubscriptionByID)
+			webhooks.DELETE("/:id", webhookController.DeleteSubscription)
+			webhooks.GET("/:id/deliveries", webhookController.GetDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookController.Redeliver)
 		}
 	}
 
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	config.Engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	config.Engine.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "Fast Food API is running",
-		})
-	})
+	config.Engine.GET("/metrics", gin.WrapH(config.Metrics.Handler()))
+
+	config.Engine.GET("/livez", gin.WrapH(health.LiveHandler()))
+	config.Engine.GET("/readyz", gin.WrapH(health.ReadyHandler(config.Readiness)))
+	// Kept for existing scripts; same report as /readyz
+	config.Engine.GET("/health", gin.WrapH(health.ReadyHandler(config.Readiness)))
+}
+
+// traced skips spans for probes and scrapes, which would drown real traffic
+func traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/health", "/livez", "/readyz", "/metrics":
+		return false
+	}
+	return true
 }